@@ -0,0 +1,215 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bind
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ava-labs/subnet-evm/accounts/abi"
+)
+
+// allowListFunctions are the functions of the IAllowList interface. If a precompile ABI contains
+// the first four of them, the generated precompile is wired to the allow list of the precompile
+// package instead of generating separate execution functions for them.
+var allowListFunctions = []string{
+	"setAdmin", "setEnabled", "setNone", "readAllowList", "setManager",
+	"batchSetAdmin", "batchSetEnabled", "batchSetNone", "getRoleMemberCount", "getRoleMembers",
+	"propose", "approve", "execute", "getProposal",
+}
+
+// allowListEvents are the events of the IAllowList interface, which are emitted by the
+// allow list of the precompile package.
+var allowListEvents = []string{"RoleSet", "ProposalCreated", "ProposalApproved", "ProposalExecuted"}
+
+var errEmptyPrecompileType = errors.New("empty precompile type name")
+
+// tmplPrecompileData is the data structure required to fill the precompile templates.
+type tmplPrecompileData struct {
+	Contract *tmplPrecompileContract // The precompile contract to generate into this file
+	Structs  map[string]*tmplStruct  // Contract struct type definitions
+}
+
+// tmplPrecompileContract contains the data needed to generate a stateful precompile.
+type tmplPrecompileContract struct {
+	Type      string                           // Type name of the main precompile binding
+	InputABI  string                           // JSON ABI used as the input to generate the precompile from
+	AllowList bool                             // Indicator whether the precompile is wired to an allow list
+	Fallback  bool                             // Indicator whether the precompile has a fallback function
+	Funcs     map[string]*tmplPrecompileMethod // Contract functions excluding the allow list functions
+	Events    map[string]*tmplEvent            // Contract events excluding the allow list events
+}
+
+// tmplPrecompileMethod is a wrapper around an abi.Method that contains the Go types of
+// its arguments, which are needed to pack and unpack them in the precompile.
+type tmplPrecompileMethod struct {
+	Original   abi.Method  // Original method as parsed by the abi package
+	Normalized abi.Method  // Normalized version of the parsed method (capitalized names, non-anonymous args/returns)
+	Inputs     []tmplField // Inputs of the method with capitalized names and Go types
+	Outputs    []tmplField // Outputs of the method with capitalized names and Go types
+	InputType  string      // Go type of the unpacked input, empty if the method has no inputs
+	OutputType string      // Go type of the packed output, empty if the method has no outputs
+}
+
+// PrecompileBind generates the Go code of a stateful precompile named [typ] in the precompile
+// package from the JSON [abiData] of its Solidity interface, along with a test file for it.
+// The generated code contains the config, module registration, gas costs, input/output packing
+// and execution functions of the precompile, leaving the custom logic of each function to be filled in.
+func PrecompileBind(typ string, abiData string) (string, string, error) {
+	if typ == "" {
+		return "", "", errEmptyPrecompileType
+	}
+	evmABI, err := abi.JSON(strings.NewReader(abiData))
+	if err != nil {
+		return "", "", err
+	}
+	// Compact the JSON ABI to embed it as a string constant
+	compactABI := new(bytes.Buffer)
+	if err := json.Compact(compactABI, []byte(abiData)); err != nil {
+		return "", "", err
+	}
+
+	var (
+		funcs     = make(map[string]*tmplPrecompileMethod)
+		events    = make(map[string]*tmplEvent)
+		structs   = make(map[string]*tmplStruct)
+		allowList = isAllowList(evmABI)
+	)
+	for _, original := range evmABI.Methods {
+		if allowList && contains(allowListFunctions, original.Name) {
+			continue
+		}
+		normalized := original
+		normalized.Name = methodNormalizer[LangGo](original.Name)
+		normalized.Inputs = make([]abi.Argument, len(original.Inputs))
+		copy(normalized.Inputs, original.Inputs)
+		for j, input := range normalized.Inputs {
+			if input.Name == "" {
+				normalized.Inputs[j].Name = fmt.Sprintf("arg%d", j)
+			}
+			if hasStruct(input.Type) {
+				bindStructType[LangGo](input.Type, structs)
+			}
+		}
+		normalized.Outputs = make([]abi.Argument, len(original.Outputs))
+		copy(normalized.Outputs, original.Outputs)
+		for j, output := range normalized.Outputs {
+			if output.Name == "" {
+				normalized.Outputs[j].Name = fmt.Sprintf("arg%d", j)
+			}
+			if hasStruct(output.Type) {
+				bindStructType[LangGo](output.Type, structs)
+			}
+		}
+		method := &tmplPrecompileMethod{
+			Original:   original,
+			Normalized: normalized,
+			Inputs:     precompileFields(normalized.Inputs, structs),
+			Outputs:    precompileFields(normalized.Outputs, structs),
+		}
+		method.InputType = precompileArgsType(method.Inputs, normalized.Name+"Input")
+		method.OutputType = precompileArgsType(method.Outputs, normalized.Name+"Output")
+		funcs[original.Name] = method
+	}
+	for _, original := range evmABI.Events {
+		if allowList && contains(allowListEvents, original.Name) {
+			continue
+		}
+		normalized := original
+		normalized.Name = methodNormalizer[LangGo](original.Name)
+		events[original.Name] = &tmplEvent{Original: original, Normalized: normalized}
+	}
+
+	data := &tmplPrecompileData{
+		Contract: &tmplPrecompileContract{
+			Type:      capitalise(typ),
+			InputABI:  strconv.Quote(compactABI.String()),
+			AllowList: allowList,
+			Fallback:  evmABI.HasFallback(),
+			Funcs:     funcs,
+			Events:    events,
+		},
+		Structs: structs,
+	}
+	funcMap := template.FuncMap{
+		"bindtype":     bindType[LangGo],
+		"capitalise":   capitalise,
+		"decapitalise": decapitalise,
+	}
+	code, err := executePrecompileTemplate(tmplSourcePrecompileGo, funcMap, data)
+	if err != nil {
+		return "", "", err
+	}
+	test, err := executePrecompileTemplate(tmplSourcePrecompileTestGo, funcMap, data)
+	if err != nil {
+		return "", "", err
+	}
+	return code, test, nil
+}
+
+// executePrecompileTemplate fills [source] with [data] and formats the generated Go code.
+func executePrecompileTemplate(source string, funcMap template.FuncMap, data *tmplPrecompileData) (string, error) {
+	buffer := new(bytes.Buffer)
+	tmpl := template.Must(template.New("").Funcs(funcMap).Parse(source))
+	if err := tmpl.Execute(buffer, data); err != nil {
+		return "", err
+	}
+	code, err := format.Source(buffer.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("%v\n%s", err, buffer)
+	}
+	return string(code), nil
+}
+
+// isAllowList returns true if [evmABI] contains the single address functions of the IAllowList interface.
+func isAllowList(evmABI abi.ABI) bool {
+	for _, name := range allowListFunctions[:4] {
+		if _, ok := evmABI.Methods[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// precompileFields returns the capitalized names and Go types of [args].
+func precompileFields(args abi.Arguments, structs map[string]*tmplStruct) []tmplField {
+	fields := make([]tmplField, 0, len(args))
+	for _, arg := range args {
+		fields = append(fields, tmplField{
+			Type:    bindType[LangGo](arg.Type, structs),
+			Name:    capitalise(arg.Name),
+			SolKind: arg.Type,
+		})
+	}
+	return fields
+}
+
+// precompileArgsType returns the Go type used to hold [fields] in the generated precompile.
+// A single argument is used directly, while multiple arguments are held by [structName].
+func precompileArgsType(fields []tmplField, structName string) string {
+	switch len(fields) {
+	case 0:
+		return ""
+	case 1:
+		return fields[0].Type
+	default:
+		return structName
+	}
+}
+
+// contains returns true if [name] is in [names].
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
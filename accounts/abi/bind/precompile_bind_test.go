@@ -0,0 +1,166 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bind
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const testAllowListABI = `
+	{"type":"function","name":"readAllowList","stateMutability":"view","inputs":[{"name":"addr","type":"address"}],"outputs":[{"name":"role","type":"uint256"}]},
+	{"type":"function","name":"setAdmin","stateMutability":"nonpayable","inputs":[{"name":"addr","type":"address"}],"outputs":[]},
+	{"type":"function","name":"setEnabled","stateMutability":"nonpayable","inputs":[{"name":"addr","type":"address"}],"outputs":[]},
+	{"type":"function","name":"setNone","stateMutability":"nonpayable","inputs":[{"name":"addr","type":"address"}],"outputs":[]},
+	{"type":"function","name":"batchSetAdmin","stateMutability":"nonpayable","inputs":[{"name":"addrs","type":"address[]"}],"outputs":[]},
+	{"type":"function","name":"getRoleMembers","stateMutability":"view","inputs":[{"name":"role","type":"uint256"},{"name":"offset","type":"uint256"},{"name":"limit","type":"uint256"}],"outputs":[{"name":"","type":"address[]"}]},
+	{"type":"event","name":"RoleSet","anonymous":false,"inputs":[{"indexed":true,"name":"role","type":"uint256"},{"indexed":true,"name":"account","type":"address"},{"indexed":true,"name":"sender","type":"address"}]}`
+
+const testCounterABI = `
+	{"type":"function","name":"getCounter","stateMutability":"view","inputs":[],"outputs":[{"name":"value","type":"uint256"},{"name":"","type":"bool"}]},
+	{"type":"function","name":"setCounter","stateMutability":"nonpayable","inputs":[{"name":"value","type":"uint256"},{"name":"","type":"bytes32"}],"outputs":[]},
+	{"type":"function","name":"addEntry","stateMutability":"nonpayable","inputs":[{"name":"entry","type":"tuple","internalType":"struct ICounter.Entry","components":[{"name":"key","type":"address"},{"name":"value","type":"uint256"}]}],"outputs":[{"name":"count","type":"uint256"}]},
+	{"type":"event","name":"CounterSet","anonymous":false,"inputs":[{"indexed":true,"name":"sender","type":"address"},{"indexed":false,"name":"value","type":"uint256"}]}`
+
+func TestPrecompileBind(t *testing.T) {
+	tests := map[string]struct {
+		abi         string
+		expected    []string
+		notExpected []string
+	}{
+		"without allow list": {
+			abi: "[" + testCounterABI + "]",
+			expected: []string{
+				"CounterConfigKey = \"counterConfig\"",
+				"GetCounterGasCost uint64 = readGasCostPerSlot",
+				"SetCounterGasCost uint64 = writeGasCostPerSlot",
+				"type SetCounterInput struct",
+				"type GetCounterOutput struct",
+				"type ICounterEntry struct",
+				"func unpackAddEntryArgs(args []interface{}) ICounterEntry",
+				"getGasCost(accessibleState, CounterAddress, \"setCounter\", SetCounterGasCost)",
+				"functions = append(functions, newStatefulPrecompileABIFunction(method, function))",
+				"ABI: CounterABI,",
+				"CounterSetEventID = CounterABI.Events[\"CounterSet\"].ID",
+				"return newStatefulPrecompileWithFunctionSelectors(nil, functions)",
+			},
+			notExpected: []string{
+				"AllowListConfig",
+				"ErrCannot",
+			},
+		},
+		"with allow list": {
+			abi: "[" + testAllowListABI + "," + testCounterABI + "]",
+			expected: []string{
+				"AllowListConfig",
+				"SetCounterGasCost uint64 = writeGasCostPerSlot + ReadAllowListGasCost",
+				"ErrCannotSetCounter = errors.New(\"non-enabled cannot call setCounter\")",
+				"func GetCounterAllowListStatus(stateDB StateDB, address common.Address) AllowListRole",
+				"functions = append(functions, createAllowListFunctions(precompileAddr)...)",
+				"GasCosts: withAllowListGasCosts(map[string]uint64{",
+				"ABI: withAllowListABI(CounterABI),",
+			},
+			notExpected: []string{
+				"ErrCannotGetCounter",
+				"func setAdmin",
+				"func batchSetAdmin",
+				"func getRoleMembers",
+				"RoleSetEventID",
+			},
+		},
+		"with fallback": {
+			abi: "[" + testCounterABI + `,{"type":"fallback","stateMutability":"nonpayable"}]`,
+			expected: []string{
+				"CounterFallbackGasCost uint64 = writeGasCostPerSlot",
+				"\"fallback\":",
+				"newStatefulPrecompileWithFunctionSelectors(newStatefulPrecompileFunction(nil, counterFallback), functions)",
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			code, testCode, err := PrecompileBind("Counter", test.abi)
+			if err != nil {
+				t.Fatalf("failed to generate precompile: %v", err)
+			}
+			for _, expected := range test.expected {
+				if !strings.Contains(code, expected) {
+					t.Errorf("generated code does not contain %q", expected)
+				}
+			}
+			for _, notExpected := range test.notExpected {
+				if strings.Contains(code, notExpected) {
+					t.Errorf("generated code contains %q", notExpected)
+				}
+			}
+			if !strings.Contains(testCode, "func TestCounterConfig(t *testing.T)") {
+				t.Errorf("generated test does not contain the config test")
+			}
+		})
+	}
+
+	if _, _, err := PrecompileBind("", "[]"); err != errEmptyPrecompileType {
+		t.Fatalf("expected %v, got %v", errEmptyPrecompileType, err)
+	}
+}
+
+// Tests that the precompiles generated by the binder compile into the precompile package and that
+// their generated tests pass.
+func TestPrecompileBindCompiles(t *testing.T) {
+	// Skip the test if no Go command can be found
+	gocmd := runtime.GOROOT() + "/bin/go"
+	if !common.FileExist(gocmd) {
+		t.Skip("go sdk not found for testing")
+	}
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+	precompileDir := filepath.Join(pwd, "..", "..", "..", "precompile") // Repo root
+
+	// Each precompile is compiled separately since the generated precompiles share the same address.
+	for name, abi := range map[string]string{
+		"without allow list": "[" + testCounterABI + "]",
+		"with allow list":    "[" + testAllowListABI + "," + testCounterABI + "]",
+		"with fallback":      "[" + testCounterABI + `,{"type":"fallback","stateMutability":"nonpayable"}]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			code, testCode, err := PrecompileBind("Counter", abi)
+			if err != nil {
+				t.Fatalf("failed to generate precompile: %v", err)
+			}
+			// Add the generated files to the precompile package through an overlay, leaving the source tree untouched.
+			ws := t.TempDir()
+			overlay := struct{ Replace map[string]string }{Replace: make(map[string]string)}
+			for file, content := range map[string]string{"counter.go": code, "counter_test.go": testCode} {
+				path := filepath.Join(ws, file)
+				if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+					t.Fatalf("failed to write %s: %v", file, err)
+				}
+				overlay.Replace[filepath.Join(precompileDir, file)] = path
+			}
+			overlayJSON, err := json.Marshal(overlay)
+			if err != nil {
+				t.Fatalf("failed to marshal overlay: %v", err)
+			}
+			overlayPath := filepath.Join(ws, "overlay.json")
+			if err := os.WriteFile(overlayPath, overlayJSON, 0o600); err != nil {
+				t.Fatalf("failed to write overlay: %v", err)
+			}
+
+			cmd := exec.Command(gocmd, "test", "-count", "1", "-overlay", overlayPath, "-run", "TestCounter", ".")
+			cmd.Dir = precompileDir
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("failed to run generated precompile test: %v\n%s", err, out)
+			}
+		})
+	}
+}
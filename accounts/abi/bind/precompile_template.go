@@ -0,0 +1,405 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bind
+
+// tmplSourcePrecompileGo is the Go precompile template.
+const tmplSourcePrecompileGo = `
+// Code generated
+// This file is a generated precompile contract with stubbed abstract functions.
+// The file is generated by a template. Please inspect every code and comment in this file before use.
+
+package precompile
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/accounts/abi"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = fmt.Errorf
+	_ = big.NewInt
+	_ = abi.ConvertType
+	_ = vmerrs.ErrOutOfGas
+)
+
+{{$contract := .Contract}}
+const (
+	// Gas costs for each function. These are set to the cost of writing a storage slot by default.
+	// Modify them to reflect the actual cost of each function.
+	{{- range .Contract.Funcs}}
+	{{.Normalized.Name}}GasCost uint64 = {{if .Original.IsConstant}}readGasCostPerSlot{{else}}writeGasCostPerSlot{{if $contract.AllowList}} + ReadAllowListGasCost{{end}}{{end}}
+	{{- end}}
+	{{- if .Contract.Fallback}}
+	{{.Contract.Type}}FallbackGasCost uint64 = writeGasCostPerSlot
+	{{- end}}
+
+	// {{.Contract.Type}}RawABI is the ABI of the {{.Contract.Type}} precompile.
+	{{.Contract.Type}}RawABI = {{.Contract.InputABI}}
+
+	// {{.Contract.Type}}ConfigKey is the key used in json config files to specify the {{.Contract.Type}}Config.
+	{{.Contract.Type}}ConfigKey = "{{decapitalise .Contract.Type}}Config"
+)
+
+var (
+	_ StatefulPrecompileConfig = &{{.Contract.Type}}Config{}
+
+	// {{.Contract.Type}}Address is the address where the {{.Contract.Type}} precompile is accessible.
+	// It must be in a reserved range and must not be used by any other precompile.
+	// Forks of subnet-evm should use the range starting at 0x0300000000000000000000000000000000000000.
+	{{.Contract.Type}}Address = common.HexToAddress("0x0300000000000000000000000000000000000000")
+
+	// {{.Contract.Type}}ABI is the parsed ABI of the {{.Contract.Type}} precompile.
+	{{.Contract.Type}}ABI = mustParseABI({{.Contract.Type}}RawABI)
+
+	// {{.Contract.Type}}Precompile is the singleton StatefulPrecompiledContract of the {{.Contract.Type}} precompile.
+	{{.Contract.Type}}Precompile StatefulPrecompiledContract = create{{.Contract.Type}}Precompile({{.Contract.Type}}Address)
+	{{- if .Contract.AllowList}}
+	{{range .Contract.Funcs}}{{if not .Original.IsConstant}}
+	ErrCannot{{.Normalized.Name}} = errors.New("non-enabled cannot call {{.Original.Name}}")
+	{{- end}}{{end}}
+	{{- end}}
+	{{range .Contract.Events}}
+	// {{.Normalized.Name}}EventID is the first topic of the logs emitted for the {{.Original.Name}} event.
+	{{.Normalized.Name}}EventID = {{$contract.Type}}ABI.Events["{{.Original.Name}}"].ID
+	{{- end}}
+)
+
+// {{.Contract.Type}}Config implements the StatefulPrecompileConfig interface while adding in the
+// {{.Contract.Type}} specific precompile address.
+type {{.Contract.Type}}Config struct {
+	{{- if .Contract.AllowList}}
+	AllowListConfig
+	{{- end}}
+	UpgradeableConfig
+}
+
+func init() {
+	mustRegisterModule(Module{
+		ConfigKey: {{.Contract.Type}}ConfigKey,
+		Address:   {{.Contract.Type}}Address,
+		NewConfig: func() StatefulPrecompileConfig { return &{{.Contract.Type}}Config{} },
+		GasCosts: {{if .Contract.AllowList}}withAllowListGasCosts({{end}}map[string]uint64{
+			{{- range .Contract.Funcs}}
+			"{{.Original.Name}}": {{.Normalized.Name}}GasCost,
+			{{- end}}
+			{{- if .Contract.Fallback}}
+			"fallback": {{.Contract.Type}}FallbackGasCost,
+			{{- end}}
+		}{{if .Contract.AllowList}}){{end}},
+		ABI: {{if .Contract.AllowList}}withAllowListABI({{.Contract.Type}}ABI){{else}}{{.Contract.Type}}ABI{{end}},
+	})
+}
+
+{{range .Structs}}
+// {{.Name}} is an auto generated low-level Go binding around an user-defined struct.
+type {{.Name}} struct {
+	{{- range .Fields}}
+	{{.Name}} {{.Type}}
+	{{- end}}
+}
+{{end}}
+
+{{range .Contract.Funcs}}
+{{- if gt (len .Inputs) 1}}
+// {{.Normalized.Name}}Input is the input of the {{.Original.Name}} function.
+type {{.Normalized.Name}}Input struct {
+	{{- range .Inputs}}
+	{{.Name}} {{.Type}}
+	{{- end}}
+}
+{{end}}
+{{- if gt (len .Outputs) 1}}
+// {{.Normalized.Name}}Output is the output of the {{.Original.Name}} function.
+type {{.Normalized.Name}}Output struct {
+	{{- range .Outputs}}
+	{{.Name}} {{.Type}}
+	{{- end}}
+}
+{{end}}
+{{- end}}
+
+// New{{.Contract.Type}}Config returns a config for a network upgrade at [blockTimestamp] that enables
+// {{.Contract.Type}}{{if .Contract.AllowList}} with the given [admins] as members of the allowlist{{end}}.
+func New{{.Contract.Type}}Config(blockTimestamp *big.Int{{if .Contract.AllowList}}, admins []common.Address{{end}}) *{{.Contract.Type}}Config {
+	return &{{.Contract.Type}}Config{
+		{{- if .Contract.AllowList}}
+		AllowListConfig:   AllowListConfig{AllowListAdmins: admins},
+		{{- end}}
+		UpgradeableConfig: UpgradeableConfig{BlockTimestamp: blockTimestamp},
+	}
+}
+
+// NewDisable{{.Contract.Type}}Config returns config for a network upgrade at [blockTimestamp]
+// that disables {{.Contract.Type}}.
+func NewDisable{{.Contract.Type}}Config(blockTimestamp *big.Int) *{{.Contract.Type}}Config {
+	return &{{.Contract.Type}}Config{
+		UpgradeableConfig: UpgradeableConfig{
+			BlockTimestamp: blockTimestamp,
+			Disable:        true,
+		},
+	}
+}
+
+// Address returns the address of the {{.Contract.Type}} precompile.
+func (c *{{.Contract.Type}}Config) Address() common.Address {
+	return {{.Contract.Type}}Address
+}
+
+// Equal returns true if [s] is a [*{{.Contract.Type}}Config] and it has been configured identical to [c].
+func (c *{{.Contract.Type}}Config) Equal(s StatefulPrecompileConfig) bool {
+	// typecast before comparison
+	other, ok := (s).(*{{.Contract.Type}}Config)
+	if !ok {
+		return false
+	}
+	// CUSTOM CODE STARTS HERE
+	// modify this boolean accordingly with your custom {{.Contract.Type}}Config to check if [other] and [c] are equal.
+	return c.UpgradeableConfig.Equal(&other.UpgradeableConfig){{if .Contract.AllowList}} && c.AllowListConfig.Equal(&other.AllowListConfig){{end}}
+}
+
+// Verify tries to verify {{.Contract.Type}}Config and returns an error accordingly.
+func (c *{{.Contract.Type}}Config) Verify() error {
+	// CUSTOM CODE STARTS HERE
+	// Add your own custom verify code for {{.Contract.Type}}Config here and return an error accordingly.
+	{{- if .Contract.AllowList}}
+	return c.AllowListConfig.Verify()
+	{{- else}}
+	return nil
+	{{- end}}
+}
+
+// Configure configures [state] with the initial configuration.
+func (c *{{.Contract.Type}}Config) Configure(_ ChainConfig, state StateDB, _ BlockContext) {
+	{{- if .Contract.AllowList}}
+	c.AllowListConfig.Configure(state, {{.Contract.Type}}Address)
+	{{- end}}
+	// CUSTOM CODE STARTS HERE
+	// Add your own custom configuration of [state] here.
+}
+
+// Reconfigure applies [c] on top of the existing [state] when the enabled precompile is reconfigured.
+func (c *{{.Contract.Type}}Config) Reconfigure(_ ChainConfig, state StateDB, _ BlockContext) {
+	{{- if .Contract.AllowList}}
+	c.AllowListConfig.Configure(state, {{.Contract.Type}}Address)
+	{{- end}}
+	// CUSTOM CODE STARTS HERE
+	// Add your own custom reconfiguration of [state] here, keeping the state modified by calls to the precompile.
+}
+
+// Contract returns the singleton stateful precompiled contract to be used for {{.Contract.Type}}.
+func (c *{{.Contract.Type}}Config) Contract() StatefulPrecompiledContract {
+	return {{.Contract.Type}}Precompile
+}
+{{if .Contract.AllowList}}
+// Get{{.Contract.Type}}AllowListStatus returns the role of [address] for the {{.Contract.Type}} list.
+func Get{{.Contract.Type}}AllowListStatus(stateDB StateDB, address common.Address) AllowListRole {
+	return getAllowListStatus(stateDB, {{.Contract.Type}}Address, address)
+}
+
+// Set{{.Contract.Type}}AllowListStatus sets the permissions of [address] to [role] for the
+// {{.Contract.Type}} list. Assumes [role] has already been verified as valid.
+func Set{{.Contract.Type}}AllowListStatus(stateDB StateDB, address common.Address, role AllowListRole) {
+	setAllowListRole(stateDB, {{.Contract.Type}}Address, address, role)
+}
+{{end}}
+
+{{range .Contract.Funcs}}
+// Pack{{.Normalized.Name}} packs {{if .InputType}}[input] as the input of {{else}}the input of {{end}}{{.Original.Name}} with its function selector.
+func Pack{{.Normalized.Name}}({{if .InputType}}input {{.InputType}}{{end}}) ([]byte, error) {
+	return {{$contract.Type}}ABI.Pack("{{.Original.Name}}"{{if gt (len .Inputs) 1}}{{range .Inputs}}, input.{{.Name}}{{end}}{{else if .InputType}}, input{{end}})
+}
+{{if .InputType}}
+// unpack{{.Normalized.Name}}Args converts the [args] of a call to {{.Original.Name}}, which are unpacked according
+// to the inputs of its ABI method, into its input.
+func unpack{{.Normalized.Name}}Args(args []interface{}) {{.InputType}} {
+	{{- if gt (len .Inputs) 1}}
+	return {{.InputType}}{
+		{{- range $i, $input := .Inputs}}
+		{{$input.Name}}: *abi.ConvertType(args[{{$i}}], new({{$input.Type}})).(*{{$input.Type}}),
+		{{- end}}
+	}
+	{{- else}}
+	return *abi.ConvertType(args[0], new({{.InputType}})).(*{{.InputType}})
+	{{- end}}
+}
+{{end}}
+// {{decapitalise .Normalized.Name}} is the execution function of {{.Original.Name}}.
+// Its arguments are unpacked and its outputs are packed according to the ABI method of {{.Original.Name}}.
+func {{decapitalise .Normalized.Name}}(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, args []interface{}, suppliedGas uint64, readOnly bool) (outputs []interface{}, remainingGas uint64, err error) {
+	if remainingGas, err = deductGas(suppliedGas, getGasCost(accessibleState, {{$contract.Type}}Address, "{{.Original.Name}}", {{.Normalized.Name}}GasCost)); err != nil {
+		return nil, 0, err
+	}
+	{{- if not .Original.IsConstant}}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+	{{- end}}
+	{{- if .InputType}}
+
+	input := unpack{{.Normalized.Name}}Args(args)
+	{{- end}}
+	{{- if and $contract.AllowList (not .Original.IsConstant)}}
+
+	// Verify that the caller is in the allow list and therefore has the right to call this function.
+	callerStatus := getAllowListStatus(accessibleState.GetStateDB(), {{$contract.Type}}Address, caller)
+	if !callerStatus.IsEnabled() {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrCannot{{.Normalized.Name}}, caller)
+	}
+	{{- end}}
+
+	// CUSTOM CODE STARTS HERE
+	{{- if .InputType}}
+	_ = input // CUSTOM CODE OPERATES ON INPUT
+	{{- end}}
+	{{- if .OutputType}}
+
+	var output {{.OutputType}} // CUSTOM CODE FOR AN OUTPUT
+	return []interface{}{ {{- if gt (len .Outputs) 1}}{{range $i, $output := .Outputs}}{{if $i}}, {{end}}output.{{$output.Name}}{{end}}{{else}}output{{end -}} }, remainingGas, nil
+	{{- else}}
+	return nil, remainingGas, nil
+	{{- end}}
+}
+{{end}}
+
+{{- if .Contract.Fallback}}
+// {{decapitalise .Contract.Type}}Fallback is executed if the {{.Contract.Type}} precompile is called without input.
+func {{decapitalise .Contract.Type}}Fallback(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = deductGas(suppliedGas, getGasCost(accessibleState, {{.Contract.Type}}Address, "fallback", {{.Contract.Type}}FallbackGasCost)); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+
+	// CUSTOM CODE STARTS HERE
+	// The returned data of the fallback is not ABI encoded.
+	return []byte{}, remainingGas, nil
+}
+{{end}}
+
+// create{{.Contract.Type}}Precompile returns a StatefulPrecompiledContract with the functions of the
+// {{.Contract.Type}} ABI{{if .Contract.AllowList}} and the allow list functions for [precompileAddr]{{end}}.
+func create{{.Contract.Type}}Precompile(precompileAddr common.Address) StatefulPrecompiledContract {
+	var functions []*statefulPrecompileFunction
+	{{- if .Contract.AllowList}}
+	functions = append(functions, createAllowListFunctions(precompileAddr)...)
+	{{- end}}
+
+	abiFunctionMap := map[string]RunStatefulPrecompileABIFunc{
+		{{- range .Contract.Funcs}}
+		"{{.Original.Name}}": {{decapitalise .Normalized.Name}},
+		{{- end}}
+	}
+	for name, function := range abiFunctionMap {
+		method, ok := {{.Contract.Type}}ABI.Methods[name]
+		if !ok {
+			panic(fmt.Errorf("method %s does not exist in the {{.Contract.Type}} ABI", name))
+		}
+		functions = append(functions, newStatefulPrecompileABIFunction(method, function))
+	}
+	{{- if .Contract.Fallback}}
+
+	return newStatefulPrecompileWithFunctionSelectors(newStatefulPrecompileFunction(nil, {{decapitalise .Contract.Type}}Fallback), functions)
+	{{- else}}
+
+	// Construct the contract with no fallback function.
+	return newStatefulPrecompileWithFunctionSelectors(nil, functions)
+	{{- end}}
+}
+`
+
+// tmplSourcePrecompileTestGo is the Go precompile test template.
+const tmplSourcePrecompileTestGo = `
+// Code generated
+// This file is a generated precompile contract test with the skeleton of test functions.
+// The file is generated by a template. Please inspect every code and comment in this file before use.
+
+package precompile
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+{{$contract := .Contract}}
+func Test{{.Contract.Type}}Config(t *testing.T) {
+	{{- if .Contract.AllowList}}
+	admins := []common.Address{ {1} }
+	{{- end}}
+	config := New{{.Contract.Type}}Config(big.NewInt(1){{if .Contract.AllowList}}, admins{{end}})
+	assert.Equal(t, {{.Contract.Type}}Address, config.Address())
+	assert.NoError(t, config.Verify())
+	assert.True(t, config.Equal(New{{.Contract.Type}}Config(big.NewInt(1){{if .Contract.AllowList}}, admins{{end}})))
+	assert.False(t, config.Equal(New{{.Contract.Type}}Config(big.NewInt(2){{if .Contract.AllowList}}, admins{{end}})))
+	assert.False(t, config.Equal(NewDisable{{.Contract.Type}}Config(big.NewInt(1))))
+	assert.True(t, NewDisable{{.Contract.Type}}Config(big.NewInt(1)).IsDisabled())
+
+	module, ok := GetModule({{.Contract.Type}}ConfigKey)
+	assert.True(t, ok)
+	assert.Equal(t, {{.Contract.Type}}Address, module.Address)
+	assert.IsType(t, &{{.Contract.Type}}Config{}, module.NewConfig())
+}
+
+// test{{.Contract.Type}}ChainConfig returns [config] as the active config of the {{.Contract.Type}} precompile.
+type test{{.Contract.Type}}ChainConfig struct {
+	ChainConfig
+	config *{{.Contract.Type}}Config
+}
+
+func (c *test{{.Contract.Type}}ChainConfig) GetActivePrecompileConfig(common.Address, *big.Int) StatefulPrecompileConfig {
+	return c.config
+}
+
+// test{{.Contract.Type}}AccessibleState provides the chain config and block context of a call to the
+// {{.Contract.Type}} precompile, which are accessed to charge the gas of the call.
+type test{{.Contract.Type}}AccessibleState struct {
+	PrecompileAccessibleState
+	chainConfig ChainConfig
+}
+
+func (s *test{{.Contract.Type}}AccessibleState) GetChainConfig() ChainConfig   { return s.chainConfig }
+func (s *test{{.Contract.Type}}AccessibleState) GetBlockContext() BlockContext { return s }
+func (s *test{{.Contract.Type}}AccessibleState) Number() *big.Int              { return common.Big1 }
+func (s *test{{.Contract.Type}}AccessibleState) Timestamp() *big.Int           { return common.Big1 }
+
+func Test{{.Contract.Type}}GasCosts(t *testing.T) {
+	module, ok := GetModule({{.Contract.Type}}ConfigKey)
+	assert.True(t, ok)
+	for name, execute := range map[string]RunStatefulPrecompileABIFunc{
+		{{- range .Contract.Funcs}}
+		"{{.Original.Name}}": {{decapitalise .Normalized.Name}},
+		{{- end}}
+	} {
+		t.Run(name, func(t *testing.T) {
+			gasCost, ok := module.GasCosts[name]
+			assert.True(t, ok)
+			assert.Contains(t, module.ABI.Methods, name)
+			config := New{{.Contract.Type}}Config(common.Big0{{if .Contract.AllowList}}, nil{{end}})
+			accessibleState := &test{{.Contract.Type}}AccessibleState{chainConfig: &test{{.Contract.Type}}ChainConfig{config: config}}
+
+			// gas is deducted before the state or the arguments of the call are accessed
+			_, remainingGas, err := execute(accessibleState, common.Address{}, {{.Contract.Type}}Address, nil, gasCost-1, false)
+			assert.ErrorIs(t, err, vmerrs.ErrOutOfGas)
+			assert.Equal(t, uint64(0), remainingGas)
+
+			// the gas cost can be overridden by the config of the precompile
+			config.GasOverrides = map[string]uint64{name: gasCost + 1}
+			_, _, err = execute(accessibleState, common.Address{}, {{.Contract.Type}}Address, nil, gasCost, false)
+			assert.ErrorIs(t, err, vmerrs.ErrOutOfGas)
+			assert.NoError(t, module.VerifyGasOverrides(config.GasOverrides))
+		})
+	}
+}
+`
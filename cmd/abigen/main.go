@@ -90,6 +90,10 @@ var (
 		Name:  "alias",
 		Usage: "Comma separated aliases for function and event renaming, e.g. original1=alias1, original2=alias2",
 	}
+	precompileFlag = &cli.BoolFlag{
+		Name:  "precompile",
+		Usage: "Generate a stateful precompile and its test in the precompile package from the contract ABI",
+	}
 )
 
 func init() {
@@ -105,6 +109,7 @@ func init() {
 		outFlag,
 		langFlag,
 		aliasFlag,
+		precompileFlag,
 	}
 	app.Action = abigen
 }
@@ -231,6 +236,9 @@ func abigen(c *cli.Context) error {
 			aliases[match[1]] = match[2]
 		}
 	}
+	if c.Bool(precompileFlag.Name) {
+		return precompileGen(c, types, abis)
+	}
 	// Generate the contract binding
 	code, err := bind.Bind(types, abis, bins, sigs, c.String(pkgFlag.Name), lang, libs, aliases)
 	if err != nil {
@@ -247,6 +255,41 @@ func abigen(c *cli.Context) error {
 	return nil
 }
 
+// precompileGen generates a stateful precompile from the single contract ABI in [abis]
+// and either writes it and its test next to each other or displays the precompile on the standard output.
+func precompileGen(c *cli.Context, types []string, abis []string) error {
+	if c.String(langFlag.Name) != "go" {
+		utils.Fatalf("Precompiles can only be generated in go (--lang)")
+	}
+	if c.String(pkgFlag.Name) != "precompile" {
+		utils.Fatalf("Precompiles can only be generated into the precompile package (--pkg)")
+	}
+	if len(abis) != 1 {
+		utils.Fatalf("Precompile generation requires exactly one contract, found %d", len(abis))
+	}
+	if c.IsSet(abiFlag.Name) && !c.IsSet(typeFlag.Name) {
+		utils.Fatalf("No precompile name specified (--type)")
+	}
+	code, test, err := bind.PrecompileBind(types[0], abis[0])
+	if err != nil {
+		utils.Fatalf("Failed to generate precompile: %v", err)
+	}
+	// Either flush it out to a file or display on the standard output
+	if !c.IsSet(outFlag.Name) {
+		fmt.Printf("%s\n", code)
+		return nil
+	}
+	out := c.String(outFlag.Name)
+	if err := os.WriteFile(out, []byte(code), 0600); err != nil {
+		utils.Fatalf("Failed to write precompile: %v", err)
+	}
+	testOut := strings.TrimSuffix(out, ".go") + "_test.go"
+	if err := os.WriteFile(testOut, []byte(test), 0600); err != nil {
+		utils.Fatalf("Failed to write precompile test: %v", err)
+	}
+	return nil
+}
+
 func main() {
 	log.Root().SetHandler(log.LvlFilterHandler(log.LvlInfo, log.StreamHandler(os.Stderr, log.TerminalFormat(true))))
 
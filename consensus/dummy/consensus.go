@@ -15,6 +15,7 @@ import (
 	"github.com/ava-labs/subnet-evm/core/state"
 	"github.com/ava-labs/subnet-evm/core/types"
 	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile"
 	"github.com/ava-labs/subnet-evm/trie"
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -27,6 +28,7 @@ var (
 	errBlockGasCostNil      = errors.New("block gas cost is nil")
 	errBlockGasCostTooLarge = errors.New("block gas cost is not uint64")
 	errBaseFeeNil           = errors.New("base fee is nil")
+	errInvalidCoinbase      = errors.New("invalid coinbase")
 )
 
 type Mode uint
@@ -150,6 +152,27 @@ func (self *DummyEngine) verifyHeaderGasFields(config *params.ChainConfig, heade
 	return nil
 }
 
+// verifyCoinbase checks that the coinbase of [header] matches the reward address configured
+// in the reward manager precompile at [parent], unless the precompile allows fee recipients.
+// If the reward manager is not enabled, the coinbase is verified against [AllowFeeRecipients]
+// during the syntactic verification of the block.
+func (self *DummyEngine) verifyCoinbase(config *params.ChainConfig, header *types.Header, parent *types.Header, chain consensus.ChainHeaderReader) error {
+	if !config.IsPrecompileEnabled(precompile.RewardManagerAddress, new(big.Int).SetUint64(header.Time)) {
+		return nil
+	}
+	configuredCoinbase, allowFeeRecipients, err := chain.GetCoinbaseAt(parent)
+	if err != nil {
+		return fmt.Errorf("failed to get coinbase at %s: %w", parent.Hash(), err)
+	}
+	if allowFeeRecipients {
+		return nil
+	}
+	if header.Coinbase != configuredCoinbase {
+		return fmt.Errorf("%w: %s does not match required coinbase address %s", errInvalidCoinbase, header.Coinbase, configuredCoinbase)
+	}
+	return nil
+}
+
 // modified from consensus.go
 func (self *DummyEngine) verifyHeader(chain consensus.ChainHeaderReader, header *types.Header, parent *types.Header, uncle bool) error {
 	var (
@@ -175,6 +198,10 @@ func (self *DummyEngine) verifyHeader(chain consensus.ChainHeaderReader, header
 	if err := self.verifyHeaderGasFields(config, header, parent, chain); err != nil {
 		return err
 	}
+	// Ensure the coinbase is allowed by the reward manager
+	if err := self.verifyCoinbase(config, header, parent, chain); err != nil {
+		return err
+	}
 	// Verify the header's timestamp
 	if header.Time > uint64(self.clock.Time().Add(allowedFutureBlockTime).Unix()) {
 		return consensus.ErrFutureBlock
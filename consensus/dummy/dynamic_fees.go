@@ -118,6 +118,24 @@ func EstimateNextBaseFee(config *params.ChainConfig, feeConfig commontype.FeeCon
 	return CalcBaseFee(config, feeConfig, parent, timestamp)
 }
 
+// EstimateNextBlockGasCost estimates the block gas cost of a block with [parent] being built at [timestamp].
+// If [timestamp] is less than the timestamp of [parent], then it uses the same timestamp as parent.
+// Warning: This function should only be used in estimation and should not be used when calculating the canonical
+// block gas cost of a subsequent block.
+func EstimateNextBlockGasCost(feeConfig commontype.FeeConfig, parent *types.Header, timestamp uint64) *big.Int {
+	if timestamp < parent.Time {
+		timestamp = parent.Time
+	}
+	return calcBlockGasCost(
+		feeConfig.TargetBlockRate,
+		feeConfig.MinBlockGasCost,
+		feeConfig.MaxBlockGasCost,
+		feeConfig.BlockGasCostStep,
+		parent.BlockGasCost,
+		parent.Time, timestamp,
+	)
+}
+
 // selectBigWithinBounds returns [value] if it is within the bounds:
 // lowerBound <= value <= upperBound or the bound at either end if [value]
 // is outside of the defined boundaries.
@@ -440,3 +440,43 @@ func TestCalcBlockGasCost(t *testing.T) {
 		})
 	}
 }
+
+func TestEstimateNextBlockGasCost(t *testing.T) {
+	feeConfig := params.DefaultFeeConfig
+	feeConfig.BlockGasCostStep = testBlockGasCostStep
+
+	tests := map[string]struct {
+		parentBlockGasCost *big.Int
+		timestamp          uint64
+
+		expected *big.Int
+	}{
+		"Nil parentBlockGasCost": {
+			parentBlockGasCost: nil,
+			timestamp:          2,
+			expected:           params.DefaultFeeConfig.MinBlockGasCost,
+		},
+		"Same timestamp": {
+			parentBlockGasCost: big.NewInt(0),
+			timestamp:          1,
+			expected:           big.NewInt(100_000),
+		},
+		"Timestamp before parent": {
+			parentBlockGasCost: big.NewInt(0),
+			timestamp:          0,
+			expected:           big.NewInt(100_000),
+		},
+		"1s after parent": {
+			parentBlockGasCost: big.NewInt(0),
+			timestamp:          2,
+			expected:           big.NewInt(50_000),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			parent := &types.Header{Time: 1, BlockGasCost: test.parentBlockGasCost}
+			assert.Zero(t, test.expected.Cmp(EstimateNextBlockGasCost(feeConfig, parent, test.timestamp)))
+		})
+	}
+}
@@ -2,6 +2,18 @@
 pragma solidity ^0.8.0;
 
 interface IAllowList {
+  // Emitted when [sender] sets the role of [account] to [role], once the PrecompileV2 upgrade is activated
+  event RoleSet(uint256 indexed role, address indexed account, address indexed sender);
+
+  // Emitted when [proposer] proposes [action] as proposal [proposalID]
+  event ProposalCreated(uint256 indexed proposalID, address indexed proposer, bytes action);
+
+  // Emitted when [approver] approves proposal [proposalID]
+  event ProposalApproved(uint256 indexed proposalID, address indexed approver);
+
+  // Emitted when [executor] executes proposal [proposalID]
+  event ProposalExecuted(uint256 indexed proposalID, address indexed executor);
+
   // Set [addr] to have the admin role over the minter list
   function setAdmin(address addr) external;
 
@@ -13,4 +25,54 @@ interface IAllowList {
 
   // Read the status of [addr]
   function readAllowList(address addr) external view returns (uint256);
+
+  // Set [addr] to have the manager role over the list, which can add and remove enabled addresses.
+  // Only available after the AllowListManager network upgrade.
+  function setManager(address addr) external;
+
+  // The functions below are available once the PrecompileV2 upgrade is activated.
+  // Addresses whose role was set before the upgrade, other than the addresses listed in
+  // the precompile configs, are only enumerated once their role is set again.
+
+  // Set each of [addrs] to have the admin role over the list
+  function batchSetAdmin(address[] calldata addrs) external;
+
+  // Set each of [addrs] to be enabled on the list
+  function batchSetEnabled(address[] calldata addrs) external;
+
+  // Set each of [addrs] to have no role over the list
+  function batchSetNone(address[] calldata addrs) external;
+
+  // Read the number of addresses with [role]
+  // Reverts if the allow list was enabled with roles granted before the PrecompileV2 upgrade, until it is
+  // re-enabled with a wiped storage
+  function getRoleMemberCount(uint256 role) external view returns (uint256);
+
+  // Read up to [limit] addresses with [role] starting from [offset]
+  // Reverts under the same conditions as getRoleMemberCount
+  function getRoleMembers(uint256 role, uint256 offset, uint256 limit) external view returns (address[] memory);
+
+  // Once a multisig is configured, minting and fee config changes are only accepted from approved proposals,
+  // and configuring another multisig replaces the approvers and expires the pending proposals.
+
+  // Propose [action], the input of a call to this precompile, to be executed once approved by the multisig
+  // threshold. The proposer must be a multisig approver and approves the proposal.
+  function propose(bytes calldata action) external returns (uint256 proposalID);
+
+  // Approve proposal [proposalID] as a multisig approver
+  function approve(uint256 proposalID) external;
+
+  // Execute the action of proposal [proposalID] as a call from this precompile once it is approved
+  // by the multisig threshold
+  function execute(uint256 proposalID) external returns (bytes memory result);
+
+  // Read the action, number of approvals and execution status of proposal [proposalID]
+  function getProposal(uint256 proposalID)
+    external
+    view
+    returns (
+      bytes memory action,
+      uint256 approvals,
+      bool executed
+    );
 }
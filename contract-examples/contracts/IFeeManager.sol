@@ -3,7 +3,38 @@ pragma solidity ^0.8.0;
 import "./IAllowList.sol";
 
 interface IFeeManager is IAllowList {
-  // Set fee config fields to contract storage
+  // Emitted when [sender] sets the fee config fields in the contract storage, once the PrecompileV2 upgrade is activated.
+  event FeeConfigChanged(
+    address indexed sender,
+    uint256 gasLimit,
+    uint256 targetBlockRate,
+    uint256 minBaseFee,
+    uint256 targetGas,
+    uint256 baseFeeChangeDenominator,
+    uint256 minBlockGasCost,
+    uint256 maxBlockGasCost,
+    uint256 blockGasCostStep
+  );
+
+  // Emitted when [sender] schedules the fee config fields to be activated at [activationTimestamp]
+  event FeeConfigScheduled(
+    address indexed sender,
+    uint256 gasLimit,
+    uint256 targetBlockRate,
+    uint256 minBaseFee,
+    uint256 targetGas,
+    uint256 baseFeeChangeDenominator,
+    uint256 minBlockGasCost,
+    uint256 maxBlockGasCost,
+    uint256 blockGasCostStep,
+    uint256 activationTimestamp
+  );
+
+  // Emitted when [sender] cancels the pending fee config scheduled to be activated at [activationTimestamp].
+  event PendingFeeConfigCancelled(address indexed sender, uint256 activationTimestamp);
+
+  // Set fee config fields to contract storage.
+  // Reverts once the PrecompileV2 upgrade is activated if a minimum fee config delay is configured.
   function setFeeConfig(
     uint256 gasLimit,
     uint256 targetBlockRate,
@@ -32,4 +63,41 @@ interface IFeeManager is IAllowList {
 
   // Get the last block number changed the fee config from the contract storage
   function getFeeConfigLastChangedAt() external view returns (uint256 blockNumber);
+
+  // The functions below are available once the PrecompileV2 upgrade is activated.
+
+  // Schedule fee config fields to be activated at [activationTimestamp], replacing any pending fee config.
+  // [activationTimestamp] must be at least the minimum fee config delay after the current block timestamp.
+  // No event is emitted at the activation: getPendingFeeConfig no longer returns the fee config and, unless it
+  // violates the fee config bounds at its activation and is dropped, getFeeConfigLastChangedAt returns its block.
+  function setFeeConfigAt(
+    uint256 gasLimit,
+    uint256 targetBlockRate,
+    uint256 minBaseFee,
+    uint256 targetGas,
+    uint256 baseFeeChangeDenominator,
+    uint256 minBlockGasCost,
+    uint256 maxBlockGasCost,
+    uint256 blockGasCostStep,
+    uint256 activationTimestamp
+  ) external;
+
+  // Get the pending fee config and its activation timestamp, which is 0 if there is no pending fee config
+  function getPendingFeeConfig()
+    external
+    view
+    returns (
+      uint256 gasLimit,
+      uint256 targetBlockRate,
+      uint256 minBaseFee,
+      uint256 targetGas,
+      uint256 baseFeeChangeDenominator,
+      uint256 minBlockGasCost,
+      uint256 maxBlockGasCost,
+      uint256 blockGasCostStep,
+      uint256 activationTimestamp
+    );
+
+  // Cancel the pending fee config
+  function cancelPendingFeeConfig() external;
 }
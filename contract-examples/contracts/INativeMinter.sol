@@ -3,6 +3,18 @@ pragma solidity ^0.8.0;
 import "./IAllowList.sol";
 
 interface INativeMinter is IAllowList {
+  // Emitted when [sender] mints [amount] number of native coins to [recipient], once the PrecompileV2 upgrade is activated
+  event NativeCoinMinted(address indexed sender, address indexed recipient, uint256 amount);
+
   // Mint [amount] number of native coins and send to [addr]
   function mintNativeCoin(address addr, uint256 amount) external;
+
+  // The functions below are available once the PrecompileV2 upgrade is activated.
+  // The minting limits are only enforced and accounted for from the same upgrade.
+
+  // Returns the total number of native coins minted through the native minter since its storage was last wiped
+  function totalMinted() external view returns (uint256);
+
+  // Returns the number of native coins that [addr] can still mint within its allowance and the max supply
+  function remainingAllowance(address addr) external view returns (uint256);
 }
@@ -0,0 +1,21 @@
+//SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+import "./IAllowList.sol";
+
+interface IRewardManager is IAllowList {
+  // Allow block producers to claim the block rewards by setting the coinbase of their blocks
+  function allowFeeRecipients() external;
+
+  // Send the block rewards to [addr]
+  function setRewardAddress(address addr) external;
+
+  // Burn the block rewards by sending them to the blackhole address
+  function disableRewards() external;
+
+  // Get the address receiving the block rewards.
+  // Returns the zero address if fee recipients are allowed.
+  function currentRewardAddress() external view returns (address rewardAddress);
+
+  // Get whether block producers may claim the block rewards
+  function areFeeRecipientsAllowed() external view returns (bool isAllowed);
+}
@@ -0,0 +1,33 @@
+//SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+interface IWarpMessenger {
+  event SendWarpMessage(
+    bytes32 indexed destinationChainID,
+    address indexed destinationAddress,
+    address indexed sender,
+    bytes message
+  );
+
+  // Send a message to [destinationAddress] on [destinationChainID].
+  // The message is signed by the validators of this chain once the block is accepted.
+  function sendWarpMessage(
+    bytes32 destinationChainID,
+    address destinationAddress,
+    bytes calldata payload
+  ) external returns (bytes32 messageID);
+
+  // Get the message included in the predicate of the transaction.
+  // Reverts if the predicate is missing, is not sent to this chain or is not
+  // signed by a quorum of the validators of the source chain.
+  function getVerifiedWarpMessage()
+    external
+    view
+    returns (
+      bytes32 sourceChainID,
+      address sourceAddress,
+      bytes32 destinationChainID,
+      address destinationAddress,
+      bytes memory payload
+    );
+}
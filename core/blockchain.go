@@ -65,13 +65,14 @@ var (
 )
 
 const (
-	bodyCacheLimit      = 256
-	blockCacheLimit     = 256
-	receiptsCacheLimit  = 32
-	txLookupCacheLimit  = 1024
-	feeConfigCacheLimit = 256
-	badBlockLimit       = 10
-	TriesInMemory       = 128
+	bodyCacheLimit           = 256
+	blockCacheLimit          = 256
+	receiptsCacheLimit       = 32
+	txLookupCacheLimit       = 1024
+	feeConfigCacheLimit      = 256
+	coinbaseConfigCacheLimit = 256
+	badBlockLimit            = 10
+	TriesInMemory            = 128
 
 	// BlockChainVersion ensures that an incompatible database forces a resync from scratch.
 	//
@@ -104,10 +105,20 @@ const (
 )
 
 // cacheableFeeConfig encapsulates fee configuration itself and the block number that it has changed at,
-// in order to cache them together.
+// along with the pending fee config and its activation timestamp, in order to cache them together.
 type cacheableFeeConfig struct {
 	feeConfig     commontype.FeeConfig
 	lastChangedAt *big.Int
+
+	pendingFeeConfig           commontype.FeeConfig
+	pendingActivationTimestamp *big.Int
+}
+
+// cacheableCoinbaseConfig encapsulates the coinbase address and whether fee recipients are allowed,
+// in order to cache them together.
+type cacheableCoinbaseConfig struct {
+	coinbaseAddress    common.Address
+	allowFeeRecipients bool
 }
 
 // CacheConfig contains the configuration values for the trie caching/pruning
@@ -181,13 +192,14 @@ type BlockChain struct {
 
 	currentBlock atomic.Value // Current head of the block chain
 
-	stateCache     state.Database // State database to reuse between imports (contains state cache)
-	stateManager   TrieWriter
-	bodyCache      *lru.Cache // Cache for the most recent block bodies
-	receiptsCache  *lru.Cache // Cache for the most recent receipts per block
-	blockCache     *lru.Cache // Cache for the most recent entire blocks
-	txLookupCache  *lru.Cache // Cache for the most recent transaction lookup data.
-	feeConfigCache *lru.Cache // Cache for the most recent feeConfig lookup data.
+	stateCache          state.Database // State database to reuse between imports (contains state cache)
+	stateManager        TrieWriter
+	bodyCache           *lru.Cache // Cache for the most recent block bodies
+	receiptsCache       *lru.Cache // Cache for the most recent receipts per block
+	blockCache          *lru.Cache // Cache for the most recent entire blocks
+	txLookupCache       *lru.Cache // Cache for the most recent transaction lookup data.
+	feeConfigCache      *lru.Cache // Cache for the most recent feeConfig lookup data.
+	coinbaseConfigCache *lru.Cache // Cache for the most recent coinbaseConfig lookup data.
 
 	running int32 // 0 if chain is running, 1 when stopped
 
@@ -244,6 +256,7 @@ func NewBlockChain(
 	blockCache, _ := lru.New(blockCacheLimit)
 	txLookupCache, _ := lru.New(txLookupCacheLimit)
 	feeConfigCache, _ := lru.New(feeConfigCacheLimit)
+	coinbaseConfigCache, _ := lru.New(coinbaseConfigCacheLimit)
 	badBlocks, _ := lru.New(badBlockLimit)
 
 	bc := &BlockChain{
@@ -254,16 +267,17 @@ func NewBlockChain(
 			Cache:     cacheConfig.TrieCleanLimit,
 			Preimages: cacheConfig.Preimages,
 		}),
-		bodyCache:      bodyCache,
-		receiptsCache:  receiptsCache,
-		blockCache:     blockCache,
-		txLookupCache:  txLookupCache,
-		feeConfigCache: feeConfigCache,
-		engine:         engine,
-		vmConfig:       vmConfig,
-		badBlocks:      badBlocks,
-		senderCacher:   newTxSenderCacher(runtime.NumCPU()),
-		acceptorQueue:  make(chan *types.Block, cacheConfig.AcceptorQueueLimit),
+		bodyCache:           bodyCache,
+		receiptsCache:       receiptsCache,
+		blockCache:          blockCache,
+		txLookupCache:       txLookupCache,
+		feeConfigCache:      feeConfigCache,
+		coinbaseConfigCache: coinbaseConfigCache,
+		engine:              engine,
+		vmConfig:            vmConfig,
+		badBlocks:           badBlocks,
+		senderCacher:        newTxSenderCacher(runtime.NumCPU()),
+		acceptorQueue:       make(chan *types.Block, cacheConfig.AcceptorQueueLimit),
 	}
 	bc.validator = NewBlockValidator(chainConfig, bc, engine)
 	bc.prefetcher = newStatePrefetcher(chainConfig, bc, engine)
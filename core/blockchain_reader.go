@@ -32,6 +32,7 @@ import (
 
 	"github.com/ava-labs/subnet-evm/commontype"
 	"github.com/ava-labs/subnet-evm/consensus"
+	"github.com/ava-labs/subnet-evm/constants"
 	"github.com/ava-labs/subnet-evm/core/rawdb"
 	"github.com/ava-labs/subnet-evm/core/state"
 	"github.com/ava-labs/subnet-evm/core/state/snapshot"
@@ -350,34 +351,81 @@ func (bc *BlockChain) SubscribeAcceptedTransactionEvent(ch chan<- NewTxsEvent) e
 }
 
 // GetFeeConfigAt returns the fee configuration and the last changed block number at [parent].
-// If FeeConfigManager is activated at [parent], returns the fee config in the precompile contract state.
+// If FeeConfigManager is activated at [parent], returns the fee config in the precompile contract state,
+// or the pending fee config if its activation timestamp is not after the timestamp of [parent] and it is within
+// the fee config bounds active at [parent]. In that case, the returned last changed block number is the number
+// of the child of [parent], which is the block that stores the pending fee config.
 // Otherwise returns the fee config in the chain config.
 // Assumes that a valid configuration is stored when the precompile is activated.
 func (bc *BlockChain) GetFeeConfigAt(parent *types.Header) (commontype.FeeConfig, *big.Int, error) {
 	config := bc.Config()
 	bigTime := new(big.Int).SetUint64(parent.Time)
-	if !config.IsFeeConfigManager(bigTime) {
+	if !config.IsPrecompileEnabled(precompile.FeeConfigManagerAddress, bigTime) {
 		return config.FeeConfig, common.Big0, nil
 	}
 
+	var cacheable *cacheableFeeConfig
 	// try to return it from the cache
 	if cached, hit := bc.feeConfigCache.Get(parent.Root); hit {
 		cachedFeeConfig, ok := cached.(*cacheableFeeConfig)
 		if !ok {
 			return commontype.EmptyFeeConfig, nil, fmt.Errorf("expected type cacheableFeeConfig, got %T", cached)
 		}
-		return cachedFeeConfig.feeConfig, cachedFeeConfig.lastChangedAt, nil
+		cacheable = cachedFeeConfig
+	} else {
+		stateDB, err := bc.StateAt(parent.Root)
+		if err != nil {
+			return commontype.EmptyFeeConfig, nil, err
+		}
+
+		storedFeeConfig := precompile.GetStoredFeeConfig(stateDB)
+		lastChangedAt := precompile.GetFeeConfigLastChangedAt(stateDB)
+		pendingFeeConfig, pendingActivationTimestamp := precompile.GetPendingFeeConfig(stateDB)
+		cacheable = &cacheableFeeConfig{
+			feeConfig:                  storedFeeConfig,
+			lastChangedAt:              lastChangedAt,
+			pendingFeeConfig:           pendingFeeConfig,
+			pendingActivationTimestamp: pendingActivationTimestamp,
+		}
+		// add it to the cache
+		bc.feeConfigCache.Add(parent.Root, cacheable)
+	}
+
+	// The pending fee config depends on the timestamp of [parent], so it is resolved after the cache lookup.
+	if precompile.IsPendingFeeConfigApplied(config, cacheable.feeConfig, cacheable.pendingFeeConfig, cacheable.pendingActivationTimestamp, bigTime) {
+		return cacheable.pendingFeeConfig, new(big.Int).Add(parent.Number, common.Big1), nil
+	}
+	return cacheable.feeConfig, cacheable.lastChangedAt, nil
+}
+
+// GetCoinbaseAt returns the configured coinbase address at [parent] and whether fee recipients
+// are allowed, in which case the block producer may set any coinbase address.
+// If RewardManager is activated at [parent], returns the reward configuration in the precompile
+// contract state. Otherwise returns the blackhole address and [AllowFeeRecipients] of the chain config.
+func (bc *BlockChain) GetCoinbaseAt(parent *types.Header) (common.Address, bool, error) {
+	config := bc.Config()
+	bigTime := new(big.Int).SetUint64(parent.Time)
+	if !config.IsPrecompileEnabled(precompile.RewardManagerAddress, bigTime) {
+		return constants.BlackholeAddr, config.AllowFeeRecipients, nil
+	}
+
+	// try to return it from the cache
+	if cached, hit := bc.coinbaseConfigCache.Get(parent.Root); hit {
+		cachedCoinbaseConfig, ok := cached.(*cacheableCoinbaseConfig)
+		if !ok {
+			return common.Address{}, false, fmt.Errorf("expected type cacheableCoinbaseConfig, got %T", cached)
+		}
+		return cachedCoinbaseConfig.coinbaseAddress, cachedCoinbaseConfig.allowFeeRecipients, nil
 	}
 
 	stateDB, err := bc.StateAt(parent.Root)
 	if err != nil {
-		return commontype.EmptyFeeConfig, nil, err
+		return common.Address{}, false, err
 	}
+	rewardAddress, feeRecipients := precompile.GetStoredRewardAddress(stateDB)
 
-	storedFeeConfig := precompile.GetStoredFeeConfig(stateDB)
-	lastChangedAt := precompile.GetFeeConfigLastChangedAt(stateDB)
-	cacheable := &cacheableFeeConfig{feeConfig: storedFeeConfig, lastChangedAt: lastChangedAt}
+	cacheable := &cacheableCoinbaseConfig{coinbaseAddress: rewardAddress, allowFeeRecipients: feeRecipients}
 	// add it to the cache
-	bc.feeConfigCache.Add(parent.Root, cacheable)
-	return storedFeeConfig, lastChangedAt, nil
+	bc.coinbaseConfigCache.Add(parent.Root, cacheable)
+	return rewardAddress, feeRecipients, nil
 }
@@ -183,7 +183,9 @@ func TestStatefulPrecompilesConfigure(t *testing.T) {
 		"allow list enabled in genesis": {
 			getConfig: func() *params.ChainConfig {
 				config := *params.TestChainConfig
-				config.ContractDeployerAllowListConfig = precompile.NewContractDeployerAllowListConfig(big.NewInt(0), []common.Address{addr})
+				config.GenesisPrecompiles = params.Precompiles{
+					precompile.ContractDeployerAllowListConfigKey: precompile.NewContractDeployerAllowListConfig(big.NewInt(0), []common.Address{addr}),
+				}
 				return &config
 			},
 			assertState: func(t *testing.T, sdb *state.StateDB) {
@@ -166,6 +166,13 @@ func generateSnapshot(diskdb ethdb.KeyValueStore, triedb *trie.Database, cache i
 	return base
 }
 
+// ResetSnapshotGeneration writes an empty generator marker to [diskdb], so the
+// snapshot is regenerated from the state trie the next time it is loaded. This
+// is used when the state trie is replaced without updating the snapshot.
+func ResetSnapshotGeneration(diskdb ethdb.KeyValueWriter) {
+	journalProgress(diskdb, []byte{}, nil)
+}
+
 // journalProgress persists the generator stats into the database to resume later.
 func journalProgress(db ethdb.KeyValueWriter, marker []byte, stats *generatorStats) {
 	// Write out the generator marker. Note it's a standalone disk layer generator
@@ -38,6 +38,7 @@ import (
 	"github.com/ava-labs/subnet-evm/core/state/snapshot"
 	"github.com/ava-labs/subnet-evm/core/types"
 	"github.com/ava-labs/subnet-evm/metrics"
+	"github.com/ava-labs/subnet-evm/precompile"
 	"github.com/ava-labs/subnet-evm/trie"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -106,6 +107,10 @@ type StateDB struct {
 	// Per-transaction access list
 	accessList *accessList
 
+	// Per-transaction storage keys of access list tuples that target a precompile,
+	// exposed to stateful precompiles as predicates of the transaction.
+	predicateStorageSlots map[common.Address][]byte
+
 	// Journal of state modifications. This is the backbone of
 	// Snapshot and RevertToSnapshot.
 	journal        *journal
@@ -150,17 +155,18 @@ func NewWithSnapshot(root common.Hash, db Database, snap snapshot.Snapshot) (*St
 		return nil, err
 	}
 	sdb := &StateDB{
-		db:                  db,
-		trie:                tr,
-		originalRoot:        root,
-		stateObjects:        make(map[common.Address]*stateObject),
-		stateObjectsPending: make(map[common.Address]struct{}),
-		stateObjectsDirty:   make(map[common.Address]struct{}),
-		logs:                make(map[common.Hash][]*types.Log),
-		preimages:           make(map[common.Hash][]byte),
-		journal:             newJournal(),
-		accessList:          newAccessList(),
-		hasher:              crypto.NewKeccakState(),
+		db:                    db,
+		trie:                  tr,
+		originalRoot:          root,
+		stateObjects:          make(map[common.Address]*stateObject),
+		stateObjectsPending:   make(map[common.Address]struct{}),
+		stateObjectsDirty:     make(map[common.Address]struct{}),
+		logs:                  make(map[common.Hash][]*types.Log),
+		preimages:             make(map[common.Hash][]byte),
+		journal:               newJournal(),
+		accessList:            newAccessList(),
+		predicateStorageSlots: make(map[common.Address][]byte),
+		hasher:                crypto.NewKeccakState(),
 	}
 	if snap != nil {
 		if snap.Root() != root {
@@ -207,9 +213,18 @@ func (s *StateDB) Error() error {
 	return s.dbErr
 }
 
-func (s *StateDB) AddLog(log *types.Log) {
+// AddLog adds a log with the specified parameters to the statedb
+// Note: blockNumber is a required argument because StateDB does not
+// know the current block number.
+func (s *StateDB) AddLog(addr common.Address, topics []common.Hash, data []byte, blockNumber uint64) {
 	s.journal.append(addLogChange{txhash: s.thash})
 
+	log := &types.Log{
+		Address:     addr,
+		Topics:      topics,
+		Data:        data,
+		BlockNumber: blockNumber,
+	}
 	log.TxHash = s.thash
 	log.TxIndex = uint(s.txIndex)
 	log.Index = s.logSize
@@ -728,6 +743,10 @@ func (s *StateDB) Copy() *StateDB {
 	// However, it doesn't cost us much to copy an empty list, so we do it anyway
 	// to not blow up if we ever decide copy it in the middle of a transaction
 	state.accessList = s.accessList.Copy()
+	state.predicateStorageSlots = make(map[common.Address][]byte, len(s.predicateStorageSlots))
+	for addr, slots := range s.predicateStorageSlots {
+		state.predicateStorageSlots[addr] = slots
+	}
 
 	// If there's a prefetcher running, make an inactive copy of it that can
 	// only access data but does not actively preload (since the user will not
@@ -906,6 +925,7 @@ func (s *StateDB) Prepare(thash common.Hash, ti int) {
 	s.thash = thash
 	s.txIndex = ti
 	s.accessList = newAccessList()
+	s.predicateStorageSlots = make(map[common.Address][]byte)
 }
 
 func (s *StateDB) clearJournalAndRefund() {
@@ -1017,9 +1037,23 @@ func (s *StateDB) commit(deleteEmptyObjects bool, snaps *snapshot.Tree, blockHas
 // - Add destination to access list (2929)
 // - Add precompiles to access list (2929)
 // - Add the contents of the optional tx access list (2930)
+// - Record the storage keys of the tx access list tuples that target a stateful precompile
 //
 // This method should only be called if Berlin/SubnetEVM/2929+2930 is applicable at the current number.
 func (s *StateDB) PrepareAccessList(sender common.Address, dst *common.Address, precompiles []common.Address, list types.AccessList) {
+	s.predicateStorageSlots = make(map[common.Address][]byte)
+	for _, el := range list {
+		// Only the first tuple for each precompile address is used as its predicate.
+		if _, exists := s.predicateStorageSlots[el.Address]; exists || !precompile.ReservedAddress(el.Address) {
+			continue
+		}
+		slots := make([]byte, 0, len(el.StorageKeys)*common.HashLength)
+		for _, key := range el.StorageKeys {
+			slots = append(slots, key.Bytes()...)
+		}
+		s.predicateStorageSlots[el.Address] = slots
+	}
+
 	s.AddAddressToAccessList(sender)
 	if dst != nil {
 		s.AddAddressToAccessList(*dst)
@@ -1036,6 +1070,14 @@ func (s *StateDB) PrepareAccessList(sender common.Address, dst *common.Address,
 	}
 }
 
+// GetPredicateStorageSlots returns the storage keys of the access list tuple of the current
+// transaction that targets [address] concatenated into a single byte slice, and whether the
+// transaction included such a tuple.
+func (s *StateDB) GetPredicateStorageSlots(address common.Address) ([]byte, bool) {
+	slots, ok := s.predicateStorageSlots[address]
+	return slots, ok
+}
+
 // AddAddressToAccessList adds the given address to the access list
 func (s *StateDB) AddAddressToAccessList(addr common.Address) {
 	if s.accessList.AddAddress(addr) {
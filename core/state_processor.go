@@ -78,6 +78,8 @@ func (p *StateProcessor) Process(block *types.Block, parent *types.Header, state
 	)
 
 	// Configure any stateful precompiles that should go into effect during this block.
+	// Note: this wipes or preserves the storage of precompiles that are disabled or re-enabled
+	// according to the storage mode of their upgrade, so it must be applied identically by the miner.
 	p.config.CheckConfigurePrecompiles(new(big.Int).SetUint64(parent.Time), block, statedb)
 
 	blockContext := NewEVMBlockContext(header, p.bc, nil)
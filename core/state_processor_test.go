@@ -331,8 +331,8 @@ func TestBadTxAllowListBlock(t *testing.T) {
 			NetworkUpgrades: params.NetworkUpgrades{
 				SubnetEVMTimestamp: big.NewInt(0),
 			},
-			PrecompileUpgrade: params.PrecompileUpgrade{
-				TxAllowListConfig: precompile.NewTxAllowListConfig(big.NewInt(0), []common.Address{}),
+			GenesisPrecompiles: params.Precompiles{
+				precompile.TxAllowListConfigKey: precompile.NewTxAllowListConfig(big.NewInt(0), []common.Address{}),
 			},
 		}
 		signer     = types.LatestSigner(config)
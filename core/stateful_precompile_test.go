@@ -9,12 +9,17 @@ import (
 	"testing"
 
 	"github.com/ava-labs/subnet-evm/commontype"
+	"github.com/ava-labs/subnet-evm/constants"
 	"github.com/ava-labs/subnet-evm/core/rawdb"
 	"github.com/ava-labs/subnet-evm/core/state"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/params"
 	"github.com/ava-labs/subnet-evm/precompile"
 	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ava-labs/subnet-evm/warp"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -49,12 +54,28 @@ func (mb *mockBlockContext) Timestamp() *big.Int { return new(big.Int).SetUint64
 type mockAccessibleState struct {
 	state        *state.StateDB
 	blockContext *mockBlockContext
+	chainID      *big.Int
+	chainConfig  precompile.ChainConfig
 }
 
 func (m *mockAccessibleState) GetStateDB() precompile.StateDB { return m.state }
 
 func (m *mockAccessibleState) GetBlockContext() precompile.BlockContext { return m.blockContext }
 
+func (m *mockAccessibleState) GetChainID() *big.Int {
+	if m.chainID == nil {
+		return common.Big1
+	}
+	return m.chainID
+}
+
+func (m *mockAccessibleState) GetChainConfig() precompile.ChainConfig {
+	if m.chainConfig == nil {
+		return params.TestChainConfig
+	}
+	return m.chainConfig
+}
+
 // This test is added within the core package so that it can import all of the required code
 // without creating any import cycles
 func TestContractDeployerAllowListRun(t *testing.T) {
@@ -73,6 +94,7 @@ func TestContractDeployerAllowListRun(t *testing.T) {
 
 	adminAddr := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
 	noRoleAddr := common.HexToAddress("0xF60C45c607D0f41687c94C314d300f483661E13a")
+	otherAddr := common.HexToAddress("0x0Fa8EA536Be85F32724D57A37758761B86416123")
 
 	for name, test := range map[string]test{
 		"set admin": {
@@ -94,6 +116,12 @@ func TestContractDeployerAllowListRun(t *testing.T) {
 
 				res = precompile.GetContractDeployerAllowListStatus(state, noRoleAddr)
 				assert.Equal(t, precompile.AllowListAdmin, res)
+
+				logs := state.Logs()
+				assert.Len(t, logs, 1)
+				assert.Equal(t, precompile.ContractDeployerAllowListAddress, logs[0].Address)
+				assert.Equal(t, []common.Hash{precompile.RoleSetEventID, common.Hash(precompile.AllowListAdmin), noRoleAddr.Hash(), adminAddr.Hash()}, logs[0].Topics)
+				assert.Empty(t, logs[0].Data)
 			},
 		},
 		"set deployer": {
@@ -205,243 +233,142 @@ func TestContractDeployerAllowListRun(t *testing.T) {
 			readOnly:    false,
 			expectedErr: vmerrs.ErrOutOfGas.Error(),
 		},
-		"read allow list no role": {
-			caller:         noRoleAddr,
-			precompileAddr: precompile.ContractDeployerAllowListAddress,
-			input: func() []byte {
-				return precompile.PackReadAllowList(noRoleAddr)
-			},
-			suppliedGas: precompile.ReadAllowListGasCost,
-			readOnly:    false,
-			expectedRes: common.Hash(precompile.AllowListNoRole).Bytes(),
-			assertState: func(t *testing.T, state *state.StateDB) {
-				res := precompile.GetContractDeployerAllowListStatus(state, noRoleAddr)
-				assert.Equal(t, precompile.AllowListNoRole, res)
-			},
-		},
-		"read allow list admin role": {
-			caller:         adminAddr,
-			precompileAddr: precompile.ContractDeployerAllowListAddress,
-			input: func() []byte {
-				return precompile.PackReadAllowList(noRoleAddr)
-			},
-			suppliedGas: precompile.ReadAllowListGasCost,
-			readOnly:    false,
-			expectedRes: common.Hash(precompile.AllowListNoRole).Bytes(),
-			assertState: func(t *testing.T, state *state.StateDB) {
-				res := precompile.GetContractDeployerAllowListStatus(state, adminAddr)
-				assert.Equal(t, precompile.AllowListAdmin, res)
-			},
-		},
-		"read allow list with readOnly enabled": {
-			caller:         adminAddr,
-			precompileAddr: precompile.ContractDeployerAllowListAddress,
-			input: func() []byte {
-				return precompile.PackReadAllowList(noRoleAddr)
-			},
-			suppliedGas: precompile.ReadAllowListGasCost,
-			readOnly:    true,
-			expectedRes: common.Hash(precompile.AllowListNoRole).Bytes(),
-			assertState: func(t *testing.T, state *state.StateDB) {
-				res := precompile.GetContractDeployerAllowListStatus(state, adminAddr)
-				assert.Equal(t, precompile.AllowListAdmin, res)
-			},
-		},
-		"read allow list out of gas": {
+		"batch set enabled": {
 			caller:         adminAddr,
 			precompileAddr: precompile.ContractDeployerAllowListAddress,
 			input: func() []byte {
-				return precompile.PackReadAllowList(noRoleAddr)
-			},
-			suppliedGas: precompile.ReadAllowListGasCost - 1,
-			readOnly:    true,
-			expectedErr: vmerrs.ErrOutOfGas.Error(),
-		},
-	} {
-		t.Run(name, func(t *testing.T) {
-			db := rawdb.NewMemoryDatabase()
-			state, err := state.New(common.Hash{}, state.NewDatabase(db), nil)
-			if err != nil {
-				t.Fatal(err)
-			}
-
-			// Set up the state so that each address has the expected permissions at the start.
-			precompile.SetContractDeployerAllowListStatus(state, adminAddr, precompile.AllowListAdmin)
-			precompile.SetContractDeployerAllowListStatus(state, noRoleAddr, precompile.AllowListNoRole)
-			blockContext := &mockBlockContext{blockNumber: common.Big0}
-			ret, remainingGas, err := precompile.ContractDeployerAllowListPrecompile.Run(&mockAccessibleState{state: state, blockContext: blockContext}, test.caller, test.precompileAddr, test.input(), test.suppliedGas, test.readOnly)
-			if len(test.expectedErr) != 0 {
-				if err == nil {
-					assert.Failf(t, "run expectedly passed without error", "expected error %q", test.expectedErr)
-				} else {
-					assert.True(t, strings.Contains(err.Error(), test.expectedErr), "expected error (%s) to contain substring (%s)", err, test.expectedErr)
-				}
-				return
-			}
-
-			if err != nil {
-				t.Fatal(err)
-			}
-
-			assert.Equal(t, uint64(0), remainingGas)
-			assert.Equal(t, test.expectedRes, ret)
-
-			test.assertState(t, state)
-		})
-	}
-}
-
-func TestTxAllowListRun(t *testing.T) {
-	type test struct {
-		caller         common.Address
-		precompileAddr common.Address
-		input          func() []byte
-		suppliedGas    uint64
-		readOnly       bool
-
-		expectedRes []byte
-		expectedErr string
-
-		assertState func(t *testing.T, state *state.StateDB)
-	}
-
-	adminAddr := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
-	noRoleAddr := common.HexToAddress("0xF60C45c607D0f41687c94C314d300f483661E13a")
-
-	for name, test := range map[string]test{
-		"set admin": {
-			caller:         adminAddr,
-			precompileAddr: precompile.TxAllowListAddress,
-			input: func() []byte {
-				input, err := precompile.PackModifyAllowList(noRoleAddr, precompile.AllowListAdmin)
+				input, err := precompile.PackBatchModifyAllowList([]common.Address{noRoleAddr, otherAddr}, precompile.AllowListEnabled)
 				if err != nil {
 					panic(err)
 				}
 				return input
 			},
-			suppliedGas: precompile.ModifyAllowListGasCost,
+			suppliedGas: 2*precompile.ModifyAllowListGasCost + 4*precompile.DynamicInputGasCostPerWord,
 			readOnly:    false,
-			expectedRes: []byte{},
 			assertState: func(t *testing.T, state *state.StateDB) {
-				res := precompile.GetTxAllowListStatus(state, adminAddr)
-				assert.Equal(t, precompile.AllowListAdmin, res)
+				assert.Equal(t, precompile.AllowListEnabled, precompile.GetContractDeployerAllowListStatus(state, noRoleAddr))
+				assert.Equal(t, precompile.AllowListEnabled, precompile.GetContractDeployerAllowListStatus(state, otherAddr))
 
-				res = precompile.GetTxAllowListStatus(state, noRoleAddr)
-				assert.Equal(t, precompile.AllowListAdmin, res)
+				logs := state.Logs()
+				assert.Len(t, logs, 2)
+				assert.Equal(t, []common.Hash{precompile.RoleSetEventID, common.Hash(precompile.AllowListEnabled), otherAddr.Hash(), adminAddr.Hash()}, logs[1].Topics)
+
+				members := runAllowListRoleMembers(t, state, precompile.ContractDeployerAllowListPrecompile, precompile.AllowListEnabled, 0, 10)
+				assert.Equal(t, []common.Address{noRoleAddr, otherAddr}, members)
 			},
 		},
-		"set allowed": {
+		"batch set none": {
 			caller:         adminAddr,
-			precompileAddr: precompile.TxAllowListAddress,
+			precompileAddr: precompile.ContractDeployerAllowListAddress,
 			input: func() []byte {
-				input, err := precompile.PackModifyAllowList(noRoleAddr, precompile.AllowListEnabled)
+				input, err := precompile.PackBatchModifyAllowList([]common.Address{adminAddr}, precompile.AllowListNoRole)
 				if err != nil {
 					panic(err)
 				}
 				return input
 			},
-			suppliedGas: precompile.ModifyAllowListGasCost,
+			suppliedGas: precompile.ModifyAllowListGasCost + 3*precompile.DynamicInputGasCostPerWord,
 			readOnly:    false,
-			expectedRes: []byte{},
 			assertState: func(t *testing.T, state *state.StateDB) {
-				res := precompile.GetTxAllowListStatus(state, adminAddr)
-				assert.Equal(t, precompile.AllowListAdmin, res)
+				assert.Equal(t, precompile.AllowListNoRole, precompile.GetContractDeployerAllowListStatus(state, adminAddr))
 
-				res = precompile.GetTxAllowListStatus(state, noRoleAddr)
-				assert.Equal(t, precompile.AllowListEnabled, res)
+				members := runAllowListRoleMembers(t, state, precompile.ContractDeployerAllowListPrecompile, precompile.AllowListAdmin, 0, 10)
+				assert.Empty(t, members)
 			},
 		},
-		"set no role": {
-			caller:         adminAddr,
-			precompileAddr: precompile.TxAllowListAddress,
+		"batch set admin from non-admin": {
+			caller:         noRoleAddr,
+			precompileAddr: precompile.ContractDeployerAllowListAddress,
 			input: func() []byte {
-				input, err := precompile.PackModifyAllowList(adminAddr, precompile.AllowListNoRole)
+				input, err := precompile.PackBatchModifyAllowList([]common.Address{noRoleAddr, otherAddr}, precompile.AllowListAdmin)
 				if err != nil {
 					panic(err)
 				}
 				return input
 			},
-			suppliedGas: precompile.ModifyAllowListGasCost,
+			suppliedGas: 2*precompile.ModifyAllowListGasCost + 4*precompile.DynamicInputGasCostPerWord,
 			readOnly:    false,
-			expectedRes: []byte{},
-			assertState: func(t *testing.T, state *state.StateDB) {
-				res := precompile.GetTxAllowListStatus(state, adminAddr)
-				assert.Equal(t, precompile.AllowListNoRole, res)
-			},
+			expectedErr: precompile.ErrCannotModifyAllowList.Error(),
 		},
-		"set no role from non-admin": {
-			caller:         noRoleAddr,
-			precompileAddr: precompile.TxAllowListAddress,
+		"batch set enabled with readOnly enabled": {
+			caller:         adminAddr,
+			precompileAddr: precompile.ContractDeployerAllowListAddress,
 			input: func() []byte {
-				input, err := precompile.PackModifyAllowList(adminAddr, precompile.AllowListNoRole)
+				input, err := precompile.PackBatchModifyAllowList([]common.Address{noRoleAddr}, precompile.AllowListEnabled)
 				if err != nil {
 					panic(err)
 				}
 				return input
 			},
-			suppliedGas: precompile.ModifyAllowListGasCost,
-			readOnly:    false,
-			expectedErr: precompile.ErrCannotModifyAllowList.Error(),
+			suppliedGas: precompile.ModifyAllowListGasCost + 3*precompile.DynamicInputGasCostPerWord,
+			readOnly:    true,
+			expectedErr: vmerrs.ErrWriteProtection.Error(),
 		},
-		"set allowed from non-admin": {
-			caller:         noRoleAddr,
-			precompileAddr: precompile.TxAllowListAddress,
+		"batch set enabled insufficient gas": {
+			caller:         adminAddr,
+			precompileAddr: precompile.ContractDeployerAllowListAddress,
 			input: func() []byte {
-				input, err := precompile.PackModifyAllowList(adminAddr, precompile.AllowListEnabled)
+				input, err := precompile.PackBatchModifyAllowList([]common.Address{noRoleAddr, otherAddr}, precompile.AllowListEnabled)
 				if err != nil {
 					panic(err)
 				}
 				return input
 			},
-			suppliedGas: precompile.ModifyAllowListGasCost,
+			suppliedGas: 2*precompile.ModifyAllowListGasCost + 4*precompile.DynamicInputGasCostPerWord - 1,
 			readOnly:    false,
-			expectedErr: precompile.ErrCannotModifyAllowList.Error(),
+			expectedErr: vmerrs.ErrOutOfGas.Error(),
 		},
-		"set admin from non-admin": {
+		"get role member count": {
 			caller:         noRoleAddr,
-			precompileAddr: precompile.TxAllowListAddress,
+			precompileAddr: precompile.ContractDeployerAllowListAddress,
 			input: func() []byte {
-				input, err := precompile.PackModifyAllowList(adminAddr, precompile.AllowListAdmin)
+				input, err := precompile.PackGetRoleMemberCount(precompile.AllowListAdmin)
 				if err != nil {
 					panic(err)
 				}
 				return input
 			},
-			suppliedGas: precompile.ModifyAllowListGasCost,
-			readOnly:    false,
-			expectedErr: precompile.ErrCannotModifyAllowList.Error(),
+			suppliedGas: precompile.ReadAllowListGasCost,
+			readOnly:    true,
+			expectedRes: common.BigToHash(common.Big1).Bytes(),
 		},
-		"set no role with readOnly enabled": {
-			caller:         adminAddr,
-			precompileAddr: precompile.TxAllowListAddress,
+		"get role members": {
+			caller:         noRoleAddr,
+			precompileAddr: precompile.ContractDeployerAllowListAddress,
 			input: func() []byte {
-				input, err := precompile.PackModifyAllowList(adminAddr, precompile.AllowListNoRole)
+				input, err := precompile.PackGetRoleMembers(precompile.AllowListAdmin, 0, 10)
 				if err != nil {
 					panic(err)
 				}
 				return input
 			},
-			suppliedGas: precompile.ModifyAllowListGasCost,
+			suppliedGas: 2 * precompile.ReadAllowListGasCost,
 			readOnly:    true,
-			expectedErr: vmerrs.ErrWriteProtection.Error(),
+			expectedRes: func() []byte {
+				output, err := precompile.AllowListABI.Methods["getRoleMembers"].Outputs.Pack([]common.Address{adminAddr})
+				if err != nil {
+					panic(err)
+				}
+				return output
+			}(),
 		},
-		"set no role insufficient gas": {
-			caller:         adminAddr,
-			precompileAddr: precompile.TxAllowListAddress,
+		"get role members with invalid role": {
+			caller:         noRoleAddr,
+			precompileAddr: precompile.ContractDeployerAllowListAddress,
 			input: func() []byte {
-				input, err := precompile.PackModifyAllowList(adminAddr, precompile.AllowListNoRole)
+				input, err := precompile.PackGetRoleMembers(precompile.AllowListNoRole, 0, 10)
 				if err != nil {
 					panic(err)
 				}
 				return input
 			},
-			suppliedGas: precompile.ModifyAllowListGasCost - 1,
-			readOnly:    false,
-			expectedErr: vmerrs.ErrOutOfGas.Error(),
+			suppliedGas: precompile.ReadAllowListGasCost,
+			readOnly:    true,
+			expectedErr: precompile.ErrInvalidAllowListRole.Error(),
 		},
 		"read allow list no role": {
 			caller:         noRoleAddr,
-			precompileAddr: precompile.TxAllowListAddress,
+			precompileAddr: precompile.ContractDeployerAllowListAddress,
 			input: func() []byte {
 				return precompile.PackReadAllowList(noRoleAddr)
 			},
@@ -449,13 +376,13 @@ func TestTxAllowListRun(t *testing.T) {
 			readOnly:    false,
 			expectedRes: common.Hash(precompile.AllowListNoRole).Bytes(),
 			assertState: func(t *testing.T, state *state.StateDB) {
-				res := precompile.GetTxAllowListStatus(state, noRoleAddr)
+				res := precompile.GetContractDeployerAllowListStatus(state, noRoleAddr)
 				assert.Equal(t, precompile.AllowListNoRole, res)
 			},
 		},
 		"read allow list admin role": {
 			caller:         adminAddr,
-			precompileAddr: precompile.TxAllowListAddress,
+			precompileAddr: precompile.ContractDeployerAllowListAddress,
 			input: func() []byte {
 				return precompile.PackReadAllowList(noRoleAddr)
 			},
@@ -463,13 +390,13 @@ func TestTxAllowListRun(t *testing.T) {
 			readOnly:    false,
 			expectedRes: common.Hash(precompile.AllowListNoRole).Bytes(),
 			assertState: func(t *testing.T, state *state.StateDB) {
-				res := precompile.GetTxAllowListStatus(state, adminAddr)
+				res := precompile.GetContractDeployerAllowListStatus(state, adminAddr)
 				assert.Equal(t, precompile.AllowListAdmin, res)
 			},
 		},
 		"read allow list with readOnly enabled": {
 			caller:         adminAddr,
-			precompileAddr: precompile.TxAllowListAddress,
+			precompileAddr: precompile.ContractDeployerAllowListAddress,
 			input: func() []byte {
 				return precompile.PackReadAllowList(noRoleAddr)
 			},
@@ -477,13 +404,13 @@ func TestTxAllowListRun(t *testing.T) {
 			readOnly:    true,
 			expectedRes: common.Hash(precompile.AllowListNoRole).Bytes(),
 			assertState: func(t *testing.T, state *state.StateDB) {
-				res := precompile.GetTxAllowListStatus(state, adminAddr)
+				res := precompile.GetContractDeployerAllowListStatus(state, adminAddr)
 				assert.Equal(t, precompile.AllowListAdmin, res)
 			},
 		},
 		"read allow list out of gas": {
 			caller:         adminAddr,
-			precompileAddr: precompile.TxAllowListAddress,
+			precompileAddr: precompile.ContractDeployerAllowListAddress,
 			input: func() []byte {
 				return precompile.PackReadAllowList(noRoleAddr)
 			},
@@ -500,9 +427,11 @@ func TestTxAllowListRun(t *testing.T) {
 			}
 
 			// Set up the state so that each address has the expected permissions at the start.
-			precompile.SetTxAllowListStatus(state, adminAddr, precompile.AllowListAdmin)
+			precompile.ActivateAllowListIndex(state, precompile.ContractDeployerAllowListAddress, []precompile.StatefulPrecompileConfig{precompile.NewContractDeployerAllowListConfig(common.Big0, nil)}, nil, common.Big0)
+			precompile.SetContractDeployerAllowListStatus(state, adminAddr, precompile.AllowListAdmin)
+			precompile.SetContractDeployerAllowListStatus(state, noRoleAddr, precompile.AllowListNoRole)
 			blockContext := &mockBlockContext{blockNumber: common.Big0}
-			ret, remainingGas, err := precompile.TxAllowListPrecompile.Run(&mockAccessibleState{state: state, blockContext: blockContext}, test.caller, test.precompileAddr, test.input(), test.suppliedGas, test.readOnly)
+			ret, remainingGas, err := precompile.ContractDeployerAllowListPrecompile.Run(&mockAccessibleState{state: state, blockContext: blockContext}, test.caller, test.precompileAddr, test.input(), test.suppliedGas, test.readOnly)
 			if len(test.expectedErr) != 0 {
 				if err == nil {
 					assert.Failf(t, "run expectedly passed without error", "expected error %q", test.expectedErr)
@@ -519,195 +448,1848 @@ func TestTxAllowListRun(t *testing.T) {
 			assert.Equal(t, uint64(0), remainingGas)
 			assert.Equal(t, test.expectedRes, ret)
 
-			test.assertState(t, state)
+			if test.assertState != nil {
+				test.assertState(t, state)
+			}
 		})
 	}
 }
 
-func TestContractNativeMinterRun(t *testing.T) {
+func TestAllowListManagerRole(t *testing.T) {
 	type test struct {
-		caller         common.Address
-		precompileAddr common.Address
-		input          func() []byte
-		suppliedGas    uint64
-		readOnly       bool
-
-		expectedRes []byte
+		caller      common.Address
+		input       func() []byte
+		chainConfig precompile.ChainConfig
 		expectedErr string
 
 		assertState func(t *testing.T, state *state.StateDB)
 	}
 
 	adminAddr := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
-	allowAddr := common.HexToAddress("0xAb5801a7D398351b8bE11C439e05C5B3259aeC9B")
-	noRoleAddr := common.HexToAddress("0xF60C45c607D0f41687c94C314d300f483661E13a")
+	managerAddr := common.HexToAddress("0x0Fa8EA536Be85F32724D57A37758761B86416123")
+	enabledAddr := common.HexToAddress("0xF60C45c607D0f41687c94C314d300f483661E13a")
+	noRoleAddr := common.HexToAddress("0x1D2FaA8A09E5CFaAbb9f1dd30a33cf0e2227D3C7")
+	packModifyAllowList := func(address common.Address, role precompile.AllowListRole) func() []byte {
+		return func() []byte {
+			input, err := precompile.PackModifyAllowList(address, role)
+			if err != nil {
+				panic(err)
+			}
+			return input
+		}
+	}
 
 	for name, test := range map[string]test{
-		"mint funds from no role fails": {
-			caller:         noRoleAddr,
-			precompileAddr: precompile.ContractNativeMinterAddress,
+		"admin sets manager": {
+			caller: adminAddr,
+			input:  packModifyAllowList(noRoleAddr, precompile.AllowListManager),
+			assertState: func(t *testing.T, state *state.StateDB) {
+				assert.Equal(t, precompile.AllowListManager, precompile.GetTxAllowListStatus(state, noRoleAddr))
+			},
+		},
+		"admin sets manager before activation": {
+			caller:      adminAddr,
+			input:       packModifyAllowList(noRoleAddr, precompile.AllowListManager),
+			chainConfig: params.TestPreSubnetEVMConfig,
+			expectedErr: precompile.ErrManagerRoleNotActivated.Error(),
+		},
+		"manager sets enabled": {
+			caller: managerAddr,
+			input:  packModifyAllowList(noRoleAddr, precompile.AllowListEnabled),
+			assertState: func(t *testing.T, state *state.StateDB) {
+				assert.Equal(t, precompile.AllowListEnabled, precompile.GetTxAllowListStatus(state, noRoleAddr))
+			},
+		},
+		"manager sets none": {
+			caller: managerAddr,
+			input:  packModifyAllowList(enabledAddr, precompile.AllowListNoRole),
+			assertState: func(t *testing.T, state *state.StateDB) {
+				assert.Equal(t, precompile.AllowListNoRole, precompile.GetTxAllowListStatus(state, enabledAddr))
+			},
+		},
+		"manager cannot set admin": {
+			caller:      managerAddr,
+			input:       packModifyAllowList(enabledAddr, precompile.AllowListAdmin),
+			expectedErr: precompile.ErrCannotModifyAllowList.Error(),
+		},
+		"manager cannot set manager": {
+			caller:      managerAddr,
+			input:       packModifyAllowList(enabledAddr, precompile.AllowListManager),
+			expectedErr: precompile.ErrCannotModifyAllowList.Error(),
+		},
+		"manager cannot remove admin": {
+			caller:      managerAddr,
+			input:       packModifyAllowList(adminAddr, precompile.AllowListNoRole),
+			expectedErr: precompile.ErrCannotModifyAllowList.Error(),
+		},
+		"manager cannot remove itself": {
+			caller:      managerAddr,
+			input:       packModifyAllowList(managerAddr, precompile.AllowListNoRole),
+			expectedErr: precompile.ErrCannotModifyAllowList.Error(),
+		},
+		"manager cannot batch remove admin": {
+			caller: managerAddr,
 			input: func() []byte {
-				input, err := precompile.PackMintInput(noRoleAddr, common.Big1)
+				input, err := precompile.PackBatchModifyAllowList([]common.Address{enabledAddr, adminAddr}, precompile.AllowListNoRole)
 				if err != nil {
 					panic(err)
 				}
 				return input
 			},
-			suppliedGas: precompile.MintGasCost,
-			readOnly:    false,
-			expectedErr: precompile.ErrCannotMint.Error(),
+			expectedErr: precompile.ErrCannotModifyAllowList.Error(),
+		},
+		"enabled cannot set enabled": {
+			caller:      enabledAddr,
+			input:       packModifyAllowList(noRoleAddr, precompile.AllowListEnabled),
+			expectedErr: precompile.ErrCannotModifyAllowList.Error(),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			db := rawdb.NewMemoryDatabase()
+			state, err := state.New(common.Hash{}, state.NewDatabase(db), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Set up the state so that each address has the expected permissions at the start.
+			precompile.SetTxAllowListStatus(state, adminAddr, precompile.AllowListAdmin)
+			precompile.SetTxAllowListStatus(state, managerAddr, precompile.AllowListManager)
+			precompile.SetTxAllowListStatus(state, enabledAddr, precompile.AllowListEnabled)
+			accessibleState := &mockAccessibleState{state: state, blockContext: &mockBlockContext{blockNumber: common.Big0}, chainConfig: test.chainConfig}
+			_, _, err = precompile.TxAllowListPrecompile.Run(accessibleState, test.caller, precompile.TxAllowListAddress, test.input(), math.MaxUint64, false)
+			if len(test.expectedErr) != 0 {
+				assert.ErrorContains(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			test.assertState(t, state)
+		})
+	}
+}
+
+func TestAllowListIndexIncomplete(t *testing.T) {
+	adminAddr := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+
+	db := rawdb.NewMemoryDatabase()
+	state, err := state.New(common.Hash{}, state.NewDatabase(db), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The precompile is enabled before the block that activates the index, so roles granted on-chain
+	// in the meantime cannot be enumerated.
+	configs := []precompile.StatefulPrecompileConfig{precompile.NewContractDeployerAllowListConfig(common.Big0, []common.Address{adminAddr})}
+	precompile.SetContractDeployerAllowListStatus(state, adminAddr, precompile.AllowListAdmin)
+	precompile.ActivateAllowListIndex(state, precompile.ContractDeployerAllowListAddress, configs, common.Big1, common.Big2)
+
+	blockContext := &mockBlockContext{blockNumber: common.Big0}
+	for name, input := range map[string]func() ([]byte, error){
+		"get role member count": func() ([]byte, error) { return precompile.PackGetRoleMemberCount(precompile.AllowListAdmin) },
+		"get role members":      func() ([]byte, error) { return precompile.PackGetRoleMembers(precompile.AllowListAdmin, 0, 10) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			input, err := input()
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, remainingGas, err := precompile.ContractDeployerAllowListPrecompile.Run(&mockAccessibleState{state: state, blockContext: blockContext}, adminAddr, precompile.ContractDeployerAllowListAddress, input, precompile.ReadAllowListGasCost, true)
+			assert.ErrorIs(t, err, precompile.ErrAllowListIndexIncomplete)
+			assert.Zero(t, remainingGas)
+		})
+	}
+}
+
+// runAllowListRoleMembers returns up to [limit] members of [role] starting from [offset] by calling
+// getRoleMembers on the allow list [precompile].
+func runAllowListRoleMembers(t *testing.T, state *state.StateDB, allowList precompile.StatefulPrecompiledContract, role precompile.AllowListRole, offset uint64, limit uint64) []common.Address {
+	input, err := precompile.PackGetRoleMembers(role, offset, limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blockContext := &mockBlockContext{blockNumber: common.Big0}
+	ret, _, err := allowList.Run(&mockAccessibleState{state: state, blockContext: blockContext}, common.Address{}, common.Address{}, input, math.MaxUint64, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	members, err := precompile.UnpackGetRoleMembersOutput(ret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return members
+}
+
+func TestPrecompileGasOverrides(t *testing.T) {
+	adminAddr := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	enabledAddr := common.HexToAddress("0xF60C45c607D0f41687c94C314d300f483661E13a")
+
+	chainConfig := *params.TestChainConfig
+	chainConfig.GenesisPrecompiles = params.Precompiles{
+		precompile.ContractDeployerAllowListConfigKey: &precompile.ContractDeployerAllowListConfig{
+			UpgradeableConfig: precompile.UpgradeableConfig{
+				BlockTimestamp: common.Big0,
+				GasOverrides:   map[string]uint64{"setEnabled": 1_000},
+			},
+		},
+	}
+	chainConfig.PrecompileUpgrades = []params.PrecompileUpgrade{
+		{StatefulPrecompileConfig: precompile.NewDisableContractDeployerAllowListConfig(big.NewInt(10))},
+		{StatefulPrecompileConfig: &precompile.ContractDeployerAllowListConfig{
+			UpgradeableConfig: precompile.UpgradeableConfig{
+				BlockTimestamp: big.NewInt(20),
+				GasOverrides:   map[string]uint64{"setEnabled": 2_000, "readAllowList": 10, "batchSetEnabled": math.MaxUint64/2 + 1},
+			},
+		}},
+	}
+
+	setEnabledInput, err := precompile.PackModifyAllowList(enabledAddr, precompile.AllowListEnabled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readAllowListInput := precompile.PackReadAllowList(enabledAddr)
+	batchSetEnabledInput, err := precompile.PackBatchModifyAllowList([]common.Address{enabledAddr, adminAddr}, precompile.AllowListEnabled)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, test := range map[string]struct {
+		timestamp   uint64
+		input       []byte
+		suppliedGas uint64
+		expectedErr string
+	}{
+		"overridden gas cost": {
+			timestamp:   0,
+			input:       setEnabledInput,
+			suppliedGas: 1_000,
+		},
+		"default gas cost": {
+			timestamp:   0,
+			input:       readAllowListInput,
+			suppliedGas: precompile.ReadAllowListGasCost,
+		},
+		"gas cost overridden by upgrade": {
+			timestamp:   20,
+			input:       setEnabledInput,
+			suppliedGas: 2_000,
+		},
+		"default gas cost overridden by upgrade": {
+			timestamp:   20,
+			input:       readAllowListInput,
+			suppliedGas: 10,
+		},
+		"insufficient gas for overridden gas cost": {
+			timestamp:   20,
+			input:       setEnabledInput,
+			suppliedGas: 1_999,
+			expectedErr: vmerrs.ErrOutOfGas.Error(),
+		},
+		"overflowing total of overridden gas costs": {
+			timestamp:   20,
+			input:       batchSetEnabledInput,
+			suppliedGas: math.MaxUint64,
+			expectedErr: vmerrs.ErrOutOfGas.Error(),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			db := rawdb.NewMemoryDatabase()
+			state, err := state.New(common.Hash{}, state.NewDatabase(db), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			precompile.SetContractDeployerAllowListStatus(state, adminAddr, precompile.AllowListAdmin)
+			blockContext := &mockBlockContext{blockNumber: common.Big0, timestamp: test.timestamp}
+			_, remainingGas, err := precompile.ContractDeployerAllowListPrecompile.Run(&mockAccessibleState{state: state, blockContext: blockContext, chainConfig: &chainConfig}, adminAddr, precompile.ContractDeployerAllowListAddress, test.input, test.suppliedGas, false)
+			if len(test.expectedErr) != 0 {
+				if err == nil {
+					assert.Failf(t, "run expectedly passed without error", "expected error %q", test.expectedErr)
+				} else {
+					assert.True(t, strings.Contains(err.Error(), test.expectedErr), "expected error (%s) to contain substring (%s)", err, test.expectedErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, uint64(0), remainingGas)
+		})
+	}
+}
+
+func TestPrecompileV2Upgrade(t *testing.T) {
+	adminAddr := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	enabledAddr := common.HexToAddress("0xF60C45c607D0f41687c94C314d300f483661E13a")
+
+	chainConfig := *params.TestChainConfig
+	chainConfig.PrecompileV2Timestamp = big.NewInt(10)
+
+	setEnabledInput, err := precompile.PackModifyAllowList(enabledAddr, precompile.AllowListEnabled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mintInput, err := precompile.PackMintInput(enabledAddr, common.Big1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mintExceedingMaxSupplyInput, err := precompile.PackMintInput(enabledAddr, common.Big2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	totalMintedInput, err := precompile.PackTotalMinted()
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFeeConfigInput, err := precompile.PackSetFeeConfig(testFeeConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFeeConfigAtInput, err := precompile.PackSetFeeConfigAt(testFeeConfig, new(big.Int).SetUint64(precompile.DefaultMinFeeConfigDelay))
+	if err != nil {
+		t.Fatal(err)
+	}
+	batchSetEnabledInput, err := precompile.PackBatchModifyAllowList([]common.Address{enabledAddr}, precompile.AllowListEnabled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	getRoleMembersInput, err := precompile.PackGetRoleMembers(precompile.AllowListAdmin, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, test := range map[string]struct {
+		precompileAddr common.Address
+		contract       precompile.StatefulPrecompiledContract
+		timestamp      uint64
+		input          []byte
+		suppliedGas    uint64
+		expectedLogs   int
+		expectedErr    string
+	}{
+		"set enabled before upgrade": {
+			precompileAddr: precompile.ContractDeployerAllowListAddress,
+			contract:       precompile.ContractDeployerAllowListPrecompile,
+			input:          setEnabledInput,
+			suppliedGas:    precompile.LegacyModifyAllowListGasCost,
+		},
+		"set enabled after upgrade": {
+			precompileAddr: precompile.ContractDeployerAllowListAddress,
+			contract:       precompile.ContractDeployerAllowListPrecompile,
+			timestamp:      10,
+			input:          setEnabledInput,
+			suppliedGas:    precompile.ModifyAllowListGasCost,
+			expectedLogs:   1,
+		},
+		"set enabled after upgrade with legacy gas cost": {
+			precompileAddr: precompile.ContractDeployerAllowListAddress,
+			contract:       precompile.ContractDeployerAllowListPrecompile,
+			timestamp:      10,
+			input:          setEnabledInput,
+			suppliedGas:    precompile.LegacyModifyAllowListGasCost,
+			expectedErr:    vmerrs.ErrOutOfGas.Error(),
+		},
+		"batch set enabled before upgrade": {
+			precompileAddr: precompile.ContractDeployerAllowListAddress,
+			contract:       precompile.ContractDeployerAllowListPrecompile,
+			input:          batchSetEnabledInput,
+			suppliedGas:    precompile.ModifyAllowListGasCost,
+			expectedErr:    "invalid function selector",
+		},
+		"batch set enabled after upgrade": {
+			precompileAddr: precompile.ContractDeployerAllowListAddress,
+			contract:       precompile.ContractDeployerAllowListPrecompile,
+			timestamp:      10,
+			input:          batchSetEnabledInput,
+			suppliedGas:    precompile.ModifyAllowListGasCost + 3*precompile.DynamicInputGasCostPerWord,
+			expectedLogs:   1,
+		},
+		"get role members before upgrade": {
+			precompileAddr: precompile.ContractDeployerAllowListAddress,
+			contract:       precompile.ContractDeployerAllowListPrecompile,
+			input:          getRoleMembersInput,
+			suppliedGas:    precompile.ReadAllowListGasCost,
+			expectedErr:    "invalid function selector",
+		},
+		"mint before upgrade": {
+			precompileAddr: precompile.ContractNativeMinterAddress,
+			contract:       precompile.ContractNativeMinterPrecompile,
+			input:          mintInput,
+			suppliedGas:    precompile.LegacyMintGasCost,
+		},
+		"mint after upgrade": {
+			precompileAddr: precompile.ContractNativeMinterAddress,
+			contract:       precompile.ContractNativeMinterPrecompile,
+			timestamp:      10,
+			input:          mintInput,
+			suppliedGas:    precompile.MintGasCost,
+			expectedLogs:   1,
+		},
+		"mint exceeding max supply before upgrade": {
+			precompileAddr: precompile.ContractNativeMinterAddress,
+			contract:       precompile.ContractNativeMinterPrecompile,
+			input:          mintExceedingMaxSupplyInput,
+			suppliedGas:    precompile.LegacyMintGasCost,
+		},
+		"mint exceeding max supply after upgrade": {
+			precompileAddr: precompile.ContractNativeMinterAddress,
+			contract:       precompile.ContractNativeMinterPrecompile,
+			timestamp:      10,
+			input:          mintExceedingMaxSupplyInput,
+			suppliedGas:    precompile.MintGasCost,
+			expectedErr:    precompile.ErrMintExceedsMaxSupply.Error(),
+		},
+		"total minted before upgrade": {
+			precompileAddr: precompile.ContractNativeMinterAddress,
+			contract:       precompile.ContractNativeMinterPrecompile,
+			input:          totalMintedInput,
+			suppliedGas:    precompile.TotalMintedGasCost,
+			expectedErr:    "invalid function selector",
+		},
+		"set fee config before upgrade": {
+			precompileAddr: precompile.FeeConfigManagerAddress,
+			contract:       precompile.FeeConfigManagerPrecompile,
+			input:          setFeeConfigInput,
+			suppliedGas:    precompile.LegacySetFeeConfigGasCost,
+		},
+		"set fee config after upgrade": {
+			precompileAddr: precompile.FeeConfigManagerAddress,
+			contract:       precompile.FeeConfigManagerPrecompile,
+			timestamp:      10,
+			input:          setFeeConfigInput,
+			suppliedGas:    precompile.SetFeeConfigGasCost,
+			expectedLogs:   1,
+		},
+		"schedule fee config before upgrade": {
+			precompileAddr: precompile.FeeConfigManagerAddress,
+			contract:       precompile.FeeConfigManagerPrecompile,
+			input:          setFeeConfigAtInput,
+			suppliedGas:    precompile.SetFeeConfigAtGasCost,
+			expectedErr:    "invalid function selector",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			db := rawdb.NewMemoryDatabase()
+			state, err := state.New(common.Hash{}, state.NewDatabase(db), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			precompile.SetContractDeployerAllowListStatus(state, adminAddr, precompile.AllowListAdmin)
+			precompile.SetContractNativeMinterStatus(state, adminAddr, precompile.AllowListAdmin)
+			precompile.SetFeeConfigManagerStatus(state, adminAddr, precompile.AllowListAdmin)
+			blockContext := &mockBlockContext{blockNumber: common.Big0, timestamp: test.timestamp}
+			// The max supply is only enforced after the upgrade
+			(&precompile.ContractNativeMinterConfig{MaxSupply: math.NewHexOrDecimal256(1)}).Configure(&chainConfig, state, blockContext)
+			_, remainingGas, err := test.contract.Run(&mockAccessibleState{state: state, blockContext: blockContext, chainConfig: &chainConfig}, adminAddr, test.precompileAddr, test.input, test.suppliedGas, false)
+			if len(test.expectedErr) != 0 {
+				if err == nil {
+					assert.Failf(t, "run expectedly passed without error", "expected error %q", test.expectedErr)
+				} else {
+					assert.True(t, strings.Contains(err.Error(), test.expectedErr), "expected error (%s) to contain substring (%s)", err, test.expectedErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, uint64(0), remainingGas)
+			assert.Len(t, state.Logs(), test.expectedLogs)
+		})
+	}
+}
+
+func TestReconfigurePrecompiles(t *testing.T) {
+	adminAddr := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	newAdminAddr := common.HexToAddress("0xF60C45c607D0f41687c94C314d300f483661E13a")
+	enabledAddr := common.HexToAddress("0x0Fa8EA536Be85F32724D57A37758761B86416123")
+
+	reconfiguredTxAllowList := precompile.NewTxAllowListConfig(big.NewInt(10), []common.Address{newAdminAddr})
+	reconfiguredTxAllowList.Reconfiguration = true
+	reconfiguredFeeManager := precompile.NewFeeManagerConfig(big.NewInt(10), []common.Address{newAdminAddr})
+	reconfiguredFeeManager.Reconfiguration = true
+
+	chainConfig := *params.TestChainConfig
+	chainConfig.GenesisPrecompiles = params.Precompiles{
+		precompile.TxAllowListConfigKey:      precompile.NewTxAllowListConfig(common.Big0, []common.Address{adminAddr}),
+		precompile.FeeConfigManagerConfigKey: precompile.NewFeeManagerConfig(common.Big0, []common.Address{adminAddr}),
+	}
+	chainConfig.PrecompileUpgrades = []params.PrecompileUpgrade{
+		{StatefulPrecompileConfig: reconfiguredTxAllowList},
+		{StatefulPrecompileConfig: reconfiguredFeeManager},
+	}
+	if err := chainConfig.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	db := rawdb.NewMemoryDatabase()
+	state, err := state.New(common.Hash{}, state.NewDatabase(db), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chainConfig.CheckConfigurePrecompiles(nil, &mockBlockContext{blockNumber: common.Big0, timestamp: 0}, state)
+
+	// modify the state of the precompiles after they are enabled
+	precompile.SetTxAllowListStatus(state, enabledAddr, precompile.AllowListEnabled)
+	if err := precompile.StoreFeeConfig(state, testFeeConfig, &mockBlockContext{blockNumber: big.NewInt(5)}); err != nil {
+		t.Fatal(err)
+	}
+
+	chainConfig.CheckConfigurePrecompiles(big.NewInt(5), &mockBlockContext{blockNumber: big.NewInt(10), timestamp: 10}, state)
+
+	// the reconfiguration adds the new admins on top of the existing state
+	assert.Equal(t, precompile.AllowListAdmin, precompile.GetTxAllowListStatus(state, adminAddr))
+	assert.Equal(t, precompile.AllowListAdmin, precompile.GetTxAllowListStatus(state, newAdminAddr))
+	assert.Equal(t, precompile.AllowListEnabled, precompile.GetTxAllowListStatus(state, enabledAddr))
+	assert.Equal(t, precompile.AllowListAdmin, precompile.GetFeeConfigManagerStatus(state, newAdminAddr))
+	// the fee config set through the precompile is kept
+	assert.Equal(t, testFeeConfig, precompile.GetStoredFeeConfig(state))
+	assert.Equal(t, big.NewInt(5), precompile.GetFeeConfigLastChangedAt(state))
+}
+
+func TestPrecompileStorageModes(t *testing.T) {
+	adminAddr := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	enabledAddr := common.HexToAddress("0x0Fa8EA536Be85F32724D57A37758761B86416123")
+
+	preserveDisable := precompile.NewDisableTxAllowListConfig(big.NewInt(5))
+	preserveDisable.StorageMode = precompile.StorageModePreserve
+	secondPreserveDisable := precompile.NewDisableTxAllowListConfig(big.NewInt(7))
+	secondPreserveDisable.StorageMode = precompile.StorageModePreserve
+	wipeEnable := precompile.NewTxAllowListConfig(big.NewInt(8), []common.Address{adminAddr})
+	wipeEnable.StorageMode = precompile.StorageModeWipe
+
+	chainConfig := *params.TestChainConfig
+	chainConfig.GenesisPrecompiles = params.Precompiles{
+		precompile.TxAllowListConfigKey: precompile.NewTxAllowListConfig(common.Big0, []common.Address{adminAddr}),
+	}
+	chainConfig.PrecompileUpgrades = []params.PrecompileUpgrade{
+		{StatefulPrecompileConfig: preserveDisable},
+		{StatefulPrecompileConfig: precompile.NewTxAllowListConfig(big.NewInt(6), []common.Address{adminAddr})},
+		{StatefulPrecompileConfig: secondPreserveDisable},
+		{StatefulPrecompileConfig: wipeEnable},
+		{StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(9))},
+	}
+	if err := chainConfig.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	db := rawdb.NewMemoryDatabase()
+	state, err := state.New(common.Hash{}, state.NewDatabase(db), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// applyUpgrades applies the upgrades activating in the block at [timestamp] and finalises the state as is
+	// done at the end of a block.
+	applyUpgrades := func(parentTimestamp *big.Int, timestamp uint64) {
+		chainConfig.CheckConfigurePrecompiles(parentTimestamp, &mockBlockContext{blockNumber: new(big.Int).SetUint64(timestamp), timestamp: timestamp}, state)
+		state.Finalise(true)
+	}
+	applyUpgrades(nil, 0)
+	precompile.SetTxAllowListStatus(state, enabledAddr, precompile.AllowListEnabled)
+
+	// disabling the precompile with the preserve storage mode keeps its storage but removes its code
+	applyUpgrades(big.NewInt(4), 5)
+	assert.True(t, state.Exist(precompile.TxAllowListAddress))
+	assert.Empty(t, state.GetCode(precompile.TxAllowListAddress))
+	assert.Equal(t, precompile.AllowListEnabled, precompile.GetTxAllowListStatus(state, enabledAddr))
+
+	// re-enabling the precompile with the default storage mode keeps the preserved storage
+	applyUpgrades(big.NewInt(5), 6)
+	assert.Equal(t, precompile.AllowListEnabled, precompile.GetTxAllowListStatus(state, enabledAddr))
+	assert.NotEmpty(t, state.GetCode(precompile.TxAllowListAddress))
+
+	// re-enabling the precompile with the wipe storage mode discards the preserved storage before configuring it
+	applyUpgrades(big.NewInt(6), 7)
+	applyUpgrades(big.NewInt(7), 8)
+	assert.Equal(t, precompile.AllowListNoRole, precompile.GetTxAllowListStatus(state, enabledAddr))
+	assert.Equal(t, precompile.AllowListAdmin, precompile.GetTxAllowListStatus(state, adminAddr))
+	assert.Equal(t, uint64(1), state.GetNonce(precompile.TxAllowListAddress))
+
+	// disabling the precompile with the default storage mode deletes its account
+	applyUpgrades(big.NewInt(8), 9)
+	assert.False(t, state.Exist(precompile.TxAllowListAddress))
+	assert.Equal(t, precompile.AllowListNoRole, precompile.GetTxAllowListStatus(state, adminAddr))
+}
+
+func TestTxAllowListRun(t *testing.T) {
+	type test struct {
+		caller         common.Address
+		precompileAddr common.Address
+		input          func() []byte
+		suppliedGas    uint64
+		readOnly       bool
+
+		expectedRes []byte
+		expectedErr string
+
+		assertState func(t *testing.T, state *state.StateDB)
+	}
+
+	adminAddr := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	noRoleAddr := common.HexToAddress("0xF60C45c607D0f41687c94C314d300f483661E13a")
+
+	for name, test := range map[string]test{
+		"set admin": {
+			caller:         adminAddr,
+			precompileAddr: precompile.TxAllowListAddress,
+			input: func() []byte {
+				input, err := precompile.PackModifyAllowList(noRoleAddr, precompile.AllowListAdmin)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.ModifyAllowListGasCost,
+			readOnly:    false,
+			expectedRes: []byte{},
+			assertState: func(t *testing.T, state *state.StateDB) {
+				res := precompile.GetTxAllowListStatus(state, adminAddr)
+				assert.Equal(t, precompile.AllowListAdmin, res)
+
+				res = precompile.GetTxAllowListStatus(state, noRoleAddr)
+				assert.Equal(t, precompile.AllowListAdmin, res)
+			},
+		},
+		"set allowed": {
+			caller:         adminAddr,
+			precompileAddr: precompile.TxAllowListAddress,
+			input: func() []byte {
+				input, err := precompile.PackModifyAllowList(noRoleAddr, precompile.AllowListEnabled)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.ModifyAllowListGasCost,
+			readOnly:    false,
+			expectedRes: []byte{},
+			assertState: func(t *testing.T, state *state.StateDB) {
+				res := precompile.GetTxAllowListStatus(state, adminAddr)
+				assert.Equal(t, precompile.AllowListAdmin, res)
+
+				res = precompile.GetTxAllowListStatus(state, noRoleAddr)
+				assert.Equal(t, precompile.AllowListEnabled, res)
+			},
+		},
+		"set no role": {
+			caller:         adminAddr,
+			precompileAddr: precompile.TxAllowListAddress,
+			input: func() []byte {
+				input, err := precompile.PackModifyAllowList(adminAddr, precompile.AllowListNoRole)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.ModifyAllowListGasCost,
+			readOnly:    false,
+			expectedRes: []byte{},
+			assertState: func(t *testing.T, state *state.StateDB) {
+				res := precompile.GetTxAllowListStatus(state, adminAddr)
+				assert.Equal(t, precompile.AllowListNoRole, res)
+			},
+		},
+		"set no role from non-admin": {
+			caller:         noRoleAddr,
+			precompileAddr: precompile.TxAllowListAddress,
+			input: func() []byte {
+				input, err := precompile.PackModifyAllowList(adminAddr, precompile.AllowListNoRole)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.ModifyAllowListGasCost,
+			readOnly:    false,
+			expectedErr: precompile.ErrCannotModifyAllowList.Error(),
+		},
+		"set allowed from non-admin": {
+			caller:         noRoleAddr,
+			precompileAddr: precompile.TxAllowListAddress,
+			input: func() []byte {
+				input, err := precompile.PackModifyAllowList(adminAddr, precompile.AllowListEnabled)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.ModifyAllowListGasCost,
+			readOnly:    false,
+			expectedErr: precompile.ErrCannotModifyAllowList.Error(),
+		},
+		"set admin from non-admin": {
+			caller:         noRoleAddr,
+			precompileAddr: precompile.TxAllowListAddress,
+			input: func() []byte {
+				input, err := precompile.PackModifyAllowList(adminAddr, precompile.AllowListAdmin)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.ModifyAllowListGasCost,
+			readOnly:    false,
+			expectedErr: precompile.ErrCannotModifyAllowList.Error(),
+		},
+		"set no role with readOnly enabled": {
+			caller:         adminAddr,
+			precompileAddr: precompile.TxAllowListAddress,
+			input: func() []byte {
+				input, err := precompile.PackModifyAllowList(adminAddr, precompile.AllowListNoRole)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.ModifyAllowListGasCost,
+			readOnly:    true,
+			expectedErr: vmerrs.ErrWriteProtection.Error(),
+		},
+		"set no role insufficient gas": {
+			caller:         adminAddr,
+			precompileAddr: precompile.TxAllowListAddress,
+			input: func() []byte {
+				input, err := precompile.PackModifyAllowList(adminAddr, precompile.AllowListNoRole)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.ModifyAllowListGasCost - 1,
+			readOnly:    false,
+			expectedErr: vmerrs.ErrOutOfGas.Error(),
+		},
+		"read allow list no role": {
+			caller:         noRoleAddr,
+			precompileAddr: precompile.TxAllowListAddress,
+			input: func() []byte {
+				return precompile.PackReadAllowList(noRoleAddr)
+			},
+			suppliedGas: precompile.ReadAllowListGasCost,
+			readOnly:    false,
+			expectedRes: common.Hash(precompile.AllowListNoRole).Bytes(),
+			assertState: func(t *testing.T, state *state.StateDB) {
+				res := precompile.GetTxAllowListStatus(state, noRoleAddr)
+				assert.Equal(t, precompile.AllowListNoRole, res)
+			},
+		},
+		"read allow list admin role": {
+			caller:         adminAddr,
+			precompileAddr: precompile.TxAllowListAddress,
+			input: func() []byte {
+				return precompile.PackReadAllowList(noRoleAddr)
+			},
+			suppliedGas: precompile.ReadAllowListGasCost,
+			readOnly:    false,
+			expectedRes: common.Hash(precompile.AllowListNoRole).Bytes(),
+			assertState: func(t *testing.T, state *state.StateDB) {
+				res := precompile.GetTxAllowListStatus(state, adminAddr)
+				assert.Equal(t, precompile.AllowListAdmin, res)
+			},
+		},
+		"read allow list with readOnly enabled": {
+			caller:         adminAddr,
+			precompileAddr: precompile.TxAllowListAddress,
+			input: func() []byte {
+				return precompile.PackReadAllowList(noRoleAddr)
+			},
+			suppliedGas: precompile.ReadAllowListGasCost,
+			readOnly:    true,
+			expectedRes: common.Hash(precompile.AllowListNoRole).Bytes(),
+			assertState: func(t *testing.T, state *state.StateDB) {
+				res := precompile.GetTxAllowListStatus(state, adminAddr)
+				assert.Equal(t, precompile.AllowListAdmin, res)
+			},
+		},
+		"read allow list out of gas": {
+			caller:         adminAddr,
+			precompileAddr: precompile.TxAllowListAddress,
+			input: func() []byte {
+				return precompile.PackReadAllowList(noRoleAddr)
+			},
+			suppliedGas: precompile.ReadAllowListGasCost - 1,
+			readOnly:    true,
+			expectedErr: vmerrs.ErrOutOfGas.Error(),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			db := rawdb.NewMemoryDatabase()
+			state, err := state.New(common.Hash{}, state.NewDatabase(db), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Set up the state so that each address has the expected permissions at the start.
+			precompile.SetTxAllowListStatus(state, adminAddr, precompile.AllowListAdmin)
+			blockContext := &mockBlockContext{blockNumber: common.Big0}
+			ret, remainingGas, err := precompile.TxAllowListPrecompile.Run(&mockAccessibleState{state: state, blockContext: blockContext}, test.caller, test.precompileAddr, test.input(), test.suppliedGas, test.readOnly)
+			if len(test.expectedErr) != 0 {
+				if err == nil {
+					assert.Failf(t, "run expectedly passed without error", "expected error %q", test.expectedErr)
+				} else {
+					assert.True(t, strings.Contains(err.Error(), test.expectedErr), "expected error (%s) to contain substring (%s)", err, test.expectedErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, uint64(0), remainingGas)
+			assert.Equal(t, test.expectedRes, ret)
+
+			test.assertState(t, state)
+		})
+	}
+}
+
+func TestMultisigProposals(t *testing.T) {
+	approverA := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	approverB := common.HexToAddress("0xAb5801a7D398351b8bE11C439e05C5B3259aeC9B")
+	recipient := common.HexToAddress("0xF60C45c607D0f41687c94C314d300f483661E13a")
+
+	db := rawdb.NewMemoryDatabase()
+	state, err := state.New(common.Hash{}, state.NewDatabase(db), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &precompile.AllowListConfig{
+		AllowListAdmins: []common.Address{approverA},
+		Multisig:        &precompile.MultisigConfig{Approvers: []common.Address{approverA, approverB}, Threshold: 2},
+	}
+	config.Configure(state, precompile.ContractNativeMinterAddress)
+
+	run := func(caller common.Address, input []byte) ([]byte, error) {
+		accessibleState := &mockAccessibleState{state: state, blockContext: &mockBlockContext{blockNumber: testBlockNumber}}
+		ret, _, err := precompile.ContractNativeMinterPrecompile.Run(accessibleState, caller, precompile.ContractNativeMinterAddress, input, 1_000_000, false)
+		return ret, err
+	}
+	mustPack := func(input []byte, err error) []byte {
+		if err != nil {
+			t.Fatal(err)
+		}
+		return input
+	}
+	mintAction := mustPack(precompile.PackMintInput(recipient, big.NewInt(10)))
+	proposalID := big.NewInt(1)
+
+	// admins cannot bypass the multisig by minting directly
+	_, err = run(approverA, mintAction)
+	assert.ErrorIs(t, err, precompile.ErrMultisigApprovalRequired)
+
+	// only approvers can propose
+	_, err = run(recipient, mustPack(precompile.PackPropose(mintAction)))
+	assert.ErrorIs(t, err, precompile.ErrNotApprover)
+
+	ret, err := run(approverA, mustPack(precompile.PackPropose(mintAction)))
+	assert.NoError(t, err)
+	assert.Equal(t, common.BigToHash(proposalID).Bytes(), ret)
+
+	// the proposer approves the proposal, which needs another approval to be executed
+	_, err = run(approverA, mustPack(precompile.PackApprove(proposalID)))
+	assert.ErrorIs(t, err, precompile.ErrProposalAlreadyApproved)
+	_, err = run(approverA, mustPack(precompile.PackExecute(proposalID)))
+	assert.ErrorIs(t, err, precompile.ErrProposalThresholdNotMet)
+
+	_, err = run(approverB, mustPack(precompile.PackApprove(proposalID)))
+	assert.NoError(t, err)
+	proposal, ok := precompile.GetProposal(state, precompile.ContractNativeMinterAddress, proposalID)
+	assert.True(t, ok)
+	assert.Equal(t, precompile.Proposal{Action: mintAction, Approvals: 2}, proposal)
+
+	// the approved action is executed by the precompile itself
+	_, err = run(approverB, mustPack(precompile.PackExecute(proposalID)))
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(10), state.GetBalance(recipient))
+	_, err = run(approverA, mustPack(precompile.PackExecute(proposalID)))
+	assert.ErrorIs(t, err, precompile.ErrProposalAlreadyExecuted)
+
+	logs := state.Logs()
+	assert.Equal(t, precompile.ProposalExecutedEventID, logs[len(logs)-1].Topics[0])
+	assert.Equal(t, precompile.NativeCoinMintedEventID, logs[len(logs)-2].Topics[0])
+	assert.Equal(t, precompile.ContractNativeMinterAddress.Hash(), logs[len(logs)-2].Topics[1])
+
+	_, err = run(approverA, mustPack(precompile.PackApprove(big.NewInt(2))))
+	assert.ErrorIs(t, err, precompile.ErrProposalNotFound)
+
+	// configuring another multisig replaces the approvers and expires the pending proposals
+	_, err = run(approverA, mustPack(precompile.PackPropose(mintAction)))
+	assert.NoError(t, err)
+	reconfig := &precompile.AllowListConfig{
+		Multisig: &precompile.MultisigConfig{Approvers: []common.Address{approverB}, Threshold: 1},
+	}
+	reconfig.Configure(state, precompile.ContractNativeMinterAddress)
+	assert.False(t, precompile.IsMultisigApprover(state, precompile.ContractNativeMinterAddress, approverA))
+	_, err = run(approverA, mustPack(precompile.PackPropose(mintAction)))
+	assert.ErrorIs(t, err, precompile.ErrNotApprover)
+	_, err = run(approverB, mustPack(precompile.PackExecute(big.NewInt(2))))
+	assert.ErrorIs(t, err, precompile.ErrProposalExpired)
+}
+
+func TestContractNativeMinterRun(t *testing.T) {
+	type test struct {
+		caller         common.Address
+		precompileAddr common.Address
+		preCondition   func(t *testing.T, state *state.StateDB)
+		input          func() []byte
+		suppliedGas    uint64
+		readOnly       bool
+
+		expectedRes []byte
+		expectedErr string
+
+		assertState func(t *testing.T, state *state.StateDB)
+	}
+
+	adminAddr := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	allowAddr := common.HexToAddress("0xAb5801a7D398351b8bE11C439e05C5B3259aeC9B")
+	noRoleAddr := common.HexToAddress("0xF60C45c607D0f41687c94C314d300f483661E13a")
+	configureLimits := func(t *testing.T, state *state.StateDB) {
+		config := &precompile.ContractNativeMinterConfig{
+			MaxSupply:        math.NewHexOrDecimal256(10),
+			MinterAllowances: map[common.Address]*math.HexOrDecimal256{allowAddr: math.NewHexOrDecimal256(3)},
+		}
+		config.Configure(params.TestChainConfig, state, &mockBlockContext{blockNumber: common.Big0})
+	}
+
+	for name, test := range map[string]test{
+		"mint funds from no role fails": {
+			caller:         noRoleAddr,
+			precompileAddr: precompile.ContractNativeMinterAddress,
+			input: func() []byte {
+				input, err := precompile.PackMintInput(noRoleAddr, common.Big1)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.MintGasCost,
+			readOnly:    false,
+			expectedErr: precompile.ErrCannotMint.Error(),
 		},
 		"mint funds from allow address": {
 			caller:         allowAddr,
 			precompileAddr: precompile.ContractNativeMinterAddress,
 			input: func() []byte {
-				input, err := precompile.PackMintInput(allowAddr, common.Big1)
+				input, err := precompile.PackMintInput(allowAddr, common.Big1)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.MintGasCost,
+			readOnly:    false,
+			expectedRes: []byte{},
+			assertState: func(t *testing.T, state *state.StateDB) {
+				res := precompile.GetContractNativeMinterStatus(state, allowAddr)
+				assert.Equal(t, precompile.AllowListEnabled, res)
+
+				assert.Equal(t, common.Big1, state.GetBalance(allowAddr), "expected minted funds")
+
+				logs := state.Logs()
+				assert.Len(t, logs, 1)
+				assert.Equal(t, precompile.ContractNativeMinterAddress, logs[0].Address)
+				assert.Equal(t, []common.Hash{precompile.NativeCoinMintedEventID, allowAddr.Hash(), allowAddr.Hash()}, logs[0].Topics)
+				assert.Equal(t, common.BigToHash(common.Big1).Bytes(), logs[0].Data)
+				assert.Equal(t, testBlockNumber.Uint64(), logs[0].BlockNumber)
+			},
+		},
+		"mint funds from admin address": {
+			caller:         adminAddr,
+			precompileAddr: precompile.ContractNativeMinterAddress,
+			input: func() []byte {
+				input, err := precompile.PackMintInput(adminAddr, common.Big1)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.MintGasCost,
+			readOnly:    false,
+			expectedRes: []byte{},
+			assertState: func(t *testing.T, state *state.StateDB) {
+				res := precompile.GetContractNativeMinterStatus(state, adminAddr)
+				assert.Equal(t, precompile.AllowListAdmin, res)
+
+				assert.Equal(t, common.Big1, state.GetBalance(adminAddr), "expected minted funds")
+			},
+		},
+		"mint max big funds": {
+			caller:         adminAddr,
+			precompileAddr: precompile.ContractNativeMinterAddress,
+			input: func() []byte {
+				input, err := precompile.PackMintInput(adminAddr, math.MaxBig256)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.MintGasCost,
+			readOnly:    false,
+			expectedRes: []byte{},
+			assertState: func(t *testing.T, state *state.StateDB) {
+				res := precompile.GetContractNativeMinterStatus(state, adminAddr)
+				assert.Equal(t, precompile.AllowListAdmin, res)
+
+				assert.Equal(t, math.MaxBig256, state.GetBalance(adminAddr), "expected minted funds")
+			},
+		},
+		"mint within allowance": {
+			caller:         allowAddr,
+			precompileAddr: precompile.ContractNativeMinterAddress,
+			preCondition:   configureLimits,
+			input: func() []byte {
+				input, err := precompile.PackMintInput(allowAddr, big.NewInt(2))
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.MintGasCost,
+			readOnly:    false,
+			expectedRes: []byte{},
+			assertState: func(t *testing.T, state *state.StateDB) {
+				assert.Equal(t, big.NewInt(2), state.GetBalance(allowAddr), "expected minted funds")
+				assert.Equal(t, big.NewInt(2), precompile.GetNativeMinterTotalMinted(state))
+				assert.Equal(t, big.NewInt(1), precompile.GetNativeMinterRemainingAllowance(state, allowAddr))
+				assert.Equal(t, big.NewInt(8), precompile.GetNativeMinterRemainingAllowance(state, adminAddr))
+			},
+		},
+		"mint exceeding allowance fails": {
+			caller:         allowAddr,
+			precompileAddr: precompile.ContractNativeMinterAddress,
+			preCondition:   configureLimits,
+			input: func() []byte {
+				input, err := precompile.PackMintInput(allowAddr, big.NewInt(4))
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.MintGasCost,
+			readOnly:    false,
+			expectedErr: precompile.ErrMintExceedsAllowance.Error(),
+		},
+		"mint exceeding max supply fails": {
+			caller:         adminAddr,
+			precompileAddr: precompile.ContractNativeMinterAddress,
+			preCondition:   configureLimits,
+			input: func() []byte {
+				input, err := precompile.PackMintInput(adminAddr, big.NewInt(11))
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.MintGasCost,
+			readOnly:    false,
+			expectedErr: precompile.ErrMintExceedsMaxSupply.Error(),
+		},
+		"mint overflowing total minted fails": {
+			caller:         adminAddr,
+			precompileAddr: precompile.ContractNativeMinterAddress,
+			preCondition: func(t *testing.T, state *state.StateDB) {
+				input, err := precompile.PackMintInput(allowAddr, common.Big1)
+				if err != nil {
+					panic(err)
+				}
+				if _, _, err := precompile.ContractNativeMinterPrecompile.Run(&mockAccessibleState{state: state, blockContext: &mockBlockContext{blockNumber: common.Big0}}, allowAddr, precompile.ContractNativeMinterAddress, input, precompile.MintGasCost, false); err != nil {
+					t.Fatal(err)
+				}
+			},
+			input: func() []byte {
+				input, err := precompile.PackMintInput(adminAddr, math.MaxBig256)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.MintGasCost,
+			readOnly:    false,
+			expectedErr: precompile.ErrMintExceedsMaxSupply.Error(),
+		},
+		"read total minted": {
+			caller:         noRoleAddr,
+			precompileAddr: precompile.ContractNativeMinterAddress,
+			preCondition: func(t *testing.T, state *state.StateDB) {
+				configureLimits(t, state)
+				input, err := precompile.PackMintInput(allowAddr, big.NewInt(3))
+				if err != nil {
+					panic(err)
+				}
+				if _, _, err := precompile.ContractNativeMinterPrecompile.Run(&mockAccessibleState{state: state, blockContext: &mockBlockContext{blockNumber: common.Big0}}, allowAddr, precompile.ContractNativeMinterAddress, input, precompile.MintGasCost, false); err != nil {
+					t.Fatal(err)
+				}
+			},
+			input: func() []byte {
+				input, err := precompile.PackTotalMinted()
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.TotalMintedGasCost,
+			readOnly:    true,
+			expectedRes: common.BigToHash(big.NewInt(3)).Bytes(),
+			assertState: func(t *testing.T, state *state.StateDB) {},
+		},
+		"read remaining allowance": {
+			caller:         noRoleAddr,
+			precompileAddr: precompile.ContractNativeMinterAddress,
+			preCondition:   configureLimits,
+			input: func() []byte {
+				input, err := precompile.PackRemainingAllowance(allowAddr)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.RemainingAllowanceGasCost,
+			readOnly:    true,
+			expectedRes: common.BigToHash(big.NewInt(3)).Bytes(),
+			assertState: func(t *testing.T, state *state.StateDB) {},
+		},
+		"read remaining allowance without limits": {
+			caller:         noRoleAddr,
+			precompileAddr: precompile.ContractNativeMinterAddress,
+			input: func() []byte {
+				input, err := precompile.PackRemainingAllowance(allowAddr)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.RemainingAllowanceGasCost,
+			readOnly:    true,
+			expectedRes: common.BigToHash(math.MaxBig256).Bytes(),
+			assertState: func(t *testing.T, state *state.StateDB) {},
+		},
+		"read remaining allowance with insufficient gas": {
+			caller:         noRoleAddr,
+			precompileAddr: precompile.ContractNativeMinterAddress,
+			input: func() []byte {
+				input, err := precompile.PackRemainingAllowance(allowAddr)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.RemainingAllowanceGasCost - 1,
+			readOnly:    false,
+			expectedErr: vmerrs.ErrOutOfGas.Error(),
+		},
+		"readOnly mint with noRole fails": {
+			caller:         noRoleAddr,
+			precompileAddr: precompile.ContractNativeMinterAddress,
+			input: func() []byte {
+				input, err := precompile.PackMintInput(adminAddr, common.Big1)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.MintGasCost,
+			readOnly:    true,
+			expectedErr: vmerrs.ErrWriteProtection.Error(),
+		},
+		"readOnly mint with allow role fails": {
+			caller:         allowAddr,
+			precompileAddr: precompile.ContractNativeMinterAddress,
+			input: func() []byte {
+				input, err := precompile.PackMintInput(allowAddr, common.Big1)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.MintGasCost,
+			readOnly:    true,
+			expectedErr: vmerrs.ErrWriteProtection.Error(),
+		},
+		"readOnly mint with admin role fails": {
+			caller:         adminAddr,
+			precompileAddr: precompile.ContractNativeMinterAddress,
+			input: func() []byte {
+				input, err := precompile.PackMintInput(adminAddr, common.Big1)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.MintGasCost,
+			readOnly:    true,
+			expectedErr: vmerrs.ErrWriteProtection.Error(),
+		},
+		"insufficient gas mint from admin": {
+			caller:         adminAddr,
+			precompileAddr: precompile.ContractNativeMinterAddress,
+			input: func() []byte {
+				input, err := precompile.PackMintInput(allowAddr, common.Big1)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.MintGasCost - 1,
+			readOnly:    false,
+			expectedErr: vmerrs.ErrOutOfGas.Error(),
+		},
+		"read from noRole address": {
+			caller:         noRoleAddr,
+			precompileAddr: precompile.ContractNativeMinterAddress,
+			input: func() []byte {
+				return precompile.PackReadAllowList(noRoleAddr)
+			},
+			suppliedGas: precompile.ReadAllowListGasCost,
+			readOnly:    false,
+			expectedRes: common.Hash(precompile.AllowListNoRole).Bytes(),
+			assertState: func(t *testing.T, state *state.StateDB) {},
+		},
+		"read from noRole address readOnly enabled": {
+			caller:         noRoleAddr,
+			precompileAddr: precompile.ContractNativeMinterAddress,
+			input: func() []byte {
+				return precompile.PackReadAllowList(noRoleAddr)
+			},
+			suppliedGas: precompile.ReadAllowListGasCost,
+			readOnly:    true,
+			expectedRes: common.Hash(precompile.AllowListNoRole).Bytes(),
+			assertState: func(t *testing.T, state *state.StateDB) {},
+		},
+		"read from noRole address with insufficient gas": {
+			caller:         noRoleAddr,
+			precompileAddr: precompile.ContractNativeMinterAddress,
+			input: func() []byte {
+				return precompile.PackReadAllowList(noRoleAddr)
+			},
+			suppliedGas: precompile.ReadAllowListGasCost - 1,
+			readOnly:    false,
+			expectedErr: vmerrs.ErrOutOfGas.Error(),
+		},
+		"set allow role from admin": {
+			caller:         adminAddr,
+			precompileAddr: precompile.ContractNativeMinterAddress,
+			input: func() []byte {
+				input, err := precompile.PackModifyAllowList(noRoleAddr, precompile.AllowListEnabled)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.ModifyAllowListGasCost,
+			readOnly:    false,
+			expectedRes: []byte{},
+			assertState: func(t *testing.T, state *state.StateDB) {
+				res := precompile.GetContractNativeMinterStatus(state, adminAddr)
+				assert.Equal(t, precompile.AllowListAdmin, res)
+
+				res = precompile.GetContractNativeMinterStatus(state, noRoleAddr)
+				assert.Equal(t, precompile.AllowListEnabled, res)
+			},
+		},
+		"set allow role from non-admin fails": {
+			caller:         allowAddr,
+			precompileAddr: precompile.ContractNativeMinterAddress,
+			input: func() []byte {
+				input, err := precompile.PackModifyAllowList(noRoleAddr, precompile.AllowListEnabled)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.ModifyAllowListGasCost,
+			readOnly:    false,
+			expectedErr: precompile.ErrCannotModifyAllowList.Error(),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			db := rawdb.NewMemoryDatabase()
+			state, err := state.New(common.Hash{}, state.NewDatabase(db), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Set up the state so that each address has the expected permissions at the start.
+			precompile.SetContractNativeMinterStatus(state, adminAddr, precompile.AllowListAdmin)
+			precompile.SetContractNativeMinterStatus(state, allowAddr, precompile.AllowListEnabled)
+			precompile.SetContractNativeMinterStatus(state, noRoleAddr, precompile.AllowListNoRole)
+			if test.preCondition != nil {
+				test.preCondition(t, state)
+			}
+			blockContext := &mockBlockContext{blockNumber: testBlockNumber}
+			ret, remainingGas, err := precompile.ContractNativeMinterPrecompile.Run(&mockAccessibleState{state: state, blockContext: blockContext}, test.caller, test.precompileAddr, test.input(), test.suppliedGas, test.readOnly)
+			if len(test.expectedErr) != 0 {
+				if err == nil {
+					assert.Failf(t, "run expectedly passed without error", "expected error %q", test.expectedErr)
+				} else {
+					assert.True(t, strings.Contains(err.Error(), test.expectedErr), "expected error (%s) to contain substring (%s)", err, test.expectedErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, uint64(0), remainingGas)
+			assert.Equal(t, test.expectedRes, ret)
+
+			test.assertState(t, state)
+		})
+	}
+}
+
+func TestContractNativeMinterInitialMint(t *testing.T) {
+	adminAddr := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	enabledAddr := common.HexToAddress("0xF60C45c607D0f41687c94C314d300f483661E13a")
+	fundedAddr := common.HexToAddress("0x0Fa8EA536Be85F32724D57A37758761B86416123")
+
+	db := rawdb.NewMemoryDatabase()
+	state, err := state.New(common.Hash{}, state.NewDatabase(db), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.AddBalance(fundedAddr, big.NewInt(5))
+
+	config := precompile.NewContractNativeMinterConfig(common.Big0, []common.Address{adminAddr})
+	config.EnabledAddresses = []common.Address{enabledAddr}
+	config.MaxSupply = math.NewHexOrDecimal256(100)
+	config.InitialMint = map[common.Address]*math.HexOrDecimal256{
+		enabledAddr: math.NewHexOrDecimal256(30),
+		fundedAddr:  math.NewHexOrDecimal256(20),
+	}
+	if err := config.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	precompile.Configure(params.TestChainConfig, &mockBlockContext{blockNumber: common.Big0}, config, state)
+
+	assert.Equal(t, precompile.AllowListAdmin, precompile.GetContractNativeMinterStatus(state, adminAddr))
+	assert.Equal(t, precompile.AllowListEnabled, precompile.GetContractNativeMinterStatus(state, enabledAddr))
+	assert.Equal(t, big.NewInt(30), state.GetBalance(enabledAddr))
+	assert.Equal(t, big.NewInt(25), state.GetBalance(fundedAddr))
+	// the initial mint counts towards the max supply
+	assert.Equal(t, big.NewInt(50), precompile.GetNativeMinterTotalMinted(state))
+	assert.Equal(t, big.NewInt(50), precompile.GetNativeMinterRemainingAllowance(state, enabledAddr))
+
+	// enabling the native minter again skips an initial mint exceeding the max supply given the total already minted
+	reenabledConfig := precompile.NewContractNativeMinterConfig(common.Big0, []common.Address{adminAddr})
+	reenabledConfig.MaxSupply = math.NewHexOrDecimal256(60)
+	reenabledConfig.InitialMint = map[common.Address]*math.HexOrDecimal256{enabledAddr: math.NewHexOrDecimal256(20)}
+	if err := reenabledConfig.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	precompile.Configure(params.TestChainConfig, &mockBlockContext{blockNumber: common.Big0}, reenabledConfig, state)
+	assert.Equal(t, big.NewInt(30), state.GetBalance(enabledAddr))
+	assert.Equal(t, big.NewInt(50), precompile.GetNativeMinterTotalMinted(state))
+	assert.Equal(t, big.NewInt(10), precompile.GetNativeMinterRemainingAllowance(state, enabledAddr))
+}
+
+func TestFeeConfigManagerRun(t *testing.T) {
+	type test struct {
+		caller         common.Address
+		precompileAddr common.Address
+		chainConfig    precompile.ChainConfig
+		preCondition   func(t *testing.T, state *state.StateDB)
+		input          func() []byte
+		suppliedGas    uint64
+		readOnly       bool
+
+		expectedRes []byte
+		expectedErr string
+
+		assertState func(t *testing.T, state *state.StateDB)
+	}
+
+	adminAddr := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	allowAddr := common.HexToAddress("0xAb5801a7D398351b8bE11C439e05C5B3259aeC9B")
+	noRoleAddr := common.HexToAddress("0xF60C45c607D0f41687c94C314d300f483661E13a")
+	boundedChainConfig := func() *params.ChainConfig {
+		config := *params.TestChainConfig
+		config.GenesisPrecompiles = params.Precompiles{
+			precompile.FeeConfigManagerConfigKey: &precompile.FeeConfigManagerConfig{
+				UpgradeableConfig: precompile.UpgradeableConfig{BlockTimestamp: common.Big0},
+				FeeConfigBounds: &precompile.FeeConfigBounds{
+					GasLimit: &precompile.FeeConfigFieldBounds{Min: big.NewInt(1_000_000), MaxDelta: big.NewInt(1_000_000)},
+				},
+			},
+		}
+		return &config
+	}()
+	storeTestFeeConfig := func(t *testing.T, state *state.StateDB) {
+		if err := precompile.StoreFeeConfig(state, testFeeConfig, &mockBlockContext{blockNumber: common.Big0}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	schedulePendingFeeConfig := func(t *testing.T, state *state.StateDB) {
+		input, err := precompile.PackSetFeeConfigAt(testFeeConfig, new(big.Int).SetUint64(precompile.DefaultMinFeeConfigDelay))
+		if err != nil {
+			panic(err)
+		}
+		if _, _, err := precompile.FeeConfigManagerPrecompile.Run(&mockAccessibleState{state: state, blockContext: &mockBlockContext{blockNumber: testBlockNumber}}, adminAddr, precompile.FeeConfigManagerAddress, input, precompile.SetFeeConfigAtGasCost, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, test := range map[string]test{
+		"set config from no role fails": {
+			caller:         noRoleAddr,
+			precompileAddr: precompile.FeeConfigManagerAddress,
+			input: func() []byte {
+				input, err := precompile.PackSetFeeConfig(testFeeConfig)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.SetFeeConfigGasCost,
+			readOnly:    false,
+			expectedErr: precompile.ErrCannotChangeFee.Error(),
+		},
+		"set config from allow address": {
+			caller:         allowAddr,
+			precompileAddr: precompile.FeeConfigManagerAddress,
+			input: func() []byte {
+				input, err := precompile.PackSetFeeConfig(testFeeConfig)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.SetFeeConfigGasCost,
+			readOnly:    false,
+			expectedRes: []byte{},
+			assertState: func(t *testing.T, state *state.StateDB) {
+				res := precompile.GetFeeConfigManagerStatus(state, allowAddr)
+				assert.Equal(t, precompile.AllowListEnabled, res)
+
+				feeConfig := precompile.GetStoredFeeConfig(state)
+				assert.Equal(t, testFeeConfig, feeConfig)
+			},
+		},
+		"set invalid config from allow address": {
+			caller:         allowAddr,
+			precompileAddr: precompile.FeeConfigManagerAddress,
+			input: func() []byte {
+				feeConfig := testFeeConfig
+				feeConfig.MinBlockGasCost = new(big.Int).Mul(feeConfig.MaxBlockGasCost, common.Big2)
+				input, err := precompile.PackSetFeeConfig(feeConfig)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.SetFeeConfigGasCost,
+			readOnly:    false,
+			expectedRes: []byte{},
+			expectedErr: "cannot be greater than maxBlockGasCost",
+			assertState: func(t *testing.T, state *state.StateDB) {
+				res := precompile.GetFeeConfigManagerStatus(state, allowAddr)
+				assert.Equal(t, precompile.AllowListEnabled, res)
+
+				feeConfig := precompile.GetStoredFeeConfig(state)
+				assert.Equal(t, testFeeConfig, feeConfig)
+			},
+		},
+		"set config from admin address": {
+			caller:         adminAddr,
+			precompileAddr: precompile.FeeConfigManagerAddress,
+			input: func() []byte {
+				input, err := precompile.PackSetFeeConfig(testFeeConfig)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.SetFeeConfigGasCost,
+			readOnly:    false,
+			expectedRes: []byte{},
+			assertState: func(t *testing.T, state *state.StateDB) {
+				res := precompile.GetFeeConfigManagerStatus(state, adminAddr)
+				assert.Equal(t, precompile.AllowListAdmin, res)
+
+				feeConfig := precompile.GetStoredFeeConfig(state)
+				assert.Equal(t, testFeeConfig, feeConfig)
+				lastChangedAt := precompile.GetFeeConfigLastChangedAt(state)
+				assert.EqualValues(t, testBlockNumber, lastChangedAt)
+
+				logs := state.Logs()
+				assert.Len(t, logs, 1)
+				assert.Equal(t, precompile.FeeConfigManagerAddress, logs[0].Address)
+				assert.Equal(t, []common.Hash{precompile.FeeConfigChangedEventID, adminAddr.Hash()}, logs[0].Topics)
+				feeConfigData, err := precompile.PackFeeConfig(testFeeConfig)
+				assert.NoError(t, err)
+				assert.Equal(t, feeConfigData, logs[0].Data)
+			},
+		},
+		"get fee config from non-enabled address": {
+			caller:         noRoleAddr,
+			precompileAddr: precompile.FeeConfigManagerAddress,
+			preCondition: func(t *testing.T, state *state.StateDB) {
+				err := precompile.StoreFeeConfig(state, testFeeConfig, &mockBlockContext{blockNumber: big.NewInt(6)})
+				if err != nil {
+					panic(err)
+				}
+			},
+			input: func() []byte {
+				return precompile.PackGetFeeConfigInput()
+			},
+			suppliedGas: precompile.GetFeeConfigGasCost,
+			readOnly:    true,
+			expectedRes: func() []byte {
+				res, err := precompile.PackFeeConfig(testFeeConfig)
+				assert.NoError(t, err)
+				return res
+			}(),
+			assertState: func(t *testing.T, state *state.StateDB) {
+				feeConfig := precompile.GetStoredFeeConfig(state)
+				lastChangedAt := precompile.GetFeeConfigLastChangedAt(state)
+				assert.Equal(t, testFeeConfig, feeConfig)
+				assert.EqualValues(t, big.NewInt(6), lastChangedAt)
+			},
+		},
+		"get last changed at from non-enabled address": {
+			caller:         noRoleAddr,
+			precompileAddr: precompile.FeeConfigManagerAddress,
+			preCondition: func(t *testing.T, state *state.StateDB) {
+				err := precompile.StoreFeeConfig(state, testFeeConfig, &mockBlockContext{blockNumber: testBlockNumber})
+				if err != nil {
+					panic(err)
+				}
+			},
+			input: func() []byte {
+				return precompile.PackGetLastChangedAtInput()
+			},
+			suppliedGas: precompile.GetLastChangedAtGasCost,
+			readOnly:    true,
+			expectedRes: common.BigToHash(testBlockNumber).Bytes(),
+			assertState: func(t *testing.T, state *state.StateDB) {
+				feeConfig := precompile.GetStoredFeeConfig(state)
+				lastChangedAt := precompile.GetFeeConfigLastChangedAt(state)
+				assert.Equal(t, testFeeConfig, feeConfig)
+				assert.Equal(t, testBlockNumber, lastChangedAt)
+			},
+		},
+		"set config within bounds": {
+			caller:         adminAddr,
+			precompileAddr: precompile.FeeConfigManagerAddress,
+			chainConfig:    boundedChainConfig,
+			preCondition:   storeTestFeeConfig,
+			input: func() []byte {
+				feeConfig := testFeeConfig
+				feeConfig.GasLimit = big.NewInt(9_000_000)
+				input, err := precompile.PackSetFeeConfig(feeConfig)
 				if err != nil {
 					panic(err)
 				}
 				return input
 			},
-			suppliedGas: precompile.MintGasCost,
+			suppliedGas: precompile.SetFeeConfigGasCost,
 			readOnly:    false,
 			expectedRes: []byte{},
 			assertState: func(t *testing.T, state *state.StateDB) {
-				res := precompile.GetContractNativeMinterStatus(state, allowAddr)
-				assert.Equal(t, precompile.AllowListEnabled, res)
-
-				assert.Equal(t, common.Big1, state.GetBalance(allowAddr), "expected minted funds")
+				assert.Equal(t, big.NewInt(9_000_000), precompile.GetStoredFeeConfig(state).GasLimit)
 			},
 		},
-		"mint funds from admin address": {
+		"set config below min bound fails": {
 			caller:         adminAddr,
-			precompileAddr: precompile.ContractNativeMinterAddress,
+			precompileAddr: precompile.FeeConfigManagerAddress,
+			chainConfig:    boundedChainConfig,
+			preCondition:   storeTestFeeConfig,
 			input: func() []byte {
-				input, err := precompile.PackMintInput(adminAddr, common.Big1)
+				feeConfig := testFeeConfig
+				feeConfig.GasLimit = big.NewInt(1)
+				input, err := precompile.PackSetFeeConfig(feeConfig)
 				if err != nil {
 					panic(err)
 				}
 				return input
 			},
-			suppliedGas: precompile.MintGasCost,
+			suppliedGas: precompile.SetFeeConfigGasCost,
 			readOnly:    false,
-			expectedRes: []byte{},
-			assertState: func(t *testing.T, state *state.StateDB) {
-				res := precompile.GetContractNativeMinterStatus(state, adminAddr)
-				assert.Equal(t, precompile.AllowListAdmin, res)
-
-				assert.Equal(t, common.Big1, state.GetBalance(adminAddr), "expected minted funds")
+			expectedErr: "gasLimit = 1 is below the minimum 1000000",
+		},
+		"set config exceeding max delta fails": {
+			caller:         adminAddr,
+			precompileAddr: precompile.FeeConfigManagerAddress,
+			chainConfig:    boundedChainConfig,
+			preCondition:   storeTestFeeConfig,
+			input: func() []byte {
+				feeConfig := testFeeConfig
+				feeConfig.GasLimit = big.NewInt(10_000_000)
+				input, err := precompile.PackSetFeeConfig(feeConfig)
+				if err != nil {
+					panic(err)
+				}
+				return input
 			},
+			suppliedGas: precompile.SetFeeConfigGasCost,
+			readOnly:    false,
+			expectedErr: precompile.ErrFeeConfigChangeTooLarge.Error(),
 		},
-		"mint max big funds": {
+		"schedule config exceeding max delta fails": {
 			caller:         adminAddr,
-			precompileAddr: precompile.ContractNativeMinterAddress,
+			precompileAddr: precompile.FeeConfigManagerAddress,
+			chainConfig:    boundedChainConfig,
+			preCondition:   storeTestFeeConfig,
 			input: func() []byte {
-				input, err := precompile.PackMintInput(adminAddr, math.MaxBig256)
+				feeConfig := testFeeConfig
+				feeConfig.GasLimit = big.NewInt(10_000_000)
+				input, err := precompile.PackSetFeeConfigAt(feeConfig, new(big.Int).SetUint64(precompile.DefaultMinFeeConfigDelay))
 				if err != nil {
 					panic(err)
 				}
 				return input
 			},
-			suppliedGas: precompile.MintGasCost,
+			suppliedGas: precompile.SetFeeConfigAtGasCost,
+			readOnly:    false,
+			expectedErr: precompile.ErrFeeConfigChangeTooLarge.Error(),
+		},
+		"schedule config from allow address": {
+			caller:         allowAddr,
+			precompileAddr: precompile.FeeConfigManagerAddress,
+			input: func() []byte {
+				input, err := precompile.PackSetFeeConfigAt(testFeeConfig, new(big.Int).SetUint64(precompile.DefaultMinFeeConfigDelay))
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.SetFeeConfigAtGasCost,
 			readOnly:    false,
 			expectedRes: []byte{},
 			assertState: func(t *testing.T, state *state.StateDB) {
-				res := precompile.GetContractNativeMinterStatus(state, adminAddr)
-				assert.Equal(t, precompile.AllowListAdmin, res)
+				pendingFeeConfig, activationTimestamp := precompile.GetPendingFeeConfig(state)
+				assert.Equal(t, testFeeConfig, pendingFeeConfig)
+				assert.Equal(t, precompile.DefaultMinFeeConfigDelay, activationTimestamp.Uint64())
+				// the stored fee config is not changed until the pending fee config is applied
+				assert.Equal(t, 0, precompile.GetFeeConfigLastChangedAt(state).Sign())
 
-				assert.Equal(t, math.MaxBig256, state.GetBalance(adminAddr), "expected minted funds")
+				logs := state.Logs()
+				assert.Len(t, logs, 1)
+				assert.Equal(t, []common.Hash{precompile.FeeConfigScheduledEventID, allowAddr.Hash()}, logs[0].Topics)
+
+				// the pending fee config is applied once the timestamp of the parent block reaches its activation
+				precompile.ApplyPendingFeeConfig(params.TestChainConfig, state, new(big.Int).SetUint64(precompile.DefaultMinFeeConfigDelay-1), &mockBlockContext{blockNumber: big.NewInt(10)})
+				assert.Equal(t, 0, precompile.GetFeeConfigLastChangedAt(state).Sign())
+				precompile.ApplyPendingFeeConfig(params.TestChainConfig, state, new(big.Int).SetUint64(precompile.DefaultMinFeeConfigDelay), &mockBlockContext{blockNumber: big.NewInt(11)})
+				assert.Equal(t, testFeeConfig, precompile.GetStoredFeeConfig(state))
+				assert.Equal(t, big.NewInt(11), precompile.GetFeeConfigLastChangedAt(state))
+				_, activationTimestamp = precompile.GetPendingFeeConfig(state)
+				assert.Equal(t, 0, activationTimestamp.Sign())
+				// applying the pending fee config is not logged, since it happens outside of any transaction
+				assert.Len(t, state.Logs(), 1)
 			},
 		},
-		"readOnly mint with noRole fails": {
-			caller:         noRoleAddr,
-			precompileAddr: precompile.ContractNativeMinterAddress,
+		"schedule config before min delay fails": {
+			caller:         adminAddr,
+			precompileAddr: precompile.FeeConfigManagerAddress,
 			input: func() []byte {
-				input, err := precompile.PackMintInput(adminAddr, common.Big1)
+				input, err := precompile.PackSetFeeConfigAt(testFeeConfig, new(big.Int).SetUint64(precompile.DefaultMinFeeConfigDelay-1))
 				if err != nil {
 					panic(err)
 				}
 				return input
 			},
-			suppliedGas: precompile.MintGasCost,
-			readOnly:    true,
-			expectedErr: vmerrs.ErrWriteProtection.Error(),
+			suppliedGas: precompile.SetFeeConfigAtGasCost,
+			readOnly:    false,
+			expectedErr: precompile.ErrFeeConfigActivationTooEarly.Error(),
 		},
-		"readOnly mint with allow role fails": {
-			caller:         allowAddr,
-			precompileAddr: precompile.ContractNativeMinterAddress,
+		"schedule config with configured min delay": {
+			caller:         adminAddr,
+			precompileAddr: precompile.FeeConfigManagerAddress,
+			preCondition: func(t *testing.T, state *state.StateDB) {
+				config := &precompile.FeeConfigManagerConfig{MinFeeConfigDelay: 10}
+				config.Configure(params.TestChainConfig, state, &mockBlockContext{blockNumber: common.Big0})
+			},
 			input: func() []byte {
-				input, err := precompile.PackMintInput(allowAddr, common.Big1)
+				input, err := precompile.PackSetFeeConfigAt(testFeeConfig, big.NewInt(10))
 				if err != nil {
 					panic(err)
 				}
 				return input
 			},
-			suppliedGas: precompile.MintGasCost,
-			readOnly:    true,
-			expectedErr: vmerrs.ErrWriteProtection.Error(),
+			suppliedGas: precompile.SetFeeConfigAtGasCost,
+			readOnly:    false,
+			expectedRes: []byte{},
+			assertState: func(t *testing.T, state *state.StateDB) {
+				_, activationTimestamp := precompile.GetPendingFeeConfig(state)
+				assert.Equal(t, big.NewInt(10), activationTimestamp)
+			},
 		},
-		"readOnly mint with admin role fails": {
+		"set config with configured min delay fails": {
 			caller:         adminAddr,
-			precompileAddr: precompile.ContractNativeMinterAddress,
+			precompileAddr: precompile.FeeConfigManagerAddress,
+			preCondition: func(t *testing.T, state *state.StateDB) {
+				config := &precompile.FeeConfigManagerConfig{MinFeeConfigDelay: 10}
+				config.Configure(params.TestChainConfig, state, &mockBlockContext{blockNumber: common.Big0})
+			},
 			input: func() []byte {
-				input, err := precompile.PackMintInput(adminAddr, common.Big1)
+				input, err := precompile.PackSetFeeConfig(testFeeConfig)
 				if err != nil {
 					panic(err)
 				}
 				return input
 			},
-			suppliedGas: precompile.MintGasCost,
-			readOnly:    true,
-			expectedErr: vmerrs.ErrWriteProtection.Error(),
+			suppliedGas: precompile.SetFeeConfigGasCost,
+			readOnly:    false,
+			expectedErr: precompile.ErrFeeConfigTimeLocked.Error(),
 		},
-		"insufficient gas mint from admin": {
+		"schedule invalid config fails": {
 			caller:         adminAddr,
-			precompileAddr: precompile.ContractNativeMinterAddress,
+			precompileAddr: precompile.FeeConfigManagerAddress,
 			input: func() []byte {
-				input, err := precompile.PackMintInput(allowAddr, common.Big1)
+				feeConfig := testFeeConfig
+				feeConfig.MinBlockGasCost = new(big.Int).Mul(feeConfig.MaxBlockGasCost, common.Big2)
+				input, err := precompile.PackSetFeeConfigAt(feeConfig, new(big.Int).SetUint64(precompile.DefaultMinFeeConfigDelay))
 				if err != nil {
 					panic(err)
 				}
 				return input
 			},
-			suppliedGas: precompile.MintGasCost - 1,
+			suppliedGas: precompile.SetFeeConfigAtGasCost,
 			readOnly:    false,
-			expectedErr: vmerrs.ErrOutOfGas.Error(),
+			expectedErr: "minBlockGasCost = 2000000 cannot be greater than maxBlockGasCost = 1000000",
 		},
-		"read from noRole address": {
+		"schedule config from no role fails": {
 			caller:         noRoleAddr,
-			precompileAddr: precompile.ContractNativeMinterAddress,
+			precompileAddr: precompile.FeeConfigManagerAddress,
 			input: func() []byte {
-				return precompile.PackReadAllowList(noRoleAddr)
+				input, err := precompile.PackSetFeeConfigAt(testFeeConfig, new(big.Int).SetUint64(precompile.DefaultMinFeeConfigDelay))
+				if err != nil {
+					panic(err)
+				}
+				return input
 			},
-			suppliedGas: precompile.ReadAllowListGasCost,
+			suppliedGas: precompile.SetFeeConfigAtGasCost,
 			readOnly:    false,
-			expectedRes: common.Hash(precompile.AllowListNoRole).Bytes(),
-			assertState: func(t *testing.T, state *state.StateDB) {},
+			expectedErr: precompile.ErrCannotChangeFee.Error(),
 		},
-		"read from noRole address readOnly enabled": {
+		"get pending fee config": {
 			caller:         noRoleAddr,
-			precompileAddr: precompile.ContractNativeMinterAddress,
+			precompileAddr: precompile.FeeConfigManagerAddress,
+			preCondition:   schedulePendingFeeConfig,
 			input: func() []byte {
-				return precompile.PackReadAllowList(noRoleAddr)
+				return precompile.PackGetPendingFeeConfigInput()
 			},
-			suppliedGas: precompile.ReadAllowListGasCost,
+			suppliedGas: precompile.GetPendingFeeConfigGasCost,
 			readOnly:    true,
-			expectedRes: common.Hash(precompile.AllowListNoRole).Bytes(),
+			expectedRes: func() []byte {
+				res, err := precompile.PackSetFeeConfigAt(testFeeConfig, new(big.Int).SetUint64(precompile.DefaultMinFeeConfigDelay))
+				assert.NoError(t, err)
+				return res[4:]
+			}(),
 			assertState: func(t *testing.T, state *state.StateDB) {},
 		},
-		"read from noRole address with insufficient gas": {
+		"cancel pending fee config": {
+			caller:         allowAddr,
+			precompileAddr: precompile.FeeConfigManagerAddress,
+			preCondition:   schedulePendingFeeConfig,
+			input: func() []byte {
+				return precompile.PackCancelPendingFeeConfigInput()
+			},
+			suppliedGas: precompile.CancelPendingFeeConfigGasCost,
+			readOnly:    false,
+			expectedRes: []byte{},
+			assertState: func(t *testing.T, state *state.StateDB) {
+				_, activationTimestamp := precompile.GetPendingFeeConfig(state)
+				assert.Equal(t, 0, activationTimestamp.Sign())
+
+				logs := state.Logs()
+				assert.Len(t, logs, 2)
+				assert.Equal(t, []common.Hash{precompile.PendingFeeConfigCancelledEventID, allowAddr.Hash()}, logs[1].Topics)
+				assert.Equal(t, common.BigToHash(new(big.Int).SetUint64(precompile.DefaultMinFeeConfigDelay)).Bytes(), logs[1].Data)
+			},
+		},
+		"cancel without pending fee config fails": {
+			caller:         adminAddr,
+			precompileAddr: precompile.FeeConfigManagerAddress,
+			input: func() []byte {
+				return precompile.PackCancelPendingFeeConfigInput()
+			},
+			suppliedGas: precompile.CancelPendingFeeConfigGasCost,
+			readOnly:    false,
+			expectedErr: precompile.ErrNoPendingFeeConfig.Error(),
+		},
+		"cancel pending fee config from no role fails": {
 			caller:         noRoleAddr,
-			precompileAddr: precompile.ContractNativeMinterAddress,
+			precompileAddr: precompile.FeeConfigManagerAddress,
+			preCondition:   schedulePendingFeeConfig,
 			input: func() []byte {
-				return precompile.PackReadAllowList(noRoleAddr)
+				return precompile.PackCancelPendingFeeConfigInput()
 			},
-			suppliedGas: precompile.ReadAllowListGasCost - 1,
+			suppliedGas: precompile.CancelPendingFeeConfigGasCost,
+			readOnly:    false,
+			expectedErr: precompile.ErrCannotChangeFee.Error(),
+		},
+		"readOnly cancel pending fee config fails": {
+			caller:         adminAddr,
+			precompileAddr: precompile.FeeConfigManagerAddress,
+			preCondition:   schedulePendingFeeConfig,
+			input: func() []byte {
+				return precompile.PackCancelPendingFeeConfigInput()
+			},
+			suppliedGas: precompile.CancelPendingFeeConfigGasCost,
+			readOnly:    true,
+			expectedErr: vmerrs.ErrWriteProtection.Error(),
+		},
+		"readOnly setFeeConfig with noRole fails": {
+			caller:         noRoleAddr,
+			precompileAddr: precompile.FeeConfigManagerAddress,
+			input: func() []byte {
+				input, err := precompile.PackSetFeeConfig(testFeeConfig)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.SetFeeConfigGasCost,
+			readOnly:    true,
+			expectedErr: vmerrs.ErrWriteProtection.Error(),
+		},
+		"readOnly setFeeConfig with allow role fails": {
+			caller:         allowAddr,
+			precompileAddr: precompile.FeeConfigManagerAddress,
+			input: func() []byte {
+				input, err := precompile.PackSetFeeConfig(testFeeConfig)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.SetFeeConfigGasCost,
+			readOnly:    true,
+			expectedErr: vmerrs.ErrWriteProtection.Error(),
+		},
+		"readOnly setFeeConfig with admin role fails": {
+			caller:         adminAddr,
+			precompileAddr: precompile.FeeConfigManagerAddress,
+			input: func() []byte {
+				input, err := precompile.PackSetFeeConfig(testFeeConfig)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.SetFeeConfigGasCost,
+			readOnly:    true,
+			expectedErr: vmerrs.ErrWriteProtection.Error(),
+		},
+		"insufficient gas setFeeConfig from admin": {
+			caller:         adminAddr,
+			precompileAddr: precompile.FeeConfigManagerAddress,
+			input: func() []byte {
+				input, err := precompile.PackSetFeeConfig(testFeeConfig)
+				if err != nil {
+					panic(err)
+				}
+				return input
+			},
+			suppliedGas: precompile.SetFeeConfigGasCost - 1,
 			readOnly:    false,
 			expectedErr: vmerrs.ErrOutOfGas.Error(),
 		},
 		"set allow role from admin": {
 			caller:         adminAddr,
-			precompileAddr: precompile.ContractNativeMinterAddress,
+			precompileAddr: precompile.FeeConfigManagerAddress,
 			input: func() []byte {
 				input, err := precompile.PackModifyAllowList(noRoleAddr, precompile.AllowListEnabled)
 				if err != nil {
@@ -719,16 +2301,16 @@ func TestContractNativeMinterRun(t *testing.T) {
 			readOnly:    false,
 			expectedRes: []byte{},
 			assertState: func(t *testing.T, state *state.StateDB) {
-				res := precompile.GetContractNativeMinterStatus(state, adminAddr)
+				res := precompile.GetFeeConfigManagerStatus(state, adminAddr)
 				assert.Equal(t, precompile.AllowListAdmin, res)
 
-				res = precompile.GetContractNativeMinterStatus(state, noRoleAddr)
+				res = precompile.GetFeeConfigManagerStatus(state, noRoleAddr)
 				assert.Equal(t, precompile.AllowListEnabled, res)
 			},
 		},
 		"set allow role from non-admin fails": {
 			caller:         allowAddr,
-			precompileAddr: precompile.ContractNativeMinterAddress,
+			precompileAddr: precompile.FeeConfigManagerAddress,
 			input: func() []byte {
 				input, err := precompile.PackModifyAllowList(noRoleAddr, precompile.AllowListEnabled)
 				if err != nil {
@@ -748,11 +2330,16 @@ func TestContractNativeMinterRun(t *testing.T) {
 				t.Fatal(err)
 			}
 			// Set up the state so that each address has the expected permissions at the start.
-			precompile.SetContractNativeMinterStatus(state, adminAddr, precompile.AllowListAdmin)
-			precompile.SetContractNativeMinterStatus(state, allowAddr, precompile.AllowListEnabled)
-			precompile.SetContractNativeMinterStatus(state, noRoleAddr, precompile.AllowListNoRole)
-			blockContext := &mockBlockContext{blockNumber: common.Big0}
-			ret, remainingGas, err := precompile.ContractNativeMinterPrecompile.Run(&mockAccessibleState{state: state, blockContext: blockContext}, test.caller, test.precompileAddr, test.input(), test.suppliedGas, test.readOnly)
+			precompile.SetFeeConfigManagerStatus(state, adminAddr, precompile.AllowListAdmin)
+			precompile.SetFeeConfigManagerStatus(state, allowAddr, precompile.AllowListEnabled)
+			precompile.SetFeeConfigManagerStatus(state, noRoleAddr, precompile.AllowListNoRole)
+
+			if test.preCondition != nil {
+				test.preCondition(t, state)
+			}
+
+			blockContext := &mockBlockContext{blockNumber: testBlockNumber}
+			ret, remainingGas, err := precompile.FeeConfigManagerPrecompile.Run(&mockAccessibleState{state: state, blockContext: blockContext, chainConfig: test.chainConfig}, test.caller, test.precompileAddr, test.input(), test.suppliedGas, test.readOnly)
 			if len(test.expectedErr) != 0 {
 				if err == nil {
 					assert.Failf(t, "run expectedly passed without error", "expected error %q", test.expectedErr)
@@ -774,14 +2361,13 @@ func TestContractNativeMinterRun(t *testing.T) {
 	}
 }
 
-func TestFeeConfigManagerRun(t *testing.T) {
+func TestRewardManagerRun(t *testing.T) {
 	type test struct {
-		caller         common.Address
-		precompileAddr common.Address
-		preCondition   func(t *testing.T, state *state.StateDB)
-		input          func() []byte
-		suppliedGas    uint64
-		readOnly       bool
+		caller       common.Address
+		preCondition func(t *testing.T, state *state.StateDB)
+		input        func() []byte
+		suppliedGas  uint64
+		readOnly     bool
 
 		expectedRes []byte
 		expectedErr string
@@ -790,230 +2376,423 @@ func TestFeeConfigManagerRun(t *testing.T) {
 	}
 
 	adminAddr := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
-	allowAddr := common.HexToAddress("0xAb5801a7D398351b8bE11C439e05C5B3259aeC9B")
+	enabledAddr := common.HexToAddress("0xAb5801a7D398351b8bE11C439e05C5B3259aeC9B")
 	noRoleAddr := common.HexToAddress("0xF60C45c607D0f41687c94C314d300f483661E13a")
+	testAddr := common.HexToAddress("0x0123")
 
 	for name, test := range map[string]test{
-		"set config from no role fails": {
-			caller:         noRoleAddr,
-			precompileAddr: precompile.FeeConfigManagerAddress,
+		"set reward address from no role fails": {
+			caller: noRoleAddr,
 			input: func() []byte {
-				input, err := precompile.PackSetFeeConfig(testFeeConfig)
+				input, err := precompile.PackSetRewardAddress(testAddr)
 				if err != nil {
 					panic(err)
 				}
 				return input
 			},
-			suppliedGas: precompile.SetFeeConfigGasCost,
+			suppliedGas: precompile.SetRewardAddressGasCost,
 			readOnly:    false,
-			expectedErr: precompile.ErrCannotChangeFee.Error(),
+			expectedErr: precompile.ErrCannotSetRewardAddress.Error(),
 		},
-		"set config from allow address": {
-			caller:         allowAddr,
-			precompileAddr: precompile.FeeConfigManagerAddress,
+		"set reward address from enabled succeeds": {
+			caller: enabledAddr,
 			input: func() []byte {
-				input, err := precompile.PackSetFeeConfig(testFeeConfig)
+				input, err := precompile.PackSetRewardAddress(testAddr)
 				if err != nil {
 					panic(err)
 				}
 				return input
 			},
-			suppliedGas: precompile.SetFeeConfigGasCost,
+			suppliedGas: precompile.SetRewardAddressGasCost,
 			readOnly:    false,
 			expectedRes: []byte{},
 			assertState: func(t *testing.T, state *state.StateDB) {
-				res := precompile.GetFeeConfigManagerStatus(state, allowAddr)
-				assert.Equal(t, precompile.AllowListEnabled, res)
-
-				feeConfig := precompile.GetStoredFeeConfig(state)
-				assert.Equal(t, testFeeConfig, feeConfig)
+				address, isFeeRecipients := precompile.GetStoredRewardAddress(state)
+				assert.Equal(t, testAddr, address)
+				assert.False(t, isFeeRecipients)
 			},
 		},
-		"set invalid config from allow address": {
-			caller:         allowAddr,
-			precompileAddr: precompile.FeeConfigManagerAddress,
+		"set empty reward address from admin fails": {
+			caller: adminAddr,
 			input: func() []byte {
-				feeConfig := testFeeConfig
-				feeConfig.MinBlockGasCost = new(big.Int).Mul(feeConfig.MaxBlockGasCost, common.Big2)
-				input, err := precompile.PackSetFeeConfig(feeConfig)
+				input, err := precompile.PackSetRewardAddress(common.Address{})
 				if err != nil {
 					panic(err)
 				}
 				return input
 			},
-			suppliedGas: precompile.SetFeeConfigGasCost,
+			suppliedGas: precompile.SetRewardAddressGasCost,
 			readOnly:    false,
-			expectedRes: []byte{},
-			expectedErr: "cannot be greater than maxBlockGasCost",
-			assertState: func(t *testing.T, state *state.StateDB) {
-				res := precompile.GetFeeConfigManagerStatus(state, allowAddr)
-				assert.Equal(t, precompile.AllowListEnabled, res)
-
-				feeConfig := precompile.GetStoredFeeConfig(state)
-				assert.Equal(t, testFeeConfig, feeConfig)
+			expectedErr: precompile.ErrEmptyRewardAddress.Error(),
+		},
+		"allow fee recipients from no role fails": {
+			caller: noRoleAddr,
+			input: func() []byte {
+				return precompile.PackAllowFeeRecipients()
 			},
+			suppliedGas: precompile.AllowFeeRecipientsGasCost,
+			readOnly:    false,
+			expectedErr: precompile.ErrCannotAllowFeeRecipients.Error(),
 		},
-		"set config from admin address": {
-			caller:         adminAddr,
-			precompileAddr: precompile.FeeConfigManagerAddress,
+		"allow fee recipients from enabled succeeds": {
+			caller: enabledAddr,
 			input: func() []byte {
-				input, err := precompile.PackSetFeeConfig(testFeeConfig)
-				if err != nil {
-					panic(err)
-				}
-				return input
+				return precompile.PackAllowFeeRecipients()
 			},
-			suppliedGas: precompile.SetFeeConfigGasCost,
+			suppliedGas: precompile.AllowFeeRecipientsGasCost,
 			readOnly:    false,
 			expectedRes: []byte{},
 			assertState: func(t *testing.T, state *state.StateDB) {
-				res := precompile.GetFeeConfigManagerStatus(state, adminAddr)
-				assert.Equal(t, precompile.AllowListAdmin, res)
-
-				feeConfig := precompile.GetStoredFeeConfig(state)
-				assert.Equal(t, testFeeConfig, feeConfig)
-				lastChangedAt := precompile.GetFeeConfigLastChangedAt(state)
-				assert.EqualValues(t, testBlockNumber, lastChangedAt)
+				_, isFeeRecipients := precompile.GetStoredRewardAddress(state)
+				assert.True(t, isFeeRecipients)
 			},
 		},
-		"get fee config from non-enabled address": {
-			caller:         noRoleAddr,
-			precompileAddr: precompile.FeeConfigManagerAddress,
+		"disable rewards from no role fails": {
+			caller: noRoleAddr,
+			input: func() []byte {
+				return precompile.PackDisableRewards()
+			},
+			suppliedGas: precompile.DisableRewardsGasCost,
+			readOnly:    false,
+			expectedErr: precompile.ErrCannotDisableRewards.Error(),
+		},
+		"disable rewards from admin succeeds": {
+			caller: adminAddr,
 			preCondition: func(t *testing.T, state *state.StateDB) {
-				err := precompile.StoreFeeConfig(state, testFeeConfig, &mockBlockContext{blockNumber: big.NewInt(6)})
-				if err != nil {
-					panic(err)
-				}
+				precompile.EnableAllowFeeRecipients(state)
 			},
 			input: func() []byte {
-				return precompile.PackGetFeeConfigInput()
+				return precompile.PackDisableRewards()
 			},
-			suppliedGas: precompile.GetFeeConfigGasCost,
-			readOnly:    true,
-			expectedRes: func() []byte {
-				res, err := precompile.PackFeeConfig(testFeeConfig)
-				assert.NoError(t, err)
-				return res
-			}(),
+			suppliedGas: precompile.DisableRewardsGasCost,
+			readOnly:    false,
+			expectedRes: []byte{},
 			assertState: func(t *testing.T, state *state.StateDB) {
-				feeConfig := precompile.GetStoredFeeConfig(state)
-				lastChangedAt := precompile.GetFeeConfigLastChangedAt(state)
-				assert.Equal(t, testFeeConfig, feeConfig)
-				assert.EqualValues(t, big.NewInt(6), lastChangedAt)
+				address, isFeeRecipients := precompile.GetStoredRewardAddress(state)
+				assert.False(t, isFeeRecipients)
+				assert.Equal(t, constants.BlackholeAddr, address)
 			},
 		},
-		"get last changed at from non-enabled address": {
-			caller:         noRoleAddr,
-			precompileAddr: precompile.FeeConfigManagerAddress,
+		"get current reward address from no role succeeds": {
+			caller: noRoleAddr,
 			preCondition: func(t *testing.T, state *state.StateDB) {
-				err := precompile.StoreFeeConfig(state, testFeeConfig, &mockBlockContext{blockNumber: testBlockNumber})
-				if err != nil {
-					panic(err)
-				}
+				precompile.StoreRewardAddress(state, testAddr)
 			},
 			input: func() []byte {
-				return precompile.PackGetLastChangedAtInput()
+				return precompile.PackCurrentRewardAddress()
 			},
-			suppliedGas: precompile.GetLastChangedAtGasCost,
+			suppliedGas: precompile.CurrentRewardAddressGasCost,
 			readOnly:    true,
-			expectedRes: common.BigToHash(testBlockNumber).Bytes(),
-			assertState: func(t *testing.T, state *state.StateDB) {
-				feeConfig := precompile.GetStoredFeeConfig(state)
-				lastChangedAt := precompile.GetFeeConfigLastChangedAt(state)
-				assert.Equal(t, testFeeConfig, feeConfig)
-				assert.Equal(t, testBlockNumber, lastChangedAt)
+			expectedRes: common.BytesToHash(testAddr.Bytes()).Bytes(),
+		},
+		"get are fee recipients allowed from no role succeeds": {
+			caller: noRoleAddr,
+			preCondition: func(t *testing.T, state *state.StateDB) {
+				precompile.EnableAllowFeeRecipients(state)
 			},
+			input: func() []byte {
+				return precompile.PackAreFeeRecipientsAllowed()
+			},
+			suppliedGas: precompile.AreFeeRecipientsAllowedGasCost,
+			readOnly:    true,
+			expectedRes: common.BigToHash(common.Big1).Bytes(),
 		},
-		"readOnly setFeeConfig with noRole fails": {
-			caller:         noRoleAddr,
-			precompileAddr: precompile.FeeConfigManagerAddress,
+		"readOnly set reward address from admin fails": {
+			caller: adminAddr,
 			input: func() []byte {
-				input, err := precompile.PackSetFeeConfig(testFeeConfig)
+				input, err := precompile.PackSetRewardAddress(testAddr)
 				if err != nil {
 					panic(err)
 				}
 				return input
 			},
-			suppliedGas: precompile.SetFeeConfigGasCost,
+			suppliedGas: precompile.SetRewardAddressGasCost,
 			readOnly:    true,
 			expectedErr: vmerrs.ErrWriteProtection.Error(),
 		},
-		"readOnly setFeeConfig with allow role fails": {
-			caller:         allowAddr,
-			precompileAddr: precompile.FeeConfigManagerAddress,
+		"insufficient gas set reward address from admin": {
+			caller: adminAddr,
 			input: func() []byte {
-				input, err := precompile.PackSetFeeConfig(testFeeConfig)
+				input, err := precompile.PackSetRewardAddress(testAddr)
 				if err != nil {
 					panic(err)
 				}
 				return input
 			},
-			suppliedGas: precompile.SetFeeConfigGasCost,
+			suppliedGas: precompile.SetRewardAddressGasCost - 1,
+			readOnly:    false,
+			expectedErr: vmerrs.ErrOutOfGas.Error(),
+		},
+		"insufficient gas current reward address": {
+			caller: noRoleAddr,
+			input: func() []byte {
+				return precompile.PackCurrentRewardAddress()
+			},
+			suppliedGas: precompile.CurrentRewardAddressGasCost - 1,
 			readOnly:    true,
-			expectedErr: vmerrs.ErrWriteProtection.Error(),
+			expectedErr: vmerrs.ErrOutOfGas.Error(),
 		},
-		"readOnly setFeeConfig with admin role fails": {
-			caller:         adminAddr,
-			precompileAddr: precompile.FeeConfigManagerAddress,
+	} {
+		t.Run(name, func(t *testing.T) {
+			db := rawdb.NewMemoryDatabase()
+			state, err := state.New(common.Hash{}, state.NewDatabase(db), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Set up the state so that each address has the expected permissions at the start.
+			precompile.SetRewardManagerAllowListStatus(state, adminAddr, precompile.AllowListAdmin)
+			precompile.SetRewardManagerAllowListStatus(state, enabledAddr, precompile.AllowListEnabled)
+			precompile.SetRewardManagerAllowListStatus(state, noRoleAddr, precompile.AllowListNoRole)
+
+			if test.preCondition != nil {
+				test.preCondition(t, state)
+			}
+
+			blockContext := &mockBlockContext{blockNumber: testBlockNumber}
+			ret, remainingGas, err := precompile.RewardManagerPrecompile.Run(&mockAccessibleState{state: state, blockContext: blockContext}, test.caller, precompile.RewardManagerAddress, test.input(), test.suppliedGas, test.readOnly)
+			if len(test.expectedErr) != 0 {
+				if err == nil {
+					assert.Failf(t, "run expectedly passed without error", "expected error %q", test.expectedErr)
+				} else {
+					assert.True(t, strings.Contains(err.Error(), test.expectedErr), "expected error (%s) to contain substring (%s)", err, test.expectedErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, uint64(0), remainingGas)
+			assert.Equal(t, test.expectedRes, ret)
+
+			if test.assertState != nil {
+				test.assertState(t, state)
+			}
+		})
+	}
+}
+
+func TestWarpMessengerRun(t *testing.T) {
+	type test struct {
+		caller      common.Address
+		input       func() []byte
+		predicate   func() []byte
+		suppliedGas uint64
+		readOnly    bool
+
+		expectedRes []byte
+		expectedErr string
+
+		assertState func(t *testing.T, state *state.StateDB)
+	}
+
+	var (
+		sourceChainID      = common.BigToHash(big.NewInt(2))
+		destinationChainID = common.BigToHash(big.NewInt(1))
+		senderAddr         = common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+		destinationAddr    = common.HexToAddress("0xF60C45c607D0f41687c94C314d300f483661E13a")
+		payload            = []byte("hello warp")
+	)
+
+	// Set up a source chain with three validators of equal weight, where any two
+	// validators exceed the configured quorum.
+	secretKeys := make([]*warp.SecretKey, 3)
+	validators := make(precompile.WarpValidatorSet, 3)
+	for i := range secretKeys {
+		sk, err := warp.NewSecretKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		secretKeys[i] = sk
+		validators[i] = &warp.Validator{PublicKey: sk.PublicKey(), Weight: 10}
+	}
+	config := precompile.NewWarpMessengerConfig(common.Big0, 60, map[common.Hash]precompile.WarpValidatorSet{sourceChainID: validators})
+	contract := config.Contract()
+
+	unsignedMessage := &warp.UnsignedMessage{
+		SourceChainID:      sourceChainID,
+		SourceAddress:      senderAddr,
+		DestinationChainID: destinationChainID,
+		DestinationAddress: destinationAddr,
+		Payload:            payload,
+	}
+	unsignedMessageBytes, err := unsignedMessage.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// signMessageBytes returns the packed predicate of [unsignedMessageBytes] signed by the validators at [indices]
+	signMessageBytes := func(unsignedMessageBytes []byte, indices ...int) []byte {
+		signatures := make([]*warp.Signature, 0, len(indices))
+		for _, index := range indices {
+			signatures = append(signatures, secretKeys[index].Sign(unsignedMessageBytes))
+		}
+		signature, err := warp.AggregateSignatures(signatures)
+		if err != nil {
+			panic(err)
+		}
+		signedMessage := &warp.SignedMessage{
+			UnsignedMessage: unsignedMessageBytes,
+			Signers:         warp.NewSigners(indices...),
+			Signature:       signature.Bytes(),
+		}
+		signedMessageBytes, err := signedMessage.Bytes()
+		if err != nil {
+			panic(err)
+		}
+		predicate := make([]byte, 0)
+		for _, key := range warp.PackPredicate(signedMessageBytes) {
+			predicate = append(predicate, key.Bytes()...)
+		}
+		return predicate
+	}
+	// signMessage returns the packed predicate of [unsignedMessage] signed by the validators at [indices]
+	signMessage := func(indices ...int) []byte {
+		return signMessageBytes(unsignedMessageBytes, indices...)
+	}
+	expectedOutput, err := precompile.PackGetVerifiedWarpMessageOutput(unsignedMessage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, test := range map[string]test{
+		"send warp message": {
+			caller: senderAddr,
 			input: func() []byte {
-				input, err := precompile.PackSetFeeConfig(testFeeConfig)
+				input, err := precompile.PackSendWarpMessage(destinationChainID, destinationAddr, payload)
 				if err != nil {
 					panic(err)
 				}
 				return input
 			},
-			suppliedGas: precompile.SetFeeConfigGasCost,
-			readOnly:    true,
-			expectedErr: vmerrs.ErrWriteProtection.Error(),
+			suppliedGas: precompile.SendWarpMessageGasCost + uint64(len(payload))*precompile.SendWarpMessageGasCostPerByte,
+			readOnly:    false,
+			expectedRes: func() []byte {
+				// The message is sent from chain 1, the chain ID of the mock accessible state
+				message := *unsignedMessage
+				message.SourceChainID = common.BigToHash(common.Big1)
+				message.DestinationChainID = destinationChainID
+				messageBytes, err := message.Bytes()
+				if err != nil {
+					panic(err)
+				}
+				return crypto.Keccak256(messageBytes)
+			}(),
+			assertState: func(t *testing.T, state *state.StateDB) {
+				logs := state.Logs()
+				assert.Len(t, logs, 1)
+				log := logs[0]
+				assert.Equal(t, precompile.WarpMessengerAddress, log.Address)
+				assert.Equal(t, []common.Hash{precompile.SendWarpMessageEventID, destinationChainID, destinationAddr.Hash(), senderAddr.Hash()}, log.Topics)
+				messageBytes, err := precompile.UnpackSendWarpMessageEventData(log.Data)
+				assert.NoError(t, err)
+				message, err := warp.ParseUnsignedMessage(messageBytes)
+				assert.NoError(t, err)
+				assert.Equal(t, common.BigToHash(common.Big1), message.SourceChainID)
+				assert.Equal(t, senderAddr, message.SourceAddress)
+				assert.Equal(t, payload, message.Payload)
+			},
 		},
-		"insufficient gas setFeeConfig from admin": {
-			caller:         adminAddr,
-			precompileAddr: precompile.FeeConfigManagerAddress,
+		"send warp message readOnly": {
+			caller: senderAddr,
 			input: func() []byte {
-				input, err := precompile.PackSetFeeConfig(testFeeConfig)
+				input, err := precompile.PackSendWarpMessage(destinationChainID, destinationAddr, payload)
 				if err != nil {
 					panic(err)
 				}
 				return input
 			},
-			suppliedGas: precompile.SetFeeConfigGasCost - 1,
-			readOnly:    false,
-			expectedErr: vmerrs.ErrOutOfGas.Error(),
+			suppliedGas: precompile.SendWarpMessageGasCost + uint64(len(payload))*precompile.SendWarpMessageGasCostPerByte,
+			readOnly:    true,
+			expectedErr: vmerrs.ErrWriteProtection.Error(),
 		},
-		"set allow role from admin": {
-			caller:         adminAddr,
-			precompileAddr: precompile.FeeConfigManagerAddress,
+		"send warp message insufficient gas": {
+			caller: senderAddr,
 			input: func() []byte {
-				input, err := precompile.PackModifyAllowList(noRoleAddr, precompile.AllowListEnabled)
+				input, err := precompile.PackSendWarpMessage(destinationChainID, destinationAddr, payload)
 				if err != nil {
 					panic(err)
 				}
 				return input
 			},
-			suppliedGas: precompile.ModifyAllowListGasCost,
+			suppliedGas: precompile.SendWarpMessageGasCost,
 			readOnly:    false,
-			expectedRes: []byte{},
-			assertState: func(t *testing.T, state *state.StateDB) {
-				res := precompile.GetFeeConfigManagerStatus(state, adminAddr)
-				assert.Equal(t, precompile.AllowListAdmin, res)
-
-				res = precompile.GetFeeConfigManagerStatus(state, noRoleAddr)
-				assert.Equal(t, precompile.AllowListEnabled, res)
+			expectedErr: vmerrs.ErrOutOfGas.Error(),
+		},
+		"get verified warp message": {
+			caller:      destinationAddr,
+			input:       precompile.PackGetVerifiedWarpMessage,
+			predicate:   func() []byte { return signMessage(0, 1, 2) },
+			suppliedGas: precompile.GetVerifiedWarpMessageBaseGasCost + uint64(len(signMessage(0, 1, 2)))*precompile.GetVerifiedWarpMessageGasCostPerByte + 3*precompile.GetVerifiedWarpMessagePerValidatorGasCost,
+			readOnly:    true,
+			expectedRes: expectedOutput,
+		},
+		"get verified warp message with quorum": {
+			caller:      destinationAddr,
+			input:       precompile.PackGetVerifiedWarpMessage,
+			predicate:   func() []byte { return signMessage(0, 2) },
+			suppliedGas: precompile.GetVerifiedWarpMessageBaseGasCost + uint64(len(signMessage(0, 2)))*precompile.GetVerifiedWarpMessageGasCostPerByte + 3*precompile.GetVerifiedWarpMessagePerValidatorGasCost,
+			readOnly:    true,
+			expectedRes: expectedOutput,
+		},
+		"get verified warp message insufficient weight": {
+			caller:      destinationAddr,
+			input:       precompile.PackGetVerifiedWarpMessage,
+			predicate:   func() []byte { return signMessage(1) },
+			suppliedGas: precompile.GetVerifiedWarpMessageBaseGasCost + uint64(len(signMessage(1)))*precompile.GetVerifiedWarpMessageGasCostPerByte + 3*precompile.GetVerifiedWarpMessagePerValidatorGasCost,
+			readOnly:    true,
+			expectedErr: precompile.ErrInvalidWarpMessage.Error(),
+		},
+		"get verified warp message mismatched signers": {
+			caller: destinationAddr,
+			input:  precompile.PackGetVerifiedWarpMessage,
+			predicate: func() []byte {
+				// Claim that validator 2 signed the message instead of validator 1
+				predicate, err := warp.UnpackPredicate(signMessage(0, 1))
+				if err != nil {
+					panic(err)
+				}
+				signedMessage, err := warp.ParseSignedMessage(predicate)
+				if err != nil {
+					panic(err)
+				}
+				signedMessage.Signers = warp.NewSigners(0, 2)
+				signedMessageBytes, err := signedMessage.Bytes()
+				if err != nil {
+					panic(err)
+				}
+				packed := make([]byte, 0)
+				for _, key := range warp.PackPredicate(signedMessageBytes) {
+					packed = append(packed, key.Bytes()...)
+				}
+				return packed
 			},
+			suppliedGas: math.MaxUint64,
+			readOnly:    true,
+			expectedErr: precompile.ErrInvalidWarpMessage.Error(),
 		},
-		"set allow role from non-admin fails": {
-			caller:         allowAddr,
-			precompileAddr: precompile.FeeConfigManagerAddress,
-			input: func() []byte {
-				input, err := precompile.PackModifyAllowList(noRoleAddr, precompile.AllowListEnabled)
+		"get verified warp message sent to another chain": {
+			caller: destinationAddr,
+			input:  precompile.PackGetVerifiedWarpMessage,
+			predicate: func() []byte {
+				message := *unsignedMessage
+				message.DestinationChainID = common.BigToHash(big.NewInt(3))
+				messageBytes, err := message.Bytes()
 				if err != nil {
 					panic(err)
 				}
-				return input
+				return signMessageBytes(messageBytes, 0, 1, 2)
 			},
-			suppliedGas: precompile.ModifyAllowListGasCost,
-			readOnly:    false,
-			expectedErr: precompile.ErrCannotModifyAllowList.Error(),
+			suppliedGas: math.MaxUint64,
+			readOnly:    true,
+			expectedErr: precompile.ErrWrongWarpDestination.Error(),
+		},
+		"get verified warp message missing predicate": {
+			caller:      destinationAddr,
+			input:       precompile.PackGetVerifiedWarpMessage,
+			suppliedGas: precompile.GetVerifiedWarpMessageBaseGasCost,
+			readOnly:    true,
+			expectedErr: precompile.ErrMissingWarpMessage.Error(),
 		},
 	} {
 		t.Run(name, func(t *testing.T) {
@@ -1022,17 +2801,19 @@ func TestFeeConfigManagerRun(t *testing.T) {
 			if err != nil {
 				t.Fatal(err)
 			}
-			// Set up the state so that each address has the expected permissions at the start.
-			precompile.SetFeeConfigManagerStatus(state, adminAddr, precompile.AllowListAdmin)
-			precompile.SetFeeConfigManagerStatus(state, allowAddr, precompile.AllowListEnabled)
-			precompile.SetFeeConfigManagerStatus(state, noRoleAddr, precompile.AllowListNoRole)
-
-			if test.preCondition != nil {
-				test.preCondition(t, state)
+			var accessList types.AccessList
+			if test.predicate != nil {
+				predicate := test.predicate()
+				storageKeys := make([]common.Hash, 0, len(predicate)/common.HashLength)
+				for i := 0; i < len(predicate); i += common.HashLength {
+					storageKeys = append(storageKeys, common.BytesToHash(predicate[i:i+common.HashLength]))
+				}
+				accessList = types.AccessList{{Address: precompile.WarpMessengerAddress, StorageKeys: storageKeys}}
 			}
+			state.PrepareAccessList(test.caller, &precompile.WarpMessengerAddress, nil, accessList)
 
 			blockContext := &mockBlockContext{blockNumber: testBlockNumber}
-			ret, remainingGas, err := precompile.FeeConfigManagerPrecompile.Run(&mockAccessibleState{state: state, blockContext: blockContext}, test.caller, test.precompileAddr, test.input(), test.suppliedGas, test.readOnly)
+			ret, remainingGas, err := contract.Run(&mockAccessibleState{state: state, blockContext: blockContext}, test.caller, precompile.WarpMessengerAddress, test.input(), test.suppliedGas, test.readOnly)
 			if len(test.expectedErr) != 0 {
 				if err == nil {
 					assert.Failf(t, "run expectedly passed without error", "expected error %q", test.expectedErr)
@@ -1049,7 +2830,9 @@ func TestFeeConfigManagerRun(t *testing.T) {
 			assert.Equal(t, uint64(0), remainingGas)
 			assert.Equal(t, test.expectedRes, ret)
 
-			test.assertState(t, state)
+			if test.assertState != nil {
+				test.assertState(t, state)
+			}
 		})
 	}
 }
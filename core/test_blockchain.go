@@ -1546,8 +1546,10 @@ func TestStatefulPrecompiles(t *testing.T, create func(db ethdb.Database, chainC
 	genesisBalance := new(big.Int).Mul(big.NewInt(1000000), big.NewInt(params.Ether))
 	config := *params.TestChainConfig
 	// Set all of the required config parameters
-	config.ContractDeployerAllowListConfig = precompile.NewContractDeployerAllowListConfig(big.NewInt(0), []common.Address{addr1})
-	config.FeeManagerConfig = precompile.NewFeeManagerConfig(big.NewInt(0), []common.Address{addr1})
+	config.GenesisPrecompiles = params.Precompiles{
+		precompile.ContractDeployerAllowListConfigKey: precompile.NewContractDeployerAllowListConfig(big.NewInt(0), []common.Address{addr1}),
+		precompile.FeeConfigManagerConfigKey:          precompile.NewFeeManagerConfig(big.NewInt(0), []common.Address{addr1}),
+	}
 	gspec := &Genesis{
 		Config: &config,
 		Alloc:  GenesisAlloc{addr1: {Balance: genesisBalance}},
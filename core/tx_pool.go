@@ -183,6 +183,8 @@ type TxPoolConfig struct {
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
+
+	Policies []TxPoolPolicy // Node-local admission policies checked after the other validations
 }
 
 // DefaultTxPoolConfig contains the default configurations for the transaction
@@ -297,7 +299,10 @@ type TxPool struct {
 	wg         sync.WaitGroup // tracks loop, scheduleReorgLoop
 	initDoneCh chan struct{}  // is closed once the pool is initialized (for tests)
 
-	changesSinceReorg int // A counter for how many drops we've performed in-between reorg.
+	changesSinceReorg int  // A counter for how many drops we've performed in-between reorg.
+	reinjecting       bool // Whether the transactions discarded by a reorg are being reinjected
+
+	policyRejectMeters []metrics.Meter // Rejection meters of the admission policies, in the order of config.Policies
 }
 
 type txpoolResetRequest struct {
@@ -335,6 +340,10 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		log.Info("Setting new local account", "address", addr)
 		pool.locals.add(addr)
 	}
+	for _, policy := range config.Policies {
+		log.Info("Enabling txpool policy", "policy", policy.Name())
+		pool.policyRejectMeters = append(pool.policyRejectMeters, metrics.GetOrRegisterMeter("txpool/policy/"+policy.Name()+"/rejected", nil))
+	}
 	pool.priced = newTxPricedList(pool.all)
 	pool.reset(nil, chain.CurrentBlock().Header())
 
@@ -684,7 +693,7 @@ func (pool *TxPool) checkTxState(from common.Address, tx *types.Transaction) err
 
 	// If the tx allow list is enabled, return an error if the from address is not allow listed.
 	headTimestamp := big.NewInt(int64(pool.currentHead.Time))
-	if pool.chainconfig.IsTxAllowList(headTimestamp) {
+	if pool.chainconfig.IsPrecompileEnabled(precompile.TxAllowListAddress, headTimestamp) {
 		txAllowListRole := precompile.GetTxAllowListStatus(pool.currentState, from)
 		if !txAllowListRole.IsEnabled() {
 			return fmt.Errorf("%w: %s", precompile.ErrSenderAddressNotAllowListed, from)
@@ -758,6 +767,26 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	return nil
 }
 
+// checkPolicies checks whether a valid transaction is admitted by the node-local
+// admission policies.
+func (pool *TxPool) checkPolicies(from common.Address, tx *types.Transaction) error {
+	for i, policy := range pool.config.Policies {
+		if err := policy.Check(from, tx); err != nil {
+			pool.policyRejectMeters[i].Mark(1)
+			return fmt.Errorf("%w %s: %v", ErrTxPoolPolicy, policy.Name(), err)
+		}
+	}
+	return nil
+}
+
+// admitPolicies accounts for a transaction inserted into the pool in the
+// node-local admission policies.
+func (pool *TxPool) admitPolicies(from common.Address, tx *types.Transaction) {
+	for _, policy := range pool.config.Policies {
+		policy.Admitted(from, tx)
+	}
+}
+
 // add validates a transaction and inserts it into the non-executable queue for later
 // pending promotion and execution. If the transaction is a replacement for an already
 // pending or queued one, it overwrites the previous transaction if its price is higher.
@@ -765,6 +794,9 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 // If a newly added transaction is marked as local, its sending account will be
 // be added to the allowlist, preventing any associated transaction from being dropped
 // out of the pool due to pricing constraints.
+//
+// Transactions reinjected after a reorg were already admitted once, so they are
+// not subject to the admission policies.
 func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err error) {
 	// If the transaction is already known, discard it
 	hash := tx.Hash()
@@ -783,6 +815,16 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		invalidTxMeter.Mark(1)
 		return false, err
 	}
+	// Ensure the transaction is admitted by the node-local policies. The policies
+	// are checked after the other validations and only account for the transaction
+	// once it is inserted into the pool.
+	from, _ := types.Sender(pool.signer, tx) // already validated
+	if !pool.reinjecting {
+		if err := pool.checkPolicies(from, tx); err != nil {
+			log.Trace("Discarding transaction rejected by txpool policy", "hash", hash, "err", err)
+			return false, err
+		}
+	}
 	// If the transaction pool is full, discard underpriced transactions
 	if uint64(pool.all.Slots()+numSlots(tx)) > pool.config.GlobalSlots+pool.config.GlobalQueue {
 		// If the new transaction is underpriced, don't accept it
@@ -821,7 +863,6 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		}
 	}
 	// Try to replace an existing transaction in the pending pool
-	from, _ := types.Sender(pool.signer, tx) // already validated
 	if list := pool.pending[from]; list != nil && list.Overlaps(tx) {
 		// Nonce already pending, check if required price bump is met
 		inserted, old := list.Add(tx, pool.config.PriceBump)
@@ -839,6 +880,9 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		pool.priced.Put(tx, isLocal)
 		pool.journalTx(from, tx)
 		pool.queueTxEvent(tx)
+		if !pool.reinjecting {
+			pool.admitPolicies(from, tx)
+		}
 		log.Trace("Pooled new executable transaction", "hash", hash, "from", from, "to", tx.To())
 
 		// Successful promotion, bump the heartbeat
@@ -860,6 +904,9 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		localGauge.Inc(1)
 	}
 	pool.journalTx(from, tx)
+	if !pool.reinjecting {
+		pool.admitPolicies(from, tx)
+	}
 
 	log.Trace("Pooled new future transaction", "hash", hash, "from", from, "to", tx.To())
 	return replaced, nil
@@ -1424,7 +1471,7 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 
 	// when we reset txPool we should explicitly check if fee struct for min base fee has changed
 	// so that we can correctly drop txs with < minBaseFee from tx pool.
-	if pool.chainconfig.IsFeeConfigManager(new(big.Int).SetUint64(newHead.Time)) {
+	if pool.chainconfig.IsPrecompileEnabled(precompile.FeeConfigManagerAddress, new(big.Int).SetUint64(newHead.Time)) {
 		feeConfig, _, err := pool.chain.GetFeeConfigAt(newHead)
 		if err != nil {
 			log.Error("Failed to get fee config state", "err", err, "root", newHead.Root)
@@ -1436,7 +1483,9 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	// Inject any transactions discarded due to reorgs
 	log.Debug("Reinjecting stale transactions", "count", len(reinject))
 	pool.chain.SenderCacher().Recover(pool.signer, reinject)
+	pool.reinjecting = true
 	pool.addTxsLocked(reinject, false)
+	pool.reinjecting = false
 
 	// Update all fork indicator by next pending block number.
 	next := new(big.Int).Add(newHead.Number, big.NewInt(1))
@@ -0,0 +1,225 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	SenderRateLimitPolicyName      = "sender-rate-limit"
+	SenderGasLimitPolicyName       = "sender-gas-limit"
+	ContractCallDenylistPolicyName = "contract-call-denylist"
+	DestinationMinTipPolicyName    = "destination-min-tip"
+)
+
+// ErrTxPoolPolicy is returned if a transaction is rejected by one of the
+// admission policies configured for the transaction pool.
+var ErrTxPoolPolicy = errors.New("transaction rejected by txpool policy")
+
+var (
+	_ TxPoolPolicy = &senderWindowPolicy{}
+	_ TxPoolPolicy = &contractCallDenylistPolicy{}
+	_ TxPoolPolicy = &destinationMinTipPolicy{}
+)
+
+// TxPoolPolicy is a node-local admission policy of the transaction pool.
+// Policies are not part of consensus: a transaction rejected by the policies
+// of this node may still be included in a block built by another node.
+type TxPoolPolicy interface {
+	// Name identifies the policy in the errors and metrics of the pool.
+	Name() string
+
+	// Check returns an error describing why [tx] sent by [from] must not be
+	// admitted to the pool. Check is only called for transactions that passed
+	// every other validation of the pool and does not account for [tx], since
+	// the pool may still reject it.
+	// Check is called with the lock of the pool held.
+	Check(from common.Address, tx *types.Transaction) error
+
+	// Admitted is called once [tx] sent by [from] is inserted into the pool,
+	// so that stateful policies account for it.
+	// Transactions reinjected after a reorg are neither checked nor accounted.
+	// Admitted is called with the lock of the pool held.
+	Admitted(from common.Address, tx *types.Transaction)
+}
+
+// senderWindow is the amount accumulated by a sender since [start].
+type senderWindow struct {
+	start  time.Time
+	amount uint64
+}
+
+// senderWindowPolicy limits the amount accumulated by the transactions of
+// each sender over fixed windows of [window].
+type senderWindowPolicy struct {
+	name   string
+	unit   string
+	limit  uint64
+	window time.Duration
+	amount func(tx *types.Transaction) uint64
+
+	windows   map[common.Address]*senderWindow
+	lastPrune time.Time
+	clock     mockable.Clock
+}
+
+// NewSenderRateLimitPolicy returns a policy that admits at most [maxTxs]
+// transactions from each sender per [window].
+func NewSenderRateLimitPolicy(maxTxs uint64, window time.Duration) TxPoolPolicy {
+	return newSenderWindowPolicy(SenderRateLimitPolicyName, "transactions", maxTxs, window, func(*types.Transaction) uint64 {
+		return 1
+	})
+}
+
+// NewSenderGasLimitPolicy returns a policy that admits transactions from each
+// sender up to a total gas limit of [maxGas] per [window].
+func NewSenderGasLimitPolicy(maxGas uint64, window time.Duration) TxPoolPolicy {
+	return newSenderWindowPolicy(SenderGasLimitPolicyName, "gas", maxGas, window, func(tx *types.Transaction) uint64 {
+		return tx.Gas()
+	})
+}
+
+func newSenderWindowPolicy(name, unit string, limit uint64, window time.Duration, amount func(tx *types.Transaction) uint64) *senderWindowPolicy {
+	return &senderWindowPolicy{
+		name:    name,
+		unit:    unit,
+		limit:   limit,
+		window:  window,
+		amount:  amount,
+		windows: make(map[common.Address]*senderWindow),
+	}
+}
+
+func (p *senderWindowPolicy) Name() string { return p.name }
+
+func (p *senderWindowPolicy) Check(from common.Address, tx *types.Transaction) error {
+	var used uint64
+	if w := p.currentWindow(from, p.clock.Time()); w != nil {
+		used = w.amount
+	}
+	amount := p.amount(tx)
+	if used+amount > p.limit || used+amount < used {
+		return fmt.Errorf("address %s exceeded %d %s per %s", from.Hex(), p.limit, p.unit, p.window)
+	}
+	return nil
+}
+
+func (p *senderWindowPolicy) Admitted(from common.Address, tx *types.Transaction) {
+	now := p.clock.Time()
+	p.prune(now)
+
+	w := p.currentWindow(from, now)
+	if w == nil {
+		w = &senderWindow{start: now}
+		p.windows[from] = w
+	}
+	amount := p.amount(tx)
+	if w.amount+amount < w.amount {
+		w.amount = math.MaxUint64
+		return
+	}
+	w.amount += amount
+}
+
+// currentWindow returns the window of [from] at [now], or nil if the sender
+// has no window or its window expired.
+func (p *senderWindowPolicy) currentWindow(from common.Address, now time.Time) *senderWindow {
+	w, exists := p.windows[from]
+	if !exists || now.Sub(w.start) >= p.window {
+		return nil
+	}
+	return w
+}
+
+// prune removes the expired windows once per [window], so that the senders
+// that stopped sending transactions are not tracked forever.
+func (p *senderWindowPolicy) prune(now time.Time) {
+	if now.Sub(p.lastPrune) < p.window {
+		return
+	}
+	p.lastPrune = now
+	for sender, w := range p.windows {
+		if now.Sub(w.start) >= p.window {
+			delete(p.windows, sender)
+		}
+	}
+}
+
+// contractCallDenylistPolicy rejects the calls to denied contracts.
+type contractCallDenylistPolicy struct {
+	denied map[common.Address][][4]byte
+}
+
+// NewContractCallDenylistPolicy returns a policy that rejects the transactions
+// calling the contracts in [denied] with one of the listed 4-byte selectors.
+// Every transaction sent to a contract without selectors is rejected.
+func NewContractCallDenylistPolicy(denied map[common.Address][][4]byte) TxPoolPolicy {
+	return &contractCallDenylistPolicy{denied: denied}
+}
+
+func (p *contractCallDenylistPolicy) Name() string { return ContractCallDenylistPolicyName }
+
+func (p *contractCallDenylistPolicy) Check(from common.Address, tx *types.Transaction) error {
+	to := tx.To()
+	if to == nil {
+		return nil
+	}
+	selectors, denied := p.denied[*to]
+	if !denied {
+		return nil
+	}
+	if len(selectors) == 0 {
+		return fmt.Errorf("calls to %s are denied", to.Hex())
+	}
+	data := tx.Data()
+	if len(data) < 4 {
+		return nil
+	}
+	for _, selector := range selectors {
+		if bytes.Equal(data[:4], selector[:]) {
+			return fmt.Errorf("calls to %s with selector %#x are denied", to.Hex(), selector)
+		}
+	}
+	return nil
+}
+
+func (p *contractCallDenylistPolicy) Admitted(common.Address, *types.Transaction) {}
+
+// destinationMinTipPolicy enforces a minimum gas tip cap per destination.
+type destinationMinTipPolicy struct {
+	minTips map[common.Address]*big.Int
+}
+
+// NewDestinationMinTipPolicy returns a policy that rejects the transactions
+// sent to the addresses in [minTips] with a gas tip cap below the minimum tip
+// of the address.
+func NewDestinationMinTipPolicy(minTips map[common.Address]*big.Int) TxPoolPolicy {
+	return &destinationMinTipPolicy{minTips: minTips}
+}
+
+func (p *destinationMinTipPolicy) Name() string { return DestinationMinTipPolicyName }
+
+func (p *destinationMinTipPolicy) Check(from common.Address, tx *types.Transaction) error {
+	to := tx.To()
+	if to == nil {
+		return nil
+	}
+	minTip, exists := p.minTips[*to]
+	if !exists || tx.GasTipCapIntCmp(minTip) >= 0 {
+		return nil
+	}
+	return fmt.Errorf("gas tip cap (%d) < minimum tip (%d) of destination %s", tx.GasTipCap(), minTip, to.Hex())
+}
+
+func (p *destinationMinTipPolicy) Admitted(common.Address, *types.Transaction) {}
@@ -0,0 +1,160 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/subnet-evm/core/rawdb"
+	"github.com/ava-labs/subnet-evm/core/state"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func policyTestTx(to *common.Address, gas uint64, tip int64, data []byte) *types.Transaction {
+	return types.NewTx(&types.DynamicFeeTx{
+		To:        to,
+		Gas:       gas,
+		GasFeeCap: big.NewInt(1_000_000),
+		GasTipCap: big.NewInt(tip),
+		Data:      data,
+	})
+}
+
+// admit checks [tx] against [policy] and accounts for it if it is admitted,
+// as the pool does once the transaction is inserted.
+func admit(policy TxPoolPolicy, from common.Address, tx *types.Transaction) error {
+	if err := policy.Check(from, tx); err != nil {
+		return err
+	}
+	policy.Admitted(from, tx)
+	return nil
+}
+
+func TestSenderWindowPolicies(t *testing.T) {
+	var (
+		sender = common.Address{1}
+		other  = common.Address{2}
+		to     = common.Address{3}
+	)
+	rateLimit := NewSenderRateLimitPolicy(2, time.Minute).(*senderWindowPolicy)
+	rateLimit.clock.Set(time.Unix(1000, 0))
+	// A checked transaction only counts against the sender once it is admitted
+	assert.NoError(t, rateLimit.Check(sender, policyTestTx(&to, 21000, 0, nil)))
+	assert.NoError(t, rateLimit.Check(sender, policyTestTx(&to, 21000, 0, nil)))
+	assert.NoError(t, rateLimit.Check(sender, policyTestTx(&to, 21000, 0, nil)))
+	assert.NoError(t, admit(rateLimit, sender, policyTestTx(&to, 21000, 0, nil)))
+	assert.NoError(t, admit(rateLimit, sender, policyTestTx(&to, 21000, 0, nil)))
+	assert.Error(t, admit(rateLimit, sender, policyTestTx(&to, 21000, 0, nil)))
+	assert.NoError(t, admit(rateLimit, other, policyTestTx(&to, 21000, 0, nil)))
+
+	// The limit is reset by the next window, and the expired windows are pruned
+	rateLimit.clock.Set(rateLimit.clock.Time().Add(time.Minute))
+	assert.NoError(t, admit(rateLimit, sender, policyTestTx(&to, 21000, 0, nil)))
+	assert.Len(t, rateLimit.windows, 1)
+
+	gasLimit := NewSenderGasLimitPolicy(50_000, time.Minute).(*senderWindowPolicy)
+	gasLimit.clock.Set(time.Unix(1000, 0))
+	assert.NoError(t, admit(gasLimit, sender, policyTestTx(&to, 30_000, 0, nil)))
+	assert.Error(t, admit(gasLimit, sender, policyTestTx(&to, 30_000, 0, nil)))
+	// A rejected transaction does not count against the sender
+	assert.NoError(t, admit(gasLimit, sender, policyTestTx(&to, 20_000, 0, nil)))
+	assert.Error(t, admit(gasLimit, other, policyTestTx(&to, 60_000, 0, nil)))
+}
+
+func TestContractCallDenylistPolicy(t *testing.T) {
+	var (
+		sender      = common.Address{1}
+		denied      = common.Address{2}
+		partial     = common.Address{3}
+		allowed     = common.Address{4}
+		transfer    = []byte{0xa9, 0x05, 0x9c, 0xbb}
+		approve     = []byte{0x09, 0x5e, 0xa7, 0xb3}
+		transferArg = append(append([]byte{}, transfer...), 1, 2, 3)
+	)
+	policy := NewContractCallDenylistPolicy(map[common.Address][][4]byte{
+		denied:  nil,
+		partial: {{0xa9, 0x05, 0x9c, 0xbb}},
+	})
+
+	tests := map[string]struct {
+		to          *common.Address
+		data        []byte
+		expectedErr bool
+	}{
+		"contract creation":           {to: nil, data: transfer},
+		"allowed contract":            {to: &allowed, data: transfer},
+		"denied contract":             {to: &denied, expectedErr: true},
+		"denied selector":             {to: &partial, data: transferArg, expectedErr: true},
+		"allowed selector":            {to: &partial, data: approve},
+		"call without selector":       {to: &partial, data: []byte{0xa9}},
+		"transfer to partial allowed": {to: &partial},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := policy.Check(sender, policyTestTx(test.to, 50_000, 0, test.data))
+			if test.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDestinationMinTipPolicy(t *testing.T) {
+	var (
+		sender = common.Address{1}
+		costly = common.Address{2}
+		other  = common.Address{3}
+	)
+	policy := NewDestinationMinTipPolicy(map[common.Address]*big.Int{costly: big.NewInt(100)})
+
+	assert.Error(t, policy.Check(sender, policyTestTx(&costly, 21000, 99, nil)))
+	assert.NoError(t, policy.Check(sender, policyTestTx(&costly, 21000, 100, nil)))
+	assert.NoError(t, policy.Check(sender, policyTestTx(&other, 21000, 0, nil)))
+	assert.NoError(t, policy.Check(sender, policyTestTx(nil, 21000, 0, nil)))
+}
+
+func TestTxPoolPolicyRejection(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := newTestBlockchain(statedb, 10000000, new(event.Feed))
+
+	config := testTxPoolConfig
+	config.Policies = []TxPoolPolicy{NewSenderRateLimitPolicy(2, time.Hour)}
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+	<-pool.initDoneCh
+
+	key, _ := crypto.GenerateKey()
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(0xffffffffffffff))
+
+	// Transactions rejected by the other validations or by the pool itself do not count against the sender
+	assert.ErrorIs(t, pool.AddRemote(transaction(0, 100, key)), ErrIntrinsicGas)
+	assert.NoError(t, pool.AddRemote(transaction(0, 100000, key)))
+	assert.ErrorIs(t, pool.AddRemote(transaction(0, 100001, key)), ErrReplaceUnderpriced)
+	assert.NoError(t, pool.AddRemote(transaction(1, 100000, key)))
+
+	err := pool.AddRemote(transaction(2, 100000, key))
+	assert.ErrorIs(t, err, ErrTxPoolPolicy)
+	assert.Contains(t, err.Error(), SenderRateLimitPolicyName)
+	assert.Equal(t, 2, pool.all.Count())
+
+	// Transactions reinjected after a reorg are not subject to the policies
+	pool.mu.Lock()
+	pool.reinjecting = true
+	errs, _ := pool.addTxsLocked([]*types.Transaction{transaction(2, 100000, key)}, false)
+	pool.reinjecting = false
+	pool.mu.Unlock()
+	assert.NoError(t, errs[0])
+	assert.Equal(t, 3, pool.all.Count())
+}
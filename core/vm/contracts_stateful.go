@@ -25,6 +25,19 @@ func (w *wrappedPrecompiledContract) Run(accessibleState precompile.PrecompileAc
 	return RunPrecompiledContract(w.p, input, suppliedGas)
 }
 
+// precompileLoggingStateDB wraps the StateDB accessed by stateful precompiles to report their storage
+// writes to [logger].
+type precompileLoggingStateDB struct {
+	StateDB
+	logger PrecompileStateLogger
+}
+
+// SetState reports the write of [value] to [key] of [addr] to the logger before applying it.
+func (s *precompileLoggingStateDB) SetState(addr common.Address, key common.Hash, value common.Hash) {
+	s.logger.CapturePrecompileStateWrite(addr, key, s.StateDB.GetState(addr, key), value)
+	s.StateDB.SetState(addr, key, value)
+}
+
 // RunStatefulPrecompiledContract confirms runs [precompile] with the specified parameters.
 func RunStatefulPrecompiledContract(precompile precompile.StatefulPrecompiledContract, accessibleState precompile.PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
 	return precompile.Run(accessibleState, caller, addr, input, suppliedGas, readOnly)
@@ -201,7 +201,12 @@ func (evm *EVM) Cancelled() bool {
 }
 
 // GetStateDB returns the evm's StateDB
+// If the tracer of the evm implements PrecompileStateLogger, the returned StateDB reports the storage
+// writes made through it to the tracer.
 func (evm *EVM) GetStateDB() precompile.StateDB {
+	if logger, ok := evm.Config.Tracer.(PrecompileStateLogger); ok && evm.Config.Debug {
+		return &precompileLoggingStateDB{StateDB: evm.StateDB, logger: logger}
+	}
 	return evm.StateDB
 }
 
@@ -210,6 +215,16 @@ func (evm *EVM) GetBlockContext() precompile.BlockContext {
 	return &evm.Context
 }
 
+// GetChainID returns the evm's chain ID
+func (evm *EVM) GetChainID() *big.Int {
+	return evm.chainConfig.ChainID
+}
+
+// GetChainConfig returns the evm's chain config
+func (evm *EVM) GetChainConfig() precompile.ChainConfig {
+	return evm.chainConfig
+}
+
 // Interpreter returns the current interpreter
 func (evm *EVM) Interpreter() *EVMInterpreter {
 	return evm.interpreter
@@ -496,7 +511,7 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 		return nil, common.Address{}, 0, vmerrs.ErrContractAddressCollision
 	}
 	// If the allow list is enabled, check that [evm.TxContext.Origin] has permission to deploy a contract.
-	if evm.chainRules.IsContractDeployerAllowListEnabled {
+	if evm.chainRules.IsPrecompileEnabled(precompile.ContractDeployerAllowListAddress) {
 		allowListRole := precompile.GetContractDeployerAllowListStatus(evm.StateDB, evm.TxContext.Origin)
 		if !allowListRole.IsEnabled() {
 			return nil, common.Address{}, 0, fmt.Errorf("tx.origin %s is not authorized to deploy a contract", evm.TxContext.Origin)
@@ -76,11 +76,14 @@ type StateDB interface {
 	// AddSlotToAccessList adds the given (address,slot) to the access list. This operation is safe to perform
 	// even if the feature/fork is not active yet
 	AddSlotToAccessList(addr common.Address, slot common.Hash)
+	// GetPredicateStorageSlots returns the storage keys of the access list tuple for [address]
+	// in the current transaction, which are used as predicates by stateful precompiles.
+	GetPredicateStorageSlots(address common.Address) ([]byte, bool)
 
 	RevertToSnapshot(int)
 	Snapshot() int
 
-	AddLog(*types.Log)
+	AddLog(addr common.Address, topics []common.Hash, data []byte, blockNumber uint64)
 	AddPreimage(common.Hash, []byte)
 
 	ForEachStorage(common.Address, func(common.Hash, common.Hash) bool) error
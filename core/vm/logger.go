@@ -46,3 +46,10 @@ type EVMLogger interface {
 	CaptureFault(pc uint64, op OpCode, gas, cost uint64, scope *ScopeContext, depth int, err error)
 	CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error)
 }
+
+// PrecompileStateLogger can optionally be implemented by an EVMLogger to be notified of the
+// storage writes made by stateful precompiles, which are not visible through the opcodes
+// executed by the EVM. [prev] is the value of [key] before it is set to [value].
+type PrecompileStateLogger interface {
+	CapturePrecompileStateWrite(addr common.Address, key common.Hash, prev common.Hash, value common.Hash)
+}
@@ -37,6 +37,8 @@ import (
 
 	"github.com/ava-labs/subnet-evm/core/vm"
 	"github.com/ava-labs/subnet-evm/eth/tracers"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile"
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -55,10 +57,13 @@ type callFrame struct {
 	Output  string      `json:"output,omitempty"`
 	Error   string      `json:"error,omitempty"`
 	Calls   []callFrame `json:"calls,omitempty"`
+	// Precompile is the decoded call if the frame calls an enabled stateful precompile.
+	Precompile *precompileCall `json:"precompile,omitempty"`
 }
 
 type callTracer struct {
 	env       *vm.EVM
+	rules     params.Rules // Rules of the traced block, which determine the enabled stateful precompiles
 	callstack []callFrame
 	interrupt uint32 // Atomic flag to signal execution interruption
 	reason    error  // Textual reason for the interruption
@@ -86,11 +91,18 @@ func (t *callTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Ad
 	if create {
 		t.callstack[0].Type = "CREATE"
 	}
+	t.rules = env.ChainConfig().AvalancheRules(env.Context.BlockNumber, env.Context.Time)
+	if !create {
+		t.callstack[0].Precompile = t.newPrecompileCall(from, to, input)
+	}
 }
 
 // CaptureEnd is called after the call finishes to finalize the tracing.
 func (t *callTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) {
 	t.callstack[0].GasUsed = uintToHex(gasUsed)
+	if t.callstack[0].Precompile != nil {
+		t.callstack[0].Precompile.captureExit(t.env.StateDB, output, err)
+	}
 	if err != nil {
 		t.callstack[0].Error = err.Error()
 		if err.Error() == "execution reverted" && len(output) > 0 {
@@ -125,6 +137,9 @@ func (t *callTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.
 		Gas:   uintToHex(gas),
 		Value: bigToHex(value),
 	}
+	if typ != vm.CREATE && typ != vm.CREATE2 && typ != vm.SELFDESTRUCT {
+		call.Precompile = t.newPrecompileCall(from, to, input)
+	}
 	t.callstack = append(t.callstack, call)
 }
 
@@ -141,6 +156,9 @@ func (t *callTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
 	size -= 1
 
 	call.GasUsed = uintToHex(gasUsed)
+	if call.Precompile != nil {
+		call.Precompile.captureExit(t.env.StateDB, output, err)
+	}
 	if err == nil {
 		call.Output = bytesToHex(output)
 	} else {
@@ -152,6 +170,27 @@ func (t *callTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
 	t.callstack[size-1].Calls = append(t.callstack[size-1].Calls, call)
 }
 
+// CapturePrecompileStateWrite implements the PrecompileStateLogger interface to record the storage
+// writes of the stateful precompile called by the current call frame.
+func (t *callTracer) CapturePrecompileStateWrite(addr common.Address, key common.Hash, prev common.Hash, value common.Hash) {
+	if call := t.callstack[len(t.callstack)-1]; call.Precompile != nil {
+		call.Precompile.captureStateWrite(addr, key, prev, value)
+	}
+}
+
+// newPrecompileCall returns the decoded call with [input] from [from] to [to] if [to] is an enabled
+// stateful precompile, or nil otherwise.
+func (t *callTracer) newPrecompileCall(from common.Address, to common.Address, input []byte) *precompileCall {
+	if !t.rules.IsPrecompileEnabled(to) {
+		return nil
+	}
+	module, ok := precompile.GetModuleByAddress(to)
+	if !ok {
+		return nil
+	}
+	return newPrecompileCall(t.env.StateDB, module, from, input)
+}
+
 // GetResult returns the json-encoded nested list of call traces, and any
 // error arising from the encoding or forceful termination (via `Stop`).
 func (t *callTracer) GetResult() (json.RawMessage, error) {
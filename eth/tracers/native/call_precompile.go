@@ -0,0 +1,243 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package native
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/accounts/abi"
+	"github.com/ava-labs/subnet-evm/commontype"
+	"github.com/ava-labs/subnet-evm/core/vm"
+	"github.com/ava-labs/subnet-evm/precompile"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// precompileCall describes a call to a stateful precompile, decoded according to the ABI of the
+// precompile, along with the effects of the call on the state of the precompile.
+type precompileCall struct {
+	Name        string                 `json:"name"`
+	Method      string                 `json:"method,omitempty"`
+	Args        map[string]interface{} `json:"args,omitempty"`
+	Outputs     map[string]interface{} `json:"outputs,omitempty"`
+	DecodeError string                 `json:"decodeError,omitempty"`
+	// CallerRole is the allow list role of the caller when the precompile is wired to an allow list.
+	CallerRole  string       `json:"callerRole,omitempty"`
+	RoleChanges []roleChange `json:"roleChanges,omitempty"`
+	// MintedAmount is the amount of native coin minted by a successful call to mintNativeCoin.
+	MintedAmount string `json:"mintedAmount,omitempty"`
+	// FeeConfigDiff contains the fields of the stored fee config that are set or scheduled
+	// to be set to a different value by a call to setFeeConfig or setFeeConfigAt.
+	FeeConfigDiff map[string]feeConfigChange `json:"feeConfigDiff,omitempty"`
+	StateWrites   []stateWrite               `json:"stateWrites,omitempty"`
+
+	addr      common.Address
+	method    *abi.Method
+	targets   []common.Address           // addresses whose allow list role is modified by the call
+	prevRoles []precompile.AllowListRole // roles of [targets] before the call
+}
+
+type roleChange struct {
+	Address string `json:"address"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+}
+
+type feeConfigChange struct {
+	From json.RawMessage `json:"from"`
+	To   json.RawMessage `json:"to"`
+}
+
+type stateWrite struct {
+	Key  string `json:"key"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// allowListRoleSetters are the allow list functions that modify the role of the addresses
+// passed to them.
+var allowListRoleSetters = map[string]bool{
+	"setAdmin":        true,
+	"setEnabled":      true,
+	"setNone":         true,
+	"setManager":      true,
+	"batchSetAdmin":   true,
+	"batchSetEnabled": true,
+	"batchSetNone":    true,
+}
+
+// newPrecompileCall decodes the call with [input] from [caller] to the stateful precompile registered
+// as [module] and records the state of the precompile that the call may modify.
+func newPrecompileCall(state vm.StateDB, module precompile.Module, caller common.Address, input []byte) *precompileCall {
+	call := &precompileCall{Name: module.ConfigKey, addr: module.Address}
+	if len(input) == 0 {
+		return call
+	}
+	if _, ok := module.ABI.Methods["readAllowList"]; ok {
+		call.CallerRole = precompile.GetAllowListStatus(state, module.Address, caller).String()
+	}
+	method, err := module.ABI.MethodById(input)
+	if err != nil {
+		call.DecodeError = err.Error()
+		return call
+	}
+	call.method = method
+	call.Method = method.Name
+	args, err := precompile.UnpackInputStrict(*method, input[4:])
+	if err != nil {
+		call.DecodeError = err.Error()
+		return call
+	}
+	call.Args = formatABIArguments(method.Inputs, args)
+
+	switch {
+	case allowListRoleSetters[method.Name]:
+		switch targets := args[0].(type) {
+		case common.Address:
+			call.targets = []common.Address{targets}
+		case []common.Address:
+			call.targets = targets
+		}
+		for _, target := range call.targets {
+			call.prevRoles = append(call.prevRoles, precompile.GetAllowListStatus(state, module.Address, target))
+		}
+	case module.Address == precompile.FeeConfigManagerAddress && method.Name == "setFeeConfig":
+		if feeConfig, err := precompile.UnpackFeeConfigInput(input[4:]); err == nil {
+			call.FeeConfigDiff = diffFeeConfigs(precompile.GetStoredFeeConfig(state), feeConfig)
+		}
+	case module.Address == precompile.FeeConfigManagerAddress && method.Name == "setFeeConfigAt":
+		if feeConfig, _, err := precompile.UnpackPendingFeeConfig(input[4:]); err == nil {
+			call.FeeConfigDiff = diffFeeConfigs(precompile.GetStoredFeeConfig(state), feeConfig)
+		}
+	}
+	return call
+}
+
+// captureExit records the [output] of the call and the changes it made to the state of the precompile.
+// If the call failed with [err], its state changes are reverted, so only the decoded input is kept.
+func (c *precompileCall) captureExit(state vm.StateDB, output []byte, err error) {
+	if err != nil || c.method == nil {
+		return
+	}
+	if len(c.method.Outputs) > 0 {
+		if outputs, err := c.method.Outputs.Unpack(output); err == nil {
+			c.Outputs = formatABIArguments(c.method.Outputs, outputs)
+		}
+	}
+	for i, target := range c.targets {
+		role := precompile.GetAllowListStatus(state, c.addr, target)
+		if role == c.prevRoles[i] {
+			continue
+		}
+		c.RoleChanges = append(c.RoleChanges, roleChange{
+			Address: addrToHex(target),
+			From:    c.prevRoles[i].String(),
+			To:      role.String(),
+		})
+	}
+	if c.addr == precompile.ContractNativeMinterAddress && c.method.Name == "mintNativeCoin" {
+		c.MintedAmount = c.Args["amount"].(string)
+	}
+}
+
+// captureStateWrite records the write of [value] to [key] of [addr] if it is made to the storage of the precompile.
+func (c *precompileCall) captureStateWrite(addr common.Address, key common.Hash, prev common.Hash, value common.Hash) {
+	if addr != c.addr {
+		return
+	}
+	c.StateWrites = append(c.StateWrites, stateWrite{
+		Key:  key.Hex(),
+		From: prev.Hex(),
+		To:   value.Hex(),
+	})
+}
+
+// diffFeeConfigs returns the JSON fields of [from] and [to] that have different values.
+func diffFeeConfigs(from commontype.FeeConfig, to commontype.FeeConfig) map[string]feeConfigChange {
+	fromFields, err := feeConfigFields(from)
+	if err != nil {
+		return nil
+	}
+	toFields, err := feeConfigFields(to)
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(fromFields)+len(toFields))
+	for name := range fromFields {
+		names = append(names, name)
+	}
+	for name := range toFields {
+		if _, ok := fromFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	diff := make(map[string]feeConfigChange)
+	for _, name := range names {
+		fromValue, toValue := fromFields[name], toFields[name]
+		if bytes.Equal(fromValue, toValue) {
+			continue
+		}
+		// fields that are omitted from the JSON encoding are zero
+		if fromValue == nil {
+			fromValue = json.RawMessage("0")
+		}
+		if toValue == nil {
+			toValue = json.RawMessage("0")
+		}
+		diff[name] = feeConfigChange{From: fromValue, To: toValue}
+	}
+	return diff
+}
+
+// feeConfigFields returns the JSON encoding of each field of [feeConfig].
+func feeConfigFields(feeConfig commontype.FeeConfig) (map[string]json.RawMessage, error) {
+	encoded, err := json.Marshal(feeConfig)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// formatABIArguments returns [values] keyed by the names of [args], formatted in the same way as
+// the other fields of the call tracer. Unnamed arguments are keyed by their position.
+func formatABIArguments(args abi.Arguments, values []interface{}) map[string]interface{} {
+	formatted := make(map[string]interface{}, len(values))
+	for i, value := range values {
+		name := args[i].Name
+		if name == "" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		formatted[name] = formatABIValue(value)
+	}
+	return formatted
+}
+
+// formatABIValue formats [value] unpacked from an ABI argument.
+func formatABIValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case *big.Int:
+		return bigToHex(value)
+	case common.Address:
+		return addrToHex(value)
+	case []common.Address:
+		formatted := make([]string, 0, len(value))
+		for _, addr := range value {
+			formatted = append(formatted, addrToHex(addr))
+		}
+		return formatted
+	case []byte:
+		return bytesToHex(value)
+	case [32]byte:
+		return bytesToHex(value[:])
+	default:
+		return value
+	}
+}
@@ -27,6 +27,7 @@
 package ethapi
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -38,13 +39,16 @@ import (
 	"github.com/ava-labs/subnet-evm/accounts/keystore"
 	"github.com/ava-labs/subnet-evm/accounts/scwallet"
 	"github.com/ava-labs/subnet-evm/commontype"
+	"github.com/ava-labs/subnet-evm/consensus/dummy"
 	"github.com/ava-labs/subnet-evm/core"
 	"github.com/ava-labs/subnet-evm/core/state"
 	"github.com/ava-labs/subnet-evm/core/types"
 	"github.com/ava-labs/subnet-evm/core/vm"
 	"github.com/ava-labs/subnet-evm/eth/tracers/logger"
 	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile"
 	"github.com/ava-labs/subnet-evm/rpc"
+	"github.com/ava-labs/subnet-evm/utils"
 	"github.com/ava-labs/subnet-evm/vmerrs"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -635,6 +639,352 @@ func (s *PublicBlockChainAPI) FeeConfig(ctx context.Context, blockNrOrHash *rpc.
 	return &FeeConfigResult{FeeConfig: feeConfig, LastChangedAt: lastChangedAt}, nil
 }
 
+// PrecompileActivationResult describes a network upgrade of a stateful precompile that has gone into effect
+// along with the storage mode that was applied to the storage of the precompile.
+type PrecompileActivationResult struct {
+	BlockTimestamp *big.Int               `json:"blockTimestamp"`
+	Disable        bool                   `json:"disable"`
+	Reconfigure    bool                   `json:"reconfigure"`
+	StorageMode    precompile.StorageMode `json:"storageMode"`
+}
+
+// PrecompileActivations returns the network upgrades of the stateful precompile at [address] that have gone
+// into effect at the given block, or at the chain head if no block is specified, in the order in which they
+// were activated.
+func (s *PublicBlockChainAPI) PrecompileActivations(ctx context.Context, address common.Address, blockNrOrHash *rpc.BlockNumberOrHash) ([]PrecompileActivationResult, error) {
+	var (
+		header *types.Header
+		err    error
+	)
+	if blockNrOrHash == nil {
+		header = s.b.CurrentHeader()
+	} else {
+		header, err = s.b.HeaderByNumberOrHash(ctx, *blockNrOrHash)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := precompile.GetModuleByAddress(address); !ok {
+		return nil, fmt.Errorf("no precompile registered at address %s", address)
+	}
+
+	configs := s.b.ChainConfig().GetActivatedPrecompileConfigs(address, new(big.Int).SetUint64(header.Time))
+	results := make([]PrecompileActivationResult, 0, len(configs))
+	for _, config := range configs {
+		results = append(results, PrecompileActivationResult{
+			BlockTimestamp: config.Timestamp(),
+			Disable:        config.IsDisabled(),
+			Reconfigure:    config.IsReconfigure(),
+			StorageMode:    precompile.EffectiveStorageMode(config),
+		})
+	}
+	return results, nil
+}
+
+// The sources of a fee config change reported by FeeConfigHistory.
+const (
+	FeeConfigChangeSourceTx        = "tx"        // set by a call to setFeeConfig
+	FeeConfigChangeSourceScheduled = "scheduled" // activated from a fee config scheduled by setFeeConfigAt
+	FeeConfigChangeSourceUpgrade   = "upgrade"   // stored by the activation of the fee config manager
+)
+
+var (
+	errInvalidBlockRange     = errors.New("invalid block range")
+	errDynamicFeesNotEnabled = errors.New("dynamic fees are not enabled before Subnet EVM")
+)
+
+// PublicFeeAPI provides an API to access the fee config history and fee forecasts of the chain.
+type PublicFeeAPI struct {
+	b Backend
+}
+
+// NewPublicFeeAPI creates a new fee API.
+func NewPublicFeeAPI(b Backend) *PublicFeeAPI {
+	return &PublicFeeAPI{b}
+}
+
+// FeeConfigChangeResult describes a change of the fee config stored by the fee config manager.
+// TxHash and Setter are only set for changes made by a call to setFeeConfig. Before PrecompileV2, they are
+// not set for a call made by a contract, since setFeeConfig emits no log that identifies it.
+type FeeConfigChangeResult struct {
+	BlockNumber *big.Int             `json:"blockNumber"`
+	TxHash      *common.Hash         `json:"txHash,omitempty"`
+	Setter      *common.Address      `json:"setter,omitempty"`
+	Source      string               `json:"source"`
+	FeeConfig   commontype.FeeConfig `json:"feeConfig"`
+}
+
+// FeeConfigHistory returns the changes of the fee config made by the fee config manager in the blocks
+// from [fromBlock] to [toBlock], in the order in which they were made.
+// The changes are found by following the last changed block number stored by the fee config manager back
+// from [toBlock], so changes made before the fee config manager was last disabled are not included.
+func (s *PublicFeeAPI) FeeConfigHistory(ctx context.Context, fromBlock rpc.BlockNumber, toBlock rpc.BlockNumber) ([]FeeConfigChangeResult, error) {
+	from, err := s.b.HeaderByNumber(ctx, fromBlock)
+	if from == nil || err != nil {
+		return nil, err
+	}
+	to, err := s.b.HeaderByNumber(ctx, toBlock)
+	if to == nil || err != nil {
+		return nil, err
+	}
+	if from.Number.Cmp(to.Number) > 0 {
+		return nil, fmt.Errorf("%w: from block %d is after to block %d", errInvalidBlockRange, from.Number, to.Number)
+	}
+
+	var (
+		config  = s.b.ChainConfig()
+		changes = make([]FeeConfigChangeResult, 0)
+		number  = to.Number.Uint64()
+	)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.BlockNumber(number))
+		if state == nil || err != nil {
+			return nil, err
+		}
+		if !config.IsPrecompileEnabled(precompile.FeeConfigManagerAddress, new(big.Int).SetUint64(header.Time)) {
+			break
+		}
+		lastChangedAt := precompile.GetFeeConfigLastChangedAt(state)
+		if lastChangedAt.Cmp(from.Number) < 0 || lastChangedAt.Cmp(header.Number) > 0 {
+			break
+		}
+		blockChanges, err := s.feeConfigChangesAt(ctx, lastChangedAt.Uint64())
+		if err != nil {
+			return nil, err
+		}
+		changes = append(blockChanges, changes...)
+		if lastChangedAt.Sign() == 0 {
+			break
+		}
+		number = lastChangedAt.Uint64() - 1
+	}
+	return changes, nil
+}
+
+// feeConfigChangesAt returns the changes of the fee config made in the block with [number], which is
+// expected to be a block in which the fee config manager stored a fee config.
+func (s *PublicFeeAPI) feeConfigChangesAt(ctx context.Context, number uint64) ([]FeeConfigChangeResult, error) {
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.BlockNumber(number))
+	if state == nil || err != nil {
+		return nil, err
+	}
+	var (
+		config          = s.b.ChainConfig()
+		blockTimestamp  = new(big.Int).SetUint64(header.Time)
+		parentTimestamp *big.Int
+		changes         []FeeConfigChangeResult
+	)
+	// A scheduled fee config is stored before the transactions of the first block it applies to are processed.
+	if number > 0 {
+		parentState, parent, err := s.b.StateAndHeaderByNumber(ctx, rpc.BlockNumber(number-1))
+		if parentState == nil || err != nil {
+			return nil, err
+		}
+		parentTimestamp = new(big.Int).SetUint64(parent.Time)
+		if config.IsPrecompileEnabled(precompile.FeeConfigManagerAddress, parentTimestamp) {
+			pendingFeeConfig, activationTimestamp := precompile.GetPendingFeeConfig(parentState)
+			storedFeeConfig := precompile.GetStoredFeeConfig(parentState)
+			if precompile.IsPendingFeeConfigApplied(config, storedFeeConfig, pendingFeeConfig, activationTimestamp, parentTimestamp) {
+				changes = append(changes, FeeConfigChangeResult{
+					BlockNumber: header.Number,
+					Source:      FeeConfigChangeSourceScheduled,
+					FeeConfig:   pendingFeeConfig,
+				})
+			}
+		}
+	}
+	// The activation of the fee config manager stores the fee config of the chain config after any scheduled
+	// fee config is applied.
+	if isFeeConfigManagerActivation(config, parentTimestamp, blockTimestamp) {
+		changes = append(changes, FeeConfigChangeResult{
+			BlockNumber: header.Number,
+			Source:      FeeConfigChangeSourceUpgrade,
+			FeeConfig:   config.GetFeeConfig(),
+		})
+	}
+
+	receipts, err := s.b.GetReceipts(ctx, header.Hash())
+	if err != nil {
+		return nil, err
+	}
+	if config.IsPrecompileV2(blockTimestamp) {
+		txChanges, err := feeConfigChangesFromLogs(header, receipts)
+		if err != nil {
+			return nil, err
+		}
+		return append(changes, txChanges...), nil
+	}
+
+	// Before PrecompileV2, setFeeConfig emits no log, so the calls are decoded from the transactions instead.
+	block, err := s.b.BlockByNumber(ctx, rpc.BlockNumber(number))
+	if block == nil || err != nil {
+		return nil, err
+	}
+	txChanges, err := feeConfigChangesFromTxs(config, block, receipts)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, txChanges...)
+	// A fee config set by a contract calling setFeeConfig is not visible in the transactions, so it is only
+	// observed through the stored fee config.
+	storedFeeConfig := precompile.GetStoredFeeConfig(state)
+	if len(changes) == 0 || !equalFeeConfigs(changes[len(changes)-1].FeeConfig, storedFeeConfig) {
+		changes = append(changes, FeeConfigChangeResult{
+			BlockNumber: header.Number,
+			Source:      FeeConfigChangeSourceTx,
+			FeeConfig:   storedFeeConfig,
+		})
+	}
+	return changes, nil
+}
+
+// isFeeConfigManagerActivation returns whether a block with [blockTimestamp] built on a parent with
+// [parentTimestamp] activates the fee config manager with a config that stores a new fee config.
+func isFeeConfigManagerActivation(config *params.ChainConfig, parentTimestamp *big.Int, blockTimestamp *big.Int) bool {
+	for _, precompileConfig := range config.GetActivatedPrecompileConfigs(precompile.FeeConfigManagerAddress, blockTimestamp) {
+		if precompileConfig.IsDisabled() || precompileConfig.IsReconfigure() {
+			continue
+		}
+		if utils.IsForkTransition(precompileConfig.Timestamp(), parentTimestamp, blockTimestamp) {
+			return true
+		}
+	}
+	return false
+}
+
+// feeConfigChangesFromLogs returns the changes of the fee config made by the calls to setFeeConfig in the
+// block with [header], as reported by the FeeConfigChanged logs of its [receipts].
+func feeConfigChangesFromLogs(header *types.Header, receipts types.Receipts) ([]FeeConfigChangeResult, error) {
+	var changes []FeeConfigChangeResult
+	for _, receipt := range receipts {
+		for _, txLog := range receipt.Logs {
+			if txLog.Address != precompile.FeeConfigManagerAddress || len(txLog.Topics) < 2 || txLog.Topics[0] != precompile.FeeConfigChangedEventID {
+				continue
+			}
+			feeConfig, err := precompile.UnpackFeeConfigInput(txLog.Data)
+			if err != nil {
+				return nil, err
+			}
+			txHash := receipt.TxHash
+			setter := common.BytesToAddress(txLog.Topics[1].Bytes())
+			changes = append(changes, FeeConfigChangeResult{
+				BlockNumber: header.Number,
+				TxHash:      &txHash,
+				Setter:      &setter,
+				Source:      FeeConfigChangeSourceTx,
+				FeeConfig:   feeConfig,
+			})
+		}
+	}
+	return changes, nil
+}
+
+// feeConfigChangesFromTxs returns the changes of the fee config made by the successful transactions of
+// [block] that call setFeeConfig on the fee config manager directly.
+func feeConfigChangesFromTxs(config *params.ChainConfig, block *types.Block, receipts types.Receipts) ([]FeeConfigChangeResult, error) {
+	var (
+		txs     = block.Transactions()
+		signer  = types.MakeSigner(config, block.Number(), new(big.Int).SetUint64(block.Time()))
+		changes []FeeConfigChangeResult
+	)
+	if len(txs) != len(receipts) {
+		return nil, fmt.Errorf("block %d has %d transactions but %d receipts", block.NumberU64(), len(txs), len(receipts))
+	}
+	for i, tx := range txs {
+		if tx.To() == nil || *tx.To() != precompile.FeeConfigManagerAddress || receipts[i].Status != types.ReceiptStatusSuccessful {
+			continue
+		}
+		feeConfig, err := precompile.UnpackSetFeeConfigInput(tx.Data())
+		if err != nil {
+			// the transaction called another function of the fee config manager
+			continue
+		}
+		setter, err := types.Sender(signer, tx)
+		if err != nil {
+			return nil, err
+		}
+		txHash := tx.Hash()
+		changes = append(changes, FeeConfigChangeResult{
+			BlockNumber: block.Number(),
+			TxHash:      &txHash,
+			Setter:      &setter,
+			Source:      FeeConfigChangeSourceTx,
+			FeeConfig:   feeConfig,
+		})
+	}
+	return changes, nil
+}
+
+// equalFeeConfigs returns whether [a] and [b] have the same values.
+func equalFeeConfigs(a commontype.FeeConfig, b commontype.FeeConfig) bool {
+	packedA, errA := precompile.PackSetFeeConfig(a)
+	packedB, errB := precompile.PackSetFeeConfig(b)
+	return errA == nil && errB == nil && bytes.Equal(packedA, packedB)
+}
+
+// FeeForecastResult contains the expected fees of a block built on the chain head at Timestamp.
+type FeeForecastResult struct {
+	Timestamp    hexutil.Uint64 `json:"timestamp"`
+	BaseFee      *hexutil.Big   `json:"baseFee"`
+	BlockGasCost *hexutil.Big   `json:"blockGasCost"`
+	MinimumTip   *hexutil.Big   `json:"minimumTip"`
+}
+
+// FeeForecast returns the expected base fee, block gas cost and minimum tip of a block built on the chain
+// head at [timestamp]. If [timestamp] is before the timestamp of the chain head, the timestamp of the chain
+// head is used instead.
+// The minimum tip assumes that the block uses [gasUsed], or as much gas as the chain head if [gasUsed] is not
+// specified, or the gas limit of the fee config if neither is set.
+func (s *PublicFeeAPI) FeeForecast(ctx context.Context, timestamp hexutil.Uint64, gasUsed *hexutil.Uint64) (*FeeForecastResult, error) {
+	var (
+		config    = s.b.ChainConfig()
+		parent    = s.b.CurrentHeader()
+		blockTime = uint64(timestamp)
+	)
+	if blockTime < parent.Time {
+		blockTime = parent.Time
+	}
+	if !config.IsSubnetEVM(new(big.Int).SetUint64(blockTime)) {
+		return nil, errDynamicFeesNotEnabled
+	}
+
+	feeConfig, _, err := s.b.GetFeeConfigAt(parent)
+	if err != nil {
+		return nil, err
+	}
+	_, baseFee, err := dummy.EstimateNextBaseFee(config, feeConfig, parent, blockTime)
+	if err != nil {
+		return nil, err
+	}
+	blockGasCost := dummy.EstimateNextBlockGasCost(feeConfig, parent, blockTime)
+
+	blockGasUsed := parent.GasUsed
+	if gasUsed != nil && *gasUsed != 0 {
+		blockGasUsed = uint64(*gasUsed)
+	}
+	if blockGasUsed == 0 {
+		blockGasUsed = feeConfig.GasLimit.Uint64()
+	}
+	minTip, err := dummy.MinRequiredTip(config, &types.Header{
+		Time:         blockTime,
+		BaseFee:      baseFee,
+		BlockGasCost: blockGasCost,
+		GasUsed:      blockGasUsed,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &FeeForecastResult{
+		Timestamp:    hexutil.Uint64(blockTime),
+		BaseFee:      (*hexutil.Big)(baseFee),
+		BlockGasCost: (*hexutil.Big)(blockGasCost),
+		MinimumTip:   (*hexutil.Big)(minTip),
+	}, nil
+}
+
 // BlockNumber returns the block number of the chain head.
 func (s *PublicBlockChainAPI) BlockNumber() hexutil.Uint64 {
 	header, _ := s.b.HeaderByNumber(context.Background(), rpc.LatestBlockNumber) // latest header should always be available
@@ -0,0 +1,254 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/commontype"
+	"github.com/ava-labs/subnet-evm/core/rawdb"
+	"github.com/ava-labs/subnet-evm/core/state"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile"
+	"github.com/ava-labs/subnet-evm/rpc"
+	"github.com/ava-labs/subnet-evm/trie"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testFeeManagerKey, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testFeeManagerAddress = crypto.PubkeyToAddress(testFeeManagerKey.PublicKey)
+)
+
+// testAccessibleState provides the state of a block being built by testFeeBackend to the fee config manager.
+type testAccessibleState struct {
+	state       *state.StateDB
+	block       *types.Block
+	chainConfig *params.ChainConfig
+}
+
+func (s *testAccessibleState) GetStateDB() precompile.StateDB           { return s.state }
+func (s *testAccessibleState) GetBlockContext() precompile.BlockContext { return s.block }
+func (s *testAccessibleState) GetChainID() *big.Int                     { return s.chainConfig.ChainID }
+func (s *testAccessibleState) GetChainConfig() precompile.ChainConfig   { return s.chainConfig }
+
+// testFeeBackend implements the parts of Backend used by PublicFeeAPI for a chain whose transactions
+// only call the fee config manager.
+type testFeeBackend struct {
+	Backend
+	t           *testing.T
+	chainConfig *params.ChainConfig
+	blocks      []*types.Block
+	states      []*state.StateDB
+	receipts    map[common.Hash]types.Receipts
+}
+
+func newTestFeeBackend(t *testing.T, chainConfig *params.ChainConfig) *testFeeBackend {
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(t, err)
+	genesis := types.NewBlockWithHeader(&types.Header{Number: common.Big0, Time: 0})
+	chainConfig.CheckConfigurePrecompiles(nil, genesis, statedb)
+	return &testFeeBackend{
+		t:           t,
+		chainConfig: chainConfig,
+		blocks:      []*types.Block{genesis},
+		states:      []*state.StateDB{statedb},
+		receipts:    map[common.Hash]types.Receipts{genesis.Hash(): nil},
+	}
+}
+
+// addBlock adds a block at [timestamp] with a transaction calling the fee config manager with each of [inputs].
+func (b *testFeeBackend) addBlock(timestamp uint64, inputs ...[]byte) {
+	var (
+		parent   = b.blocks[len(b.blocks)-1]
+		statedb  = b.states[len(b.states)-1].Copy()
+		header   = &types.Header{ParentHash: parent.Hash(), Number: new(big.Int).Add(parent.Number(), common.Big1), Time: timestamp}
+		signer   = types.MakeSigner(b.chainConfig, header.Number, new(big.Int).SetUint64(timestamp))
+		txs      []*types.Transaction
+		receipts types.Receipts
+	)
+	accessibleState := &testAccessibleState{state: statedb, block: types.NewBlockWithHeader(header), chainConfig: b.chainConfig}
+	b.chainConfig.CheckConfigurePrecompiles(new(big.Int).SetUint64(parent.Time()), accessibleState.block, statedb)
+	for i, input := range inputs {
+		tx, err := types.SignTx(types.NewTx(&types.LegacyTx{
+			Nonce:    statedb.GetNonce(testFeeManagerAddress),
+			To:       &precompile.FeeConfigManagerAddress,
+			Gas:      1_000_000,
+			GasPrice: common.Big0,
+			Data:     input,
+		}), signer, testFeeManagerKey)
+		require.NoError(b.t, err)
+		statedb.SetNonce(testFeeManagerAddress, tx.Nonce()+1)
+
+		statedb.Prepare(tx.Hash(), i)
+		status := types.ReceiptStatusSuccessful
+		snapshot := statedb.Snapshot()
+		if _, _, err := precompile.FeeConfigManagerPrecompile.Run(accessibleState, testFeeManagerAddress, precompile.FeeConfigManagerAddress, input, tx.Gas(), false); err != nil {
+			statedb.RevertToSnapshot(snapshot)
+			status = types.ReceiptStatusFailed
+		}
+		txs = append(txs, tx)
+		receipts = append(receipts, &types.Receipt{TxHash: tx.Hash(), Status: status, Logs: statedb.GetLogs(tx.Hash(), common.Hash{})})
+	}
+	block := types.NewBlock(header, txs, nil, receipts, trie.NewStackTrie(nil))
+	b.blocks = append(b.blocks, block)
+	b.states = append(b.states, statedb)
+	b.receipts[block.Hash()] = receipts
+}
+
+func (b *testFeeBackend) HeaderByNumber(_ context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	block, _ := b.BlockByNumber(context.Background(), number)
+	if block == nil {
+		return nil, nil
+	}
+	return block.Header(), nil
+}
+
+func (b *testFeeBackend) BlockByNumber(_ context.Context, number rpc.BlockNumber) (*types.Block, error) {
+	if number == rpc.LatestBlockNumber {
+		return b.blocks[len(b.blocks)-1], nil
+	}
+	if number < 0 || int(number) >= len(b.blocks) {
+		return nil, nil
+	}
+	return b.blocks[number], nil
+}
+
+func (b *testFeeBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*state.StateDB, *types.Header, error) {
+	header, _ := b.HeaderByNumber(ctx, number)
+	if header == nil {
+		return nil, nil, nil
+	}
+	return b.states[header.Number.Uint64()].Copy(), header, nil
+}
+
+func (b *testFeeBackend) GetReceipts(_ context.Context, hash common.Hash) (types.Receipts, error) {
+	return b.receipts[hash], nil
+}
+
+func (b *testFeeBackend) ChainConfig() *params.ChainConfig { return b.chainConfig }
+
+// testFeeConfigWithGasLimit returns the default fee config with [gasLimit].
+func testFeeConfigWithGasLimit(gasLimit int64) commontype.FeeConfig {
+	feeConfig := params.DefaultFeeConfig
+	feeConfig.GasLimit = big.NewInt(gasLimit)
+	return feeConfig
+}
+
+func mustPackSetFeeConfig(t *testing.T, feeConfig commontype.FeeConfig) []byte {
+	input, err := precompile.PackSetFeeConfig(feeConfig)
+	require.NoError(t, err)
+	return input
+}
+
+// assertFeeConfigChanges asserts that [changes] have the block numbers, sources and fee configs of [expected],
+// and that the changes made by a transaction report its hash and setter.
+func assertFeeConfigChanges(t *testing.T, backend *testFeeBackend, expected []FeeConfigChangeResult, changes []FeeConfigChangeResult) {
+	require.Len(t, changes, len(expected))
+	for i, change := range changes {
+		assert.Equal(t, expected[i].BlockNumber.Uint64(), change.BlockNumber.Uint64(), "change %d", i)
+		assert.Equal(t, expected[i].Source, change.Source, "change %d", i)
+		assert.True(t, equalFeeConfigs(expected[i].FeeConfig, change.FeeConfig), "change %d: expected fee config %v, got %v", i, expected[i].FeeConfig, change.FeeConfig)
+		if change.Source != FeeConfigChangeSourceTx {
+			assert.Nil(t, change.TxHash, "change %d", i)
+			assert.Nil(t, change.Setter, "change %d", i)
+			continue
+		}
+		if assert.NotNil(t, change.TxHash, "change %d", i) && assert.NotNil(t, change.Setter, "change %d", i) {
+			txs := backend.blocks[change.BlockNumber.Uint64()].Transactions()
+			assert.Equal(t, txs[len(txs)-1].Hash(), *change.TxHash, "change %d", i)
+			assert.Equal(t, testFeeManagerAddress, *change.Setter, "change %d", i)
+		}
+	}
+}
+
+func TestFeeConfigHistoryBeforePrecompileV2(t *testing.T) {
+	chainConfig := *params.TestChainConfig
+	chainConfig.NetworkUpgrades.PrecompileV2Timestamp = nil
+	chainConfig.GenesisPrecompiles = params.Precompiles{
+		precompile.FeeConfigManagerConfigKey: precompile.NewFeeManagerConfig(common.Big0, []common.Address{testFeeManagerAddress}),
+	}
+	feeConfig := testFeeConfigWithGasLimit(10_000_000)
+
+	backend := newTestFeeBackend(t, &chainConfig)
+	backend.addBlock(1, mustPackSetFeeConfig(t, feeConfig))
+	backend.addBlock(2)
+	// setFeeConfig emits no log before PrecompileV2
+	assert.Empty(t, backend.receipts[backend.blocks[1].Hash()][0].Logs)
+
+	changes, err := NewPublicFeeAPI(backend).FeeConfigHistory(context.Background(), 0, rpc.LatestBlockNumber)
+	require.NoError(t, err)
+	assertFeeConfigChanges(t, backend, []FeeConfigChangeResult{
+		{BlockNumber: common.Big0, Source: FeeConfigChangeSourceUpgrade, FeeConfig: params.DefaultFeeConfig},
+		{BlockNumber: common.Big1, Source: FeeConfigChangeSourceTx, FeeConfig: feeConfig},
+	}, changes)
+}
+
+func TestFeeConfigHistoryScheduled(t *testing.T) {
+	const scheduledAt = 1 + precompile.DefaultMinFeeConfigDelay
+	var (
+		scheduledFeeConfig = testFeeConfigWithGasLimit(params.DefaultFeeConfig.GasLimit.Int64() + 10_000_000)
+		boundedFeeConfig   = testFeeConfigWithGasLimit(params.DefaultFeeConfig.GasLimit.Int64() + 500_000)
+	)
+
+	tests := map[string]struct {
+		feeConfigBounds *precompile.FeeConfigBounds
+		expected        []FeeConfigChangeResult
+	}{
+		"applied": {
+			expected: []FeeConfigChangeResult{
+				{BlockNumber: common.Big0, Source: FeeConfigChangeSourceUpgrade, FeeConfig: params.DefaultFeeConfig},
+				{BlockNumber: common.Big3, Source: FeeConfigChangeSourceScheduled, FeeConfig: scheduledFeeConfig},
+				{BlockNumber: common.Big3, Source: FeeConfigChangeSourceTx, FeeConfig: boundedFeeConfig},
+			},
+		},
+		"dropped out of bounds": {
+			feeConfigBounds: &precompile.FeeConfigBounds{
+				GasLimit: &precompile.FeeConfigFieldBounds{MaxDelta: big.NewInt(1_000_000)},
+			},
+			expected: []FeeConfigChangeResult{
+				{BlockNumber: common.Big0, Source: FeeConfigChangeSourceUpgrade, FeeConfig: params.DefaultFeeConfig},
+				{BlockNumber: common.Big3, Source: FeeConfigChangeSourceTx, FeeConfig: boundedFeeConfig},
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			chainConfig := *params.TestChainConfig
+			chainConfig.GenesisPrecompiles = params.Precompiles{
+				precompile.FeeConfigManagerConfigKey: precompile.NewFeeManagerConfig(common.Big0, []common.Address{testFeeManagerAddress}),
+			}
+			if test.feeConfigBounds != nil {
+				// the bounds are added after the fee config is scheduled
+				reconfigured := precompile.NewFeeManagerConfig(big.NewInt(10), nil)
+				reconfigured.Reconfiguration = true
+				reconfigured.FeeConfigBounds = test.feeConfigBounds
+				chainConfig.PrecompileUpgrades = []params.PrecompileUpgrade{{StatefulPrecompileConfig: reconfigured}}
+			}
+			boundedInput := mustPackSetFeeConfig(t, boundedFeeConfig)
+			scheduledInput, err := precompile.PackSetFeeConfigAt(scheduledFeeConfig, new(big.Int).SetUint64(scheduledAt))
+			require.NoError(t, err)
+
+			backend := newTestFeeBackend(t, &chainConfig)
+			backend.addBlock(1, scheduledInput)
+			backend.addBlock(scheduledAt)
+			backend.addBlock(scheduledAt+1, boundedInput)
+			for i, block := range backend.blocks[1:] {
+				for _, receipt := range backend.receipts[block.Hash()] {
+					require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status, "block %d", i+1)
+				}
+			}
+
+			changes, err := NewPublicFeeAPI(backend).FeeConfigHistory(context.Background(), 0, rpc.LatestBlockNumber)
+			require.NoError(t, err)
+			assertFeeConfigChanges(t, backend, test.expected, changes)
+		})
+	}
+}
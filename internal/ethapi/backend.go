@@ -121,6 +121,12 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 			Service:   NewPublicBlockChainAPI(apiBackend),
 			Public:    true,
 			Name:      "internal-public-blockchain",
+		}, {
+			Namespace: "fee",
+			Version:   "1.0",
+			Service:   NewPublicFeeAPI(apiBackend),
+			Public:    true,
+			Name:      "internal-public-fee",
 		}, {
 			Namespace: "eth",
 			Version:   "1.0",
@@ -162,7 +162,17 @@ func (w *worker) commitNewWork() (*types.Block, error) {
 	if w.coinbase == (common.Address{}) {
 		return nil, errors.New("cannot mine without etherbase")
 	}
-	header.Coinbase = w.coinbase
+	// The coinbase is set to the etherbase of the worker only if fee recipients are allowed
+	// at [parent]. Otherwise, the block rewards go to the configured reward address.
+	configuredCoinbase, allowFeeRecipients, err := w.chain.GetCoinbaseAt(parent.Header())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configured coinbase: %w", err)
+	}
+	if allowFeeRecipients {
+		header.Coinbase = w.coinbase
+	} else {
+		header.Coinbase = configuredCoinbase
+	}
 	if err := w.engine.Prepare(w.chain, header); err != nil {
 		return nil, fmt.Errorf("failed to prepare header for mining: %w", err)
 	}
@@ -188,11 +198,11 @@ func (w *worker) commitNewWork() (*types.Block, error) {
 	}
 	if len(localTxs) > 0 {
 		txs := types.NewTransactionsByPriceAndNonce(env.signer, localTxs, header.BaseFee)
-		w.commitTransactions(env, txs, w.coinbase)
+		w.commitTransactions(env, txs, header.Coinbase)
 	}
 	if len(remoteTxs) > 0 {
 		txs := types.NewTransactionsByPriceAndNonce(env.signer, remoteTxs, header.BaseFee)
-		w.commitTransactions(env, txs, w.coinbase)
+		w.commitTransactions(env, txs, header.Coinbase)
 	}
 
 	return w.commit(env)
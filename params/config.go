@@ -88,8 +88,8 @@ var (
 		},
 	}
 
-	TestChainConfig        = &ChainConfig{big.NewInt(1), DefaultFeeConfig, false, big.NewInt(0), big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), NetworkUpgrades{big.NewInt(0)}, PrecompileUpgrade{}, UpgradeConfig{}}
-	TestPreSubnetEVMConfig = &ChainConfig{big.NewInt(1), DefaultFeeConfig, false, big.NewInt(0), big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), NetworkUpgrades{}, PrecompileUpgrade{}, UpgradeConfig{}}
+	TestChainConfig        = &ChainConfig{big.NewInt(1), DefaultFeeConfig, false, big.NewInt(0), big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), NetworkUpgrades{big.NewInt(0), big.NewInt(0), big.NewInt(0)}, nil, UpgradeConfig{}}
+	TestPreSubnetEVMConfig = &ChainConfig{big.NewInt(1), DefaultFeeConfig, false, big.NewInt(0), big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), NetworkUpgrades{}, nil, UpgradeConfig{}}
 )
 
 // ChainConfig is the core config which determines the blockchain settings.
@@ -117,9 +117,45 @@ type ChainConfig struct {
 	IstanbulBlock       *big.Int `json:"istanbulBlock,omitempty"`       // Istanbul switch block (nil = no fork, 0 = already on istanbul)
 	MuirGlacierBlock    *big.Int `json:"muirGlacierBlock,omitempty"`    // Eip-2384 (bomb delay) switch block (nil = no fork, 0 = already activated)
 
-	NetworkUpgrades              // Config for timestamps that enable avalanche network upgrades
-	PrecompileUpgrade            // Config for enabling precompiles from genesis
-	UpgradeConfig     `json:"-"` // Config specified in upgradeBytes (avalanche network upgrades or enable/disabling precompiles). Skip encoding/decoding directly into ChainConfig.
+	NetworkUpgrades                // Config for timestamps that enable avalanche network upgrades
+	GenesisPrecompiles Precompiles `json:"-"` // Config for enabling precompiles from genesis. Encoded alongside the other fields of ChainConfig by MarshalJSON and UnmarshalJSON.
+	UpgradeConfig      `json:"-"`  // Config specified in upgradeBytes (avalanche network upgrades or enable/disabling precompiles). Skip encoding/decoding directly into ChainConfig.
+}
+
+// UnmarshalJSON parses the JSON-encoded data into the chain config, including the configs
+// of the precompiles enabled from genesis, which are keyed by the config key of their module.
+func (c *ChainConfig) UnmarshalJSON(data []byte) error {
+	// use a type without methods to avoid recursively calling UnmarshalJSON
+	type chainConfigJSON ChainConfig
+	decoded := chainConfigJSON{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*c = ChainConfig(decoded)
+	return json.Unmarshal(data, &c.GenesisPrecompiles)
+}
+
+// MarshalJSON returns the JSON encoding of the chain config, including the configs
+// of the precompiles enabled from genesis, which are keyed by the config key of their module.
+func (c ChainConfig) MarshalJSON() ([]byte, error) {
+	// use a type without methods to avoid recursively calling MarshalJSON
+	type chainConfigJSON ChainConfig
+	encoded, err := json.Marshal(chainConfigJSON(c))
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+	for key, config := range c.GenesisPrecompiles {
+		configBytes, err := json.Marshal(config)
+		if err != nil {
+			return nil, err
+		}
+		fields[key] = configBytes
+	}
+	return json.Marshal(fields)
 }
 
 // UpgradeConfig includes the following configs that may be specified in upgradeBytes:
@@ -146,16 +182,16 @@ func (c *ChainConfig) String() string {
 	if err != nil {
 		networkUpgradesBytes = []byte("cannot marshal NetworkUpgrades")
 	}
-	precompileUpgradeBytes, err := json.Marshal(c.PrecompileUpgrade)
+	genesisPrecompilesBytes, err := json.Marshal(c.GenesisPrecompiles)
 	if err != nil {
-		precompileUpgradeBytes = []byte("cannot marshal PrecompileUpgrade")
+		genesisPrecompilesBytes = []byte("cannot marshal GenesisPrecompiles")
 	}
 	upgradeConfigBytes, err := json.Marshal(c.UpgradeConfig)
 	if err != nil {
 		upgradeConfigBytes = []byte("cannot marshal UpgradeConfig")
 	}
 
-	return fmt.Sprintf("{ChainID: %v Homestead: %v EIP150: %v EIP155: %v EIP158: %v Byzantium: %v Constantinople: %v Petersburg: %v Istanbul: %v, Muir Glacier: %v, Subnet EVM: %v, FeeConfig: %v, AllowFeeRecipients: %v, NetworkUpgrades: %v, PrecompileUpgrade: %v, UpgradeConfig: %v, Engine: Dummy Consensus Engine}",
+	return fmt.Sprintf("{ChainID: %v Homestead: %v EIP150: %v EIP155: %v EIP158: %v Byzantium: %v Constantinople: %v Petersburg: %v Istanbul: %v, Muir Glacier: %v, Subnet EVM: %v, FeeConfig: %v, AllowFeeRecipients: %v, NetworkUpgrades: %v, GenesisPrecompiles: %v, UpgradeConfig: %v, Engine: Dummy Consensus Engine}",
 		c.ChainID,
 		c.HomesteadBlock,
 		c.EIP150Block,
@@ -170,7 +206,7 @@ func (c *ChainConfig) String() string {
 		string(feeBytes),
 		c.AllowFeeRecipients,
 		string(networkUpgradesBytes),
-		string(precompileUpgradeBytes),
+		string(genesisPrecompilesBytes),
 		string(upgradeConfigBytes),
 	)
 }
@@ -227,28 +263,20 @@ func (c *ChainConfig) IsSubnetEVM(blockTimestamp *big.Int) bool {
 	return utils.IsForked(c.getNetworkUpgrades().SubnetEVMTimestamp, blockTimestamp)
 }
 
-// IsContractDeployerAllowList returns whether [blockTimestamp] is either equal to the ContractDeployerAllowList fork block timestamp or greater.
-func (c *ChainConfig) IsContractDeployerAllowList(blockTimestamp *big.Int) bool {
-	config := c.GetContractDeployerAllowListConfig(blockTimestamp)
-	return config != nil && !config.Disable
-}
-
-// IsContractNativeMinter returns whether [blockTimestamp] is either equal to the NativeMinter fork block timestamp or greater.
-func (c *ChainConfig) IsContractNativeMinter(blockTimestamp *big.Int) bool {
-	config := c.GetContractNativeMinterConfig(blockTimestamp)
-	return config != nil && !config.Disable
+// IsAllowListManager returns whether [blockTimestamp] is either equal to the AllowListManager fork block timestamp or greater.
+func (c *ChainConfig) IsAllowListManager(blockTimestamp *big.Int) bool {
+	return utils.IsForked(c.getNetworkUpgrades().AllowListManagerTimestamp, blockTimestamp)
 }
 
-// IsTxAllowList returns whether [blockTimestamp] is either equal to the TxAllowList fork block timestamp or greater.
-func (c *ChainConfig) IsTxAllowList(blockTimestamp *big.Int) bool {
-	config := c.GetTxAllowListConfig(blockTimestamp)
-	return config != nil && !config.Disable
+// IsPrecompileV2 returns whether [blockTimestamp] is either equal to the PrecompileV2 fork block timestamp or greater.
+func (c *ChainConfig) IsPrecompileV2(blockTimestamp *big.Int) bool {
+	return utils.IsForked(c.getNetworkUpgrades().PrecompileV2Timestamp, blockTimestamp)
 }
 
-// IsFeeConfigManager returns whether [blockTimestamp] is either equal to the FeeConfigManager fork block timestamp or greater.
-func (c *ChainConfig) IsFeeConfigManager(blockTimestamp *big.Int) bool {
-	config := c.GetFeeConfigManagerConfig(blockTimestamp)
-	return config != nil && !config.Disable
+// IsPrecompileEnabled returns whether the stateful precompile at [address] is enabled at [blockTimestamp].
+func (c *ChainConfig) IsPrecompileEnabled(address common.Address, blockTimestamp *big.Int) bool {
+	config := c.GetActivePrecompileConfig(address, blockTimestamp)
+	return config != nil && !config.IsDisabled()
 }
 
 // CheckCompatible checks whether scheduled fork transitions have been imported
@@ -281,6 +309,16 @@ func (c *ChainConfig) Verify() error {
 		return err
 	}
 
+	// Verify the fee config stored when the fee config manager is enabled is within its bounds.
+	if err := c.verifyFeeConfigBounds(); err != nil {
+		return err
+	}
+
+	// Verify the initial mint of the native minter can be checked against its max supply.
+	if err := c.verifyNativeMinterInitialMint(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -337,6 +375,8 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 	lastFork = fork{}
 	for _, cur := range []fork{
 		{name: "subnetEVMTimestamp", block: c.SubnetEVMTimestamp},
+		{name: "allowListManagerTimestamp", block: c.AllowListManagerTimestamp, optional: true},
+		{name: "precompileV2Timestamp", block: c.PrecompileV2Timestamp, optional: true},
 	} {
 		if lastFork.name != "" {
 			// Next one must be higher number
@@ -476,13 +516,9 @@ type Rules struct {
 	IsByzantium, IsConstantinople, IsPetersburg, IsIstanbul bool
 
 	// Rules for Avalanche releases
-	IsSubnetEVM bool
-
-	// Optional stateful precompile rules
-	IsContractDeployerAllowListEnabled bool
-	IsContractNativeMinterEnabled      bool
-	IsTxAllowListEnabled               bool
-	IsFeeConfigManagerEnabled          bool
+	IsSubnetEVM        bool
+	IsAllowListManager bool
+	IsPrecompileV2     bool
 
 	// Precompiles maps addresses to stateful precompiled contracts that are enabled
 	// for this rule set.
@@ -491,6 +527,12 @@ type Rules struct {
 	Precompiles map[common.Address]precompile.StatefulPrecompiledContract
 }
 
+// IsPrecompileEnabled returns true if the stateful precompile at [address] is enabled for this rule set.
+func (r *Rules) IsPrecompileEnabled(address common.Address) bool {
+	_, ok := r.Precompiles[address]
+	return ok
+}
+
 // Rules ensures c's ChainID is not nil.
 func (c *ChainConfig) rules(num *big.Int) Rules {
 	chainID := c.ChainID
@@ -516,10 +558,8 @@ func (c *ChainConfig) AvalancheRules(blockNum, blockTimestamp *big.Int) Rules {
 	rules := c.rules(blockNum)
 
 	rules.IsSubnetEVM = c.IsSubnetEVM(blockTimestamp)
-	rules.IsContractDeployerAllowListEnabled = c.IsContractDeployerAllowList(blockTimestamp)
-	rules.IsContractNativeMinterEnabled = c.IsContractNativeMinter(blockTimestamp)
-	rules.IsTxAllowListEnabled = c.IsTxAllowList(blockTimestamp)
-	rules.IsFeeConfigManagerEnabled = c.IsFeeConfigManager(blockTimestamp)
+	rules.IsAllowListManager = c.IsAllowListManager(blockTimestamp)
+	rules.IsPrecompileV2 = c.IsPrecompileV2(blockTimestamp)
 
 	// Initialize the stateful precompiles that should be enabled at [blockTimestamp].
 	rules.Precompiles = make(map[common.Address]precompile.StatefulPrecompiledContract)
@@ -537,3 +577,8 @@ func (c *ChainConfig) AvalancheRules(blockNum, blockTimestamp *big.Int) Rules {
 func (c *ChainConfig) GetFeeConfig() commontype.FeeConfig {
 	return c.FeeConfig
 }
+
+// AllowedFeeRecipients returns AllowedFeeRecipients
+func (c *ChainConfig) AllowedFeeRecipients() bool {
+	return c.AllowFeeRecipients
+}
@@ -30,6 +30,8 @@ import (
 	"math/big"
 	"reflect"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 func TestCheckCompatible(t *testing.T) {
@@ -127,6 +129,25 @@ func TestCheckCompatible(t *testing.T) {
 				RewindTo:     0,
 			},
 		},
+		{
+			stored:        allowListManagerConfig(10),
+			new:           allowListManagerConfig(20),
+			headHeight:    10,
+			headTimestamp: 5,
+			wantErr:       nil,
+		},
+		{
+			stored:        allowListManagerConfig(10),
+			new:           allowListManagerConfig(20),
+			headHeight:    10,
+			headTimestamp: 15,
+			wantErr: &ConfigCompatError{
+				What:         "AllowListManager fork block timestamp",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    big.NewInt(20),
+				RewindTo:     9,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -136,3 +157,26 @@ func TestCheckCompatible(t *testing.T) {
 		}
 	}
 }
+
+// allowListManagerConfig returns a copy of TestChainConfig that activates the AllowListManager upgrade at [timestamp].
+func allowListManagerConfig(timestamp int64) *ChainConfig {
+	config := *TestChainConfig
+	config.AllowListManagerTimestamp = big.NewInt(timestamp)
+	return &config
+}
+
+func TestAllowListManagerUpgrade(t *testing.T) {
+	config := allowListManagerConfig(10)
+	if config.IsAllowListManager(big.NewInt(9)) {
+		t.Fatal("expected AllowListManager to be inactive before its timestamp")
+	}
+	if !config.IsAllowListManager(big.NewInt(10)) || !config.AvalancheRules(common.Big0, big.NewInt(10)).IsAllowListManager {
+		t.Fatal("expected AllowListManager to be active at its timestamp")
+	}
+
+	// the AllowListManager upgrade cannot be activated without the SubnetEVM upgrade
+	config.SubnetEVMTimestamp = nil
+	if err := config.CheckConfigForkOrder(); err == nil {
+		t.Fatal("expected fork ordering error")
+	}
+}
@@ -9,7 +9,9 @@ import (
 
 // NetworkUpgrades contains timestamps that enable avalanche network upgrades.
 type NetworkUpgrades struct {
-	SubnetEVMTimestamp *big.Int `json:"subnetEVMTimestamp,omitempty"` // A placeholder for the latest avalanche forks (nil = no fork, 0 = already activated)
+	SubnetEVMTimestamp        *big.Int `json:"subnetEVMTimestamp,omitempty"`        // A placeholder for the latest avalanche forks (nil = no fork, 0 = already activated)
+	AllowListManagerTimestamp *big.Int `json:"allowListManagerTimestamp,omitempty"` // Enables the Manager role of allow list precompiles (nil = no fork, 0 = already activated)
+	PrecompileV2Timestamp     *big.Int `json:"precompileV2Timestamp,omitempty"`     // Enables the verification, gas costs and accounting changes of stateful precompiles (nil = no fork, 0 = already activated)
 }
 
 func (n *NetworkUpgrades) CheckCompatible(newcfg *NetworkUpgrades, headTimestamp *big.Int) *ConfigCompatError {
@@ -17,6 +19,12 @@ func (n *NetworkUpgrades) CheckCompatible(newcfg *NetworkUpgrades, headTimestamp
 	if isForkIncompatible(n.SubnetEVMTimestamp, newcfg.SubnetEVMTimestamp, headTimestamp) {
 		return newCompatError("SubnetEVM fork block timestamp", n.SubnetEVMTimestamp, newcfg.SubnetEVMTimestamp)
 	}
+	if isForkIncompatible(n.AllowListManagerTimestamp, newcfg.AllowListManagerTimestamp, headTimestamp) {
+		return newCompatError("AllowListManager fork block timestamp", n.AllowListManagerTimestamp, newcfg.AllowListManagerTimestamp)
+	}
+	if isForkIncompatible(n.PrecompileV2Timestamp, newcfg.PrecompileV2Timestamp, headTimestamp) {
+		return newCompatError("PrecompileV2 fork block timestamp", n.PrecompileV2Timestamp, newcfg.PrecompileV2Timestamp)
+	}
 
 	return nil
 }
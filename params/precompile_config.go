@@ -4,97 +4,162 @@
 package params
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 
 	"github.com/ava-labs/subnet-evm/precompile"
 	"github.com/ava-labs/subnet-evm/utils"
-)
-
-// precompileKey is a helper type used to reference each of the
-// possible stateful precompile types that can be activated
-// as a network upgrade.
-type precompileKey int
-
-const (
-	contractDeployerAllowListKey precompileKey = iota + 1
-	contractNativeMinterKey
-	txAllowListKey
-	feeManagerKey
+	"github.com/ethereum/go-ethereum/common"
 )
 
 var (
-	precompileKeys = []precompileKey{contractDeployerAllowListKey, contractNativeMinterKey, txAllowListKey, feeManagerKey}
+	errEmptyPrecompileUpgrade   = errors.New("precompile upgrade cannot be empty")
+	errMultiplePrecompileKeys   = errors.New("precompile upgrade must specify exactly one key")
+	errUnknownPrecompileKey     = errors.New("unknown precompile config key")
+	errUnknownPrecompileAddress = errors.New("no precompile module registered at address")
 )
 
+// Precompiles maps the config key of each registered precompile module to the config
+// enabling that precompile in the genesis.
+type Precompiles map[string]precompile.StatefulPrecompileConfig
+
+// UnmarshalJSON parses the JSON-encoded data into the configs of the registered precompile modules.
+// Keys that do not belong to a registered module are ignored, since the genesis precompile configs
+// are specified alongside the other fields of the chain config.
+func (p *Precompiles) UnmarshalJSON(data []byte) error {
+	raw := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*p = make(Precompiles)
+	for _, module := range precompile.RegisteredModules() {
+		value, ok := raw[module.ConfigKey]
+		if !ok || string(value) == "null" {
+			continue
+		}
+		config := module.NewConfig()
+		if err := json.Unmarshal(value, config); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", module.ConfigKey, err)
+		}
+		(*p)[module.ConfigKey] = config
+	}
+	return nil
+}
+
 // PrecompileUpgrade is a helper struct embedded in UpgradeConfig, representing
-// each of the possible stateful precompile types that can be activated
-// as a network upgrade.
+// a stateful precompile that is enabled or disabled as a network upgrade.
+// It is encoded as a JSON object with the config key of the precompile module as its only key.
 type PrecompileUpgrade struct {
-	ContractDeployerAllowListConfig *precompile.ContractDeployerAllowListConfig `json:"contractDeployerAllowListConfig,omitempty"` // Config for the contract deployer allow list precompile
-	ContractNativeMinterConfig      *precompile.ContractNativeMinterConfig      `json:"contractNativeMinterConfig,omitempty"`      // Config for the native minter precompile
-	TxAllowListConfig               *precompile.TxAllowListConfig               `json:"txAllowListConfig,omitempty"`               // Config for the tx allow list precompile
-	FeeManagerConfig                *precompile.FeeConfigManagerConfig          `json:"feeManagerConfig,omitempty"`                // Config for the fee manager precompile
+	precompile.StatefulPrecompileConfig
 }
 
-func (p *PrecompileUpgrade) getByKey(key precompileKey) (precompile.StatefulPrecompileConfig, bool) {
-	switch key {
-	case contractDeployerAllowListKey:
-		return p.ContractDeployerAllowListConfig, p.ContractDeployerAllowListConfig != nil
-	case contractNativeMinterKey:
-		return p.ContractNativeMinterConfig, p.ContractNativeMinterConfig != nil
-	case txAllowListKey:
-		return p.TxAllowListConfig, p.TxAllowListConfig != nil
-	case feeManagerKey:
-		return p.FeeManagerConfig, p.FeeManagerConfig != nil
+// UnmarshalJSON parses the JSON-encoded data into the config of the precompile module
+// registered with the only key of the object.
+func (u *PrecompileUpgrade) UnmarshalJSON(data []byte) error {
+	raw := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	switch len(raw) {
+	case 0:
+		return errEmptyPrecompileUpgrade
+	case 1:
 	default:
-		panic(fmt.Sprintf("unknown upgrade key: %v", key))
+		return fmt.Errorf("%w: found %d keys", errMultiplePrecompileKeys, len(raw))
+	}
+
+	for key, value := range raw {
+		module, ok := precompile.GetModule(key)
+		if !ok {
+			return fmt.Errorf("%w: %s", errUnknownPrecompileKey, key)
+		}
+		config := module.NewConfig()
+		if err := json.Unmarshal(value, config); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", key, err)
+		}
+		u.StatefulPrecompileConfig = config
 	}
+	return nil
+}
+
+// MarshalJSON returns the JSON encoding of the config keyed by the config key of
+// the precompile module registered at its address.
+func (u PrecompileUpgrade) MarshalJSON() ([]byte, error) {
+	if u.StatefulPrecompileConfig == nil {
+		return nil, errEmptyPrecompileUpgrade
+	}
+	module, ok := precompile.GetModuleByAddress(u.Address())
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnknownPrecompileAddress, u.Address())
+	}
+	return json.Marshal(map[string]precompile.StatefulPrecompileConfig{module.ConfigKey: u.StatefulPrecompileConfig})
 }
 
 // VerifyPrecompileUpgrades checks [c.PrecompileUpgrades] is well formed:
-// - [upgrades] must specify exactly one key per PrecompileUpgrade
+// - [upgrades] must specify exactly one registered precompile per PrecompileUpgrade
 // - the specified blockTimestamps must monotonically increase
 // - the specified blockTimestamps must be compatible with those
 //   specified in the chainConfig by genesis.
 // - check a precompile is disabled before it is re-enabled
+// - check a precompile is enabled before it is reconfigured, which keeps it enabled
+// - the parameters of each config that enables a precompile must be valid, and its allow list admins
+//   must be unique once the PrecompileV2 upgrade is active
+// - the gas overrides of each config must override functions of its precompile with non-zero gas costs
+// - the storage mode of each config must be valid and must not be set on configs that reconfigure a precompile
 func (c *ChainConfig) VerifyPrecompileUpgrades() error {
+	// verify the genesis precompile configs are keyed by the module registered at their address
+	for key, config := range c.GenesisPrecompiles {
+		module, ok := precompile.GetModule(key)
+		if !ok {
+			return fmt.Errorf("%w: %s", errUnknownPrecompileKey, key)
+		}
+		if config == nil || module.Address != config.Address() {
+			return fmt.Errorf("invalid genesis precompile config for %s", key)
+		}
+		if config.IsReconfigure() {
+			return fmt.Errorf("genesis precompile config for %s cannot reconfigure", key)
+		}
+		if mode := config.GetStorageMode(); !mode.Valid() {
+			return fmt.Errorf("genesis precompile config for %s: %w: %s", key, precompile.ErrInvalidStorageMode, mode)
+		}
+	}
+
 	var lastBlockTimestamp *big.Int
 	for i, upgrade := range c.PrecompileUpgrades {
-		hasKey := false // used to verify if there is only one key per Upgrade
-
-		for _, key := range precompileKeys {
-			config, ok := upgrade.getByKey(key)
-			if !ok {
-				continue
-			}
-			if hasKey {
-				return fmt.Errorf("PrecompileUpgrades[%d] has more than one key set", i)
-			}
-			configTimestamp := config.Timestamp()
-			if configTimestamp == nil {
-				return fmt.Errorf("PrecompileUpgrades[%d] cannot have a nil timestamp", i)
-			}
-			// Verify specified timestamps are monotonically increasing across all precompile keys.
-			// Note: It is OK for multiple configs of different keys to specify the same timestamp.
-			if lastBlockTimestamp != nil && configTimestamp.Cmp(lastBlockTimestamp) < 0 {
-				return fmt.Errorf("PrecompileUpgrades[%d] config timestamp (%v) < previous timestamp (%v)", i, configTimestamp, lastBlockTimestamp)
-			}
-			lastBlockTimestamp = configTimestamp
-			hasKey = true
-		}
-		if !hasKey {
+		if upgrade.StatefulPrecompileConfig == nil {
 			return fmt.Errorf("empty precompile upgrade at index %d", i)
 		}
+		if _, ok := precompile.GetModuleByAddress(upgrade.Address()); !ok {
+			return fmt.Errorf("PrecompileUpgrades[%d]: %w: %s", i, errUnknownPrecompileAddress, upgrade.Address())
+		}
+		configTimestamp := upgrade.Timestamp()
+		if configTimestamp == nil {
+			return fmt.Errorf("PrecompileUpgrades[%d] cannot have a nil timestamp", i)
+		}
+		// Verify specified timestamps are monotonically increasing across all precompile keys.
+		// Note: It is OK for multiple configs of different keys to specify the same timestamp.
+		if lastBlockTimestamp != nil && configTimestamp.Cmp(lastBlockTimestamp) < 0 {
+			return fmt.Errorf("PrecompileUpgrades[%d] config timestamp (%v) < previous timestamp (%v)", i, configTimestamp, lastBlockTimestamp)
+		}
+		lastBlockTimestamp = configTimestamp
 	}
 
-	for _, key := range precompileKeys {
+	for _, module := range precompile.RegisteredModules() {
 		var (
 			lastUpgraded *big.Int
 			disabled     bool
 		)
 		// check the genesis chain config for any enabled upgrade
-		if config, ok := c.PrecompileUpgrade.getByKey(key); ok {
+		if config, ok := c.GenesisPrecompiles[module.ConfigKey]; ok {
+			if err := c.verifyPrecompileConfig(config); err != nil {
+				return fmt.Errorf("invalid genesis precompile config for %s: %w", config.Address(), err)
+			}
+			if err := module.VerifyGasOverrides(config.GetGasOverrides()); err != nil {
+				return fmt.Errorf("invalid genesis precompile config for %s: %w", config.Address(), err)
+			}
 			disabled = false
 			lastUpgraded = config.Timestamp()
 		} else {
@@ -102,18 +167,39 @@ func (c *ChainConfig) VerifyPrecompileUpgrades() error {
 		}
 		// next range over upgrades to verify correct use of disabled and blockTimestamps.
 		for i, upgrade := range c.PrecompileUpgrades {
-			config, ok := upgrade.getByKey(key)
-			// Skip the upgrade if it's not relevant to [key].
-			if !ok {
+			// Skip the upgrade if it's not relevant to [module].
+			if upgrade.Address() != module.Address {
 				continue
 			}
+			config := upgrade.StatefulPrecompileConfig
 
-			if disabled == config.IsDisabled() {
+			if config.IsReconfigure() {
+				if config.IsDisabled() {
+					return fmt.Errorf("PrecompileUpgrades[%d] cannot both disable and reconfigure", i)
+				}
+				if disabled {
+					return fmt.Errorf("PrecompileUpgrades[%d] cannot reconfigure a disabled precompile", i)
+				}
+				if config.GetStorageMode() != "" {
+					return fmt.Errorf("PrecompileUpgrades[%d] cannot set a storage mode when reconfiguring", i)
+				}
+			} else if disabled == config.IsDisabled() {
 				return fmt.Errorf("PrecompileUpgrades[%d] disable should be [%v]", i, !disabled)
 			}
+			if mode := config.GetStorageMode(); !mode.Valid() {
+				return fmt.Errorf("PrecompileUpgrades[%d]: %w: %s", i, precompile.ErrInvalidStorageMode, mode)
+			}
 			if lastUpgraded != nil && (config.Timestamp().Cmp(lastUpgraded) <= 0) {
 				return fmt.Errorf("PrecompileUpgrades[%d] config timestamp (%v) <= previous timestamp (%v)", i, config.Timestamp(), lastUpgraded)
 			}
+			if !config.IsDisabled() {
+				if err := c.verifyPrecompileConfig(config); err != nil {
+					return fmt.Errorf("PrecompileUpgrades[%d] has an invalid config: %w", i, err)
+				}
+			}
+			if err := module.VerifyGasOverrides(config.GetGasOverrides()); err != nil {
+				return fmt.Errorf("PrecompileUpgrades[%d] has an invalid config: %w", i, err)
+			}
 
 			disabled = config.IsDisabled()
 			lastUpgraded = config.Timestamp()
@@ -123,73 +209,152 @@ func (c *ChainConfig) VerifyPrecompileUpgrades() error {
 	return nil
 }
 
-// getActivePrecompileConfig returns the most recent precompile config corresponding to [key].
+// adminsVerifier is implemented by the configs of the precompiles with an allow list.
+type adminsVerifier interface {
+	VerifyAdmins() error
+}
+
+// verifyPrecompileConfig verifies the parameters of [config]. The allow list admins of [config] are
+// only verified if it activates once the PrecompileV2 upgrade is active, so that existing genesis
+// files and upgrades with duplicate admins keep being accepted.
+func (c *ChainConfig) verifyPrecompileConfig(config precompile.StatefulPrecompileConfig) error {
+	if err := config.Verify(); err != nil {
+		return err
+	}
+	timestamp := config.Timestamp()
+	if timestamp == nil {
+		timestamp = common.Big0
+	}
+	if verifier, ok := config.(adminsVerifier); ok && c.IsPrecompileV2(timestamp) {
+		return verifier.VerifyAdmins()
+	}
+	return nil
+}
+
+// verifyFeeConfigBounds checks that [c.FeeConfig], which is stored as the fee config whenever the
+// fee config manager is enabled, is within the fee config bounds of each config that enables it.
+// Configs that reconfigure the fee config manager keep the current fee config, so they are not checked.
+func (c *ChainConfig) verifyFeeConfigBounds() error {
+	configs := make([]precompile.StatefulPrecompileConfig, 0, len(c.PrecompileUpgrades)+1)
+	if config, ok := c.GenesisPrecompiles[precompile.FeeConfigManagerConfigKey]; ok {
+		configs = append(configs, config)
+	}
+	for _, upgrade := range c.PrecompileUpgrades {
+		configs = append(configs, upgrade.StatefulPrecompileConfig)
+	}
+	for _, config := range configs {
+		feeManagerConfig, ok := config.(*precompile.FeeConfigManagerConfig)
+		if !ok || feeManagerConfig.IsDisabled() || feeManagerConfig.IsReconfigure() || feeManagerConfig.FeeConfigBounds == nil {
+			continue
+		}
+		if err := feeManagerConfig.FeeConfigBounds.VerifyFeeConfig(c.FeeConfig); err != nil {
+			return fmt.Errorf("fee config manager config at timestamp %v: %w", feeManagerConfig.Timestamp(), err)
+		}
+	}
+	return nil
+}
+
+// verifyNativeMinterInitialMint checks that the native minter is not enabled with both an initial mint and a max
+// supply on top of the storage preserved from a previous activation. The total amount minted before cannot be known
+// when the upgrades are verified, so the initial mint could exceed the max supply once it is added to the total amount
+// minted. Otherwise, the initial mint is verified against the max supply by the config itself.
+func (c *ChainConfig) verifyNativeMinterInitialMint() error {
+	configs := make([]precompile.StatefulPrecompileConfig, 0, len(c.PrecompileUpgrades)+1)
+	if config, ok := c.GenesisPrecompiles[precompile.ContractNativeMinterConfigKey]; ok {
+		configs = append(configs, config)
+	}
+	for _, upgrade := range c.PrecompileUpgrades {
+		if upgrade.Address() == precompile.ContractNativeMinterAddress {
+			configs = append(configs, upgrade.StatefulPrecompileConfig)
+		}
+	}
+	preserved := false
+	for _, config := range configs {
+		if config.IsDisabled() {
+			preserved = precompile.EffectiveStorageMode(config) == precompile.StorageModePreserve
+			continue
+		}
+		minterConfig, ok := config.(*precompile.ContractNativeMinterConfig)
+		if !ok || config.IsReconfigure() {
+			continue
+		}
+		if preserved && config.GetStorageMode() != precompile.StorageModeWipe && len(minterConfig.InitialMint) > 0 && minterConfig.MaxSupply != nil {
+			return fmt.Errorf("native minter config at timestamp %v: %w: cannot be specified with a max supply when enabling the native minter on preserved storage", config.Timestamp(), precompile.ErrInvalidInitialMint)
+		}
+	}
+	return nil
+}
+
+// getActivePrecompileConfig returns the most recent config of the precompile registered as [module].
 // If none have occurred, returns nil.
-func (c *ChainConfig) getActivePrecompileConfig(blockTimestamp *big.Int, key precompileKey, upgrades []PrecompileUpgrade) precompile.StatefulPrecompileConfig {
-	configs := c.getActivatingPrecompileConfigs(nil, blockTimestamp, key, upgrades)
+func (c *ChainConfig) getActivePrecompileConfig(blockTimestamp *big.Int, module precompile.Module, upgrades []PrecompileUpgrade) precompile.StatefulPrecompileConfig {
+	configs := c.getActivatingPrecompileConfigs(nil, blockTimestamp, module, upgrades)
 	if len(configs) == 0 {
 		return nil
 	}
 	return configs[len(configs)-1] // return the most recent config
 }
 
-// getActivatingPrecompileConfigs returns all forks configured to activate during the state transition from a block with timestamp [from]
-// to a block with timestamp [to].
-func (c *ChainConfig) getActivatingPrecompileConfigs(from *big.Int, to *big.Int, key precompileKey, upgrades []PrecompileUpgrade) []precompile.StatefulPrecompileConfig {
+// getActivatingPrecompileConfigs returns all configs of the precompile registered as [module] that are
+// configured to activate during the state transition from a block with timestamp [from] to a block with
+// timestamp [to].
+func (c *ChainConfig) getActivatingPrecompileConfigs(from *big.Int, to *big.Int, module precompile.Module, upgrades []PrecompileUpgrade) []precompile.StatefulPrecompileConfig {
 	configs := make([]precompile.StatefulPrecompileConfig, 0)
-	// First check the embedded [upgrade] for precompiles configured
-	// in the genesis chain config.
-	if config, ok := c.PrecompileUpgrade.getByKey(key); ok {
+	// First check the genesis chain config for the precompile.
+	if config, ok := c.GenesisPrecompiles[module.ConfigKey]; ok {
 		if utils.IsForkTransition(config.Timestamp(), from, to) {
 			configs = append(configs, config)
 		}
 	}
 	// Loop over all upgrades checking for the requested precompile config.
 	for _, upgrade := range upgrades {
-		if config, ok := upgrade.getByKey(key); ok {
-			// Check if the precompile activates in the specified range.
-			if utils.IsForkTransition(config.Timestamp(), from, to) {
-				configs = append(configs, config)
-			}
+		if upgrade.Address() != module.Address {
+			continue
+		}
+		// Check if the precompile activates in the specified range.
+		if utils.IsForkTransition(upgrade.Timestamp(), from, to) {
+			configs = append(configs, upgrade.StatefulPrecompileConfig)
 		}
 	}
 	return configs
 }
 
-// GetContractDeployerAllowListConfig returns the latest forked ContractDeployerAllowListConfig
+// GetActivePrecompileConfig returns the latest forked config of the precompile at [address]
 // specified by [c] or nil if it was never enabled.
-func (c *ChainConfig) GetContractDeployerAllowListConfig(blockTimestamp *big.Int) *precompile.ContractDeployerAllowListConfig {
-	if val := c.getActivePrecompileConfig(blockTimestamp, contractDeployerAllowListKey, c.PrecompileUpgrades); val != nil {
-		return val.(*precompile.ContractDeployerAllowListConfig)
+// Note: the returned config may disable the precompile.
+func (c *ChainConfig) GetActivePrecompileConfig(address common.Address, blockTimestamp *big.Int) precompile.StatefulPrecompileConfig {
+	module, ok := precompile.GetModuleByAddress(address)
+	if !ok {
+		return nil
 	}
-	return nil
+	return c.getActivePrecompileConfig(blockTimestamp, module, c.PrecompileUpgrades)
 }
 
-// GetContractNativeMinterConfig returns the latest forked ContractNativeMinterConfig
-// specified by [c] or nil if it was never enabled.
-func (c *ChainConfig) GetContractNativeMinterConfig(blockTimestamp *big.Int) *precompile.ContractNativeMinterConfig {
-	if val := c.getActivePrecompileConfig(blockTimestamp, contractNativeMinterKey, c.PrecompileUpgrades); val != nil {
-		return val.(*precompile.ContractNativeMinterConfig)
+// GetActivatedPrecompileConfigs returns all configs of the precompile at [address] specified by [c]
+// that have gone into effect at [blockTimestamp], in the order in which they were activated.
+// Returns an empty slice if there is no precompile registered at [address].
+func (c *ChainConfig) GetActivatedPrecompileConfigs(address common.Address, blockTimestamp *big.Int) []precompile.StatefulPrecompileConfig {
+	module, ok := precompile.GetModuleByAddress(address)
+	if !ok {
+		return []precompile.StatefulPrecompileConfig{}
 	}
-	return nil
+	return c.getActivatingPrecompileConfigs(nil, blockTimestamp, module, c.PrecompileUpgrades)
 }
 
-// GetTxAllowListConfig returns the latest forked TxAllowListConfig
-// specified by [c] or nil if it was never enabled.
-func (c *ChainConfig) GetTxAllowListConfig(blockTimestamp *big.Int) *precompile.TxAllowListConfig {
-	if val := c.getActivePrecompileConfig(blockTimestamp, txAllowListKey, c.PrecompileUpgrades); val != nil {
-		return val.(*precompile.TxAllowListConfig)
+// IsPrecompileConfigured returns whether the stateful precompile at [address] is enabled by the genesis
+// or by any of the precompile upgrades of [c], regardless of their activation timestamps.
+func (c *ChainConfig) IsPrecompileConfigured(address common.Address) bool {
+	for _, config := range c.GenesisPrecompiles {
+		if config.Address() == address && !config.IsDisabled() {
+			return true
+		}
 	}
-	return nil
-}
-
-// GetFeeConfigManagerConfig returns the latest forked FeeManagerConfig
-// specified by [c] or nil if it was never enabled.
-func (c *ChainConfig) GetFeeConfigManagerConfig(blockTimestamp *big.Int) *precompile.FeeConfigManagerConfig {
-	if val := c.getActivePrecompileConfig(blockTimestamp, feeManagerKey, c.PrecompileUpgrades); val != nil {
-		return val.(*precompile.FeeConfigManagerConfig)
+	for _, upgrade := range c.PrecompileUpgrades {
+		if upgrade.Address() == address && !upgrade.IsDisabled() {
+			return true
+		}
 	}
-	return nil
+	return false
 }
 
 // CheckPrecompilesCompatible checks if [precompileUpgrades] are compatible with [c] at [headTimestamp].
@@ -197,8 +362,8 @@ func (c *ChainConfig) GetFeeConfigManagerConfig(blockTimestamp *big.Int) *precom
 // [precompileUpgrades]. Upgrades not already forked may be modified or absent from [precompileUpgrades].
 // Returns nil if [precompileUpgrades] is compatible with [c].
 func (c *ChainConfig) CheckPrecompilesCompatible(precompileUpgrades []PrecompileUpgrade, headTimestamp *big.Int) *ConfigCompatError {
-	for _, key := range precompileKeys {
-		if err := c.checkPrecompileCompatible(key, precompileUpgrades, headTimestamp); err != nil {
+	for _, module := range precompile.RegisteredModules() {
+		if err := c.checkPrecompileCompatible(module, precompileUpgrades, headTimestamp); err != nil {
 			return err
 		}
 	}
@@ -206,13 +371,13 @@ func (c *ChainConfig) CheckPrecompilesCompatible(precompileUpgrades []Precompile
 	return nil
 }
 
-// checkPrecompileCompatible verifies that the precompile specified by [key] is compatible between [c] and [precompileUpgrades] at [headTimestamp].
+// checkPrecompileCompatible verifies that the precompile registered as [module] is compatible between [c] and [precompileUpgrades] at [headTimestamp].
 // Returns an error if upgrades already forked at [headTimestamp] are missing from [precompileUpgrades].
 // Upgrades that have already gone into effect cannot be modified or absent from [precompileUpgrades].
-func (c *ChainConfig) checkPrecompileCompatible(key precompileKey, precompileUpgrades []PrecompileUpgrade, headTimestamp *big.Int) *ConfigCompatError {
+func (c *ChainConfig) checkPrecompileCompatible(module precompile.Module, precompileUpgrades []PrecompileUpgrade, headTimestamp *big.Int) *ConfigCompatError {
 	// all active upgrades must match
-	activeUpgrades := c.getActivatingPrecompileConfigs(nil, headTimestamp, key, c.PrecompileUpgrades)
-	newUpgrades := c.getActivatingPrecompileConfigs(nil, headTimestamp, key, precompileUpgrades)
+	activeUpgrades := c.getActivatingPrecompileConfigs(nil, headTimestamp, module, c.PrecompileUpgrades)
+	newUpgrades := c.getActivatingPrecompileConfigs(nil, headTimestamp, module, precompileUpgrades)
 
 	// first, check existing upgrades are there
 	for i, upgrade := range activeUpgrades {
@@ -250,8 +415,8 @@ func (c *ChainConfig) checkPrecompileCompatible(key precompileKey, precompileUpg
 // have been activated through an upgrade.
 func (c *ChainConfig) EnabledStatefulPrecompiles(blockTimestamp *big.Int) []precompile.StatefulPrecompileConfig {
 	statefulPrecompileConfigs := make([]precompile.StatefulPrecompileConfig, 0)
-	for _, key := range precompileKeys {
-		if config := c.getActivePrecompileConfig(blockTimestamp, key, c.PrecompileUpgrades); config != nil {
+	for _, module := range precompile.RegisteredModules() {
+		if config := c.getActivePrecompileConfig(blockTimestamp, module, c.PrecompileUpgrades); config != nil {
 			statefulPrecompileConfigs = append(statefulPrecompileConfigs, config)
 		}
 	}
@@ -261,21 +426,40 @@ func (c *ChainConfig) EnabledStatefulPrecompiles(blockTimestamp *big.Int) []prec
 
 // CheckConfigurePrecompiles checks if any of the precompiles specified by the chain config are enabled or disabled by the block
 // transition from [parentTimestamp] to the timestamp set in [blockContext]. If this is the case, it calls [Configure]
-// or [Deconfigure] to apply the necessary state transitions for the upgrade.
+// or [Deconfigure] to apply the necessary state transitions for the upgrade, wiping or preserving the storage of the
+// precompile according to the storage mode of the upgrade.
 // This function is called:
 // - within genesis setup to configure the starting state for precompiles enabled at genesis,
 // - during block processing to update the state before processing the given block.
+// It also applies the pending fee config of the FeeConfigManager if it is active for the given block, and indexes
+// the members of the allow lists of the enabled precompiles once the PrecompileV2 upgrade is activated.
 func (c *ChainConfig) CheckConfigurePrecompiles(parentTimestamp *big.Int, blockContext precompile.BlockContext, statedb precompile.StateDB) {
+	// The fee config of a block is resolved from the state of its parent, so the pending fee config
+	// becomes the stored fee config in the first block that it applies to.
+	if parentTimestamp != nil && c.IsPrecompileEnabled(precompile.FeeConfigManagerAddress, parentTimestamp) {
+		precompile.ApplyPendingFeeConfig(c, statedb, parentTimestamp, blockContext)
+	}
 	blockTimestamp := blockContext.Timestamp()
-	for _, key := range precompileKeys { // Note: configure precompiles in a deterministic order.
-		for _, config := range c.getActivatingPrecompileConfigs(parentTimestamp, blockTimestamp, key, c.PrecompileUpgrades) {
+	for _, module := range precompile.RegisteredModules() { // Note: configure precompiles in a deterministic order.
+		for _, config := range c.getActivatingPrecompileConfigs(parentTimestamp, blockTimestamp, module, c.PrecompileUpgrades) {
 			// If this transition activates the upgrade, configure the stateful precompile.
-			// (or deconfigure it if it is being disabled.)
-			if config.IsDisabled() {
-				statedb.Suicide(config.Address())
-			} else {
+			// (or deconfigure it if it is being disabled, or reconfigure its existing state.)
+			switch {
+			case config.IsDisabled():
+				precompile.Deconfigure(config, statedb)
+			case config.IsReconfigure():
+				precompile.Reconfigure(c, blockContext, config, statedb)
+			default:
 				precompile.Configure(c, blockContext, config, statedb)
 			}
 		}
 	}
+	if c.IsPrecompileV2(blockTimestamp) {
+		for _, config := range c.EnabledStatefulPrecompiles(blockTimestamp) {
+			if config.IsDisabled() {
+				continue
+			}
+			precompile.ActivateAllowListIndex(statedb, config.Address(), c.GetActivatedPrecompileConfigs(config.Address(), blockTimestamp), parentTimestamp, blockTimestamp)
+		}
+	}
 }
@@ -4,19 +4,21 @@
 package params
 
 import (
+	"encoding/json"
 	"math/big"
 	"testing"
 
 	"github.com/ava-labs/subnet-evm/precompile"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestValidateWithChainConfig(t *testing.T) {
 	admins := []common.Address{{1}}
 	config := &ChainConfig{
-		PrecompileUpgrade: PrecompileUpgrade{
-			TxAllowListConfig: &precompile.TxAllowListConfig{
+		GenesisPrecompiles: Precompiles{
+			precompile.TxAllowListConfigKey: &precompile.TxAllowListConfig{
 				UpgradeableConfig: precompile.UpgradeableConfig{
 					BlockTimestamp: big.NewInt(2),
 				},
@@ -26,11 +28,11 @@ func TestValidateWithChainConfig(t *testing.T) {
 	config.PrecompileUpgrades = []PrecompileUpgrade{
 		{
 			// disable TxAllowList at timestamp 4
-			TxAllowListConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(4)),
+			StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(4)),
 		},
 		{
 			// re-enable TxAllowList at timestamp 5
-			TxAllowListConfig: precompile.NewTxAllowListConfig(big.NewInt(5), admins),
+			StatefulPrecompileConfig: precompile.NewTxAllowListConfig(big.NewInt(5), admins),
 		},
 	}
 
@@ -43,7 +45,7 @@ func TestValidateWithChainConfig(t *testing.T) {
 	badConfig.PrecompileUpgrades = append(
 		badConfig.PrecompileUpgrades,
 		PrecompileUpgrade{
-			TxAllowListConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(5)),
+			StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(5)),
 		},
 	)
 	err = badConfig.VerifyPrecompileUpgrades()
@@ -54,7 +56,7 @@ func TestValidateWithChainConfig(t *testing.T) {
 	badConfig.PrecompileUpgrades = append(
 		badConfig.PrecompileUpgrades,
 		PrecompileUpgrade{
-			TxAllowListConfig: precompile.NewTxAllowListConfig(big.NewInt(5), admins),
+			StatefulPrecompileConfig: precompile.NewTxAllowListConfig(big.NewInt(5), admins),
 		},
 	)
 	err = badConfig.VerifyPrecompileUpgrades()
@@ -66,10 +68,10 @@ func TestValidate(t *testing.T) {
 	config := &ChainConfig{}
 	config.PrecompileUpgrades = []PrecompileUpgrade{
 		{
-			TxAllowListConfig: precompile.NewTxAllowListConfig(big.NewInt(1), admins),
+			StatefulPrecompileConfig: precompile.NewTxAllowListConfig(big.NewInt(1), admins),
 		},
 		{
-			TxAllowListConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(2)),
+			StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(2)),
 		},
 	}
 
@@ -83,10 +85,10 @@ func TestValidateRequiresSortedTimestamps(t *testing.T) {
 	config := &ChainConfig{}
 	config.PrecompileUpgrades = []PrecompileUpgrade{
 		{
-			TxAllowListConfig: precompile.NewTxAllowListConfig(big.NewInt(2), admins),
+			StatefulPrecompileConfig: precompile.NewTxAllowListConfig(big.NewInt(2), admins),
 		},
 		{
-			ContractDeployerAllowListConfig: precompile.NewContractDeployerAllowListConfig(big.NewInt(1), admins),
+			StatefulPrecompileConfig: precompile.NewContractDeployerAllowListConfig(big.NewInt(1), admins),
 		},
 	}
 
@@ -95,23 +97,409 @@ func TestValidateRequiresSortedTimestamps(t *testing.T) {
 	assert.ErrorContains(t, err, "config timestamp (1) < previous timestamp (2)")
 }
 
+func TestValidatePrecompileConfigs(t *testing.T) {
+	admins := []common.Address{{1}}
+	for name, test := range map[string]struct {
+		upgrade     PrecompileUpgrade
+		expectedErr string
+	}{
+		"valid reward manager config": {
+			upgrade: PrecompileUpgrade{
+				StatefulPrecompileConfig: precompile.NewRewardManagerConfig(big.NewInt(1), admins, &precompile.InitialRewardConfig{AllowFeeRecipients: true}),
+			},
+		},
+		"reward manager config with both rewards enabled": {
+			upgrade: PrecompileUpgrade{
+				StatefulPrecompileConfig: precompile.NewRewardManagerConfig(big.NewInt(1), admins, &precompile.InitialRewardConfig{
+					AllowFeeRecipients: true,
+					RewardAddress:      common.Address{2},
+				}),
+			},
+			expectedErr: precompile.ErrCannotEnableBothRewards.Error(),
+		},
+		"fee config manager config with invalid bounds": {
+			upgrade: PrecompileUpgrade{
+				StatefulPrecompileConfig: &precompile.FeeConfigManagerConfig{
+					UpgradeableConfig: precompile.UpgradeableConfig{BlockTimestamp: big.NewInt(1)},
+					FeeConfigBounds: &precompile.FeeConfigBounds{
+						GasLimit: &precompile.FeeConfigFieldBounds{Min: big.NewInt(10), Max: big.NewInt(1)},
+					},
+				},
+			},
+			expectedErr: precompile.ErrInvalidFeeConfigBounds.Error(),
+		},
+		"valid gas overrides": {
+			upgrade: PrecompileUpgrade{
+				StatefulPrecompileConfig: &precompile.ContractNativeMinterConfig{
+					UpgradeableConfig: precompile.UpgradeableConfig{
+						BlockTimestamp: big.NewInt(1),
+						GasOverrides:   map[string]uint64{"mintNativeCoin": 10_000, "setEnabled": 5_000},
+					},
+				},
+			},
+		},
+		"gas override of unknown function": {
+			upgrade: PrecompileUpgrade{
+				StatefulPrecompileConfig: &precompile.ContractNativeMinterConfig{
+					UpgradeableConfig: precompile.UpgradeableConfig{
+						BlockTimestamp: big.NewInt(1),
+						GasOverrides:   map[string]uint64{"setFeeConfig": 10_000},
+					},
+				},
+			},
+			expectedErr: precompile.ErrInvalidGasOverride.Error(),
+		},
+		"zero gas override": {
+			upgrade: PrecompileUpgrade{
+				StatefulPrecompileConfig: &precompile.TxAllowListConfig{
+					UpgradeableConfig: precompile.UpgradeableConfig{
+						BlockTimestamp: big.NewInt(1),
+						GasOverrides:   map[string]uint64{"setAdmin": 0},
+					},
+				},
+			},
+			expectedErr: precompile.ErrInvalidGasOverride.Error(),
+		},
+		"enabled address is an admin": {
+			upgrade: PrecompileUpgrade{
+				StatefulPrecompileConfig: &precompile.TxAllowListConfig{
+					AllowListConfig:   precompile.AllowListConfig{AllowListAdmins: admins, EnabledAddresses: admins},
+					UpgradeableConfig: precompile.UpgradeableConfig{BlockTimestamp: big.NewInt(1)},
+				},
+			},
+			expectedErr: precompile.ErrDuplicateEnabledAddress.Error(),
+		},
+		"initial mint exceeds max supply": {
+			upgrade: PrecompileUpgrade{
+				StatefulPrecompileConfig: &precompile.ContractNativeMinterConfig{
+					UpgradeableConfig: precompile.UpgradeableConfig{BlockTimestamp: big.NewInt(1)},
+					MaxSupply:         math.NewHexOrDecimal256(10),
+					InitialMint:       map[common.Address]*math.HexOrDecimal256{{2}: math.NewHexOrDecimal256(11)},
+				},
+			},
+			expectedErr: precompile.ErrInvalidInitialMint.Error(),
+		},
+		"duplicate admins": {
+			upgrade: PrecompileUpgrade{
+				StatefulPrecompileConfig: precompile.NewTxAllowListConfig(big.NewInt(1), []common.Address{{1}, {1}}),
+			},
+			expectedErr: precompile.ErrDuplicateAdmin.Error(),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			upgrades := NetworkUpgrades{PrecompileV2Timestamp: big.NewInt(0)}
+			config := &ChainConfig{NetworkUpgrades: upgrades}
+			config.PrecompileUpgrades = []PrecompileUpgrade{test.upgrade}
+			err := config.VerifyPrecompileUpgrades()
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedErr)
+			}
+
+			// configs in the genesis are verified as well
+			module, ok := precompile.GetModuleByAddress(test.upgrade.Address())
+			assert.True(t, ok)
+			config = &ChainConfig{NetworkUpgrades: upgrades, GenesisPrecompiles: Precompiles{module.ConfigKey: test.upgrade.StatefulPrecompileConfig}}
+			err = config.VerifyPrecompileUpgrades()
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestVerifyPrecompileConfigsBeforePrecompileV2(t *testing.T) {
+	duplicateAdmins := func(timestamp int64) precompile.StatefulPrecompileConfig {
+		return precompile.NewTxAllowListConfig(big.NewInt(timestamp), []common.Address{{1}, {1}})
+	}
+
+	// the admins of configs activating before the PrecompileV2 upgrade are not verified
+	config := &ChainConfig{
+		NetworkUpgrades:    NetworkUpgrades{PrecompileV2Timestamp: big.NewInt(10)},
+		GenesisPrecompiles: Precompiles{precompile.TxAllowListConfigKey: duplicateAdmins(0)},
+	}
+	assert.NoError(t, config.VerifyPrecompileUpgrades())
+
+	config.PrecompileUpgrades = []PrecompileUpgrade{
+		{StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(5))},
+		{StatefulPrecompileConfig: duplicateAdmins(10)},
+	}
+	assert.ErrorIs(t, config.VerifyPrecompileUpgrades(), precompile.ErrDuplicateAdmin)
+
+	// the admins are not verified without the upgrade
+	config.PrecompileV2Timestamp = nil
+	assert.NoError(t, config.VerifyPrecompileUpgrades())
+
+	// the other parameters are verified regardless of the upgrade
+	for name, test := range map[string]struct {
+		config      precompile.StatefulPrecompileConfig
+		expectedErr string
+	}{
+		"negative initial mint": {
+			config: &precompile.ContractNativeMinterConfig{
+				UpgradeableConfig: precompile.UpgradeableConfig{BlockTimestamp: big.NewInt(0)},
+				InitialMint:       map[common.Address]*math.HexOrDecimal256{{2}: math.NewHexOrDecimal256(-1)},
+			},
+			expectedErr: precompile.ErrInvalidInitialMint.Error(),
+		},
+		"quorum numerator exceeds the quorum denominator": {
+			config:      precompile.NewWarpMessengerConfig(big.NewInt(0), precompile.WarpQuorumDenominator+1, nil),
+			expectedErr: "cannot exceed quorum denominator",
+		},
+		"duplicate enabled addresses": {
+			config: &precompile.TxAllowListConfig{
+				UpgradeableConfig: precompile.UpgradeableConfig{BlockTimestamp: big.NewInt(0)},
+				AllowListConfig:   precompile.AllowListConfig{EnabledAddresses: []common.Address{{2}, {2}}},
+			},
+			expectedErr: precompile.ErrDuplicateEnabledAddress.Error(),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			config := &ChainConfig{
+				NetworkUpgrades:    NetworkUpgrades{PrecompileV2Timestamp: big.NewInt(10)},
+				GenesisPrecompiles: Precompiles{},
+			}
+			module, ok := precompile.GetModuleByAddress(test.config.Address())
+			assert.True(t, ok)
+			config.GenesisPrecompiles[module.ConfigKey] = test.config
+			assert.ErrorContains(t, config.VerifyPrecompileUpgrades(), test.expectedErr)
+
+			config.GenesisPrecompiles = nil
+			config.PrecompileUpgrades = []PrecompileUpgrade{{StatefulPrecompileConfig: test.config}}
+			assert.ErrorContains(t, config.VerifyPrecompileUpgrades(), test.expectedErr)
+		})
+	}
+}
+
+func TestVerifyFeeConfigBounds(t *testing.T) {
+	boundedConfig := func(timestamp int64, gasLimitBounds *precompile.FeeConfigFieldBounds) *precompile.FeeConfigManagerConfig {
+		return &precompile.FeeConfigManagerConfig{
+			UpgradeableConfig: precompile.UpgradeableConfig{BlockTimestamp: big.NewInt(timestamp)},
+			FeeConfigBounds:   &precompile.FeeConfigBounds{GasLimit: gasLimitBounds},
+		}
+	}
+	inBounds := &precompile.FeeConfigFieldBounds{Min: big.NewInt(1_000_000), Max: big.NewInt(10_000_000)}
+	outOfBounds := &precompile.FeeConfigFieldBounds{Min: big.NewInt(10_000_000)}
+
+	for name, test := range map[string]struct {
+		genesisPrecompiles Precompiles
+		upgrades           []PrecompileUpgrade
+		expectedErr        error
+	}{
+		"genesis fee config within genesis bounds": {
+			genesisPrecompiles: Precompiles{precompile.FeeConfigManagerConfigKey: boundedConfig(0, inBounds)},
+		},
+		"genesis fee config outside of genesis bounds": {
+			genesisPrecompiles: Precompiles{precompile.FeeConfigManagerConfigKey: boundedConfig(0, outOfBounds)},
+			expectedErr:        precompile.ErrFeeConfigOutOfBounds,
+		},
+		"genesis fee config outside of upgrade bounds": {
+			upgrades: []PrecompileUpgrade{
+				{StatefulPrecompileConfig: boundedConfig(1, inBounds)},
+				{StatefulPrecompileConfig: precompile.NewDisableFeeManagerConfig(big.NewInt(2))},
+				{StatefulPrecompileConfig: boundedConfig(3, outOfBounds)},
+			},
+			expectedErr: precompile.ErrFeeConfigOutOfBounds,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			config := *TestChainConfig
+			config.GenesisPrecompiles = test.genesisPrecompiles
+			config.PrecompileUpgrades = test.upgrades
+			err := config.Verify()
+			if test.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestVerifyNativeMinterInitialMint(t *testing.T) {
+	minterConfig := func(timestamp int64, storageMode precompile.StorageMode, initialMint bool) *precompile.ContractNativeMinterConfig {
+		config := &precompile.ContractNativeMinterConfig{
+			UpgradeableConfig: precompile.UpgradeableConfig{BlockTimestamp: big.NewInt(timestamp), StorageMode: storageMode},
+			MaxSupply:         math.NewHexOrDecimal256(1_000),
+		}
+		if initialMint {
+			config.InitialMint = map[common.Address]*math.HexOrDecimal256{{1}: math.NewHexOrDecimal256(100)}
+		}
+		return config
+	}
+	disableConfig := func(timestamp int64, storageMode precompile.StorageMode) *precompile.ContractNativeMinterConfig {
+		config := precompile.NewDisableContractNativeMinterConfig(big.NewInt(timestamp))
+		config.StorageMode = storageMode
+		return config
+	}
+
+	for name, test := range map[string]struct {
+		upgrades    []PrecompileUpgrade
+		expectedErr error
+	}{
+		"initial mint after a wiping disable": {
+			upgrades: []PrecompileUpgrade{
+				{StatefulPrecompileConfig: minterConfig(1, "", true)},
+				{StatefulPrecompileConfig: disableConfig(2, "")},
+				{StatefulPrecompileConfig: minterConfig(3, "", true)},
+			},
+		},
+		"initial mint after a preserving disable": {
+			upgrades: []PrecompileUpgrade{
+				{StatefulPrecompileConfig: minterConfig(1, "", true)},
+				{StatefulPrecompileConfig: disableConfig(2, precompile.StorageModePreserve)},
+				{StatefulPrecompileConfig: minterConfig(3, "", true)},
+			},
+			expectedErr: precompile.ErrInvalidInitialMint,
+		},
+		"initial mint wiping the storage preserved by the disable": {
+			upgrades: []PrecompileUpgrade{
+				{StatefulPrecompileConfig: minterConfig(1, "", true)},
+				{StatefulPrecompileConfig: disableConfig(2, precompile.StorageModePreserve)},
+				{StatefulPrecompileConfig: minterConfig(3, precompile.StorageModeWipe, true)},
+			},
+		},
+		"no initial mint after a preserving disable": {
+			upgrades: []PrecompileUpgrade{
+				{StatefulPrecompileConfig: minterConfig(1, "", true)},
+				{StatefulPrecompileConfig: disableConfig(2, precompile.StorageModePreserve)},
+				{StatefulPrecompileConfig: minterConfig(3, "", false)},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			config := *TestChainConfig
+			config.PrecompileUpgrades = test.upgrades
+			err := config.Verify()
+			if test.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.expectedErr)
+			}
+		})
+	}
+}
+
 func TestGetPrecompileConfig(t *testing.T) {
 	assert := assert.New(t)
 	baseConfig := *SubnetEVMDefaultChainConfig
 	config := &baseConfig
-	config.PrecompileUpgrade = PrecompileUpgrade{
-		ContractDeployerAllowListConfig: precompile.NewContractDeployerAllowListConfig(big.NewInt(10), []common.Address{}),
+	config.GenesisPrecompiles = Precompiles{
+		precompile.ContractDeployerAllowListConfigKey: precompile.NewContractDeployerAllowListConfig(big.NewInt(10), []common.Address{}),
 	}
 
-	deployerConfig := config.GetContractDeployerAllowListConfig(big.NewInt(0))
+	deployerConfig := config.GetActivePrecompileConfig(precompile.ContractDeployerAllowListAddress, big.NewInt(0))
 	assert.Nil(deployerConfig)
 
-	deployerConfig = config.GetContractDeployerAllowListConfig(big.NewInt(10))
+	deployerConfig = config.GetActivePrecompileConfig(precompile.ContractDeployerAllowListAddress, big.NewInt(10))
 	assert.NotNil(deployerConfig)
 
-	deployerConfig = config.GetContractDeployerAllowListConfig(big.NewInt(11))
+	deployerConfig = config.GetActivePrecompileConfig(precompile.ContractDeployerAllowListAddress, big.NewInt(11))
 	assert.NotNil(deployerConfig)
 
-	txAllowListConfig := config.GetTxAllowListConfig(big.NewInt(0))
+	txAllowListConfig := config.GetActivePrecompileConfig(precompile.TxAllowListAddress, big.NewInt(0))
 	assert.Nil(txAllowListConfig)
 }
+
+func TestGasOverridesUpgrade(t *testing.T) {
+	upgradeBytes := []byte(`{"precompileUpgrades":[` +
+		`{"txAllowListConfig":{"blockTimestamp":1,"adminAddresses":["0x0100000000000000000000000000000000000000"],"gasOverrides":{"setEnabled":1000}}},` +
+		`{"txAllowListConfig":{"blockTimestamp":2,"disable":true}},` +
+		`{"txAllowListConfig":{"blockTimestamp":3,"adminAddresses":["0x0100000000000000000000000000000000000000"],"gasOverrides":{"setEnabled":2000,"readAllowList":10}}}` +
+		`]}`)
+
+	var upgradeConfig UpgradeConfig
+	assert.NoError(t, json.Unmarshal(upgradeBytes, &upgradeConfig))
+	config := &ChainConfig{}
+	config.PrecompileUpgrades = upgradeConfig.PrecompileUpgrades
+	assert.NoError(t, config.VerifyPrecompileUpgrades())
+
+	// the gas overrides of the active config apply
+	assert.Equal(t, map[string]uint64{"setEnabled": 1000}, config.GetActivePrecompileConfig(precompile.TxAllowListAddress, big.NewInt(1)).GetGasOverrides())
+	assert.Empty(t, config.GetActivePrecompileConfig(precompile.TxAllowListAddress, big.NewInt(2)).GetGasOverrides())
+	assert.Equal(t, map[string]uint64{"setEnabled": 2000, "readAllowList": 10}, config.GetActivePrecompileConfig(precompile.TxAllowListAddress, big.NewInt(3)).GetGasOverrides())
+
+	// the gas overrides are preserved when the upgrades are encoded
+	encoded, err := json.Marshal(upgradeConfig)
+	assert.NoError(t, err)
+	var decoded UpgradeConfig
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+	for i, upgrade := range decoded.PrecompileUpgrades {
+		assert.True(t, upgrade.Equal(upgradeConfig.PrecompileUpgrades[i].StatefulPrecompileConfig))
+	}
+}
+
+func TestPrecompileUpgradeJSON(t *testing.T) {
+	upgradeBytes := []byte(`{"precompileUpgrades":[{"txAllowListConfig":{"blockTimestamp":1,"adminAddresses":["0x0100000000000000000000000000000000000000"]}},{"txAllowListConfig":{"blockTimestamp":2,"disable":true}}]}`)
+
+	var upgradeConfig UpgradeConfig
+	assert.NoError(t, json.Unmarshal(upgradeBytes, &upgradeConfig))
+	assert.Len(t, upgradeConfig.PrecompileUpgrades, 2)
+	assert.True(t, upgradeConfig.PrecompileUpgrades[0].Equal(precompile.NewTxAllowListConfig(big.NewInt(1), []common.Address{{1}})))
+	assert.True(t, upgradeConfig.PrecompileUpgrades[1].Equal(precompile.NewDisableTxAllowListConfig(big.NewInt(2))))
+
+	encoded, err := json.Marshal(upgradeConfig)
+	assert.NoError(t, err)
+	var decoded UpgradeConfig
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Len(t, decoded.PrecompileUpgrades, 2)
+	for i, upgrade := range decoded.PrecompileUpgrades {
+		assert.True(t, upgrade.Equal(upgradeConfig.PrecompileUpgrades[i].StatefulPrecompileConfig))
+	}
+
+	for name, test := range map[string]struct {
+		upgradeBytes string
+		expectedErr  string
+	}{
+		"empty upgrade": {
+			upgradeBytes: `{}`,
+			expectedErr:  errEmptyPrecompileUpgrade.Error(),
+		},
+		"multiple keys": {
+			upgradeBytes: `{"txAllowListConfig":{"blockTimestamp":1},"feeManagerConfig":{"blockTimestamp":1}}`,
+			expectedErr:  errMultiplePrecompileKeys.Error(),
+		},
+		"unknown key": {
+			upgradeBytes: `{"unknownConfig":{"blockTimestamp":1}}`,
+			expectedErr:  errUnknownPrecompileKey.Error(),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var upgrade PrecompileUpgrade
+			err := json.Unmarshal([]byte(test.upgradeBytes), &upgrade)
+			assert.ErrorContains(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestGenesisPrecompilesJSON(t *testing.T) {
+	config := *TestChainConfig
+	config.GenesisPrecompiles = Precompiles{
+		precompile.ContractDeployerAllowListConfigKey: precompile.NewContractDeployerAllowListConfig(big.NewInt(0), []common.Address{{1}}),
+		precompile.FeeConfigManagerConfigKey:          precompile.NewFeeManagerConfig(big.NewInt(10), []common.Address{{2}}),
+	}
+
+	encoded, err := json.Marshal(&config)
+	assert.NoError(t, err)
+
+	// the precompile configs are encoded alongside the other fields of the chain config
+	fields := make(map[string]json.RawMessage)
+	assert.NoError(t, json.Unmarshal(encoded, &fields))
+	assert.Contains(t, fields, precompile.ContractDeployerAllowListConfigKey)
+	assert.Contains(t, fields, precompile.FeeConfigManagerConfigKey)
+	assert.Contains(t, fields, "chainId")
+
+	var decoded ChainConfig
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, config.ChainID, decoded.ChainID)
+	assert.Len(t, decoded.GenesisPrecompiles, 2)
+	for key, config := range config.GenesisPrecompiles {
+		assert.True(t, config.Equal(decoded.GenesisPrecompiles[key]))
+	}
+	assert.True(t, decoded.IsPrecompileEnabled(precompile.ContractDeployerAllowListAddress, big.NewInt(0)))
+	assert.False(t, decoded.IsPrecompileEnabled(precompile.FeeConfigManagerAddress, big.NewInt(9)))
+	assert.True(t, decoded.IsPrecompileEnabled(precompile.FeeConfigManagerAddress, big.NewInt(10)))
+}
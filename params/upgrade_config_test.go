@@ -9,13 +9,30 @@ import (
 
 	"github.com/ava-labs/subnet-evm/precompile"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/stretchr/testify/assert"
 )
 
+// newReconfigureTxAllowListConfig returns a config that reconfigures the enabled tx allow list at
+// [blockTimestamp] with [admins] as additional admins.
+func newReconfigureTxAllowListConfig(blockTimestamp *big.Int, admins []common.Address) *precompile.TxAllowListConfig {
+	config := precompile.NewTxAllowListConfig(blockTimestamp, admins)
+	config.Reconfiguration = true
+	return config
+}
+
+// newStorageModeConfig sets the storage mode of [config] to [mode] and returns it.
+func newStorageModeConfig(config *precompile.TxAllowListConfig, mode precompile.StorageMode) *precompile.TxAllowListConfig {
+	config.StorageMode = mode
+	return config
+}
+
 func TestVerifyUpgradeConfig(t *testing.T) {
 	admins := []common.Address{{1}}
 	chainConfig := *TestChainConfig
-	chainConfig.TxAllowListConfig = precompile.NewTxAllowListConfig(big.NewInt(1), admins)
+	chainConfig.GenesisPrecompiles = Precompiles{
+		precompile.TxAllowListConfigKey: precompile.NewTxAllowListConfig(big.NewInt(1), admins),
+	}
 
 	type test struct {
 		upgrades            []PrecompileUpgrade
@@ -27,7 +44,7 @@ func TestVerifyUpgradeConfig(t *testing.T) {
 			expectedErrorString: "disable should be [true]",
 			upgrades: []PrecompileUpgrade{
 				{
-					TxAllowListConfig: precompile.NewTxAllowListConfig(big.NewInt(2), admins),
+					StatefulPrecompileConfig: precompile.NewTxAllowListConfig(big.NewInt(2), admins),
 				},
 			},
 		},
@@ -35,7 +52,7 @@ func TestVerifyUpgradeConfig(t *testing.T) {
 			expectedErrorString: "config timestamp (0) <= previous timestamp (1)",
 			upgrades: []PrecompileUpgrade{
 				{
-					TxAllowListConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(0)),
+					StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(0)),
 				},
 			},
 		},
@@ -43,7 +60,92 @@ func TestVerifyUpgradeConfig(t *testing.T) {
 			expectedErrorString: "config timestamp (1) <= previous timestamp (1)",
 			upgrades: []PrecompileUpgrade{
 				{
-					TxAllowListConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(1)),
+					StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(1)),
+				},
+			},
+		},
+		"reconfigure enabled precompile": {
+			upgrades: []PrecompileUpgrade{
+				{
+					StatefulPrecompileConfig: newReconfigureTxAllowListConfig(big.NewInt(2), []common.Address{{2}}),
+				},
+				{
+					StatefulPrecompileConfig: newReconfigureTxAllowListConfig(big.NewInt(3), []common.Address{{3}}),
+				},
+			},
+		},
+		"disable reconfigured precompile": {
+			upgrades: []PrecompileUpgrade{
+				{
+					StatefulPrecompileConfig: newReconfigureTxAllowListConfig(big.NewInt(2), []common.Address{{2}}),
+				},
+				{
+					StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(3)),
+				},
+			},
+		},
+		"reconfigure before enable": {
+			expectedErrorString: "config timestamp (0) <= previous timestamp (1)",
+			upgrades: []PrecompileUpgrade{
+				{
+					StatefulPrecompileConfig: newReconfigureTxAllowListConfig(big.NewInt(0), []common.Address{{2}}),
+				},
+			},
+		},
+		"reconfigure disabled precompile": {
+			expectedErrorString: "cannot reconfigure a disabled precompile",
+			upgrades: []PrecompileUpgrade{
+				{
+					StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(2)),
+				},
+				{
+					StatefulPrecompileConfig: newReconfigureTxAllowListConfig(big.NewInt(3), []common.Address{{2}}),
+				},
+			},
+		},
+		"reconfigure and disable": {
+			expectedErrorString: "cannot both disable and reconfigure",
+			upgrades: []PrecompileUpgrade{
+				{
+					StatefulPrecompileConfig: func() *precompile.TxAllowListConfig {
+						config := precompile.NewDisableTxAllowListConfig(big.NewInt(2))
+						config.Reconfiguration = true
+						return config
+					}(),
+				},
+			},
+		},
+		"invalid reconfigure config": {
+			expectedErrorString: precompile.ErrDuplicateAdmin.Error(),
+			upgrades: []PrecompileUpgrade{
+				{
+					StatefulPrecompileConfig: newReconfigureTxAllowListConfig(big.NewInt(2), []common.Address{{2}, {2}}),
+				},
+			},
+		},
+		"preserve storage on disable and wipe on re-enable": {
+			upgrades: []PrecompileUpgrade{
+				{
+					StatefulPrecompileConfig: newStorageModeConfig(precompile.NewDisableTxAllowListConfig(big.NewInt(2)), precompile.StorageModePreserve),
+				},
+				{
+					StatefulPrecompileConfig: newStorageModeConfig(precompile.NewTxAllowListConfig(big.NewInt(3), admins), precompile.StorageModeWipe),
+				},
+			},
+		},
+		"invalid storage mode": {
+			expectedErrorString: precompile.ErrInvalidStorageMode.Error(),
+			upgrades: []PrecompileUpgrade{
+				{
+					StatefulPrecompileConfig: newStorageModeConfig(precompile.NewDisableTxAllowListConfig(big.NewInt(2)), "keep"),
+				},
+			},
+		},
+		"storage mode on reconfigure": {
+			expectedErrorString: "cannot set a storage mode when reconfiguring",
+			upgrades: []PrecompileUpgrade{
+				{
+					StatefulPrecompileConfig: newStorageModeConfig(newReconfigureTxAllowListConfig(big.NewInt(2), []common.Address{{2}}), precompile.StorageModeWipe),
 				},
 			},
 		},
@@ -69,9 +171,21 @@ func TestVerifyUpgradeConfig(t *testing.T) {
 
 func TestCheckCompatibleUpgradeConfigs(t *testing.T) {
 	admins := []common.Address{{1}}
+	initialMintConfig := func(blockTimestamp *big.Int, amount int64) *precompile.ContractNativeMinterConfig {
+		config := precompile.NewContractNativeMinterConfig(blockTimestamp, admins)
+		config.InitialMint = map[common.Address]*math.HexOrDecimal256{{2}: math.NewHexOrDecimal256(amount)}
+		return config
+	}
+	enabledAddressesConfig := func(blockTimestamp *big.Int, enabled []common.Address) *precompile.TxAllowListConfig {
+		config := precompile.NewTxAllowListConfig(blockTimestamp, admins)
+		config.EnabledAddresses = enabled
+		return config
+	}
 	chainConfig := *TestChainConfig
-	chainConfig.TxAllowListConfig = precompile.NewTxAllowListConfig(big.NewInt(1), admins)
-	chainConfig.ContractDeployerAllowListConfig = precompile.NewContractDeployerAllowListConfig(big.NewInt(10), admins)
+	chainConfig.GenesisPrecompiles = Precompiles{
+		precompile.TxAllowListConfigKey:               precompile.NewTxAllowListConfig(big.NewInt(1), admins),
+		precompile.ContractDeployerAllowListConfigKey: precompile.NewContractDeployerAllowListConfig(big.NewInt(10), admins),
+	}
 
 	type test struct {
 		configs             []*UpgradeConfig
@@ -86,10 +200,10 @@ func TestCheckCompatibleUpgradeConfigs(t *testing.T) {
 				{
 					PrecompileUpgrades: []PrecompileUpgrade{
 						{
-							TxAllowListConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
+							StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
 						},
 						{
-							TxAllowListConfig: precompile.NewTxAllowListConfig(big.NewInt(7), admins),
+							StatefulPrecompileConfig: precompile.NewTxAllowListConfig(big.NewInt(7), admins),
 						},
 					},
 				},
@@ -101,20 +215,20 @@ func TestCheckCompatibleUpgradeConfigs(t *testing.T) {
 				{
 					PrecompileUpgrades: []PrecompileUpgrade{
 						{
-							TxAllowListConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
+							StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
 						},
 						{
-							TxAllowListConfig: precompile.NewTxAllowListConfig(big.NewInt(7), admins),
+							StatefulPrecompileConfig: precompile.NewTxAllowListConfig(big.NewInt(7), admins),
 						},
 					},
 				},
 				{
 					PrecompileUpgrades: []PrecompileUpgrade{
 						{
-							TxAllowListConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
+							StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
 						},
 						{
-							TxAllowListConfig: precompile.NewTxAllowListConfig(big.NewInt(8), admins),
+							StatefulPrecompileConfig: precompile.NewTxAllowListConfig(big.NewInt(8), admins),
 						},
 					},
 				},
@@ -127,20 +241,20 @@ func TestCheckCompatibleUpgradeConfigs(t *testing.T) {
 				{
 					PrecompileUpgrades: []PrecompileUpgrade{
 						{
-							TxAllowListConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
+							StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
 						},
 						{
-							TxAllowListConfig: precompile.NewTxAllowListConfig(big.NewInt(7), admins),
+							StatefulPrecompileConfig: precompile.NewTxAllowListConfig(big.NewInt(7), admins),
 						},
 					},
 				},
 				{
 					PrecompileUpgrades: []PrecompileUpgrade{
 						{
-							TxAllowListConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
+							StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
 						},
 						{
-							TxAllowListConfig: precompile.NewTxAllowListConfig(big.NewInt(8), admins),
+							StatefulPrecompileConfig: precompile.NewTxAllowListConfig(big.NewInt(8), admins),
 						},
 					},
 				},
@@ -152,17 +266,17 @@ func TestCheckCompatibleUpgradeConfigs(t *testing.T) {
 				{
 					PrecompileUpgrades: []PrecompileUpgrade{
 						{
-							TxAllowListConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
+							StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
 						},
 						{
-							TxAllowListConfig: precompile.NewTxAllowListConfig(big.NewInt(7), admins),
+							StatefulPrecompileConfig: precompile.NewTxAllowListConfig(big.NewInt(7), admins),
 						},
 					},
 				},
 				{
 					PrecompileUpgrades: []PrecompileUpgrade{
 						{
-							TxAllowListConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
+							StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
 						},
 					},
 				},
@@ -175,17 +289,17 @@ func TestCheckCompatibleUpgradeConfigs(t *testing.T) {
 				{
 					PrecompileUpgrades: []PrecompileUpgrade{
 						{
-							TxAllowListConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
+							StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
 						},
 						{
-							TxAllowListConfig: precompile.NewTxAllowListConfig(big.NewInt(7), admins),
+							StatefulPrecompileConfig: precompile.NewTxAllowListConfig(big.NewInt(7), admins),
 						},
 					},
 				},
 				{
 					PrecompileUpgrades: []PrecompileUpgrade{
 						{
-							TxAllowListConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
+							StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
 						},
 					},
 				},
@@ -198,46 +312,190 @@ func TestCheckCompatibleUpgradeConfigs(t *testing.T) {
 				{
 					PrecompileUpgrades: []PrecompileUpgrade{
 						{
-							TxAllowListConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
+							StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
 						},
 						{
-							TxAllowListConfig: precompile.NewTxAllowListConfig(big.NewInt(7), admins),
+							StatefulPrecompileConfig: precompile.NewTxAllowListConfig(big.NewInt(7), admins),
 						},
 					},
 				},
 				{
 					PrecompileUpgrades: []PrecompileUpgrade{
 						{
-							TxAllowListConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
+							StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
 						},
 						{
 							// uses a different (empty) admin list, not allowed
-							TxAllowListConfig: precompile.NewTxAllowListConfig(big.NewInt(7), []common.Address{}),
+							StatefulPrecompileConfig: precompile.NewTxAllowListConfig(big.NewInt(7), []common.Address{}),
+						},
+					},
+				},
+			},
+		},
+		"change initial mint after activation not allowed": {
+			expectedErrorString: "mismatching PrecompileUpgrade",
+			startTimestamps:     []*big.Int{big.NewInt(5), big.NewInt(8)},
+			configs: []*UpgradeConfig{
+				{
+					PrecompileUpgrades: []PrecompileUpgrade{
+						{
+							StatefulPrecompileConfig: initialMintConfig(big.NewInt(7), 100),
+						},
+					},
+				},
+				{
+					PrecompileUpgrades: []PrecompileUpgrade{
+						{
+							// mints a different amount on activation, not allowed
+							StatefulPrecompileConfig: initialMintConfig(big.NewInt(7), 200),
+						},
+					},
+				},
+			},
+		},
+		"change initial mint before activation": {
+			startTimestamps: []*big.Int{big.NewInt(5), big.NewInt(6)},
+			configs: []*UpgradeConfig{
+				{
+					PrecompileUpgrades: []PrecompileUpgrade{
+						{
+							StatefulPrecompileConfig: initialMintConfig(big.NewInt(7), 100),
+						},
+					},
+				},
+				{
+					PrecompileUpgrades: []PrecompileUpgrade{
+						{
+							StatefulPrecompileConfig: initialMintConfig(big.NewInt(7), 200),
+						},
+					},
+				},
+			},
+		},
+		"change enabled addresses after activation not allowed": {
+			expectedErrorString: "mismatching PrecompileUpgrade",
+			startTimestamps:     []*big.Int{big.NewInt(5), big.NewInt(8)},
+			configs: []*UpgradeConfig{
+				{
+					PrecompileUpgrades: []PrecompileUpgrade{
+						{
+							StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
+						},
+						{
+							StatefulPrecompileConfig: enabledAddressesConfig(big.NewInt(7), []common.Address{{2}}),
+						},
+					},
+				},
+				{
+					PrecompileUpgrades: []PrecompileUpgrade{
+						{
+							StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
+						},
+						{
+							// enables different addresses, not allowed
+							StatefulPrecompileConfig: enabledAddressesConfig(big.NewInt(7), []common.Address{{3}}),
+						},
+					},
+				},
+			},
+		},
+		"reconfigure, reschedule upgrade before it happens": {
+			startTimestamps: []*big.Int{big.NewInt(5), big.NewInt(6)},
+			configs: []*UpgradeConfig{
+				{
+					PrecompileUpgrades: []PrecompileUpgrade{
+						{
+							StatefulPrecompileConfig: newReconfigureTxAllowListConfig(big.NewInt(7), []common.Address{{2}}),
+						},
+					},
+				},
+				{
+					PrecompileUpgrades: []PrecompileUpgrade{
+						{
+							StatefulPrecompileConfig: newReconfigureTxAllowListConfig(big.NewInt(8), []common.Address{{2}}),
+						},
+					},
+				},
+			},
+		},
+		"reconfigure, change upgrade config after upgrade not allowed": {
+			expectedErrorString: "mismatching PrecompileUpgrade",
+			startTimestamps:     []*big.Int{big.NewInt(5), big.NewInt(8)},
+			configs: []*UpgradeConfig{
+				{
+					PrecompileUpgrades: []PrecompileUpgrade{
+						{
+							StatefulPrecompileConfig: newReconfigureTxAllowListConfig(big.NewInt(7), []common.Address{{2}}),
+						},
+					},
+				},
+				{
+					PrecompileUpgrades: []PrecompileUpgrade{
+						{
+							// adds a different admin, not allowed
+							StatefulPrecompileConfig: newReconfigureTxAllowListConfig(big.NewInt(7), []common.Address{{3}}),
 						},
 					},
 				},
 			},
 		},
+		"reconfigure, replace with re-enable after upgrade not allowed": {
+			expectedErrorString: "mismatching PrecompileUpgrade",
+			startTimestamps:     []*big.Int{big.NewInt(5), big.NewInt(8)},
+			configs: []*UpgradeConfig{
+				{
+					PrecompileUpgrades: []PrecompileUpgrade{
+						{
+							StatefulPrecompileConfig: newReconfigureTxAllowListConfig(big.NewInt(7), []common.Address{{2}}),
+						},
+					},
+				},
+				{
+					PrecompileUpgrades: []PrecompileUpgrade{
+						{
+							// the same config that re-enables the precompile instead of reconfiguring it, not allowed
+							StatefulPrecompileConfig: precompile.NewTxAllowListConfig(big.NewInt(7), []common.Address{{2}}),
+						},
+					},
+				},
+			},
+		},
+		"reconfigure, cancel upgrade after it happens": {
+			expectedErrorString: "mismatching missing PrecompileUpgrade",
+			startTimestamps:     []*big.Int{big.NewInt(5), big.NewInt(8)},
+			configs: []*UpgradeConfig{
+				{
+					PrecompileUpgrades: []PrecompileUpgrade{
+						{
+							StatefulPrecompileConfig: newReconfigureTxAllowListConfig(big.NewInt(7), []common.Address{{2}}),
+						},
+					},
+				},
+				{
+					PrecompileUpgrades: []PrecompileUpgrade{},
+				},
+			},
+		},
 		"disable and re-enable, identical upgrade config should be accepted": {
 			startTimestamps: []*big.Int{big.NewInt(5), big.NewInt(8)},
 			configs: []*UpgradeConfig{
 				{
 					PrecompileUpgrades: []PrecompileUpgrade{
 						{
-							TxAllowListConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
+							StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
 						},
 						{
-							TxAllowListConfig: precompile.NewTxAllowListConfig(big.NewInt(7), admins),
+							StatefulPrecompileConfig: precompile.NewTxAllowListConfig(big.NewInt(7), admins),
 						},
 					},
 				},
 				{
 					PrecompileUpgrades: []PrecompileUpgrade{
 						{
-							TxAllowListConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
+							StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(6)),
 						},
 						{
-							TxAllowListConfig: precompile.NewTxAllowListConfig(big.NewInt(7), admins),
+							StatefulPrecompileConfig: precompile.NewTxAllowListConfig(big.NewInt(7), admins),
 						},
 					},
 				},
@@ -278,3 +536,63 @@ func TestCheckCompatibleUpgradeConfigs(t *testing.T) {
 		})
 	}
 }
+
+func TestReconfigurePrecompileUpgrade(t *testing.T) {
+	admins := []common.Address{{1}}
+	chainConfig := *TestChainConfig
+	chainConfig.GenesisPrecompiles = Precompiles{
+		precompile.TxAllowListConfigKey: precompile.NewTxAllowListConfig(big.NewInt(1), admins),
+	}
+	reconfigured := newReconfigureTxAllowListConfig(big.NewInt(5), []common.Address{{2}})
+	chainConfig.PrecompileUpgrades = []PrecompileUpgrade{{StatefulPrecompileConfig: reconfigured}}
+	assert.NoError(t, chainConfig.Verify())
+
+	// the precompile stays enabled across the reconfiguration, which becomes its active config
+	for _, timestamp := range []int64{1, 4, 5, 6} {
+		assert.True(t, chainConfig.IsPrecompileEnabled(precompile.TxAllowListAddress, big.NewInt(timestamp)))
+	}
+	assert.False(t, chainConfig.GetActivePrecompileConfig(precompile.TxAllowListAddress, big.NewInt(4)).IsReconfigure())
+	assert.True(t, chainConfig.GetActivePrecompileConfig(precompile.TxAllowListAddress, big.NewInt(5)).Equal(reconfigured))
+
+	// a reconfiguration cannot be specified in the genesis
+	chainConfig.GenesisPrecompiles = Precompiles{precompile.TxAllowListConfigKey: reconfigured}
+	chainConfig.PrecompileUpgrades = nil
+	assert.ErrorContains(t, chainConfig.Verify(), "cannot reconfigure")
+}
+
+func TestPrecompileStorageModes(t *testing.T) {
+	admins := []common.Address{{1}}
+	chainConfig := *TestChainConfig
+	chainConfig.GenesisPrecompiles = Precompiles{
+		precompile.TxAllowListConfigKey: precompile.NewTxAllowListConfig(big.NewInt(1), admins),
+	}
+	chainConfig.PrecompileUpgrades = []PrecompileUpgrade{
+		{StatefulPrecompileConfig: newStorageModeConfig(precompile.NewDisableTxAllowListConfig(big.NewInt(5)), precompile.StorageModePreserve)},
+		{StatefulPrecompileConfig: precompile.NewTxAllowListConfig(big.NewInt(6), admins)},
+		{StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(7))},
+		{StatefulPrecompileConfig: newStorageModeConfig(precompile.NewTxAllowListConfig(big.NewInt(8), admins), precompile.StorageModeWipe)},
+	}
+	assert.NoError(t, chainConfig.Verify())
+
+	// only the activations that have gone into effect are returned, along with the storage mode each of them applied
+	configs := chainConfig.GetActivatedPrecompileConfigs(precompile.TxAllowListAddress, big.NewInt(7))
+	modes := make([]precompile.StorageMode, 0, len(configs))
+	for _, config := range configs {
+		modes = append(modes, precompile.EffectiveStorageMode(config))
+	}
+	assert.Equal(t, []precompile.StorageMode{
+		precompile.StorageModePreserve, // enabled in genesis
+		precompile.StorageModePreserve,
+		precompile.StorageModePreserve,
+		precompile.StorageModeWipe, // disabled with the default storage mode
+	}, modes)
+	assert.Len(t, chainConfig.GetActivatedPrecompileConfigs(precompile.TxAllowListAddress, big.NewInt(8)), 5)
+	assert.Empty(t, chainConfig.GetActivatedPrecompileConfigs(common.Address{1}, big.NewInt(8)))
+
+	// the storage mode of an upgrade cannot be changed after it has gone into effect
+	newUpgrades := make([]PrecompileUpgrade, len(chainConfig.PrecompileUpgrades))
+	copy(newUpgrades, chainConfig.PrecompileUpgrades)
+	newUpgrades[0] = PrecompileUpgrade{StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(5))}
+	assert.Nil(t, chainConfig.CheckPrecompilesCompatible(newUpgrades, big.NewInt(4)))
+	assert.NotNil(t, chainConfig.CheckPrecompilesCompatible(newUpgrades, big.NewInt(5)))
+}
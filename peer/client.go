@@ -4,6 +4,7 @@
 package peer
 
 import (
+	"context"
 	"errors"
 
 	"github.com/ava-labs/avalanchego/ids"
@@ -19,20 +20,36 @@ var (
 
 // Client defines ability to send request / response through the Network
 type Client interface {
-	// RequestAny synchronously sends request to the first connected peer that matches the specified minVersion in
-	// random order.
+	// RequestAny synchronously sends request to a connected peer that matches the specified minVersion,
+	// selected at random weighted by the score of each peer.
 	// A peer is considered a match if its version is greater than or equal to the specified minVersion
+	// Returns the response bytes and the nodeID of the peer that was selected.
 	// Returns errNoPeersMatchingVersion if no peer could be found matching specified version
 	// and errRequestFailed if the request should be retried.
-	RequestAny(minVersion *version.Application, request []byte) ([]byte, error)
+	RequestAny(minVersion *version.Application, request []byte) ([]byte, ids.NodeID, error)
+
+	// RequestAnyWithContext is RequestAny, except that it returns the error of [ctx] as soon as [ctx]
+	// is cancelled or reaches its deadline.
+	// Note: the deadline of [ctx] is not sent to the peer, since the AppSender of avalanchego applies its
+	// own timeout to requests. The peer may keep processing a request after [ctx] is done.
+	RequestAnyWithContext(ctx context.Context, minVersion *version.Application, request []byte) ([]byte, ids.NodeID, error)
 
 	// Request synchronously sends request to the selected nodeID
 	// Returns response bytes
 	// Returns errRequestFailed if request should be retried
 	Request(nodeID ids.NodeID, request []byte) ([]byte, error)
 
+	// RequestWithContext is Request, except that it returns the error of [ctx] as soon as [ctx]
+	// is cancelled or reaches its deadline. As for RequestAnyWithContext, the deadline of [ctx]
+	// is not sent to the peer.
+	RequestWithContext(ctx context.Context, nodeID ids.NodeID, request []byte) ([]byte, error)
+
 	// Gossip sends given gossip message to peers
 	Gossip(gossip []byte) error
+
+	// TrackInvalidResponse lowers the score of [nodeID] after the response it sent could not be
+	// parsed or validated.
+	TrackInvalidResponse(nodeID ids.NodeID)
 }
 
 // client implements Client interface
@@ -41,39 +58,50 @@ type client struct {
 	network Network
 }
 
-// RequestAny synchronously sends request to the first connected peer that matches the specified minVersion in
-// random order and blocks until it receives a response or the request could not be sent or times out.
-// Returns the response bytes from the peer.
-func (c *client) RequestAny(minVersion *version.Application, request []byte) ([]byte, error) {
+// RequestAny synchronously sends request to a connected peer that matches the specified minVersion
+// and blocks until it receives a response or the request could not be sent or times out.
+// Returns the response bytes and the nodeID of the peer.
+func (c *client) RequestAny(minVersion *version.Application, request []byte) ([]byte, ids.NodeID, error) {
+	return c.RequestAnyWithContext(context.Background(), minVersion, request)
+}
+
+// RequestAnyWithContext synchronously sends request to a connected peer that matches the specified minVersion
+// and blocks until it receives a response, the request could not be sent or times out, or [ctx] is done.
+// Returns the response bytes and the nodeID of the peer.
+func (c *client) RequestAnyWithContext(ctx context.Context, minVersion *version.Application, request []byte) ([]byte, ids.NodeID, error) {
 	waitingHandler := newWaitingResponseHandler()
-	if err := c.network.RequestAny(minVersion, request, waitingHandler); err != nil {
-		return nil, err
+	nodeID, err := c.network.RequestAnyWithContext(ctx, minVersion, request, waitingHandler)
+	if err != nil {
+		return nil, nodeID, err
 	}
-	response := <-waitingHandler.responseChan
-	if waitingHandler.failed {
-		return nil, errRequestFailed
-	}
-	return response, nil
+	response, err := waitingHandler.waitForResponse(ctx)
+	return response, nodeID, err
 }
 
 // Request synchronously sends [request] message to specified [nodeID]
 // This function blocks until a response is received from the peer
 func (c *client) Request(nodeID ids.NodeID, request []byte) ([]byte, error) {
+	return c.RequestWithContext(context.Background(), nodeID, request)
+}
+
+// RequestWithContext synchronously sends [request] message to specified [nodeID]
+// This function blocks until a response is received from the peer or [ctx] is done
+func (c *client) RequestWithContext(ctx context.Context, nodeID ids.NodeID, request []byte) ([]byte, error) {
 	waitingHandler := newWaitingResponseHandler()
-	if err := c.network.Request(nodeID, request, waitingHandler); err != nil {
+	if err := c.network.RequestWithContext(ctx, nodeID, request, waitingHandler); err != nil {
 		return nil, err
 	}
-	response := <-waitingHandler.responseChan
-	if waitingHandler.failed {
-		return nil, errRequestFailed
-	}
-	return response, nil
+	return waitingHandler.waitForResponse(ctx)
 }
 
 func (c *client) Gossip(gossip []byte) error {
 	return c.network.Gossip(gossip)
 }
 
+func (c *client) TrackInvalidResponse(nodeID ids.NodeID) {
+	c.network.TrackInvalidResponse(nodeID)
+}
+
 // NewClient returns Client for a given network
 func NewClient(network Network) Client {
 	return &client{
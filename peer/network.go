@@ -27,25 +27,32 @@ import (
 const minRequestHandlingDuration = 100 * time.Millisecond
 
 var (
-	errAcquiringSemaphore                      = errors.New("error acquiring semaphore")
-	_                     Network              = &network{}
-	_                     validators.Connector = &network{}
-	_                     common.AppHandler    = &network{}
+	_ Network              = &network{}
+	_ validators.Connector = &network{}
+	_ common.AppHandler    = &network{}
 )
 
 type Network interface {
 	validators.Connector
 	common.AppHandler
 
-	// RequestAny synchronously sends request to the first connected peer that matches the specified minVersion in
-	// random order.
+	// RequestAny sends request to a connected peer that matches the specified minVersion, selected at random
+	// weighted by the score of each peer, and returns the nodeID of the selected peer.
 	// A peer is considered a match if its version is greater than or equal to the specified minVersion
 	// Returns an error if the request could not be sent to a peer with the desired [minVersion].
-	RequestAny(minVersion *version.Application, message []byte, handler message.ResponseHandler) error
+	RequestAny(minVersion *version.Application, message []byte, handler message.ResponseHandler) (ids.NodeID, error)
+
+	// RequestAnyWithContext is RequestAny, except that the request is abandoned if [ctx] is cancelled
+	// or reaches its deadline before a response is received, in which case the handler is notified of a failure.
+	RequestAnyWithContext(ctx context.Context, minVersion *version.Application, message []byte, handler message.ResponseHandler) (ids.NodeID, error)
 
 	// Request sends message to given nodeID, notifying handler when there's a response or timeout
 	Request(nodeID ids.NodeID, message []byte, handler message.ResponseHandler) error
 
+	// RequestWithContext is Request, except that the request is abandoned if [ctx] is cancelled
+	// or reaches its deadline before a response is received, in which case the handler is notified of a failure.
+	RequestWithContext(ctx context.Context, nodeID ids.NodeID, message []byte, handler message.ResponseHandler) error
+
 	// Gossip sends given gossip message to peers
 	Gossip(gossip []byte) error
 
@@ -62,22 +69,31 @@ type Network interface {
 
 	// Size returns the size of the network in number of connected peers
 	Size() uint32
+
+	// TrackInvalidResponse lowers the score of [nodeID] after it responded with an invalid response
+	// and benches it if it failed too many requests in a row.
+	TrackInvalidResponse(nodeID ids.NodeID)
+
+	// PeerScores returns the request statistics and the score of each connected peer
+	PeerScores() []PeerScore
 }
 
 // network is an implementation of Network that processes message requests for
 // each peer in linear fashion
 type network struct {
-	lock                          sync.RWMutex                        // lock for mutating state of this Network struct
-	self                          ids.NodeID                          // NodeID of this node
-	requestIDGen                  uint32                              // requestID counter used to track outbound requests
-	outstandingResponseHandlerMap map[uint32]message.ResponseHandler  // maps avalanchego requestID => response handler
-	activeRequests                *semaphore.Weighted                 // controls maximum number of active outbound requests
-	appSender                     common.AppSender                    // avalanchego AppSender for sending messages
-	codec                         codec.Manager                       // Codec used for parsing messages
-	requestHandler                message.RequestHandler              // maps request type => handler
-	gossipHandler                 message.GossipHandler               // maps gossip type => handler
-	peers                         map[ids.NodeID]*version.Application // maps nodeID => version.Version
-	stats                         stats.RequestHandlerStats           // Provide request handler metrics
+	lock                          sync.RWMutex                       // lock for mutating state of this Network struct
+	self                          ids.NodeID                         // NodeID of this node
+	requestIDGen                  uint32                             // requestID counter used to track outbound requests
+	outstandingResponseHandlerMap map[uint32]message.ResponseHandler // maps avalanchego requestID => response handler
+	outstandingRequestDoneMap     map[uint32]chan struct{}           // maps avalanchego requestID => channel closed when the request is fulfilled, for requests that can be cancelled
+	cancelledRequests             map[uint32]struct{}                // set of requestIDs cancelled before the engine notified a response or failure
+	activeRequests                *semaphore.Weighted                // controls maximum number of active outbound requests
+	appSender                     common.AppSender                   // avalanchego AppSender for sending messages
+	codec                         codec.Manager                      // Codec used for parsing messages
+	requestHandler                message.RequestHandler             // maps request type => handler
+	gossipHandler                 message.GossipHandler              // maps gossip type => handler
+	peers                         *peerTracker                       // tracks the version and request statistics of connected peers
+	stats                         stats.RequestHandlerStats          // Provide request handler metrics
 }
 
 func NewNetwork(appSender common.AppSender, codec codec.Manager, self ids.NodeID, maxActiveRequests int64) Network {
@@ -86,64 +102,85 @@ func NewNetwork(appSender common.AppSender, codec codec.Manager, self ids.NodeID
 		codec:                         codec,
 		self:                          self,
 		outstandingResponseHandlerMap: make(map[uint32]message.ResponseHandler),
-		peers:                         make(map[ids.NodeID]*version.Application),
+		outstandingRequestDoneMap:     make(map[uint32]chan struct{}),
+		cancelledRequests:             make(map[uint32]struct{}),
+		peers:                         newPeerTracker(),
 		activeRequests:                semaphore.NewWeighted(maxActiveRequests),
 		gossipHandler:                 message.NoopMempoolGossipHandler{},
 		stats:                         stats.NewRequestHandlerStats(),
 	}
 }
 
-// RequestAny sends given request to the first connected peer that matches the specified minVersion
+// RequestAny sends given request to a connected peer that matches the specified minVersion
 // A peer is considered a match if its version is greater than or equal to the specified minVersion
 // If minVersion is nil, then the request will be sent to any peer regardless of their version
+// The peer is selected at random weighted by its score, which favours reliable peers with a high bandwidth.
+// Benched peers are only selected if all the matching peers are benched.
+// Returns the nodeID of the selected peer.
 // Returns a non-nil error if we were not able to send a request to a peer with >= [minVersion]
 // or we fail to send a request to the selected peer.
-func (n *network) RequestAny(minVersion *version.Application, request []byte, handler message.ResponseHandler) error {
-	// Take a slot from total [activeRequests] and block until a slot becomes available.
-	if err := n.activeRequests.Acquire(context.Background(), 1); err != nil {
-		return errAcquiringSemaphore
+func (n *network) RequestAny(minVersion *version.Application, request []byte, handler message.ResponseHandler) (ids.NodeID, error) {
+	return n.RequestAnyWithContext(context.Background(), minVersion, request, handler)
+}
+
+// RequestAnyWithContext sends given request to a connected peer that matches the specified minVersion, as RequestAny.
+// Returns the error of [ctx] if it is cancelled while waiting for a slot of the active requests.
+// If [ctx] is cancelled after the request is sent, [handler] is notified of a failure.
+func (n *network) RequestAnyWithContext(ctx context.Context, minVersion *version.Application, request []byte, handler message.ResponseHandler) (ids.NodeID, error) {
+	// Take a slot from total [activeRequests] and block until a slot becomes available or [ctx] is cancelled.
+	if err := n.activeRequests.Acquire(ctx, 1); err != nil {
+		return ids.EmptyNodeID, err
 	}
 
 	n.lock.Lock()
 	defer n.lock.Unlock()
 
-	for nodeID, nodeVersion := range n.peers {
-		// map iteration is sufficiently random to avoid hitting same peer so here
-		// we get a random peerID key that we compare minimum version that
-		// we have
-		if minVersion == nil || nodeVersion.Compare(minVersion) >= 0 {
-			return n.request(nodeID, request, handler)
-		}
+	nodeID, ok := n.peers.selectPeer(minVersion)
+	if !ok {
+		n.activeRequests.Release(1)
+		return ids.EmptyNodeID, fmt.Errorf("no peers found matching version %s out of %d peers", minVersion, n.peers.size())
 	}
-
-	n.activeRequests.Release(1)
-	return fmt.Errorf("no peers found matching version %s out of %d peers", minVersion, len(n.peers))
+	return nodeID, n.request(ctx, nodeID, request, handler)
 }
 
 // Request sends request message bytes to specified nodeID, notifying the responseHandler on response or failure
 func (n *network) Request(nodeID ids.NodeID, request []byte, responseHandler message.ResponseHandler) error {
+	return n.RequestWithContext(context.Background(), nodeID, request, responseHandler)
+}
+
+// RequestWithContext sends request message bytes to specified nodeID, as Request.
+// Returns the error of [ctx] if it is cancelled while waiting for a slot of the active requests.
+// If [ctx] is cancelled after the request is sent, [responseHandler] is notified of a failure.
+func (n *network) RequestWithContext(ctx context.Context, nodeID ids.NodeID, request []byte, responseHandler message.ResponseHandler) error {
 	if nodeID == ids.EmptyNodeID {
 		return fmt.Errorf("cannot send request to empty nodeID, nodeID=%s, requestLen=%d", nodeID, len(request))
 	}
 
-	// Take a slot from total [activeRequests] and block until a slot becomes available.
-	if err := n.activeRequests.Acquire(context.Background(), 1); err != nil {
-		return errAcquiringSemaphore
+	// Take a slot from total [activeRequests] and block until a slot becomes available or [ctx] is cancelled.
+	if err := n.activeRequests.Acquire(ctx, 1); err != nil {
+		return err
 	}
 
 	n.lock.Lock()
 	defer n.lock.Unlock()
 
-	return n.request(nodeID, request, responseHandler)
+	return n.request(ctx, nodeID, request, responseHandler)
 }
 
 // request sends request message bytes to specified nodeID and adds [responseHandler] to [outstandingRequestHandlers]
 // so that it can be invoked when the network receives either a response or failure message.
 // Assumes [nodeID] is never [self] since we guarantee [self] will not be added to the [peers] map.
 // Releases active requests semaphore if there was an error in sending the request
-// Returns an error if [appSender] is unable to make the request.
+// Returns an error if [appSender] is unable to make the request or if [ctx] is already done.
+// If [ctx] can be cancelled, the request is cancelled when [ctx] is done before the request is fulfilled.
 // Assumes write lock is held
-func (n *network) request(nodeID ids.NodeID, request []byte, responseHandler message.ResponseHandler) error {
+func (n *network) request(ctx context.Context, nodeID ids.NodeID, request []byte, responseHandler message.ResponseHandler) error {
+	// Do not send the request if [ctx] was cancelled while waiting for the lock.
+	if err := ctx.Err(); err != nil {
+		n.activeRequests.Release(1)
+		return err
+	}
+
 	log.Debug("sending request to peer", "nodeID", nodeID, "requestLen", len(request))
 
 	// generate requestID
@@ -155,8 +192,6 @@ func (n *network) request(nodeID ids.NodeID, request []byte, responseHandler mes
 	nodeIDs := ids.NewNodeIDSet(1)
 	nodeIDs.Add(nodeID)
 
-	// send app request to the peer
-	// on failure: release the activeRequests slot, mark message as processed and return fatal error
 	// Send app request to [nodeID].
 	// On failure, release the slot from active requests and [outstandingRequestHandlers].
 	if err := n.appSender.SendAppRequest(nodeIDs, requestID, request); err != nil {
@@ -164,11 +199,59 @@ func (n *network) request(nodeID ids.NodeID, request []byte, responseHandler mes
 		delete(n.outstandingResponseHandlerMap, requestID)
 		return err
 	}
+	n.peers.trackRequest(nodeID, requestID)
+
+	if done := ctx.Done(); done != nil {
+		requestDone := make(chan struct{})
+		n.outstandingRequestDoneMap[requestID] = requestDone
+		go func() {
+			select {
+			case <-done:
+				n.cancelRequest(nodeID, requestID, ctx.Err())
+			case <-requestDone:
+			}
+		}()
+	}
 
 	log.Debug("sent request message to peer", "nodeID", nodeID, "requestID", requestID)
 	return nil
 }
 
+// cancelRequest abandons the request with [requestID] sent to [nodeID] after its context is done with [err].
+// The request is marked as processed and its response handler is notified of a failure, unless the request
+// was fulfilled in the meantime. The engine still notifies the network of the response or the failure of the
+// request later on, which is then ignored.
+// The request keeps its slot of the active requests until the engine notifies the network, since it is
+// still in flight until then and the slots bound the number of requests outstanding in the engine.
+func (n *network) cancelRequest(nodeID ids.NodeID, requestID uint32, err error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	handler, exists := n.removeRequestHandler(requestID)
+	if !exists {
+		return
+	}
+	log.Debug("cancelling request to peer", "nodeID", nodeID, "requestID", requestID, "err", err)
+	n.cancelledRequests[requestID] = struct{}{}
+	n.peers.cancelRequest(requestID)
+	if err := handler.OnFailure(nodeID, requestID); err != nil {
+		log.Error("response handler failed on cancelled request", "nodeID", nodeID, "requestID", requestID, "err", err)
+	}
+}
+
+// clearCancelledRequest returns true if [requestID] was cancelled and forgets it, since the engine
+// notifies the network of either a response or a failure once for each request. The slot of the
+// active requests kept by the cancelled request is released.
+// Assumes write lock is held
+func (n *network) clearCancelledRequest(requestID uint32) bool {
+	if _, cancelled := n.cancelledRequests[requestID]; !cancelled {
+		return false
+	}
+	delete(n.cancelledRequests, requestID)
+	n.activeRequests.Release(1)
+	return true
+}
+
 // AppRequest is called by avalanchego -> VM when there is an incoming AppRequest from a peer
 // error returned by this function is expected to be treated as fatal by the engine
 // returns error if the requestHandler returns an error
@@ -209,7 +292,7 @@ func (n *network) AppRequest(nodeID ids.NodeID, requestID uint32, deadline time.
 
 	responseBytes, err := req.Handle(ctx, nodeID, requestID, n.requestHandler)
 	switch {
-	case err != nil && err != context.DeadlineExceeded:
+	case err != nil && !errors.Is(err, context.DeadlineExceeded):
 		return err // Return a fatal error
 	case responseBytes != nil:
 		return n.appSender.SendAppResponse(nodeID, requestID, responseBytes) // Propagate fatal error
@@ -228,6 +311,11 @@ func (n *network) AppResponse(nodeID ids.NodeID, requestID uint32, response []by
 
 	log.Debug("received AppResponse from peer", "nodeID", nodeID, "requestID", requestID)
 
+	if n.clearCancelledRequest(requestID) {
+		log.Debug("dropping response to cancelled request", "nodeID", nodeID, "requestID", requestID, "responseLen", len(response))
+		return nil
+	}
+	n.peers.trackResponse(requestID, len(response))
 	handler, exists := n.getRequestHandler(requestID)
 	if !exists {
 		// Should never happen since the engine should be managing outstanding requests
@@ -249,6 +337,11 @@ func (n *network) AppRequestFailed(nodeID ids.NodeID, requestID uint32) error {
 	defer n.lock.Unlock()
 	log.Debug("received AppRequestFailed from peer", "nodeID", nodeID, "requestID", requestID)
 
+	if n.clearCancelledRequest(requestID) {
+		log.Debug("dropping failure of cancelled request", "nodeID", nodeID, "requestID", requestID)
+		return nil
+	}
+	n.peers.trackFailure(requestID)
 	handler, exists := n.getRequestHandler(requestID)
 	if !exists {
 		// Should never happen since the engine should be managing outstanding requests
@@ -263,13 +356,30 @@ func (n *network) AppRequestFailed(nodeID ids.NodeID, requestID uint32) error {
 // This is called by either [AppResponse] or [AppRequestFailed].
 // assumes that the write lock is held.
 func (n *network) getRequestHandler(requestID uint32) (message.ResponseHandler, bool) {
+	handler, exists := n.removeRequestHandler(requestID)
+	if !exists {
+		return nil, false
+	}
+	// release activeRequests slot
+	n.activeRequests.Release(1)
+	return handler, true
+}
+
+// removeRequestHandler marks the request with [requestID] as processed and returns its response handler,
+// without releasing its slot of the active requests. Returns false if the request is not outstanding.
+// Assumes write lock is held
+func (n *network) removeRequestHandler(requestID uint32) (message.ResponseHandler, bool) {
 	handler, exists := n.outstandingResponseHandlerMap[requestID]
 	if !exists {
 		return nil, false
 	}
-	// mark message as processed, release activeRequests slot
+	// mark message as processed
 	delete(n.outstandingResponseHandlerMap, requestID)
-	n.activeRequests.Release(1)
+	// stop waiting for the context of the request to be cancelled
+	if requestDone, exists := n.outstandingRequestDoneMap[requestID]; exists {
+		close(requestDone)
+		delete(n.outstandingRequestDoneMap, requestID)
+	}
 	return handler, true
 }
 
@@ -304,12 +414,12 @@ func (n *network) Connected(nodeID ids.NodeID, nodeVersion *version.Application)
 		return nil
 	}
 
-	if storedVersion, exists := n.peers[nodeID]; exists {
+	if storedVersion, exists := n.peers.getVersion(nodeID); exists {
 		// Peer is already connected, update the version if it has changed.
 		// Log a warning message since the consensus engine should never call Connected on a peer
 		// that we have already marked as Connected.
 		if nodeVersion.Compare(storedVersion) != 0 {
-			n.peers[nodeID] = nodeVersion
+			n.peers.connected(nodeID, nodeVersion)
 			log.Warn("received Connected message for already connected peer, updating node version", "nodeID", nodeID, "storedVersion", storedVersion, "nodeVersion", nodeVersion)
 		} else {
 			log.Warn("ignoring peer connected event for already connected peer with identical version", "nodeID", nodeID)
@@ -317,7 +427,7 @@ func (n *network) Connected(nodeID ids.NodeID, nodeVersion *version.Application)
 		return nil
 	}
 
-	n.peers[nodeID] = nodeVersion
+	n.peers.connected(nodeID, nodeVersion)
 	return nil
 }
 
@@ -328,13 +438,13 @@ func (n *network) Disconnected(nodeID ids.NodeID) error {
 	defer n.lock.Unlock()
 
 	// if this peer already exists, log a warning and ignore the request
-	if _, exists := n.peers[nodeID]; !exists {
+	if _, exists := n.peers.getVersion(nodeID); !exists {
 		// we're not connected to this peer, nothing to do here
 		log.Warn("received peer disconnect request to unconnected peer", "nodeID", nodeID)
 		return nil
 	}
 
-	delete(n.peers, nodeID)
+	n.peers.disconnected(nodeID)
 	return nil
 }
 
@@ -344,7 +454,7 @@ func (n *network) Shutdown() {
 	defer n.lock.Unlock()
 
 	// reset peers map
-	n.peers = make(map[ids.NodeID]*version.Application)
+	n.peers = newPeerTracker()
 }
 
 func (n *network) SetGossipHandler(handler message.GossipHandler) {
@@ -365,5 +475,19 @@ func (n *network) Size() uint32 {
 	n.lock.RLock()
 	defer n.lock.RUnlock()
 
-	return uint32(len(n.peers))
+	return uint32(n.peers.size())
+}
+
+func (n *network) TrackInvalidResponse(nodeID ids.NodeID) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.peers.trackInvalidResponse(nodeID)
+}
+
+func (n *network) PeerScores() []PeerScore {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+
+	return n.peers.scores()
 }
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -103,8 +104,9 @@ func TestRequestAnyRequestsRoutingAndResponse(t *testing.T) {
 			defer wg.Done()
 			requestBytes, err := message.RequestToBytes(codecManager, requestMessage)
 			assert.NoError(t, err)
-			responseBytes, err := client.RequestAny(defaultPeerVersion, requestBytes)
+			responseBytes, respondingNodeID, err := client.RequestAny(defaultPeerVersion, requestBytes)
 			assert.NoError(t, err)
+			assert.Equal(t, nodeID, respondingNodeID)
 			assert.NotNil(t, responseBytes)
 
 			var response TestMessage
@@ -253,7 +255,7 @@ func TestRequestMinVersion(t *testing.T) {
 	}))
 
 	// ensure version does not match
-	responseBytes, err := client.RequestAny(&version.Application{
+	responseBytes, _, err := client.RequestAny(&version.Application{
 		Major: 2,
 		Minor: 0,
 		Patch: 0,
@@ -262,12 +264,13 @@ func TestRequestMinVersion(t *testing.T) {
 	assert.Nil(t, responseBytes)
 
 	// ensure version matches and the request goes through
-	responseBytes, err = client.RequestAny(&version.Application{
+	responseBytes, respondingNodeID, err := client.RequestAny(&version.Application{
 		Major: 1,
 		Minor: 0,
 		Patch: 0,
 	}, requestBytes)
 	assert.NoError(t, err)
+	assert.Equal(t, nodeID, respondingNodeID)
 
 	var response TestMessage
 	if _, err = codecManager.Unmarshal(responseBytes, &response); err != nil {
@@ -427,6 +430,142 @@ func TestNetworkPropagatesRequestHandlerError(t *testing.T) {
 	assert.Error(t, clientNetwork.AppRequest(nodeID, requestID, time.Now().Add(time.Second), requestMessage))
 }
 
+func TestRequestCancelledBeforeResponse(t *testing.T) {
+	codecManager := buildCodec(t, TestMessage{})
+	nodeID := ids.GenerateTestNodeID()
+
+	sentRequests := make(chan uint32, 1)
+	sender := testAppSender{
+		sendAppRequestFn: func(_ ids.NodeIDSet, requestID uint32, _ []byte) error {
+			sentRequests <- requestID // never respond
+			return nil
+		},
+	}
+	net := NewNetwork(sender, codecManager, ids.EmptyNodeID, 1)
+	client := NewClient(net)
+	assert.NoError(t, net.Connected(nodeID, defaultPeerVersion))
+	defer net.Shutdown()
+
+	requestBytes, err := marshalStruct(codecManager, TestMessage{Message: "hello there"})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	responseBytes, err := client.RequestWithContext(ctx, nodeID, requestBytes)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Nil(t, responseBytes)
+	requestID := <-sentRequests
+
+	// The request is marked as processed but keeps its slot of the active requests while it is in flight
+	impl := net.(*network)
+	impl.lock.RLock()
+	assert.Len(t, impl.outstandingResponseHandlerMap, 0)
+	assert.Len(t, impl.outstandingRequestDoneMap, 0)
+	assert.Len(t, impl.cancelledRequests, 1)
+	impl.lock.RUnlock()
+	assert.False(t, impl.activeRequests.TryAcquire(1))
+
+	// The late response of the engine is ignored and releases the slot once
+	assert.NoError(t, net.AppResponse(nodeID, requestID, []byte("late response")))
+	assert.Len(t, impl.cancelledRequests, 0)
+	assert.True(t, impl.activeRequests.TryAcquire(1))
+	assert.False(t, impl.activeRequests.TryAcquire(1))
+	impl.activeRequests.Release(1)
+}
+
+func TestRequestCancelledWhileWaitingForSlot(t *testing.T) {
+	codecManager := buildCodec(t, TestMessage{})
+	nodeID := ids.GenerateTestNodeID()
+
+	var sentRequests uint32
+	sender := testAppSender{
+		sendAppRequestFn: func(ids.NodeIDSet, uint32, []byte) error {
+			atomic.AddUint32(&sentRequests, 1)
+			return nil
+		},
+	}
+	net := NewNetwork(sender, codecManager, ids.EmptyNodeID, 1)
+	assert.NoError(t, net.Connected(nodeID, defaultPeerVersion))
+	defer net.Shutdown()
+
+	// Take the only slot of the active requests with a request that is never responded to
+	assert.NoError(t, net.Request(nodeID, []byte("hello there"), newWaitingResponseHandler()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := net.RequestWithContext(ctx, nodeID, []byte("hello there"), newWaitingResponseHandler())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	_, err = net.RequestAnyWithContext(ctx, defaultPeerVersion, []byte("hello there"), newWaitingResponseHandler())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.EqualValues(t, 1, atomic.LoadUint32(&sentRequests))
+}
+
+func TestRequestCancellationRace(t *testing.T) {
+	codecManager := buildCodec(t, TestMessage{})
+	nodeID := ids.GenerateTestNodeID()
+
+	var net Network
+	senderWg := &sync.WaitGroup{}
+	sender := testAppSender{
+		sendAppRequestFn: func(_ ids.NodeIDSet, requestID uint32, _ []byte) error {
+			senderWg.Add(1)
+			go func() {
+				defer senderWg.Done()
+				// Respond or fail around the time the context of the request is cancelled
+				time.Sleep(time.Duration(rand.Intn(2000)) * time.Microsecond) // #nosec G404
+				if requestID%2 == 0 {
+					assert.NoError(t, net.AppResponse(nodeID, requestID, []byte("response")))
+				} else {
+					assert.NoError(t, net.AppRequestFailed(nodeID, requestID))
+				}
+			}()
+			return nil
+		},
+	}
+	const maxActiveRequests = 64
+	net = NewNetwork(sender, codecManager, ids.EmptyNodeID, maxActiveRequests)
+	client := NewClient(net)
+	assert.NoError(t, net.Connected(nodeID, defaultPeerVersion))
+	defer net.Shutdown()
+
+	var (
+		requestWg                     = &sync.WaitGroup{}
+		responses, failures, timeouts uint32
+	)
+	for i := 0; i < 500; i++ {
+		requestWg.Add(1)
+		go func() {
+			defer requestWg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(rand.Intn(4000))*time.Microsecond) // #nosec G404
+			defer cancel()
+			responseBytes, _, err := client.RequestAnyWithContext(ctx, defaultPeerVersion, []byte("request"))
+			switch {
+			case err == nil:
+				assert.Equal(t, []byte("response"), responseBytes)
+				atomic.AddUint32(&responses, 1)
+			case errors.Is(err, errRequestFailed):
+				atomic.AddUint32(&failures, 1)
+			case errors.Is(err, context.DeadlineExceeded):
+				atomic.AddUint32(&timeouts, 1)
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	requestWg.Wait()
+	senderWg.Wait()
+	assert.EqualValues(t, 500, responses+failures+timeouts)
+
+	// Every request was cleaned up exactly once
+	impl := net.(*network)
+	impl.lock.RLock()
+	assert.Len(t, impl.outstandingResponseHandlerMap, 0)
+	assert.Len(t, impl.outstandingRequestDoneMap, 0)
+	assert.Len(t, impl.cancelledRequests, 0)
+	impl.lock.RUnlock()
+	assert.True(t, impl.activeRequests.TryAcquire(maxActiveRequests))
+}
+
 func buildCodec(t *testing.T, types ...interface{}) codec.Manager {
 	codecManager := codec.NewDefaultManager()
 	c := linearcodec.NewDefault()
@@ -0,0 +1,326 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package peer
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+	"github.com/ava-labs/avalanchego/version"
+
+	"github.com/ava-labs/subnet-evm/metrics"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// maxConsecutiveFailures is the number of failed requests or invalid responses in a row
+	// after which a peer is benched.
+	maxConsecutiveFailures = 3
+
+	// benchDuration is the amount of time a benched peer is not selected by RequestAny,
+	// unless all the peers matching the requested version are benched.
+	benchDuration = time.Minute
+
+	// ewmaAlpha is the weight of the latest measurement in the moving averages of the
+	// latency and bandwidth of a peer.
+	ewmaAlpha = 0.2
+
+	// minLatency bounds the latency used to compute the bandwidth of a response, so that
+	// the bandwidth of an instant response is finite.
+	minLatency = time.Millisecond
+)
+
+var peersBenchedCounter = metrics.GetOrRegisterCounter("net_peers_benched", nil)
+
+// PeerScore describes the behaviour of a connected peer as observed by the requests sent to it.
+type PeerScore struct {
+	NodeID  ids.NodeID `json:"nodeID"`
+	Version string     `json:"version"`
+
+	Requests         uint64 `json:"requests"`
+	Responses        uint64 `json:"responses"`
+	Failures         uint64 `json:"failures"`
+	InvalidResponses uint64 `json:"invalidResponses"`
+
+	// Latency is the moving average of the time it took the peer to respond.
+	Latency time.Duration `json:"latency"`
+	// Bandwidth is the moving average of the response bytes per second of the peer.
+	Bandwidth float64 `json:"bandwidth"`
+	// Score is the weight of the peer when RequestAny selects a peer.
+	Score float64 `json:"score"`
+	// BenchedUntil is set if the peer is benched after failing too many requests in a row.
+	BenchedUntil *time.Time `json:"benchedUntil,omitempty"`
+}
+
+// peerInfo holds the version and the request statistics of a connected peer.
+type peerInfo struct {
+	version *version.Application
+
+	requests         uint64
+	responses        uint64
+	failures         uint64
+	invalidResponses uint64
+
+	// consecutiveFailures is reset by a response and [prevConsecutiveFailures] keeps its value
+	// before the reset, since an invalid response is only reported after the response is tracked.
+	consecutiveFailures     int
+	prevConsecutiveFailures int
+
+	latency      float64 // moving average of the latency in nanoseconds, zero until the first response
+	bandwidth    float64 // moving average of the bandwidth in bytes per second, zero until the first response
+	benchedUntil time.Time
+}
+
+// reliability returns the estimated probability that the peer responds validly to a request.
+// The ratio is smoothed so that peers without requests have a reliability of 1/2.
+func (p *peerInfo) reliability() float64 {
+	valid := p.responses - p.invalidResponses
+	if p.invalidResponses > p.responses {
+		valid = 0
+	}
+	return (float64(valid) + 1) / (float64(p.requests) + 2)
+}
+
+// score returns the weight of the peer when selecting a peer, given [defaultBandwidth] for
+// peers whose bandwidth has not been measured yet.
+func (p *peerInfo) score(defaultBandwidth float64) float64 {
+	bandwidth := p.bandwidth
+	if bandwidth == 0 {
+		bandwidth = defaultBandwidth
+	}
+	return p.reliability() * bandwidth
+}
+
+// outstandingRequest is a request sent to [nodeID] at [start] that has not been responded to yet.
+type outstandingRequest struct {
+	nodeID ids.NodeID
+	start  time.Time
+}
+
+// peerTracker tracks the latency, bandwidth, failures and invalid responses of the connected peers,
+// selects the peer to send a request to based on these statistics and benches misbehaving peers.
+// It is not safe for concurrent use, callers are expected to hold the lock of the network.
+type peerTracker struct {
+	peers    map[ids.NodeID]*peerInfo
+	requests map[uint32]outstandingRequest // maps requestID => outstanding request
+	clock    mockable.Clock
+}
+
+func newPeerTracker() *peerTracker {
+	return &peerTracker{
+		peers:    make(map[ids.NodeID]*peerInfo),
+		requests: make(map[uint32]outstandingRequest),
+	}
+}
+
+// connected adds [nodeID] to the tracked peers or updates its version if it is already tracked.
+func (p *peerTracker) connected(nodeID ids.NodeID, nodeVersion *version.Application) {
+	if info, exists := p.peers[nodeID]; exists {
+		info.version = nodeVersion
+		return
+	}
+	p.peers[nodeID] = &peerInfo{version: nodeVersion}
+}
+
+// disconnected stops tracking [nodeID].
+func (p *peerTracker) disconnected(nodeID ids.NodeID) {
+	delete(p.peers, nodeID)
+}
+
+// getVersion returns the version of [nodeID] and true if [nodeID] is tracked.
+func (p *peerTracker) getVersion(nodeID ids.NodeID) (*version.Application, bool) {
+	info, exists := p.peers[nodeID]
+	if !exists {
+		return nil, false
+	}
+	return info.version, true
+}
+
+// size returns the number of tracked peers.
+func (p *peerTracker) size() int {
+	return len(p.peers)
+}
+
+// selectPeer returns a random peer whose version is greater than or equal to [minVersion],
+// weighted by the score of each peer. Benched peers are only selected if all the peers
+// matching [minVersion] are benched. If [minVersion] is nil, all the peers match.
+// Returns false if no peer matches [minVersion].
+func (p *peerTracker) selectPeer(minVersion *version.Application) (ids.NodeID, bool) {
+	now := p.clock.Time()
+	var available, benched []ids.NodeID
+	for nodeID, info := range p.peers {
+		if minVersion != nil && info.version.Compare(minVersion) < 0 {
+			continue
+		}
+		if now.Before(info.benchedUntil) {
+			benched = append(benched, nodeID)
+		} else {
+			available = append(available, nodeID)
+		}
+	}
+	if len(available) == 0 {
+		available = benched
+	}
+	if len(available) == 0 {
+		return ids.EmptyNodeID, false
+	}
+
+	defaultBandwidth := p.averageBandwidth()
+	scores := make([]float64, len(available))
+	totalScore := 0.0
+	for i, nodeID := range available {
+		scores[i] = p.peers[nodeID].score(defaultBandwidth)
+		totalScore += scores[i]
+	}
+	target := rand.Float64() * totalScore // #nosec G404
+	for i, nodeID := range available {
+		if target < scores[i] {
+			return nodeID, true
+		}
+		target -= scores[i]
+	}
+	// Only reached due to rounding errors
+	return available[len(available)-1], true
+}
+
+// averageBandwidth returns the average bandwidth of the peers whose bandwidth has been measured,
+// or 1 if no bandwidth has been measured yet.
+func (p *peerTracker) averageBandwidth() float64 {
+	var (
+		total float64
+		count int
+	)
+	for _, info := range p.peers {
+		if info.bandwidth > 0 {
+			total += info.bandwidth
+			count++
+		}
+	}
+	if count == 0 {
+		return 1
+	}
+	return total / float64(count)
+}
+
+// trackRequest records that request [requestID] was sent to [nodeID].
+func (p *peerTracker) trackRequest(nodeID ids.NodeID, requestID uint32) {
+	p.requests[requestID] = outstandingRequest{nodeID: nodeID, start: p.clock.Time()}
+	if info, exists := p.peers[nodeID]; exists {
+		info.requests++
+	}
+}
+
+// trackResponse records the response of [responseLen] bytes to request [requestID] and updates the
+// latency and bandwidth of the peer the request was sent to.
+func (p *peerTracker) trackResponse(requestID uint32, responseLen int) {
+	request, exists := p.requests[requestID]
+	if !exists {
+		return
+	}
+	delete(p.requests, requestID)
+	info, exists := p.peers[request.nodeID]
+	if !exists {
+		return
+	}
+
+	latency := p.clock.Time().Sub(request.start)
+	if latency < minLatency {
+		latency = minLatency
+	}
+	bandwidth := float64(responseLen) / latency.Seconds()
+	if info.responses == 0 {
+		info.latency = float64(latency)
+		info.bandwidth = bandwidth
+	} else {
+		info.latency = ewmaAlpha*float64(latency) + (1-ewmaAlpha)*info.latency
+		info.bandwidth = ewmaAlpha*bandwidth + (1-ewmaAlpha)*info.bandwidth
+	}
+	info.responses++
+	info.prevConsecutiveFailures = info.consecutiveFailures
+	info.consecutiveFailures = 0
+}
+
+// trackFailure records that request [requestID] failed.
+func (p *peerTracker) trackFailure(requestID uint32) {
+	request, exists := p.requests[requestID]
+	if !exists {
+		return
+	}
+	delete(p.requests, requestID)
+	info, exists := p.peers[request.nodeID]
+	if !exists {
+		return
+	}
+	info.failures++
+	info.consecutiveFailures++
+	p.benchIfFailing(request.nodeID, info)
+}
+
+// cancelRequest forgets request [requestID], which was cancelled before the peer it was sent to
+// responded, so that it does not count against the peer.
+func (p *peerTracker) cancelRequest(requestID uint32) {
+	request, exists := p.requests[requestID]
+	if !exists {
+		return
+	}
+	delete(p.requests, requestID)
+	if info, exists := p.peers[request.nodeID]; exists && info.requests > 0 {
+		info.requests--
+	}
+}
+
+// trackInvalidResponse records that the last response of [nodeID] was invalid. Since the response
+// was already tracked, the invalid response restores the consecutive failures preceding it.
+func (p *peerTracker) trackInvalidResponse(nodeID ids.NodeID) {
+	info, exists := p.peers[nodeID]
+	if !exists {
+		return
+	}
+	info.invalidResponses++
+	info.consecutiveFailures = info.prevConsecutiveFailures + 1
+	info.prevConsecutiveFailures = info.consecutiveFailures
+	p.benchIfFailing(nodeID, info)
+}
+
+// benchIfFailing benches [nodeID] for [benchDuration] if it failed [maxConsecutiveFailures] requests in a row.
+func (p *peerTracker) benchIfFailing(nodeID ids.NodeID, info *peerInfo) {
+	if info.consecutiveFailures < maxConsecutiveFailures {
+		return
+	}
+	info.consecutiveFailures = 0
+	info.prevConsecutiveFailures = 0
+	info.benchedUntil = p.clock.Time().Add(benchDuration)
+	peersBenchedCounter.Inc(1)
+	log.Debug("benching peer", "nodeID", nodeID, "until", info.benchedUntil)
+}
+
+// scores returns the statistics of the tracked peers.
+func (p *peerTracker) scores() []PeerScore {
+	var (
+		now              = p.clock.Time()
+		defaultBandwidth = p.averageBandwidth()
+		scores           = make([]PeerScore, 0, len(p.peers))
+	)
+	for nodeID, info := range p.peers {
+		score := PeerScore{
+			NodeID:           nodeID,
+			Version:          info.version.String(),
+			Requests:         info.requests,
+			Responses:        info.responses,
+			Failures:         info.failures,
+			InvalidResponses: info.invalidResponses,
+			Latency:          time.Duration(info.latency),
+			Bandwidth:        info.bandwidth,
+			Score:            info.score(defaultBandwidth),
+		}
+		if now.Before(info.benchedUntil) {
+			benchedUntil := info.benchedUntil
+			score.BenchedUntil = &benchedUntil
+		}
+		scores = append(scores, score)
+	}
+	return scores
+}
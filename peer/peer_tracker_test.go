@@ -0,0 +1,124 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package peer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/version"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerTrackerSelectPeerVersion(t *testing.T) {
+	tracker := newPeerTracker()
+	oldPeer, newPeer := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	tracker.connected(oldPeer, &version.Application{Major: 1, Minor: 7, Patch: 0})
+	tracker.connected(newPeer, &version.Application{Major: 1, Minor: 8, Patch: 0})
+
+	for i := 0; i < 100; i++ {
+		nodeID, ok := tracker.selectPeer(&version.Application{Major: 1, Minor: 8, Patch: 0})
+		assert.True(t, ok)
+		assert.Equal(t, newPeer, nodeID)
+	}
+
+	_, ok := tracker.selectPeer(&version.Application{Major: 2, Minor: 0, Patch: 0})
+	assert.False(t, ok)
+
+	tracker.disconnected(newPeer)
+	_, ok = tracker.selectPeer(&version.Application{Major: 1, Minor: 8, Patch: 0})
+	assert.False(t, ok)
+}
+
+func TestPeerTrackerSelectPeerScore(t *testing.T) {
+	tracker := newPeerTracker()
+	tracker.clock.Set(time.Unix(1000, 0))
+	fastPeer, slowPeer := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	tracker.connected(fastPeer, defaultPeerVersion)
+	tracker.connected(slowPeer, defaultPeerVersion)
+
+	// Both peers respond with the same number of bytes, but [slowPeer] takes 10 times longer.
+	requestID := uint32(0)
+	for i := 0; i < 10; i++ {
+		tracker.trackRequest(fastPeer, requestID)
+		tracker.trackRequest(slowPeer, requestID+1)
+		tracker.clock.Set(tracker.clock.Time().Add(10 * time.Millisecond))
+		tracker.trackResponse(requestID, 1024)
+		tracker.clock.Set(tracker.clock.Time().Add(90 * time.Millisecond))
+		tracker.trackResponse(requestID+1, 1024)
+		requestID += 2
+	}
+
+	selected := make(map[ids.NodeID]int)
+	for i := 0; i < 1000; i++ {
+		nodeID, ok := tracker.selectPeer(nil)
+		assert.True(t, ok)
+		selected[nodeID]++
+	}
+	assert.Greater(t, selected[fastPeer], 800)
+	assert.Greater(t, selected[slowPeer], 0)
+
+	for _, score := range tracker.scores() {
+		assert.EqualValues(t, 10, score.Requests)
+		assert.EqualValues(t, 10, score.Responses)
+		assert.Nil(t, score.BenchedUntil)
+		switch score.NodeID {
+		case fastPeer:
+			assert.Equal(t, 10*time.Millisecond, score.Latency)
+		case slowPeer:
+			assert.Equal(t, 100*time.Millisecond, score.Latency)
+		}
+	}
+}
+
+func TestPeerTrackerBenching(t *testing.T) {
+	tracker := newPeerTracker()
+	tracker.clock.Set(time.Unix(1000, 0))
+	goodPeer, badPeer := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	tracker.connected(goodPeer, defaultPeerVersion)
+	tracker.connected(badPeer, defaultPeerVersion)
+
+	// Failures that are not consecutive do not bench the peer
+	requestID := uint32(0)
+	for _, failed := range []bool{true, true, false, true, true} {
+		tracker.trackRequest(badPeer, requestID)
+		if failed {
+			tracker.trackFailure(requestID)
+		} else {
+			tracker.trackResponse(requestID, 1024)
+		}
+		requestID++
+	}
+	assert.True(t, tracker.peers[badPeer].benchedUntil.IsZero())
+
+	// An invalid response does not reset the consecutive failures
+	tracker.trackRequest(badPeer, requestID)
+	tracker.trackResponse(requestID, 1024)
+	tracker.trackInvalidResponse(badPeer)
+	assert.Equal(t, tracker.clock.Time().Add(benchDuration), tracker.peers[badPeer].benchedUntil)
+
+	for i := 0; i < 100; i++ {
+		nodeID, ok := tracker.selectPeer(nil)
+		assert.True(t, ok)
+		assert.Equal(t, goodPeer, nodeID)
+	}
+
+	// A benched peer is selected if all the matching peers are benched
+	tracker.disconnected(goodPeer)
+	nodeID, ok := tracker.selectPeer(nil)
+	assert.True(t, ok)
+	assert.Equal(t, badPeer, nodeID)
+	scores := tracker.scores()
+	assert.Len(t, scores, 1)
+	assert.NotNil(t, scores[0].BenchedUntil)
+	assert.EqualValues(t, 6, scores[0].Requests)
+	assert.EqualValues(t, 2, scores[0].Responses)
+	assert.EqualValues(t, 4, scores[0].Failures)
+	assert.EqualValues(t, 1, scores[0].InvalidResponses)
+
+	// The peer is no longer benched after [benchDuration]
+	tracker.clock.Set(tracker.clock.Time().Add(benchDuration))
+	assert.Nil(t, tracker.scores()[0].BenchedUntil)
+}
@@ -4,6 +4,8 @@
 package peer
 
 import (
+	"context"
+
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/subnet-evm/plugin/evm/message"
 )
@@ -33,6 +35,20 @@ func (w *waitingResponseHandler) OnFailure(ids.NodeID, uint32) error {
 	return nil
 }
 
+// waitForResponse blocks until the request succeeds or fails and returns the response bytes.
+// Returns the error of [ctx] if the request failed after [ctx] was cancelled, or errRequestFailed
+// if the request failed otherwise.
+func (w *waitingResponseHandler) waitForResponse(ctx context.Context) ([]byte, error) {
+	response := <-w.responseChan
+	if w.failed {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errRequestFailed
+	}
+	return response, nil
+}
+
 // newWaitingResponseHandler returns new instance of the waitingResponseHandler
 func newWaitingResponseHandler() *waitingResponseHandler {
 	return &waitingResponseHandler{responseChan: make(chan []byte)}
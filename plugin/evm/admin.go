@@ -9,6 +9,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/utils/profiler"
+	"github.com/ava-labs/subnet-evm/peer"
 	"github.com/ethereum/go-ethereum/log"
 )
 
@@ -79,3 +80,15 @@ func (p *Admin) GetVMConfig(r *http.Request, args *struct{}, reply *ConfigReply)
 	reply.Config = &p.vm.config
 	return nil
 }
+
+type PeerScoresReply struct {
+	Peers []peer.PeerScore `json:"peers"`
+}
+
+// GetPeerScores returns the request statistics and the score of each connected peer
+func (p *Admin) GetPeerScores(r *http.Request, args *struct{}, reply *PeerScoresReply) error {
+	log.Info("Admin: GetPeerScores called")
+
+	reply.Peers = p.vm.Network.PeerScores()
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/ethereum/go-ethereum/rlp"
 
 	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/precompile"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
@@ -44,10 +45,22 @@ func (b *Block) Accept() error {
 	if err := vm.acceptedBlockDB.Put(lastAcceptedKey, b.id[:]); err != nil {
 		return fmt.Errorf("failed to put %s as the last accepted block: %w", b.ID(), err)
 	}
+	if err := b.signWarpMessages(); err != nil {
+		return fmt.Errorf("failed to sign warp messages of %s: %w", b.ID(), err)
+	}
 
 	return vm.db.Commit()
 }
 
+// signWarpMessages signs the warp messages sent by the transactions in [b]
+func (b *Block) signWarpMessages() error {
+	header := b.ethBlock.Header()
+	if !b.vm.chainConfig.IsPrecompileEnabled(precompile.WarpMessengerAddress, new(big.Int).SetUint64(header.Time)) {
+		return nil
+	}
+	return b.vm.warpBackend.AcceptReceipts(b.vm.chain.GetReceiptsByHash(b.ethBlock.Hash()))
+}
+
 // Reject implements the snowman.Block interface
 func (b *Block) Reject() error {
 	b.status = choices.Rejected
@@ -262,13 +262,15 @@ func (b *blockBuilder) signalTxsReady() {
 // and notifies the VM when the tx pool has transactions to be
 // put into a new block.
 func (b *blockBuilder) awaitSubmittedTxs() {
+	// txSubmitChan is invoked when new transactions are issued as well as on re-orgs which
+	// may orphan transactions that were previously in a preferred block.
+	// Note: the channel is subscribed before returning, so that transactions issued
+	// right after the block builder starts are not missed.
+	txSubmitChan := b.chain.GetTxSubmitCh()
 	b.shutdownWg.Add(1)
 	go b.ctx.Log.RecoverAndPanic(func() {
 		defer b.shutdownWg.Done()
 
-		// txSubmitChan is invoked when new transactions are issued as well as on re-orgs which
-		// may orphan transactions that were previously in a preferred block.
-		txSubmitChan := b.chain.GetTxSubmitCh()
 		for {
 			select {
 			case txsEvent := <-txSubmitChan:
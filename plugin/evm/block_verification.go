@@ -112,6 +112,7 @@ func (v blockValidatorLegacy) SyntacticVerify(b *Block) error {
 
 type blockValidatorSubnetEVM struct {
 	feeConfigManagerEnabled bool
+	rewardManagerEnabled    bool
 }
 
 func (v blockValidatorSubnetEVM) SyntacticVerify(b *Block) error {
@@ -187,7 +188,9 @@ func (v blockValidatorSubnetEVM) SyntacticVerify(b *Block) error {
 		return errUncleHashMismatch
 	}
 	// Coinbase must be zero, if AllowFeeRecipients is not enabled
-	if !b.vm.chainConfig.AllowFeeRecipients && b.ethBlock.Coinbase() != constants.BlackholeAddr {
+	// if reward manager is enabled, the coinbase depends on state. State is not available here,
+	// so the coinbase is verified by the consensus engine instead.
+	if !v.rewardManagerEnabled && !b.vm.chainConfig.AllowFeeRecipients && b.ethBlock.Coinbase() != constants.BlackholeAddr {
 		return errInvalidBlock
 	}
 	// Block must not have any uncles
@@ -6,10 +6,13 @@ package evm
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"time"
 
+	"github.com/ava-labs/subnet-evm/core"
 	"github.com/ava-labs/subnet-evm/eth"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/spf13/cast"
 )
 
@@ -33,10 +36,14 @@ const (
 	defaultPriorityRegossipFrequency              = 1 * time.Second
 	defaultPriorityRegossipMaxTxs                 = 32
 	defaultPriorityRegossipTxsPerAddress          = 16
+	defaultTxsPullGossipFrequency                 = 10 * time.Second
+	defaultTxPoolPolicySenderWindow               = 1 * time.Minute
 	defaultOfflinePruningBloomFilterSize   uint64 = 512 // Default size (MB) for the offline pruner to use
 	defaultLogLevel                               = "info"
 	defaultMaxOutboundActiveRequests              = 8
 	defaultPopulateMissingTriesParallelism        = 1024
+	defaultStateSyncServerTrieCache               = 64 // MB
+	defaultStateSyncMinBlocks                     = 300_000
 )
 
 var defaultEnabledAPIs = []string{
@@ -59,6 +66,7 @@ type Config struct {
 	SnowmanAPIEnabled bool   `json:"snowman-api-enabled"`
 	AdminAPIEnabled   bool   `json:"admin-api-enabled"`
 	AdminAPIDir       string `json:"admin-api-dir"`
+	WarpAPIEnabled    bool   `json:"warp-api-enabled"`
 
 	// EnabledEthAPIs is a list of Ethereum services that should be enabled
 	// If none is specified, then we use the default list [defaultEnabledAPIs]
@@ -112,6 +120,12 @@ type Config struct {
 	PriorityRegossipMaxTxs        int              `json:"priority-regossip-max-txs"`
 	PriorityRegossipTxsPerAddress int              `json:"priority-regossip-txs-per-address"`
 	PriorityRegossipAddresses     []common.Address `json:"priority-regossip-addresses"`
+	// TxsPullGossipFrequency is how often the transactions missing from the mempool are pulled
+	// from a random peer. Pulling is disabled if it is 0.
+	TxsPullGossipFrequency Duration `json:"txs-pull-gossip-frequency"`
+
+	// TxPoolPolicies are the node-local admission policies of the transaction pool
+	TxPoolPolicies TxPoolPoliciesConfig `json:"tx-pool-policies"`
 
 	// Log level
 	LogLevel string `json:"log-level"`
@@ -126,6 +140,12 @@ type Config struct {
 
 	// VM2VM network
 	MaxOutboundActiveRequests int64 `json:"max-outbound-active-requests"`
+
+	// Sync settings
+	StateSyncEnabled         bool   `json:"state-sync-enabled"`
+	StateSyncSkipResume      bool   `json:"state-sync-skip-resume"`
+	StateSyncServerTrieCache int    `json:"state-sync-server-trie-cache"`
+	StateSyncMinBlocks       uint64 `json:"state-sync-min-blocks"`
 }
 
 // EthAPIs returns an array of strings representing the Eth APIs that should be enabled
@@ -158,10 +178,14 @@ func (c *Config) SetDefaults() {
 	c.PriorityRegossipFrequency.Duration = defaultPriorityRegossipFrequency
 	c.PriorityRegossipMaxTxs = defaultPriorityRegossipMaxTxs
 	c.PriorityRegossipTxsPerAddress = defaultPriorityRegossipTxsPerAddress
+	c.TxsPullGossipFrequency.Duration = defaultTxsPullGossipFrequency
+	c.TxPoolPolicies.SenderWindow.Duration = defaultTxPoolPolicySenderWindow
 	c.OfflinePruningBloomFilterSize = defaultOfflinePruningBloomFilterSize
 	c.LogLevel = defaultLogLevel
 	c.MaxOutboundActiveRequests = defaultMaxOutboundActiveRequests
 	c.PopulateMissingTriesParallelism = defaultPopulateMissingTriesParallelism
+	c.StateSyncServerTrieCache = defaultStateSyncServerTrieCache
+	c.StateSyncMinBlocks = defaultStateSyncMinBlocks
 }
 
 func (d *Duration) UnmarshalJSON(data []byte) (err error) {
@@ -190,5 +214,78 @@ func (c *Config) Validate() error {
 	if c.Pruning && c.CommitInterval == 0 {
 		return fmt.Errorf("cannot use commit interval of 0 with pruning enabled")
 	}
+	return c.TxPoolPolicies.Validate()
+}
+
+// TxPoolPoliciesConfig configures the node-local admission policies of the transaction pool.
+// The policies are not enforced by consensus, so the transactions rejected by this node may
+// still be included in the blocks built by other nodes. A policy is disabled if its field is empty.
+type TxPoolPoliciesConfig struct {
+	// SenderMaxTxs is the maximum number of transactions admitted from each sender per SenderWindow
+	SenderMaxTxs uint64 `json:"sender-max-txs"`
+	// SenderMaxGas is the maximum total gas of the transactions admitted from each sender per SenderWindow
+	SenderMaxGas uint64   `json:"sender-max-gas"`
+	SenderWindow Duration `json:"sender-window"`
+
+	// DeniedContractCalls are the contract calls rejected by the transaction pool
+	DeniedContractCalls []DeniedContractCall `json:"denied-contract-calls"`
+	// DestinationMinTips maps destination addresses to the minimum gas tip cap (in wei) of the transactions sent to them
+	DestinationMinTips map[common.Address]uint64 `json:"destination-min-tips"`
+}
+
+// DeniedContractCall denies the calls to Address with one of Selectors, or every call to Address if
+// Selectors is empty.
+type DeniedContractCall struct {
+	Address   common.Address  `json:"address"`
+	Selectors []hexutil.Bytes `json:"selectors"`
+}
+
+// Validate returns an error if the policies cannot be enforced.
+func (c *TxPoolPoliciesConfig) Validate() error {
+	if (c.SenderMaxTxs > 0 || c.SenderMaxGas > 0) && c.SenderWindow.Duration <= 0 {
+		return fmt.Errorf("cannot limit the transactions of senders with a sender window of %s", c.SenderWindow.Duration)
+	}
+	denied := make(map[common.Address]struct{}, len(c.DeniedContractCalls))
+	for _, call := range c.DeniedContractCalls {
+		if _, exists := denied[call.Address]; exists {
+			return fmt.Errorf("contract %s is denied more than once", call.Address)
+		}
+		denied[call.Address] = struct{}{}
+		for _, selector := range call.Selectors {
+			if len(selector) != 4 {
+				return fmt.Errorf("invalid selector %s denied for contract %s: selectors must be 4 bytes", selector, call.Address)
+			}
+		}
+	}
 	return nil
 }
+
+// Policies returns the transaction pool policies enabled by [c]. Assumes [c] has been validated.
+func (c *TxPoolPoliciesConfig) Policies() []core.TxPoolPolicy {
+	var policies []core.TxPoolPolicy
+	if len(c.DeniedContractCalls) > 0 {
+		denied := make(map[common.Address][][4]byte, len(c.DeniedContractCalls))
+		for _, call := range c.DeniedContractCalls {
+			selectors := make([][4]byte, len(call.Selectors))
+			for i, selector := range call.Selectors {
+				copy(selectors[i][:], selector)
+			}
+			denied[call.Address] = selectors
+		}
+		policies = append(policies, core.NewContractCallDenylistPolicy(denied))
+	}
+	if len(c.DestinationMinTips) > 0 {
+		minTips := make(map[common.Address]*big.Int, len(c.DestinationMinTips))
+		for addr, minTip := range c.DestinationMinTips {
+			minTips[addr] = new(big.Int).SetUint64(minTip)
+		}
+		policies = append(policies, core.NewDestinationMinTipPolicy(minTips))
+	}
+	if c.SenderMaxTxs > 0 {
+		policies = append(policies, core.NewSenderRateLimitPolicy(c.SenderMaxTxs, c.SenderWindow.Duration))
+	}
+	if c.SenderMaxGas > 0 {
+		policies = append(policies, core.NewSenderGasLimitPolicy(c.SenderMaxGas, c.SenderWindow.Duration))
+	}
+	return policies
+}
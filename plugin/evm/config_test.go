@@ -9,6 +9,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -58,3 +60,46 @@ func TestUnmarshalConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestTxPoolPoliciesConfig(t *testing.T) {
+	var config Config
+	config.SetDefaults()
+	givenJSON := []byte(`{
+		"tx-pool-policies": {
+			"sender-max-txs": 10,
+			"sender-max-gas": 1000000,
+			"denied-contract-calls": [
+				{"address": "0x0100000000000000000000000000000000000000"},
+				{"address": "0x0200000000000000000000000000000000000000", "selectors": ["0xa9059cbb"]}
+			],
+			"destination-min-tips": {"0x0300000000000000000000000000000000000000": 1000}
+		}
+	}`)
+	assert.NoError(t, json.Unmarshal(givenJSON, &config))
+	assert.Equal(t, defaultTxPoolPolicySenderWindow, config.TxPoolPolicies.SenderWindow.Duration)
+	assert.NoError(t, config.TxPoolPolicies.Validate())
+
+	var names []string
+	for _, policy := range config.TxPoolPolicies.Policies() {
+		names = append(names, policy.Name())
+	}
+	assert.Equal(t, []string{
+		core.ContractCallDenylistPolicyName,
+		core.DestinationMinTipPolicyName,
+		core.SenderRateLimitPolicyName,
+		core.SenderGasLimitPolicyName,
+	}, names)
+
+	config.TxPoolPolicies.DeniedContractCalls[1].Selectors[0] = hexutil.Bytes{0xa9, 0x05}
+	assert.Error(t, config.TxPoolPolicies.Validate())
+
+	// A contract denied more than once is rejected, since its entries would be ambiguous
+	config.TxPoolPolicies.DeniedContractCalls[1] = DeniedContractCall{Address: config.TxPoolPolicies.DeniedContractCalls[0].Address}
+	assert.Error(t, config.TxPoolPolicies.Validate())
+
+	config.TxPoolPolicies = TxPoolPoliciesConfig{SenderMaxTxs: 10}
+	assert.Error(t, config.TxPoolPolicies.Validate())
+
+	var empty TxPoolPoliciesConfig
+	assert.Empty(t, empty.Policies())
+}
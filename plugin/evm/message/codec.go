@@ -34,6 +34,10 @@ func BuildCodec() (codec.Manager, error) {
 		c.RegisterType(CodeRequest{}),
 		c.RegisterType(CodeResponse{}),
 
+		// mempool pull gossip types
+		c.RegisterType(TxsPullRequest{}),
+		c.RegisterType(TxsPullResponse{}),
+
 		codecManager.RegisterCodec(Version, c),
 	)
 	return codecManager, errs.Err
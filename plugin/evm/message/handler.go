@@ -34,6 +34,7 @@ type RequestHandler interface {
 	HandleTrieLeafsRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, leafsRequest LeafsRequest) ([]byte, error)
 	HandleBlockRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, request BlockRequest) ([]byte, error)
 	HandleCodeRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, codeRequest CodeRequest) ([]byte, error)
+	HandleTxsPullRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, txsPullRequest TxsPullRequest) ([]byte, error)
 }
 
 // ResponseHandler handles response for a sent request
@@ -6,9 +6,16 @@ package message
 import (
 	"fmt"
 
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
+var _ block.StateSummary = SyncSummary{}
+
 // SyncableBlock provides the information necessary to sync a node starting
 // at the given block.
 type SyncableBlock struct {
@@ -21,3 +28,75 @@ type SyncableBlock struct {
 func (s SyncableBlock) String() string {
 	return fmt.Sprintf("SyncableBlock(BlockHash=%s, BlockNumber=%d, BlockRoot=%s, AtomicRoot=%s)", s.BlockHash, s.BlockNumber, s.BlockRoot, s.AtomicRoot)
 }
+
+// SyncSummary wraps a SyncableBlock so that it can be exchanged with the
+// consensus engine as a block.StateSummary.
+// [acceptImpl] is invoked when the engine accepts the summary and is expected
+// to start syncing the state of the wrapped block.
+type SyncSummary struct {
+	SyncableBlock
+
+	summaryID  ids.ID
+	bytes      []byte
+	acceptImpl func(SyncSummary) (bool, error)
+}
+
+// NewSyncSummary returns a SyncSummary for the block with [blockHash] at [blockNumber]
+// and state root [blockRoot].
+func NewSyncSummary(codec codec.Manager, blockHash common.Hash, blockNumber uint64, blockRoot common.Hash) (SyncSummary, error) {
+	syncableBlock := SyncableBlock{
+		BlockNumber: blockNumber,
+		BlockRoot:   blockRoot,
+		BlockHash:   blockHash,
+	}
+	bytes, err := codec.Marshal(Version, &syncableBlock)
+	if err != nil {
+		return SyncSummary{}, err
+	}
+
+	return SyncSummary{
+		SyncableBlock: syncableBlock,
+		summaryID:     ids.ID(crypto.Keccak256Hash(bytes)),
+		bytes:         bytes,
+	}, nil
+}
+
+// NewSyncSummaryFromBytes parses [summaryBytes] into a SyncSummary that calls
+// [acceptImpl] when it is accepted.
+func NewSyncSummaryFromBytes(codec codec.Manager, summaryBytes []byte, acceptImpl func(SyncSummary) (bool, error)) (SyncSummary, error) {
+	var syncableBlock SyncableBlock
+	if _, err := codec.Unmarshal(summaryBytes, &syncableBlock); err != nil {
+		return SyncSummary{}, err
+	}
+
+	return SyncSummary{
+		SyncableBlock: syncableBlock,
+		summaryID:     ids.ID(crypto.Keccak256Hash(summaryBytes)),
+		bytes:         summaryBytes,
+		acceptImpl:    acceptImpl,
+	}, nil
+}
+
+func (s SyncSummary) Bytes() []byte {
+	return s.bytes
+}
+
+func (s SyncSummary) Height() uint64 {
+	return s.BlockNumber
+}
+
+func (s SyncSummary) ID() ids.ID {
+	return s.summaryID
+}
+
+func (s SyncSummary) String() string {
+	return fmt.Sprintf("SyncSummary(BlockHash=%s, BlockNumber=%d, BlockRoot=%s)", s.BlockHash, s.BlockNumber, s.BlockRoot)
+}
+
+// Accept invokes [acceptImpl] to start syncing to this summary.
+func (s SyncSummary) Accept() (bool, error) {
+	if s.acceptImpl == nil {
+		return false, fmt.Errorf("accept implementation not specified for summary: %s", s)
+	}
+	return s.acceptImpl(s)
+}
@@ -0,0 +1,128 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/units"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// MaxTxsFilterSize is the maximum size in bytes of the bloom filter of a TxsPullRequest.
+	MaxTxsFilterSize = 64 * units.KiB
+
+	// minTxsFilterSize is the minimum size in bytes of the bloom filter of a TxsPullRequest.
+	minTxsFilterSize = 8
+
+	// txsFilterBitsPerTx and txsFilterNumHashes give a false positive rate of about 1% until
+	// the filter reaches [MaxTxsFilterSize].
+	txsFilterBitsPerTx = 10
+	txsFilterNumHashes = 7
+
+	// maxTxsFilterNumHashes is the maximum number of hash functions of the bloom filter of a TxsPullRequest.
+	maxTxsFilterNumHashes = 16
+)
+
+var (
+	_ Request = TxsPullRequest{}
+
+	errInvalidTxsFilterSize      = errors.New("invalid txs filter size")
+	errInvalidTxsFilterNumHashes = errors.New("invalid number of txs filter hashes")
+)
+
+// TxsPullRequest is a request for the transactions in the mempool of a peer that are missing from
+// the mempool of the requester, which are the transactions whose hashes are not in Filter.
+type TxsPullRequest struct {
+	// Salt is hashed together with each transaction hash so that the false positives of the filter
+	// differ between requests.
+	Salt common.Hash `serialize:"true"`
+	// NumHashes is the number of bits of Filter set for each transaction hash.
+	NumHashes uint8 `serialize:"true"`
+	// Filter is a bloom filter of the hashes of the transactions known to the requester.
+	Filter []byte `serialize:"true"`
+}
+
+// NewTxsPullRequest returns a TxsPullRequest with a bloom filter of [txHashes] salted with [salt].
+func NewTxsPullRequest(salt common.Hash, txHashes []common.Hash) TxsPullRequest {
+	size := (len(txHashes)*txsFilterBitsPerTx + 7) / 8
+	switch {
+	case size < minTxsFilterSize:
+		size = minTxsFilterSize
+	case size > MaxTxsFilterSize:
+		size = MaxTxsFilterSize
+	}
+	request := TxsPullRequest{
+		Salt:      salt,
+		NumHashes: txsFilterNumHashes,
+		Filter:    make([]byte, size),
+	}
+	for _, txHash := range txHashes {
+		for _, bit := range request.filterBits(txHash) {
+			request.Filter[bit/8] |= 1 << (bit % 8)
+		}
+	}
+	return request
+}
+
+func (r TxsPullRequest) String() string {
+	return fmt.Sprintf("TxsPullRequest(Salt=%s, NumHashes=%d, FilterSize=%d)", r.Salt, r.NumHashes, len(r.Filter))
+}
+
+func (r TxsPullRequest) Handle(ctx context.Context, nodeID ids.NodeID, requestID uint32, handler RequestHandler) ([]byte, error) {
+	return handler.HandleTxsPullRequest(ctx, nodeID, requestID, r)
+}
+
+// Verify returns an error if the bloom filter of [r] is malformed.
+func (r TxsPullRequest) Verify() error {
+	if len(r.Filter) < minTxsFilterSize || len(r.Filter) > MaxTxsFilterSize {
+		return fmt.Errorf("%w: %d", errInvalidTxsFilterSize, len(r.Filter))
+	}
+	if r.NumHashes == 0 || r.NumHashes > maxTxsFilterNumHashes {
+		return fmt.Errorf("%w: %d", errInvalidTxsFilterNumHashes, r.NumHashes)
+	}
+	return nil
+}
+
+// Contains returns false if [txHash] is not in the bloom filter of [r], or true if it may be.
+// Assumes [r] has been verified.
+func (r TxsPullRequest) Contains(txHash common.Hash) bool {
+	for _, bit := range r.filterBits(txHash) {
+		if r.Filter[bit/8]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// filterBits returns the positions of the bits of the bloom filter of [r] that are set for [txHash].
+// The positions are derived from the hash of [txHash] salted with [r.Salt] by double hashing.
+func (r TxsPullRequest) filterBits(txHash common.Hash) []uint64 {
+	digest := crypto.Keccak256(r.Salt[:], txHash[:])
+	var (
+		h1      = binary.BigEndian.Uint64(digest[:8])
+		h2      = binary.BigEndian.Uint64(digest[8:16])
+		numBits = uint64(len(r.Filter)) * 8
+		bits    = make([]uint64, r.NumHashes)
+	)
+	for i := range bits {
+		bits[i] = (h1 + uint64(i)*h2) % numBits
+	}
+	return bits
+}
+
+// TxsPullResponse is a response to a TxsPullRequest
+// handler: evm.TxsPullHandler
+type TxsPullResponse struct {
+	// Txs contains the RLP encoded transactions of the mempool of the peer that are not in the filter
+	// of the request.
+	Txs []byte `serialize:"true"`
+}
@@ -0,0 +1,120 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMarshalTxsPullRequest asserts that the structure or serialization logic hasn't changed, primarily to
+// ensure compatibility with the network.
+func TestMarshalTxsPullRequest(t *testing.T) {
+	txsPullRequest := TxsPullRequest{
+		Salt:      common.BytesToHash([]byte("salt")),
+		NumHashes: 3,
+		Filter:    []byte{1, 2, 3, 4, 5, 6, 7, 8},
+	}
+
+	base64TxsPullRequest := "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAc2FsdAMAAAAIAQIDBAUGBwg="
+
+	codec, err := BuildCodec()
+	assert.NoError(t, err)
+
+	txsPullRequestBytes, err := codec.Marshal(Version, txsPullRequest)
+	assert.NoError(t, err)
+	assert.Equal(t, base64TxsPullRequest, base64.StdEncoding.EncodeToString(txsPullRequestBytes))
+
+	var r TxsPullRequest
+	_, err = codec.Unmarshal(txsPullRequestBytes, &r)
+	assert.NoError(t, err)
+	assert.Equal(t, txsPullRequest, r)
+}
+
+// TestMarshalTxsPullResponse asserts that the structure or serialization logic hasn't changed, primarily to
+// ensure compatibility with the network.
+func TestMarshalTxsPullResponse(t *testing.T) {
+	txsPullResponse := TxsPullResponse{
+		Txs: []byte("some txs"),
+	}
+
+	base64TxsPullResponse := "AAAAAAAIc29tZSB0eHM="
+
+	codec, err := BuildCodec()
+	assert.NoError(t, err)
+
+	txsPullResponseBytes, err := codec.Marshal(Version, txsPullResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, base64TxsPullResponse, base64.StdEncoding.EncodeToString(txsPullResponseBytes))
+
+	var r TxsPullResponse
+	_, err = codec.Unmarshal(txsPullResponseBytes, &r)
+	assert.NoError(t, err)
+	assert.Equal(t, txsPullResponse.Txs, r.Txs)
+}
+
+func TestTxsPullRequestFilter(t *testing.T) {
+	knownTxHashes := make([]common.Hash, 1000)
+	for i := range knownTxHashes {
+		knownTxHashes[i] = crypto.Keccak256Hash([]byte{'k', byte(i), byte(i >> 8)})
+	}
+	request := NewTxsPullRequest(common.Hash{1}, knownTxHashes)
+	assert.NoError(t, request.Verify())
+	assert.Len(t, request.Filter, 1250)
+
+	for _, txHash := range knownTxHashes {
+		assert.True(t, request.Contains(txHash))
+	}
+	falsePositives := 0
+	for i := 0; i < 1000; i++ {
+		if request.Contains(crypto.Keccak256Hash([]byte{'u', byte(i), byte(i >> 8)})) {
+			falsePositives++
+		}
+	}
+	assert.Less(t, falsePositives, 50)
+
+	// The filter of an empty mempool has the minimum size and contains nothing
+	empty := NewTxsPullRequest(common.Hash{1}, nil)
+	assert.NoError(t, empty.Verify())
+	assert.False(t, empty.Contains(knownTxHashes[0]))
+
+	// A different salt changes the bits set for each hash
+	assert.NotEqual(t, request.Filter, NewTxsPullRequest(common.Hash{2}, knownTxHashes).Filter)
+}
+
+func TestTxsPullRequestVerify(t *testing.T) {
+	tests := map[string]struct {
+		request     TxsPullRequest
+		expectedErr error
+	}{
+		"valid": {
+			request: TxsPullRequest{NumHashes: 7, Filter: make([]byte, minTxsFilterSize)},
+		},
+		"empty filter": {
+			request:     TxsPullRequest{NumHashes: 7},
+			expectedErr: errInvalidTxsFilterSize,
+		},
+		"filter too large": {
+			request:     TxsPullRequest{NumHashes: 7, Filter: make([]byte, MaxTxsFilterSize+1)},
+			expectedErr: errInvalidTxsFilterSize,
+		},
+		"no hashes": {
+			request:     TxsPullRequest{Filter: make([]byte, minTxsFilterSize)},
+			expectedErr: errInvalidTxsFilterNumHashes,
+		},
+		"too many hashes": {
+			request:     TxsPullRequest{NumHashes: maxTxsFilterNumHashes + 1, Filter: make([]byte, minTxsFilterSize)},
+			expectedErr: errInvalidTxsFilterNumHashes,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, test.request.Verify(), test.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,272 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"context"
+	"crypto/rand"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/version"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/metrics"
+	"github.com/ava-labs/subnet-evm/peer"
+	"github.com/ava-labs/subnet-evm/plugin/evm/message"
+)
+
+var (
+	// TxsPullVersion is the minimum peer version sent TxsPullRequest.
+	// Peers only report the version of avalanchego, so this matches peers running a subnet-evm
+	// release that does not serve TxsPullRequest yet: these peers drop the request, which then
+	// fails when it times out and is retried with a random peer on the next pull.
+	TxsPullVersion = &version.Application{
+		Major: 1,
+		Minor: 7,
+		Patch: 16,
+	}
+
+	// Metrics of the transactions pulled from peers. The ratio of [txsPullAddedCounter] to [txsPullReceivedCounter]
+	// is the rate at which pulled transactions were missing from the mempool.
+	txsPullRequestsCounter = metrics.NewRegisteredCounter("gossip/pull/requests", nil)
+	txsPullFailuresCounter = metrics.NewRegisteredCounter("gossip/pull/failures", nil)
+	txsPullReceivedCounter = metrics.NewRegisteredCounter("gossip/pull/received", nil)
+	txsPullAddedCounter    = metrics.NewRegisteredCounter("gossip/pull/added", nil)
+
+	// Metrics of the transactions served to peers. The transactions skipped because they were in the filter of the
+	// request are the transactions that are not sent again to a peer that already knows them.
+	txsPullServedCounter       = metrics.NewRegisteredCounter("gossip/pull/served", nil)
+	txsPullServedBytesCounter  = metrics.NewRegisteredCounter("gossip/pull/served_bytes", nil)
+	txsPullSkippedCounter      = metrics.NewRegisteredCounter("gossip/pull/skipped", nil)
+	txsPullSkippedBytesCounter = metrics.NewRegisteredCounter("gossip/pull/skipped_bytes", nil)
+)
+
+// pullGossiper periodically requests the transactions missing from the mempool from a random peer.
+// The request contains a bloom filter of the hashes of the transactions in the mempool, so that the
+// peer only responds with the transactions that are not known yet.
+type pullGossiper struct {
+	ctx       *snow.Context
+	frequency time.Duration
+
+	client peer.Client
+	txPool *core.TxPool
+	codec  codec.Manager
+
+	shutdownChan chan struct{}
+	shutdownWg   *sync.WaitGroup
+}
+
+// createPullGossiper constructs and starts a pullGossiper that pulls transactions
+// every [vm.config.TxsPullGossipFrequency].
+func (vm *VM) createPullGossiper() *pullGossiper {
+	puller := &pullGossiper{
+		ctx:          vm.ctx,
+		frequency:    vm.config.TxsPullGossipFrequency.Duration,
+		client:       vm.client,
+		txPool:       vm.chain.GetTxPool(),
+		codec:        vm.networkCodec,
+		shutdownChan: vm.shutdownChan,
+		shutdownWg:   &vm.shutdownWg,
+	}
+	puller.awaitTxsPull()
+	return puller
+}
+
+// awaitTxsPull pulls transactions from a random peer every [frequency] until shutdown.
+func (p *pullGossiper) awaitTxsPull() {
+	p.shutdownWg.Add(1)
+	go p.ctx.Log.RecoverAndPanic(func() {
+		defer p.shutdownWg.Done()
+
+		pullTicker := time.NewTicker(p.frequency)
+		defer pullTicker.Stop()
+
+		for {
+			select {
+			case <-pullTicker.C:
+				if err := p.pullTxs(); err != nil {
+					log.Debug("failed to pull eth transactions", "err", err)
+				}
+			case <-p.shutdownChan:
+				return
+			}
+		}
+	})
+}
+
+// pullTxs requests the transactions missing from the mempool from a random peer matching [TxsPullVersion]
+// and adds the transactions in the response to the mempool.
+func (p *pullGossiper) pullTxs() error {
+	pending, queued := p.txPool.Content()
+	txHashes := make([]common.Hash, 0)
+	for _, txs := range pending {
+		for _, tx := range txs {
+			txHashes = append(txHashes, tx.Hash())
+		}
+	}
+	for _, txs := range queued {
+		for _, tx := range txs {
+			txHashes = append(txHashes, tx.Hash())
+		}
+	}
+
+	var salt common.Hash
+	if _, err := rand.Read(salt[:]); err != nil {
+		return err
+	}
+	requestBytes, err := message.RequestToBytes(p.codec, message.NewTxsPullRequest(salt, txHashes))
+	if err != nil {
+		return err
+	}
+
+	// Abandon the request if it is not fulfilled before the next pull.
+	ctx, cancel := context.WithTimeout(context.Background(), p.frequency)
+	defer cancel()
+
+	txsPullRequestsCounter.Inc(1)
+	responseBytes, nodeID, err := p.client.RequestAnyWithContext(ctx, TxsPullVersion, requestBytes)
+	if err != nil {
+		txsPullFailuresCounter.Inc(1)
+		return err
+	}
+	var response message.TxsPullResponse
+	if _, err := p.codec.Unmarshal(responseBytes, &response); err != nil {
+		txsPullFailuresCounter.Inc(1)
+		p.client.TrackInvalidResponse(nodeID)
+		return err
+	}
+	if len(response.Txs) == 0 {
+		return nil
+	}
+	// The maximum size of this encoded object is enforced by the codec.
+	txs := make([]*types.Transaction, 0)
+	if err := rlp.DecodeBytes(response.Txs, &txs); err != nil {
+		txsPullFailuresCounter.Inc(1)
+		p.client.TrackInvalidResponse(nodeID)
+		return err
+	}
+
+	txsPullReceivedCounter.Inc(int64(len(txs)))
+	for i, err := range p.txPool.AddRemotes(txs) {
+		if err != nil {
+			log.Trace(
+				"failed to add pulled tx to mempool",
+				"err", err,
+				"tx", txs[i].Hash(),
+			)
+			continue
+		}
+		txsPullAddedCounter.Inc(1)
+	}
+	return nil
+}
+
+// TxsPullHandler serves requests for the transactions of the mempool that are missing
+// from the bloom filter of the request.
+type TxsPullHandler struct {
+	txPool                  *core.TxPool
+	codec                   codec.Manager
+	remoteGossipOnlyEnabled bool
+}
+
+func NewTxsPullHandler(vm *VM) *TxsPullHandler {
+	return &TxsPullHandler{
+		txPool:                  vm.chain.GetTxPool(),
+		codec:                   vm.networkCodec,
+		remoteGossipOnlyEnabled: vm.config.RemoteGossipOnlyEnabled,
+	}
+}
+
+// OnTxsPullRequest responds with the pending transactions of the mempool that are not in the filter
+// of [request], up to [message.TxMsgSoftCapSize] of encoded transactions.
+// Never returns an error, since errors returned by request handlers are fatal.
+func (h *TxsPullHandler) OnTxsPullRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, request message.TxsPullRequest) ([]byte, error) {
+	if err := request.Verify(); err != nil {
+		log.Debug("dropping invalid TxsPullRequest", "peerID", nodeID, "requestID", requestID, "err", err)
+		return nil, nil
+	}
+
+	var (
+		txs     = make([]*types.Transaction, 0)
+		txsSize = common.StorageSize(0)
+	)
+pending:
+	for _, accountTxs := range h.txPool.Pending(true) {
+		for _, tx := range accountTxs {
+			// Respond with the transactions selected so far if the deadline of the request is close.
+			if ctx.Err() != nil {
+				break pending
+			}
+			txHash, size := tx.Hash(), tx.Size()
+			if request.Contains(txHash) {
+				txsPullSkippedCounter.Inc(1)
+				txsPullSkippedBytesCounter.Inc(int64(size))
+				continue
+			}
+			if h.remoteGossipOnlyEnabled && h.txPool.HasLocal(txHash) {
+				continue
+			}
+			if txsSize+size > message.TxMsgSoftCapSize {
+				break pending
+			}
+			txs = append(txs, tx)
+			txsSize += size
+		}
+	}
+
+	txBytes, err := rlp.EncodeToBytes(txs)
+	if err != nil {
+		log.Warn("failed to encode TxsPullResponse txs", "peerID", nodeID, "requestID", requestID, "err", err)
+		return nil, nil
+	}
+	responseBytes, err := h.codec.Marshal(message.Version, message.TxsPullResponse{Txs: txBytes})
+	if err != nil {
+		log.Warn("failed to marshal TxsPullResponse", "peerID", nodeID, "requestID", requestID, "err", err)
+		return nil, nil
+	}
+	txsPullServedCounter.Inc(int64(len(txs)))
+	txsPullServedBytesCounter.Inc(int64(txsSize))
+	return responseBytes, nil
+}
+
+var _ message.RequestHandler = &networkHandler{}
+
+// networkHandler serves the state sync requests of peers with [syncHandler] and
+// the requests for the transactions of the mempool with [txsPullHandler].
+type networkHandler struct {
+	syncHandler    message.RequestHandler
+	txsPullHandler *TxsPullHandler
+}
+
+func newNetworkHandler(syncHandler message.RequestHandler, txsPullHandler *TxsPullHandler) message.RequestHandler {
+	return &networkHandler{
+		syncHandler:    syncHandler,
+		txsPullHandler: txsPullHandler,
+	}
+}
+
+func (n *networkHandler) HandleTrieLeafsRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, leafsRequest message.LeafsRequest) ([]byte, error) {
+	return n.syncHandler.HandleTrieLeafsRequest(ctx, nodeID, requestID, leafsRequest)
+}
+
+func (n *networkHandler) HandleBlockRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, blockRequest message.BlockRequest) ([]byte, error) {
+	return n.syncHandler.HandleBlockRequest(ctx, nodeID, requestID, blockRequest)
+}
+
+func (n *networkHandler) HandleCodeRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, codeRequest message.CodeRequest) ([]byte, error) {
+	return n.syncHandler.HandleCodeRequest(ctx, nodeID, requestID, codeRequest)
+}
+
+func (n *networkHandler) HandleTxsPullRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, txsPullRequest message.TxsPullRequest) ([]byte, error) {
+	return n.txsPullHandler.OnTxsPullRequest(ctx, nodeID, requestID, txsPullRequest)
+}
@@ -0,0 +1,97 @@
+// (c) 2021-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+
+	subnetEVM "github.com/ava-labs/subnet-evm/chain"
+	"github.com/ava-labs/subnet-evm/plugin/evm/message"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+type stateSyncServerConfig struct {
+	Chain *subnetEVM.ETHChain
+
+	// SyncableInterval is the interval at which blocks are eligible to provide syncable block summaries.
+	// Since state is only committed to disk every CommitInterval blocks when pruning is enabled,
+	// this is set to the commit interval.
+	SyncableInterval uint64
+
+	Codec codec.Manager
+}
+
+type stateSyncServer struct {
+	chain *subnetEVM.ETHChain
+
+	syncableInterval uint64
+	codec            codec.Manager
+}
+
+type StateSyncServer interface {
+	GetLastStateSummary() (block.StateSummary, error)
+	GetStateSummary(uint64) (block.StateSummary, error)
+}
+
+func NewStateSyncServer(config *stateSyncServerConfig) StateSyncServer {
+	return &stateSyncServer{
+		chain:            config.Chain,
+		syncableInterval: config.SyncableInterval,
+		codec:            config.Codec,
+	}
+}
+
+// GetLastStateSummary returns the latest state summary.
+// State summary is calculated by the block nearest to last accepted
+// that is divisible by [syncableInterval]
+// If no summary is available, [database.ErrNotFound] must be returned.
+func (server *stateSyncServer) GetLastStateSummary() (block.StateSummary, error) {
+	lastHeight := server.chain.LastAcceptedBlock().NumberU64()
+	lastSyncSummaryNumber := lastHeight - lastHeight%server.syncableInterval
+
+	summary, err := server.stateSummaryAtHeight(lastSyncSummaryNumber)
+	if err != nil {
+		log.Debug("could not get latest state summary", "err", err)
+		return nil, database.ErrNotFound
+	}
+	log.Debug("Serving syncable block at latest height", "summary", summary)
+	return summary, nil
+}
+
+// GetStateSummary implements StateSyncableVM and returns a summary corresponding
+// to the provided [height] if the node can serve state sync data for that key.
+// If not, [database.ErrNotFound] must be returned.
+func (server *stateSyncServer) GetStateSummary(height uint64) (block.StateSummary, error) {
+	if height%server.syncableInterval != 0 {
+		return nil, database.ErrNotFound
+	}
+
+	summary, err := server.stateSummaryAtHeight(height)
+	if err != nil {
+		log.Debug("could not get state summary", "height", height, "err", err)
+		return nil, database.ErrNotFound
+	}
+
+	log.Debug("Serving syncable block at requested height", "height", height, "summary", summary)
+	return summary, nil
+}
+
+// stateSummaryAtHeight returns the summary of the accepted block at [height]
+// if the state of the block is available on disk.
+func (server *stateSyncServer) stateSummaryAtHeight(height uint64) (message.SyncSummary, error) {
+	blk := server.chain.GetBlockByNumber(height)
+	if blk == nil {
+		return message.SyncSummary{}, fmt.Errorf("block not found for height (%d)", height)
+	}
+
+	if !server.chain.BlockChain().HasState(blk.Root()) {
+		return message.SyncSummary{}, fmt.Errorf("block root does not exist for height (%d), root (%s)", height, blk.Root())
+	}
+
+	return message.NewSyncSummary(server.codec, blk.Hash(), height, blk.Root())
+}
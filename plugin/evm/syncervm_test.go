@@ -0,0 +1,325 @@
+// (c) 2021-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	engCommon "github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/version"
+
+	"github.com/ava-labs/subnet-evm/consensus/dummy"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/core/rawdb"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/plugin/evm/message"
+	statesyncclient "github.com/ava-labs/subnet-evm/statesync/client"
+	"github.com/ava-labs/subnet-evm/statesync/client/stats"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ statesyncclient.Client = &testBlocksClient{}
+
+// testBlocksClient serves block requests from [blocks] and records the number of
+// parents requested in each call.
+type testBlocksClient struct {
+	blocks    map[common.Hash]*types.Block
+	requested []uint16
+	onRequest func()
+}
+
+func (c *testBlocksClient) GetLeafs(context.Context, message.LeafsRequest) (message.LeafsResponse, error) {
+	return message.LeafsResponse{}, errors.New("unexpected leafs request")
+}
+
+func (c *testBlocksClient) GetBlocks(ctx context.Context, hash common.Hash, height uint64, parents uint16) ([]*types.Block, error) {
+	c.requested = append(c.requested, parents)
+	if c.onRequest != nil {
+		c.onRequest()
+	}
+	var blocks []*types.Block
+	for len(blocks) < int(parents) {
+		block, ok := c.blocks[hash]
+		if !ok || block.NumberU64() != height {
+			break
+		}
+		blocks = append(blocks, block)
+		hash = block.ParentHash()
+		height--
+	}
+	if len(blocks) == 0 {
+		return nil, errors.New("block not found")
+	}
+	return blocks, nil
+}
+
+func (c *testBlocksClient) GetCode(context.Context, common.Hash) ([]byte, error) {
+	return nil, errors.New("unexpected code request")
+}
+
+// newTestBlocksClient returns a client serving the genesis block and [numBlocks] blocks on top of it.
+func newTestBlocksClient(t *testing.T, numBlocks int) (*testBlocksClient, []*types.Block) {
+	gspec := &core.Genesis{
+		Config: params.TestChainConfig,
+	}
+	db := rawdb.NewMemoryDatabase()
+	genesis := gspec.MustCommit(db)
+	blocks, _, err := core.GenerateChain(params.TestChainConfig, genesis, dummy.NewETHFaker(), db, numBlocks, 0, func(i int, b *core.BlockGen) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks = append([]*types.Block{genesis}, blocks...)
+	client := &testBlocksClient{blocks: make(map[common.Hash]*types.Block, len(blocks))}
+	for _, block := range blocks {
+		client.blocks[block.Hash()] = block
+	}
+	return client, blocks
+}
+
+func TestSyncBlocksNearGenesis(t *testing.T) {
+	client, blocks := newTestBlocksClient(t, 40)
+	chaindb := rawdb.NewMemoryDatabase()
+	syncer := &stateSyncerClient{
+		stateSyncClientConfig: &stateSyncClientConfig{
+			chaindb: chaindb,
+			client:  client,
+			stats:   stats.NewNoOpStats(),
+		},
+	}
+	last := blocks[len(blocks)-1]
+
+	// The blocks at the top of the chain are already on disk and are not requested.
+	for _, block := range blocks[len(blocks)-4:] {
+		rawdb.WriteBlock(chaindb, block)
+		rawdb.WriteCanonicalHash(chaindb, block.Hash(), block.NumberU64())
+	}
+	assert.NoError(t, syncer.syncBlocks(context.Background(), last.Hash(), last.NumberU64(), parentsToGet))
+
+	// Requests never ask for blocks below genesis.
+	assert.Equal(t, []uint16{parentsPerRequest, 5}, client.requested)
+	for _, block := range blocks {
+		assert.NotNil(t, rawdb.ReadBlock(chaindb, block.Hash(), block.NumberU64()), "missing block %d", block.NumberU64())
+		assert.Equal(t, block.Hash(), rawdb.ReadCanonicalHash(chaindb, block.NumberU64()))
+	}
+}
+
+func TestSyncBlocksCancelled(t *testing.T) {
+	client, blocks := newTestBlocksClient(t, 100)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client.onRequest = cancel
+
+	chaindb := rawdb.NewMemoryDatabase()
+	syncer := &stateSyncerClient{
+		stateSyncClientConfig: &stateSyncClientConfig{
+			chaindb: chaindb,
+			client:  client,
+			stats:   stats.NewNoOpStats(),
+		},
+	}
+	last := blocks[len(blocks)-1]
+	assert.ErrorIs(t, syncer.syncBlocks(ctx, last.Hash(), last.NumberU64(), parentsToGet), context.Canceled)
+	assert.Len(t, client.requested, 1)
+	// Blocks fetched before the cancellation are not written.
+	assert.Nil(t, rawdb.ReadBlock(chaindb, last.Hash(), last.NumberU64()))
+}
+
+// syncTest specifies the parameters of an end-to-end state sync between two VMs.
+type syncTest struct {
+	syncableInterval   uint64
+	stateSyncMinBlocks uint64
+	shouldSync         bool
+}
+
+// syncVMSetup holds a server VM that accepted blocks beyond its last state summary and a
+// syncer VM connected to it at the current version of avalanchego.
+type syncVMSetup struct {
+	serverVM       *VM
+	syncerVM       *VM
+	syncerIssuer   <-chan engCommon.Message
+	contractAddr   common.Address
+	numBlocksBuilt int
+}
+
+// Teardown shuts down both VMs.
+func (s *syncVMSetup) Teardown(t *testing.T) {
+	assert.NoError(t, s.serverVM.Shutdown())
+	assert.NoError(t, s.syncerVM.Shutdown())
+}
+
+// createSyncServerAndClientVMs returns a server VM, which builds blocks transferring funds and deploying
+// a contract with storage, and a syncer VM with state sync enabled, whose requests are served by the server.
+func createSyncServerAndClientVMs(t *testing.T, test syncTest) *syncVMSetup {
+	serverConfigJSON := fmt.Sprintf("{\"pruning-enabled\":false, \"commit-interval\":%d}", test.syncableInterval)
+	serverIssuer, serverVM, _, serverAppSender := GenesisVM(t, true, genesisJSONSubnetEVM, serverConfigJSON, "")
+
+	// The constructor stores 1 in slot 0 and deploys a contract made of a single STOP opcode.
+	contractCode := common.FromHex("0x6001600055600060005360016000f3")
+	contractAddr := crypto.CreateAddress(testEthAddrs[0], 0)
+	numBlocks := int(test.syncableInterval) + 4
+	for i := 0; i < numBlocks; i++ {
+		var tx *types.Transaction
+		if i == 0 {
+			tx = types.NewContractCreation(0, common.Big0, 200_000, big.NewInt(testMinGasPrice), contractCode)
+		} else {
+			tx = types.NewTransaction(uint64(i), testEthAddrs[1], common.Big1, params.TxGas, big.NewInt(testMinGasPrice), nil)
+		}
+		signedTx, err := types.SignTx(tx, types.NewEIP155Signer(serverVM.chainConfig.ChainID), testKeys[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, err := range serverVM.chain.AddRemoteTxsSync([]*types.Transaction{signedTx}) {
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		serverVM.clock.Set(serverVM.clock.Time().Add(2 * time.Second))
+		issueAndAccept(t, serverIssuer, serverVM)
+	}
+	serverVM.chain.BlockChain().DrainAcceptorQueue()
+
+	stateSyncEnabledJSON := fmt.Sprintf("{\"state-sync-enabled\":true, \"state-sync-min-blocks\":%d}", test.stateSyncMinBlocks)
+	syncerIssuer, syncerVM, _, syncerAppSender := GenesisVM(t, false, genesisJSONSubnetEVM, stateSyncEnabledJSON, "")
+	enabled, err := syncerVM.StateSyncEnabled()
+	assert.NoError(t, err)
+	assert.True(t, enabled)
+
+	// route the requests of [syncerVM] to [serverVM] and the responses back to [syncerVM]
+	syncerAppSender.SendAppRequestF = func(nodeSet ids.NodeIDSet, requestID uint32, request []byte) error {
+		if _, ok := nodeSet.Pop(); !ok {
+			t.Fatal("expected nodeSet to contain at least 1 nodeID")
+		}
+		go serverVM.AppRequest(syncerVM.ctx.NodeID, requestID, time.Now().Add(time.Second), request)
+		return nil
+	}
+	serverAppSender.SendAppResponseF = func(_ ids.NodeID, requestID uint32, response []byte) error {
+		go syncerVM.AppResponse(serverVM.ctx.NodeID, requestID, response)
+		return nil
+	}
+
+	// the server is connected at the version reported by the nodes running this release
+	assert.NoError(t, syncerVM.Connected(serverVM.ctx.NodeID, version.CurrentApp))
+
+	return &syncVMSetup{
+		serverVM:       serverVM,
+		syncerVM:       syncerVM,
+		syncerIssuer:   syncerIssuer,
+		contractAddr:   contractAddr,
+		numBlocksBuilt: numBlocks,
+	}
+}
+
+func testSyncerVM(t *testing.T, vmSetup *syncVMSetup, test syncTest) {
+	t.Helper()
+	var (
+		serverVM = vmSetup.serverVM
+		syncerVM = vmSetup.syncerVM
+	)
+
+	summary, err := serverVM.GetLastStateSummary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, test.syncableInterval, summary.Height())
+	parsedSummary, err := syncerVM.ParseStateSummary(summary.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	retrievedSummary, err := serverVM.GetStateSummary(parsedSummary.Height())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, summary, retrievedSummary)
+
+	shouldSync, err := parsedSummary.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, test.shouldSync, shouldSync)
+	if !shouldSync {
+		return
+	}
+	assert.Equal(t, engCommon.StateSyncDone, <-vmSetup.syncerIssuer)
+	if err := syncerVM.StateSyncClient.Error(); err != nil {
+		t.Fatal("state sync failed", err)
+	}
+
+	// the syncer starts bootstrapping from the block of the summary, with its state available
+	assert.NoError(t, syncerVM.SetState(snow.Bootstrapping))
+	summaryBlock := serverVM.chain.GetBlockByNumber(summary.Height())
+	lastAcceptedID, err := syncerVM.LastAccepted()
+	assert.NoError(t, err)
+	assert.Equal(t, ids.ID(summaryBlock.Hash()), lastAcceptedID)
+	assert.True(t, syncerVM.chain.BlockChain().HasState(summaryBlock.Root()), "unavailable state for last accepted block")
+
+	// the syncer executes the blocks accepted by the server after the summary on top of the synced state
+	syncerVM.clock.Set(serverVM.clock.Time())
+	for height := summary.Height() + 1; height <= uint64(vmSetup.numBlocksBuilt); height++ {
+		blockBytes, err := rlp.EncodeToBytes(serverVM.chain.GetBlockByNumber(height))
+		if err != nil {
+			t.Fatal(err)
+		}
+		blk, err := syncerVM.ParseBlock(blockBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := blk.Verify(); err != nil {
+			t.Fatal(err)
+		}
+		if err := blk.Accept(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assert.NoError(t, syncerVM.SetState(snow.NormalOp))
+	assert.True(t, syncerVM.bootstrapped)
+	syncerVM.chain.BlockChain().DrainAcceptorQueue()
+	assert.Equal(t, serverVM.chain.LastAcceptedBlock().Hash(), syncerVM.chain.LastAcceptedBlock().Hash())
+
+	serverState, err := serverVM.chain.BlockChain().State()
+	if err != nil {
+		t.Fatal(err)
+	}
+	syncerState, err := syncerVM.chain.BlockChain().State()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, serverState.GetBalance(testEthAddrs[1]), syncerState.GetBalance(testEthAddrs[1]))
+	assert.Equal(t, []byte{0}, syncerState.GetCode(vmSetup.contractAddr))
+	assert.Equal(t, common.BigToHash(common.Big1), syncerState.GetState(vmSetup.contractAddr, common.Hash{}))
+}
+
+func TestStateSyncFromScratch(t *testing.T) {
+	test := syncTest{
+		syncableInterval:   16,
+		stateSyncMinBlocks: 10, // must be less than [syncableInterval] to perform sync
+		shouldSync:         true,
+	}
+	vmSetup := createSyncServerAndClientVMs(t, test)
+	defer vmSetup.Teardown(t)
+
+	testSyncerVM(t, vmSetup, test)
+}
+
+func TestSkipStateSync(t *testing.T) {
+	test := syncTest{
+		syncableInterval:   16,
+		stateSyncMinBlocks: 20, // must be greater than [syncableInterval] to skip sync
+		shouldSync:         false,
+	}
+	vmSetup := createSyncServerAndClientVMs(t, test)
+	defer vmSetup.Teardown(t)
+
+	testSyncerVM(t, vmSetup, test)
+}
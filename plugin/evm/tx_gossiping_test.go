@@ -4,6 +4,7 @@
 package evm
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"encoding/json"
 	"fmt"
@@ -14,6 +15,7 @@ import (
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/version"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -415,3 +417,116 @@ func TestMempoolTxsPriorityRegossip(t *testing.T) {
 	assert.Len(queued, 10, "unexpected length of queued txs")
 	assert.ElementsMatch(txs, queued)
 }
+
+// show that pull requests are served with the pending txs missing from the filter of the request
+func TestMempoolTxsPullRequestHandling(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := crypto.GenerateKey()
+	assert.NoError(err)
+
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	cfgJson, err := fundAddressByGenesis([]common.Address{addr})
+	assert.NoError(err)
+
+	_, vm, _, _ := GenesisVM(t, true, cfgJson, "", "")
+	defer func() {
+		err := vm.Shutdown()
+		assert.NoError(err)
+	}()
+	vm.chain.GetTxPool().SetGasPrice(common.Big1)
+	vm.chain.GetTxPool().SetMinFee(common.Big0)
+
+	txs := getValidTxs(key, 3, big.NewInt(226*params.GWei))
+	errs := vm.chain.GetTxPool().AddRemotesSync(txs)
+	for _, err := range errs {
+		assert.NoError(err, "failed adding subnet-evm tx to remote mempool")
+	}
+
+	handler := NewTxsPullHandler(vm)
+	request := message.NewTxsPullRequest(common.Hash{1}, []common.Hash{txs[0].Hash()})
+	responseBytes, err := handler.OnTxsPullRequest(context.Background(), ids.GenerateTestNodeID(), 1, request)
+	assert.NoError(err)
+
+	var response message.TxsPullResponse
+	_, err = vm.networkCodec.Unmarshal(responseBytes, &response)
+	assert.NoError(err)
+	pulledTxs := make([]*types.Transaction, 0)
+	assert.NoError(rlp.DecodeBytes(response.Txs, &pulledTxs))
+	pulledHashes := make([]common.Hash, 0, len(pulledTxs))
+	for _, tx := range pulledTxs {
+		pulledHashes = append(pulledHashes, tx.Hash())
+	}
+	assert.ElementsMatch([]common.Hash{txs[1].Hash(), txs[2].Hash()}, pulledHashes)
+
+	// invalid requests are dropped without a response
+	responseBytes, err = handler.OnTxsPullRequest(context.Background(), ids.GenerateTestNodeID(), 2, message.TxsPullRequest{})
+	assert.NoError(err)
+	assert.Nil(responseBytes)
+}
+
+func TestMempoolTxsPull(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := crypto.GenerateKey()
+	assert.NoError(err)
+
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	cfgJson, err := fundAddressByGenesis([]common.Address{addr})
+	assert.NoError(err)
+
+	_, serverVM, _, serverSender := GenesisVM(t, true, cfgJson, "", "")
+	_, pullerVM, _, pullerSender := GenesisVM(t, true, cfgJson, "", "")
+	defer func() {
+		assert.NoError(serverVM.Shutdown())
+		assert.NoError(pullerVM.Shutdown())
+	}()
+	for _, vm := range []*VM{serverVM, pullerVM} {
+		vm.chain.GetTxPool().SetGasPrice(common.Big1)
+		vm.chain.GetTxPool().SetMinFee(common.Big0)
+	}
+
+	// route the requests of [pullerVM] to [serverVM] and the responses back to [pullerVM]
+	pullerSender.SendAppRequestF = func(nodeSet ids.NodeIDSet, requestID uint32, request []byte) error {
+		if _, ok := nodeSet.Pop(); !ok {
+			t.Fatal("expected nodeSet to contain at least 1 nodeID")
+		}
+		go serverVM.AppRequest(pullerVM.ctx.NodeID, requestID, time.Now().Add(time.Second), request)
+		return nil
+	}
+	serverSender.SendAppResponseF = func(_ ids.NodeID, requestID uint32, response []byte) error {
+		go pullerVM.AppResponse(serverVM.ctx.NodeID, requestID, response)
+		return nil
+	}
+
+	// the server is connected at the version reported by the nodes running this release
+	assert.NoError(pullerVM.Connected(serverVM.ctx.NodeID, version.CurrentApp))
+
+	txs := getValidTxs(key, 3, big.NewInt(226*params.GWei))
+	for _, err := range serverVM.chain.GetTxPool().AddRemotesSync(txs) {
+		assert.NoError(err, "failed adding subnet-evm tx to remote mempool")
+	}
+	// the puller already knows the first transaction
+	for _, err := range pullerVM.chain.GetTxPool().AddRemotesSync(txs[:1]) {
+		assert.NoError(err, "failed adding subnet-evm tx to remote mempool")
+	}
+
+	puller := &pullGossiper{
+		ctx:       pullerVM.ctx,
+		frequency: time.Second,
+		client:    pullerVM.client,
+		txPool:    pullerVM.chain.GetTxPool(),
+		codec:     pullerVM.networkCodec,
+	}
+	assert.NoError(puller.pullTxs())
+	assert.Eventually(func() bool {
+		for _, tx := range txs {
+			if !pullerVM.chain.GetTxPool().Has(tx.Hash()) {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond, "pulled transactions were not added to the mempool")
+}
@@ -28,14 +28,21 @@ import (
 	"github.com/ava-labs/subnet-evm/params"
 	"github.com/ava-labs/subnet-evm/peer"
 	"github.com/ava-labs/subnet-evm/plugin/evm/message"
+	"github.com/ava-labs/subnet-evm/precompile"
+	statesyncclient "github.com/ava-labs/subnet-evm/statesync/client"
+	statesyncclientstats "github.com/ava-labs/subnet-evm/statesync/client/stats"
+	"github.com/ava-labs/subnet-evm/statesync/handlers"
+	handlerstats "github.com/ava-labs/subnet-evm/statesync/handlers/stats"
+	"github.com/ava-labs/subnet-evm/trie"
 
 	"github.com/prometheus/client_golang/prometheus"
 
 	// Force-load tracer engine to trigger registration
 	//
-	// We must import this package (not referenced elsewhere) so that the native "callTracer"
-	// is added to a map of client-accessible tracers. In geth, this is done
+	// We must import these packages (not referenced elsewhere) so that the native "callTracer"
+	// and the JavaScript tracers are added to the client-accessible tracers. In geth, this is done
 	// inside of cmd/geth.
+	_ "github.com/ava-labs/subnet-evm/eth/tracers/js"
 	_ "github.com/ava-labs/subnet-evm/eth/tracers/native"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -68,6 +75,7 @@ import (
 var (
 	_ block.ChainVM              = &VM{}
 	_ block.HeightIndexedChainVM = &VM{}
+	_ block.StateSyncableVM      = &VM{}
 )
 
 const (
@@ -82,6 +90,10 @@ const (
 	// Prefixes for metrics gatherers
 	ethMetricsPrefix        = "eth"
 	chainStateMetricsPrefix = "chain_state"
+
+	// State sync client settings
+	stateSyncMaxAttempts   = 32
+	stateSyncMaxRetryDelay = 5 * time.Second
 )
 
 // Define the API endpoints for the VM
@@ -95,6 +107,7 @@ var (
 	// Set last accepted key to be longer than the keys used to store accepted block IDs.
 	lastAcceptedKey = []byte("last_accepted_key")
 	acceptedPrefix  = []byte("snowman_accepted")
+	metadataPrefix  = []byte("metadata")
 	ethDBPrefix     = []byte("ethdb")
 )
 
@@ -144,12 +157,16 @@ type VM struct {
 	// [acceptedBlockDB] is the database to store the last accepted
 	// block.
 	acceptedBlockDB database.Database
+	// [metadataDB] is used to store one off keys.
+	metadataDB database.Database
 
 	toEngine chan<- commonEng.Message
 
 	builder *blockBuilder
 
 	gossiper Gossiper
+	// [pullGossiper] is started once the VM reaches normal operation
+	pullGossiper *pullGossiper
 
 	clock mockable.Clock
 
@@ -167,6 +184,13 @@ type VM struct {
 	multiGatherer avalanchegoMetrics.MultiGatherer
 
 	bootstrapped bool
+
+	// State sync server and client
+	StateSyncServer
+	StateSyncClient
+
+	// [warpBackend] signs the warp messages sent by accepted blocks
+	warpBackend *warpBackend
 }
 
 // setLogLevel initializes logger and sets the log level with the original [os.StdErr] interface
@@ -265,6 +289,7 @@ func (vm *VM) Initialize(
 	vm.chaindb = Database{prefixdb.NewNested(ethDBPrefix, baseDB)}
 	vm.db = versiondb.New(baseDB)
 	vm.acceptedBlockDB = prefixdb.New(acceptedPrefix, vm.db)
+	vm.metadataDB = prefixdb.New(metadataPrefix, vm.db)
 	g := new(core.Genesis)
 	if err := json.Unmarshal(genesisBytes, g); err != nil {
 		return err
@@ -290,6 +315,7 @@ func (vm *VM) Initialize(
 	ethConfig.RPCTxFeeCap = vm.config.RPCTxFeeCap
 	ethConfig.TxPool.NoLocals = !vm.config.LocalTxsEnabled
 	ethConfig.TxPool.Locals = vm.config.PriorityRegossipAddresses
+	ethConfig.TxPool.Policies = vm.config.TxPoolPolicies.Policies()
 	ethConfig.AllowUnfinalizedQueries = vm.config.AllowUnfinalizedQueries
 	ethConfig.AllowUnprotectedTxs = vm.config.AllowUnprotectedTxs
 	ethConfig.Preimages = vm.config.Preimages
@@ -314,21 +340,6 @@ func (vm *VM) Initialize(
 		}
 	}
 
-	// Handle custom fee recipient
-	ethConfig.Miner.Etherbase = constants.BlackholeAddr
-	switch {
-	case common.IsHexAddress(vm.config.FeeRecipient):
-		if g.Config.AllowFeeRecipients {
-			address := common.HexToAddress(vm.config.FeeRecipient)
-			log.Info("Setting fee recipient", "address", address)
-			ethConfig.Miner.Etherbase = address
-			break
-		}
-		return errors.New("cannot specify a custom fee recipient on this blockchain")
-	case g.Config.AllowFeeRecipients:
-		log.Warn("Chain enabled `AllowFeeRecipients`, but chain config has not specified any coinbase address. Defaulting to the blackhole address.")
-	}
-
 	vm.chainConfig = g.Config
 	vm.networkID = ethConfig.NetworkId
 
@@ -342,6 +353,26 @@ func (vm *VM) Initialize(
 		vm.chainConfig.UpgradeConfig = upgradeConfig
 	}
 
+	// Handle custom fee recipient
+	// Note: the miner only uses the fee recipient if fee recipients are allowed, either by
+	// [AllowFeeRecipients] or by the reward manager precompile. Otherwise, the block rewards
+	// are sent to the address configured in the reward manager or burned.
+	ethConfig.Miner.Etherbase = constants.BlackholeAddr
+	switch {
+	case common.IsHexAddress(vm.config.FeeRecipient):
+		address := common.HexToAddress(vm.config.FeeRecipient)
+		if !g.Config.AllowFeeRecipients {
+			if !vm.chainConfig.IsPrecompileConfigured(precompile.RewardManagerAddress) {
+				return errors.New("cannot specify a custom fee recipient on this blockchain")
+			}
+			log.Warn("Chain has not enabled `AllowFeeRecipients`, fee recipient will only be used if allowed by the reward manager precompile", "address", address)
+		}
+		log.Info("Setting fee recipient", "address", address)
+		ethConfig.Miner.Etherbase = address
+	case g.Config.AllowFeeRecipients:
+		log.Warn("Chain enabled `AllowFeeRecipients`, but chain config has not specified any coinbase address. Defaulting to the blackhole address.")
+	}
+
 	// create genesisHash after applying upgradeBytes in case
 	// upgradeBytes modifies genesis.
 	vm.genesisHash = ethConfig.Genesis.ToBlock(nil).Hash()
@@ -369,6 +400,16 @@ func (vm *VM) Initialize(
 		return err
 	}
 
+	if err := vm.initializeWarpBackend(); err != nil {
+		return err
+	}
+
+	vm.setAppRequestHandlers()
+	vm.initializeStateSyncServer()
+	if err := vm.initializeStateSyncClient(); err != nil {
+		return err
+	}
+
 	go vm.ctx.Log.RecoverAndPanic(vm.startContinuousProfiler)
 
 	return nil
@@ -419,6 +460,67 @@ func (vm *VM) initializeChain(lastAcceptedHash common.Hash, ethConfig ethconfig.
 	return vm.initChainState(vm.chain.LastAcceptedBlock())
 }
 
+// initializeWarpBackend initializes the backend used to sign warp messages with the
+// key derived from the staking signer of this node.
+func (vm *VM) initializeWarpBackend() error {
+	signer, err := newStakingWarpSigner(vm.ctx.StakingLeafSigner)
+	if err != nil {
+		return fmt.Errorf("failed to initialize warp signer: %w", err)
+	}
+	vm.warpBackend = newWarpBackend(prefixdb.New(warpPrefix, vm.db), signer)
+	return nil
+}
+
+// initializeStateSyncServer should be called after [vm.chain] is initialized.
+func (vm *VM) initializeStateSyncServer() {
+	vm.StateSyncServer = NewStateSyncServer(&stateSyncServerConfig{
+		Chain:            vm.chain,
+		SyncableInterval: vm.config.CommitInterval,
+		Codec:            vm.networkCodec,
+	})
+}
+
+// initializeStateSyncClient should be called after [vm.chain] and [vm.State] are initialized.
+func (vm *VM) initializeStateSyncClient() error {
+	var syncerStats statesyncclientstats.ClientSyncerStats
+	if metrics.Enabled {
+		syncerStats = statesyncclientstats.NewClientSyncerStats()
+	} else {
+		syncerStats = statesyncclientstats.NewNoOpStats()
+	}
+
+	vm.StateSyncClient = NewStateSyncClient(&stateSyncClientConfig{
+		enabled:            vm.config.StateSyncEnabled,
+		skipResume:         vm.config.StateSyncSkipResume,
+		stateSyncMinBlocks: vm.config.StateSyncMinBlocks,
+		lastAcceptedHeight: vm.chain.LastAcceptedBlock().NumberU64(),
+		chain:              vm.chain,
+		state:              vm.State,
+		chaindb:            vm.chaindb,
+		metadataDB:         vm.metadataDB,
+		acceptedBlockDB:    vm.acceptedBlockDB,
+		db:                 vm.db,
+		client: statesyncclient.NewClient(
+			vm.client,
+			vm.networkCodec,
+			stateSyncMaxAttempts,
+			stateSyncMaxRetryDelay,
+			syncerStats,
+		),
+		stats:    syncerStats,
+		codec:    vm.networkCodec,
+		toEngine: vm.toEngine,
+	})
+
+	// If StateSync is disabled, clear any ongoing summary so that we will not attempt to resume
+	// sync using a snapshot that has been modified by the node running normal operations.
+	if !vm.config.StateSyncEnabled {
+		return vm.StateSyncClient.StateSyncClearOngoingSummary()
+	}
+
+	return nil
+}
+
 func (vm *VM) initChainState(lastAcceptedBlock *types.Block) error {
 	config := &chain.Config{
 		DecidedCacheSize:    decidedCacheSize,
@@ -447,9 +549,42 @@ func (vm *VM) initChainState(lastAcceptedBlock *types.Block) error {
 	return vm.multiGatherer.Register(chainStateMetricsPrefix, chainStateRegisterer)
 }
 
+// setAppRequestHandlers sets the request handlers for the VM to serve state sync
+// requests and the transactions of the mempool.
+func (vm *VM) setAppRequestHandlers() {
+	// Create separate EVM TrieDB (read only) for serving leafs requests.
+	// We create a separate TrieDB here, so that it has a separate cache from the one
+	// used by the node when processing blocks.
+	evmTrieDB := trie.NewDatabaseWithConfig(
+		vm.chaindb,
+		&trie.Config{
+			Cache: vm.config.StateSyncServerTrieCache,
+		},
+	)
+	var handlerStats handlerstats.HandlerStats
+	if metrics.Enabled {
+		handlerStats = handlerstats.NewHandlerStats()
+	} else {
+		handlerStats = handlerstats.NewNoopHandlerStats()
+	}
+	syncRequestHandler := handlers.NewSyncHandler(
+		handlers.NewLeafsRequestHandler(evmTrieDB, handlerStats, vm.networkCodec),
+		handlers.NewBlockRequestHandler(vm.chain.BlockChain().GetBlock, vm.networkCodec, handlerStats),
+		handlers.NewCodeRequestHandler(evmTrieDB.DiskDB(), handlerStats, vm.networkCodec),
+	)
+	if vm.chainConfig.SubnetEVMTimestamp == nil {
+		vm.Network.SetRequestHandler(syncRequestHandler)
+		return
+	}
+	vm.Network.SetRequestHandler(newNetworkHandler(syncRequestHandler, NewTxsPullHandler(vm)))
+}
+
 func (vm *VM) initGossipHandling() {
 	if vm.chainConfig.SubnetEVMTimestamp != nil {
 		vm.Network.SetGossipHandler(NewGossipHandler(vm))
+		if vm.pullGossiper == nil && vm.config.TxsPullGossipFrequency.Duration > 0 {
+			vm.pullGossiper = vm.createPullGossiper()
+		}
 	}
 }
 
@@ -457,6 +592,9 @@ func (vm *VM) SetState(state snow.State) error {
 	switch state {
 	case snow.Bootstrapping:
 		vm.bootstrapped = false
+		if err := vm.StateSyncClient.Error(); err != nil {
+			return err
+		}
 		return nil
 	case snow.NormalOp:
 		vm.initGossipHandling()
@@ -473,6 +611,9 @@ func (vm *VM) Shutdown() error {
 		return nil
 	}
 
+	if err := vm.StateSyncClient.Shutdown(); err != nil {
+		log.Error("error stopping state syncer", "err", err)
+	}
 	close(vm.shutdownChan)
 	vm.chain.Stop()
 	vm.shutdownWg.Wait()
@@ -640,6 +781,13 @@ func (vm *VM) CreateHandlers() (map[string]*commonEng.HTTPHandler, error) {
 		enabledAPIs = append(enabledAPIs, "snowman")
 	}
 
+	if vm.config.WarpAPIEnabled {
+		if err := handler.RegisterName("warp", &WarpAPI{vm.warpBackend}); err != nil {
+			return nil, err
+		}
+		enabledAPIs = append(enabledAPIs, "warp")
+	}
+
 	log.Info(fmt.Sprintf("Enabled APIs: %s", strings.Join(enabledAPIs, ", ")))
 	apis[ethRPCEndpoint] = &commonEng.HTTPHandler{
 		LockOptions: commonEng.NoLock,
@@ -701,7 +849,10 @@ func (vm *VM) currentRules() params.Rules {
 // follows the ruleset defined by [rules]
 func (vm *VM) getBlockValidator(rules params.Rules) BlockValidator {
 	if rules.IsSubnetEVM {
-		return blockValidatorSubnetEVM{feeConfigManagerEnabled: rules.IsFeeConfigManagerEnabled}
+		return blockValidatorSubnetEVM{
+			feeConfigManagerEnabled: rules.IsPrecompileEnabled(precompile.FeeConfigManagerAddress),
+			rewardManagerEnabled:    rules.IsPrecompileEnabled(precompile.RewardManagerAddress),
+		}
 	}
 
 	return legacyBlockValidator
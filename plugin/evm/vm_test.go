@@ -7,6 +7,7 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"crypto/rand"
+	"crypto/rsa"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -68,6 +69,9 @@ var (
 	genesisBalance          *big.Int
 )
 
+// testStakingSigner is the staking key of the test nodes, used to derive their warp signing key
+var testStakingSigner *rsa.PrivateKey
+
 func init() {
 	key1, _ := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
 	key2, _ := crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
@@ -76,6 +80,12 @@ func init() {
 	addr2 := crypto.PubkeyToAddress(key2.PublicKey)
 	testEthAddrs = append(testEthAddrs, addr1, addr2)
 
+	var err error
+	testStakingSigner, err = rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
 	minBlockTime = time.Millisecond
 	maxBlockTime = time.Millisecond
 	firstTxAmount = new(big.Int).Mul(big.NewInt(testMinGasPrice), big.NewInt(21000*100))
@@ -110,6 +120,7 @@ func NewContext() *snow.Context {
 	ctx.ChainID = testCChainID
 	ctx.AVAXAssetID = testAvaxAssetID
 	ctx.XChainID = testXChainID
+	ctx.StakingLeafSigner = testStakingSigner
 	aliaser := ctx.BCLookup.(ids.Aliaser)
 	_ = aliaser.Alias(testCChainID, "C")
 	_ = aliaser.Alias(testCChainID, testCChainID.String())
@@ -2108,7 +2119,9 @@ func TestBuildAllowListActivationBlock(t *testing.T) {
 	if err := genesis.UnmarshalJSON([]byte(genesisJSONSubnetEVM)); err != nil {
 		t.Fatal(err)
 	}
-	genesis.Config.ContractDeployerAllowListConfig = precompile.NewContractDeployerAllowListConfig(big.NewInt(time.Now().Unix()), testEthAddrs)
+	genesis.Config.GenesisPrecompiles = params.Precompiles{
+		precompile.ContractDeployerAllowListConfigKey: precompile.NewContractDeployerAllowListConfig(big.NewInt(time.Now().Unix()), testEthAddrs),
+	}
 
 	genesisJSON, err := genesis.MarshalJSON()
 	if err != nil {
@@ -2172,7 +2185,9 @@ func TestTxAllowListSuccessfulTx(t *testing.T) {
 	if err := genesis.UnmarshalJSON([]byte(genesisJSONSubnetEVM)); err != nil {
 		t.Fatal(err)
 	}
-	genesis.Config.TxAllowListConfig = precompile.NewTxAllowListConfig(big.NewInt(0), testEthAddrs[0:1])
+	genesis.Config.GenesisPrecompiles = params.Precompiles{
+		precompile.TxAllowListConfigKey: precompile.NewTxAllowListConfig(big.NewInt(0), testEthAddrs[0:1]),
+	}
 	genesisJSON, err := genesis.MarshalJSON()
 	if err != nil {
 		t.Fatal(err)
@@ -2248,7 +2263,9 @@ func TestTxAllowListDisablePrecompile(t *testing.T) {
 		t.Fatal(err)
 	}
 	enableAllowListTimestamp := time.Unix(0, 0) // enable at genesis
-	genesis.Config.TxAllowListConfig = precompile.NewTxAllowListConfig(big.NewInt(enableAllowListTimestamp.Unix()), testEthAddrs[0:1])
+	genesis.Config.GenesisPrecompiles = params.Precompiles{
+		precompile.TxAllowListConfigKey: precompile.NewTxAllowListConfig(big.NewInt(enableAllowListTimestamp.Unix()), testEthAddrs[0:1]),
+	}
 	genesisJSON, err := genesis.MarshalJSON()
 	if err != nil {
 		t.Fatal(err)
@@ -2263,7 +2280,7 @@ func TestTxAllowListDisablePrecompile(t *testing.T) {
 	precompileConfigs.PrecompileUpgrades = append(
 		precompileConfigs.PrecompileUpgrades,
 		params.PrecompileUpgrade{
-			TxAllowListConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(disableAllowListTimestamp.Unix())),
+			StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(disableAllowListTimestamp.Unix())),
 		},
 	)
 
@@ -2355,7 +2372,9 @@ func TestFeeManagerChangeFee(t *testing.T) {
 	if err := genesis.UnmarshalJSON([]byte(genesisJSONSubnetEVM)); err != nil {
 		t.Fatal(err)
 	}
-	genesis.Config.FeeManagerConfig = precompile.NewFeeManagerConfig(big.NewInt(0), testEthAddrs[0:1])
+	genesis.Config.GenesisPrecompiles = params.Precompiles{
+		precompile.FeeConfigManagerConfigKey: precompile.NewFeeManagerConfig(big.NewInt(0), testEthAddrs[0:1]),
+	}
 
 	// set a lower fee config now
 	testLowFeeConfig := commontype.FeeConfig{
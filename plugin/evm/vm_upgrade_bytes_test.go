@@ -6,6 +6,7 @@ package evm
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"math/big"
 	"testing"
 	"time"
@@ -26,7 +27,7 @@ func TestVMUpgradeBytesPrecompile(t *testing.T) {
 	upgradeConfig := &params.UpgradeConfig{
 		PrecompileUpgrades: []params.PrecompileUpgrade{
 			{
-				TxAllowListConfig: precompile.NewTxAllowListConfig(big.NewInt(enableAllowListTimestamp.Unix()), testEthAddrs[0:1]),
+				StatefulPrecompileConfig: precompile.NewTxAllowListConfig(big.NewInt(enableAllowListTimestamp.Unix()), testEthAddrs[0:1]),
 			},
 		},
 	}
@@ -69,7 +70,7 @@ func TestVMUpgradeBytesPrecompile(t *testing.T) {
 	upgradeConfig.PrecompileUpgrades = append(
 		upgradeConfig.PrecompileUpgrades,
 		params.PrecompileUpgrade{
-			TxAllowListConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(disableAllowListTimestamp.Unix())),
+			StatefulPrecompileConfig: precompile.NewDisableTxAllowListConfig(big.NewInt(disableAllowListTimestamp.Unix())),
 		},
 	)
 	upgradeBytesJSON, err = json.Marshal(upgradeConfig)
@@ -261,3 +262,27 @@ func TestVMUpgradeBytesNetworkUpgradesWithGenesis(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestVMCustomFeeRecipientRequiresRewardManager(t *testing.T) {
+	configJSON := fmt.Sprintf(`{"feeRecipient": %q}`, testEthAddrs[1].Hex())
+
+	// fee recipients are rejected if neither the chain nor the reward manager can allow them
+	vm := &VM{}
+	ctx, dbManager, genesisBytes, issuer := setupGenesis(t, genesisJSONSubnetEVM)
+	err := vm.Initialize(ctx, dbManager, genesisBytes, nil, []byte(configJSON), issuer, []*common.Fx{}, &common.SenderTest{T: t})
+	assert.ErrorContains(t, err, "cannot specify a custom fee recipient on this blockchain")
+
+	// the fee recipient is accepted once the reward manager is configured by upgradeBytes
+	upgradeBytesJSON, err := json.Marshal(&params.UpgradeConfig{
+		PrecompileUpgrades: []params.PrecompileUpgrade{
+			{
+				StatefulPrecompileConfig: precompile.NewRewardManagerConfig(big.NewInt(time.Now().Add(time.Hour).Unix()), testEthAddrs[0:1], nil),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("could not marshal upgradeConfig to json: %s", err)
+	}
+	_, vm, _, _ = GenesisVM(t, false, genesisJSONSubnetEVM, configJSON, string(upgradeBytesJSON))
+	assert.NoError(t, vm.Shutdown())
+}
@@ -0,0 +1,150 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/precompile"
+	"github.com/ava-labs/subnet-evm/warp"
+	"github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	warpPrefix = []byte("warp")
+	// warpKeyDerivationMessage is signed by the staking key of this node to derive
+	// the key used to sign warp messages.
+	warpKeyDerivationMessage = []byte("subnet-evm warp signing key")
+
+	errWarpMessageNotFound       = errors.New("warp message not found")
+	errNoStakingSigner           = errors.New("no staking signer to derive the warp signing key from")
+	errNondeterministicSignature = errors.New("staking signer does not produce deterministic signatures")
+)
+
+// signedWarpMessage is the record stored by the warpBackend for each message
+// signed by this node.
+type signedWarpMessage struct {
+	UnsignedMessage []byte
+	Signature       []byte
+}
+
+// warpBackend signs the warp messages sent by accepted transactions with the
+// BLS key of this node and stores the signatures so that they can be served
+// to relayers that aggregate them.
+type warpBackend struct {
+	lock   sync.RWMutex
+	db     database.Database
+	signer warp.Signer
+}
+
+// newWarpBackend returns a warpBackend storing signatures in [db] and signing with [signer].
+func newWarpBackend(db database.Database, signer warp.Signer) *warpBackend {
+	return &warpBackend{
+		db:     db,
+		signer: signer,
+	}
+}
+
+// newStakingWarpSigner returns the BLS key of this node derived from [stakingSigner],
+// the signer of the staking certificate of the node, so that the warp signing key is
+// bound to the staking identity of the node and is never written to disk.
+// The key is derived from the signature of [warpKeyDerivationMessage], which requires
+// a deterministic signature scheme such as the RSA staking keys of avalanchego.
+// Note: avalanchego does not provide BLS staking keys to VMs yet, so the validator
+// sets configured in the warp precompile list the public keys derived here.
+func newStakingWarpSigner(stakingSigner crypto.Signer) (*warp.SecretKey, error) {
+	if stakingSigner == nil {
+		return nil, errNoStakingSigner
+	}
+	digest := sha256.Sum256(warpKeyDerivationMessage)
+	seed, err := stakingSigner.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign warp key derivation message: %w", err)
+	}
+	otherSeed, err := stakingSigner.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign warp key derivation message: %w", err)
+	}
+	if !bytes.Equal(seed, otherSeed) {
+		return nil, errNondeterministicSignature
+	}
+	return warp.SecretKeyFromSeed(seed)
+}
+
+// PublicKey returns the public key corresponding to the key used to sign warp messages
+func (b *warpBackend) PublicKey() *warp.PublicKey {
+	return b.signer.PublicKey()
+}
+
+// AddMessage signs [unsignedMessage] and stores it along with its signature.
+// Returns the ID of the message.
+func (b *warpBackend) AddMessage(unsignedMessage []byte) (common.Hash, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	messageID := ethcrypto.Keccak256Hash(unsignedMessage)
+	record, err := rlp.EncodeToBytes(&signedWarpMessage{
+		UnsignedMessage: unsignedMessage,
+		Signature:       b.signer.Sign(unsignedMessage).Bytes(),
+	})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := b.db.Put(messageID[:], record); err != nil {
+		return common.Hash{}, fmt.Errorf("failed to write warp message %s: %w", messageID, err)
+	}
+	return messageID, nil
+}
+
+// GetMessage returns the unsigned message and signature of the warp message
+// identified by [messageID].
+func (b *warpBackend) GetMessage(messageID common.Hash) (*signedWarpMessage, error) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	recordBytes, err := b.db.Get(messageID[:])
+	if err == database.ErrNotFound {
+		return nil, fmt.Errorf("%w: %s", errWarpMessageNotFound, messageID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	record := new(signedWarpMessage)
+	if err := rlp.DecodeBytes(recordBytes, record); err != nil {
+		return nil, fmt.Errorf("failed to parse warp message %s: %w", messageID, err)
+	}
+	return record, nil
+}
+
+// AcceptReceipts signs all warp messages sent by the transactions of [receipts].
+func (b *warpBackend) AcceptReceipts(receipts types.Receipts) error {
+	for _, receipt := range receipts {
+		for _, txLog := range receipt.Logs {
+			if txLog.Address != precompile.WarpMessengerAddress || len(txLog.Topics) == 0 || txLog.Topics[0] != precompile.SendWarpMessageEventID {
+				continue
+			}
+			unsignedMessage, err := precompile.UnpackSendWarpMessageEventData(txLog.Data)
+			if err != nil {
+				return fmt.Errorf("failed to unpack warp message sent by %s: %w", txLog.TxHash, err)
+			}
+			messageID, err := b.AddMessage(unsignedMessage)
+			if err != nil {
+				return err
+			}
+			log.Debug("signed warp message", "messageID", messageID, "txHash", txLog.TxHash)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/precompile"
+	"github.com/ava-labs/subnet-evm/warp"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStakingWarpSigner(t *testing.T) {
+	signer, err := newStakingWarpSigner(testStakingSigner)
+	assert.NoError(t, err)
+	// The key is derived deterministically from the staking key
+	otherSigner, err := newStakingWarpSigner(testStakingSigner)
+	assert.NoError(t, err)
+	assert.Equal(t, signer.Bytes(), otherSigner.Bytes())
+
+	_, err = newStakingWarpSigner(nil)
+	assert.ErrorIs(t, err, errNoStakingSigner)
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	_, err = newStakingWarpSigner(ecdsaKey)
+	assert.ErrorIs(t, err, errNondeterministicSignature)
+}
+
+func TestWarpBackendAcceptReceipts(t *testing.T) {
+	signer, err := warp.NewSecretKey()
+	assert.NoError(t, err)
+	backend := newWarpBackend(memdb.New(), signer)
+
+	message := &warp.UnsignedMessage{
+		SourceChainID:      common.Hash{1},
+		SourceAddress:      common.Address{2},
+		DestinationChainID: common.Hash{3},
+		DestinationAddress: common.Address{4},
+		Payload:            []byte("payload"),
+	}
+	unsignedMessage, err := message.Bytes()
+	assert.NoError(t, err)
+	data, err := precompile.PackSendWarpMessageEventData(unsignedMessage)
+	assert.NoError(t, err)
+
+	receipts := types.Receipts{{
+		Logs: []*types.Log{
+			{Address: common.Address{5}, Topics: []common.Hash{precompile.SendWarpMessageEventID}, Data: data},
+			{Address: precompile.WarpMessengerAddress, Topics: []common.Hash{precompile.SendWarpMessageEventID}, Data: data},
+		},
+	}}
+	assert.NoError(t, backend.AcceptReceipts(receipts))
+
+	messageID, err := message.ID()
+	assert.NoError(t, err)
+	signedMessage, err := backend.GetMessage(messageID)
+	assert.NoError(t, err)
+	assert.Equal(t, unsignedMessage, signedMessage.UnsignedMessage)
+	signature, err := warp.SignatureFromBytes(signedMessage.Signature)
+	assert.NoError(t, err)
+	assert.True(t, warp.Verify(signer.PublicKey(), signature, unsignedMessage))
+}
@@ -0,0 +1,42 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// WarpAPI introduces warp messaging specific functionality to the evm
+type WarpAPI struct{ backend *warpBackend }
+
+// GetSignatureReply defines the reply that will be sent from the
+// GetSignature API call
+type GetSignatureReply struct {
+	MessageID       common.Hash   `json:"messageID"`
+	UnsignedMessage hexutil.Bytes `json:"unsignedMessage"`
+	Signature       hexutil.Bytes `json:"signature"`
+	PublicKey       hexutil.Bytes `json:"publicKey"`
+}
+
+// GetSignature returns the signature of this node for the warp message identified by [messageID]
+func (api *WarpAPI) GetSignature(ctx context.Context, messageID common.Hash) (*GetSignatureReply, error) {
+	message, err := api.backend.GetMessage(messageID)
+	if err != nil {
+		return nil, err
+	}
+	return &GetSignatureReply{
+		MessageID:       messageID,
+		UnsignedMessage: message.UnsignedMessage,
+		Signature:       message.Signature,
+		PublicKey:       api.backend.PublicKey().Bytes(),
+	}, nil
+}
+
+// GetPublicKey returns the public key this node uses to sign warp messages
+func (api *WarpAPI) GetPublicKey(ctx context.Context) (hexutil.Bytes, error) {
+	return api.backend.PublicKey().Bytes(), nil
+}
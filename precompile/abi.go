@@ -0,0 +1,114 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/subnet-evm/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	ErrInvalidABIInput  = errors.New("invalid ABI encoded input")
+	ErrInvalidABIOutput = errors.New("invalid ABI output")
+)
+
+// RunStatefulPrecompileABIFunc is the execution function of a stateful precompile function that is
+// bound to an ABI method. [args] are the arguments of the call unpacked according to the inputs of the
+// method and the returned [outputs] are packed according to the outputs of the method.
+type RunStatefulPrecompileABIFunc func(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, args []interface{}, suppliedGas uint64, readOnly bool) (outputs []interface{}, remainingGas uint64, err error)
+
+// newStatefulPrecompileABIFunction creates a stateful precompile function that is selected by the ID of [method].
+// The input of the call is strictly unpacked into the arguments of [method] before [execute] is called and the
+// outputs returned by [execute] are packed according to [method], so that the function can accept and return
+// dynamic types such as bytes, string and arrays.
+// If [method] has dynamic arguments, [DynamicInputGasCostPerWord] is deducted for each word of the input before
+// it is unpacked, since the size of the input is then unbounded. The input of the other methods has a fixed size.
+// Note: [execute] is still responsible for deducting the gas of the call, which may depend on its arguments.
+func newStatefulPrecompileABIFunction(method abi.Method, execute RunStatefulPrecompileABIFunc) *statefulPrecompileFunction {
+	dynamicInput := hasDynamicInputs(method)
+	return newStatefulPrecompileFunction(method.ID, func(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		remainingGas = suppliedGas
+		if dynamicInput {
+			inputWords := (uint64(len(input)) + common.HashLength - 1) / common.HashLength
+			if remainingGas, err = deductGasPerItem(suppliedGas, inputWords, DynamicInputGasCostPerWord); err != nil {
+				return nil, 0, err
+			}
+		}
+		args, err := UnpackInputStrict(method, input)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+
+		outputs, remainingGas, err := execute(accessibleState, caller, addr, args, remainingGas, readOnly)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+
+		ret, err = method.Outputs.Pack(outputs...)
+		if err != nil {
+			return nil, remainingGas, fmt.Errorf("%w for %s: %s", ErrInvalidABIOutput, method.Name, err)
+		}
+		return ret, remainingGas, nil
+	})
+}
+
+// hasDynamicInputs returns true if the size of the encoding of the arguments of [method] depends on their values.
+func hasDynamicInputs(method abi.Method) bool {
+	for _, input := range method.Inputs {
+		if isDynamicType(input.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// isDynamicType returns true if [t] is encoded with a size that depends on its value, as in the ABI specification.
+func isDynamicType(t abi.Type) bool {
+	switch t.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy:
+		return true
+	case abi.ArrayTy:
+		return isDynamicType(*t.Elem)
+	case abi.TupleTy:
+		for _, elem := range t.TupleElems {
+			if isDynamicType(*elem) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// UnpackInputStrict unpacks [input] into the arguments of [method] and returns an error if [input] is
+// not the canonical ABI encoding of the unpacked arguments, such as if it has trailing bytes, non-zero
+// padding or out of order offsets of dynamic types.
+// Assumes that [input] does not include the function selector.
+func UnpackInputStrict(method abi.Method, input []byte) ([]interface{}, error) {
+	args, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, fmt.Errorf("%w for %s: %s", ErrInvalidABIInput, method.Name, err)
+	}
+	canonical, err := method.Inputs.Pack(args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w for %s: %s", ErrInvalidABIInput, method.Name, err)
+	}
+	if !bytes.Equal(canonical, input) {
+		return nil, fmt.Errorf("%w for %s: input is not canonically encoded", ErrInvalidABIInput, method.Name)
+	}
+	return args, nil
+}
+
+// mustParseABI parses [rawABI] and panics if it is invalid
+func mustParseABI(rawABI string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(rawABI))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
@@ -0,0 +1,109 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+var testABI = mustParseABI(`[
+	{"type":"function","name":"countAddresses","stateMutability":"view","inputs":[{"name":"addresses","type":"address[]"}],"outputs":[{"name":"count","type":"uint8"}]},
+	{"type":"function","name":"echo","stateMutability":"view","inputs":[{"name":"payload","type":"bytes"}],"outputs":[{"name":"payload","type":"bytes"}]},
+	{"type":"function","name":"noop","stateMutability":"nonpayable","inputs":[],"outputs":[]}
+]`)
+
+func newTestABIPrecompile() StatefulPrecompiledContract {
+	countAddresses := newStatefulPrecompileABIFunction(testABI.Methods["countAddresses"], func(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, args []interface{}, suppliedGas uint64, readOnly bool) ([]interface{}, uint64, error) {
+		return []interface{}{uint8(len(args[0].([]common.Address)))}, suppliedGas, nil
+	})
+	echo := newStatefulPrecompileABIFunction(testABI.Methods["echo"], func(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, args []interface{}, suppliedGas uint64, readOnly bool) ([]interface{}, uint64, error) {
+		return args, suppliedGas, nil
+	})
+	noop := newStatefulPrecompileABIFunction(testABI.Methods["noop"], func(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, args []interface{}, suppliedGas uint64, readOnly bool) ([]interface{}, uint64, error) {
+		// return an output that is not part of the method to test output validation
+		return []interface{}{uint8(1)}, suppliedGas, nil
+	})
+	return newStatefulPrecompileWithFunctionSelectors(nil, []*statefulPrecompileFunction{countAddresses, echo, noop})
+}
+
+func TestStatefulPrecompileABIFunction(t *testing.T) {
+	precompile := newTestABIPrecompile()
+	mustPack := func(name string, args ...interface{}) []byte {
+		input, err := testABI.Pack(name, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return input
+	}
+	addresses := []common.Address{{1}, {2}, {3}}
+
+	tests := map[string]struct {
+		input           []byte
+		suppliedGas     uint64
+		expectedRes     []byte
+		expectedGasUsed uint64
+		expectedErr     error
+	}{
+		"dynamic array input": {
+			input:           mustPack("countAddresses", addresses),
+			expectedRes:     common.LeftPadBytes([]byte{3}, common.HashLength),
+			expectedGasUsed: 5 * DynamicInputGasCostPerWord, // offset, length and 3 addresses
+		},
+		"dynamic bytes input and output": {
+			input:           mustPack("echo", []byte("hello world")),
+			expectedRes:     mustPack("echo", []byte("hello world"))[selectorLen:],
+			expectedGasUsed: 3 * DynamicInputGasCostPerWord, // offset, length and payload
+		},
+		"insufficient gas to unpack dynamic input": {
+			input:       mustPack("countAddresses", addresses),
+			suppliedGas: 5*DynamicInputGasCostPerWord - 1,
+			expectedErr: vmerrs.ErrOutOfGas,
+		},
+		"trailing bytes": {
+			input:       append(mustPack("countAddresses", addresses), 0),
+			expectedErr: ErrInvalidABIInput,
+		},
+		"truncated input": {
+			input:       mustPack("countAddresses", addresses)[:100],
+			expectedErr: ErrInvalidABIInput,
+		},
+		"non-zero padding": {
+			input: func() []byte {
+				input := mustPack("countAddresses", addresses)
+				// set the first byte of the padding of the first address
+				input[selectorLen+2*common.HashLength] = 1
+				return input
+			}(),
+			expectedErr: ErrInvalidABIInput,
+		},
+		"missing input": {
+			input:       testABI.Methods["echo"].ID,
+			expectedErr: ErrInvalidABIInput,
+		},
+		"invalid output": {
+			input:       mustPack("noop"),
+			expectedErr: ErrInvalidABIOutput,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			suppliedGas := test.suppliedGas
+			if suppliedGas == 0 {
+				suppliedGas = 100
+			}
+			res, remainingGas, err := precompile.Run(nil, common.Address{}, common.Address{}, test.input, suppliedGas, false)
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedRes, res)
+			assert.Equal(t, suppliedGas-test.expectedGasUsed, remainingGas)
+		})
+	}
+}
@@ -8,10 +8,34 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/ava-labs/subnet-evm/accounts/abi"
 	"github.com/ava-labs/subnet-evm/vmerrs"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
+// AllowListRawABI is the ABI of the IAllowList interface
+const AllowListRawABI = `[
+	{"type":"event","name":"RoleSet","anonymous":false,"inputs":[{"indexed":true,"name":"role","type":"uint256"},{"indexed":true,"name":"account","type":"address"},{"indexed":true,"name":"sender","type":"address"}]},
+	{"type":"event","name":"ProposalCreated","anonymous":false,"inputs":[{"indexed":true,"name":"proposalID","type":"uint256"},{"indexed":true,"name":"proposer","type":"address"},{"indexed":false,"name":"action","type":"bytes"}]},
+	{"type":"event","name":"ProposalApproved","anonymous":false,"inputs":[{"indexed":true,"name":"proposalID","type":"uint256"},{"indexed":true,"name":"approver","type":"address"}]},
+	{"type":"event","name":"ProposalExecuted","anonymous":false,"inputs":[{"indexed":true,"name":"proposalID","type":"uint256"},{"indexed":true,"name":"executor","type":"address"}]},
+	{"type":"function","name":"setAdmin","stateMutability":"nonpayable","inputs":[{"name":"addr","type":"address"}],"outputs":[]},
+	{"type":"function","name":"setEnabled","stateMutability":"nonpayable","inputs":[{"name":"addr","type":"address"}],"outputs":[]},
+	{"type":"function","name":"setNone","stateMutability":"nonpayable","inputs":[{"name":"addr","type":"address"}],"outputs":[]},
+	{"type":"function","name":"readAllowList","stateMutability":"view","inputs":[{"name":"addr","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"setManager","stateMutability":"nonpayable","inputs":[{"name":"addr","type":"address"}],"outputs":[]},
+	{"type":"function","name":"batchSetAdmin","stateMutability":"nonpayable","inputs":[{"name":"addrs","type":"address[]"}],"outputs":[]},
+	{"type":"function","name":"batchSetEnabled","stateMutability":"nonpayable","inputs":[{"name":"addrs","type":"address[]"}],"outputs":[]},
+	{"type":"function","name":"batchSetNone","stateMutability":"nonpayable","inputs":[{"name":"addrs","type":"address[]"}],"outputs":[]},
+	{"type":"function","name":"getRoleMemberCount","stateMutability":"view","inputs":[{"name":"role","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"getRoleMembers","stateMutability":"view","inputs":[{"name":"role","type":"uint256"},{"name":"offset","type":"uint256"},{"name":"limit","type":"uint256"}],"outputs":[{"name":"","type":"address[]"}]},
+	{"type":"function","name":"propose","stateMutability":"nonpayable","inputs":[{"name":"action","type":"bytes"}],"outputs":[{"name":"proposalID","type":"uint256"}]},
+	{"type":"function","name":"approve","stateMutability":"nonpayable","inputs":[{"name":"proposalID","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"execute","stateMutability":"nonpayable","inputs":[{"name":"proposalID","type":"uint256"}],"outputs":[{"name":"result","type":"bytes"}]},
+	{"type":"function","name":"getProposal","stateMutability":"view","inputs":[{"name":"proposalID","type":"uint256"}],"outputs":[{"name":"action","type":"bytes"},{"name":"approvals","type":"uint256"},{"name":"executed","type":"bool"}]}
+]`
+
 // Enum constants for valid AllowListRole
 type AllowListRole common.Hash
 
@@ -19,41 +43,171 @@ var (
 	AllowListNoRole  AllowListRole = AllowListRole(common.BigToHash(big.NewInt(0))) // No role assigned - this is equivalent to common.Hash{} and deletes the key from the DB when set
 	AllowListEnabled AllowListRole = AllowListRole(common.BigToHash(big.NewInt(1))) // Deployers are allowed to create new contracts
 	AllowListAdmin   AllowListRole = AllowListRole(common.BigToHash(big.NewInt(2))) // Admin - allowed to modify both the admin and deployer list as well as deploy contracts
+	AllowListManager AllowListRole = AllowListRole(common.BigToHash(big.NewInt(3))) // Manager - allowed to add and remove enabled addresses as well as deploy contracts, once the AllowListManager upgrade is activated
 
 	// AllowList function signatures
 	setAdminSignature      = CalculateFunctionSelector("setAdmin(address)")
 	setEnabledSignature    = CalculateFunctionSelector("setEnabled(address)")
 	setNoneSignature       = CalculateFunctionSelector("setNone(address)")
 	readAllowListSignature = CalculateFunctionSelector("readAllowList(address)")
+	setManagerSignature    = CalculateFunctionSelector("setManager(address)")
+
+	// AllowListABI is the parsed ABI of the IAllowList interface implemented by every allow list precompile.
+	AllowListABI = mustParseABI(AllowListRawABI)
+
+	// RoleSetEventID is the first topic of the log emitted when the role of an address is
+	// modified through an allow list. The remaining topics are the new role, the modified
+	// address and the caller that modified it.
+	RoleSetEventID = crypto.Keccak256Hash([]byte("RoleSet(uint256,address,address)"))
+
 	// Error returned when an invalid write is attempted
 	ErrCannotModifyAllowList = errors.New("non-admin cannot modify allow list")
+	// Error returned when the Manager role is assigned before the AllowListManager upgrade is activated
+	ErrManagerRoleNotActivated = errors.New("manager role is not activated")
+	// Error returned when an allow list config specifies the same admin more than once
+	ErrDuplicateAdmin = errors.New("duplicate address in admin list")
+	// Error returned when an allow list config specifies the same enabled address more than once or also as an admin
+	ErrDuplicateEnabledAddress = errors.New("duplicate address in enabled list")
+	// Error returned when the members of a role that cannot be enumerated are requested
+	ErrInvalidAllowListRole = errors.New("invalid allow list role")
+	// Error returned when the members of a role are requested from an allow list whose index misses roles granted
+	// before the PrecompileV2 upgrade
+	ErrAllowListIndexIncomplete = errors.New("allow list index is incomplete")
 
 	allowListInputLen = common.HashLength
+
+	// allowListGasCosts are the default gas costs of the allow list functions keyed by function name.
+	// The gas cost of the batch setters is charged per modified address and the gas cost of
+	// getRoleMembers is charged once plus once per returned member.
+	allowListGasCosts = map[string]uint64{
+		"setAdmin":           ModifyAllowListGasCost,
+		"setEnabled":         ModifyAllowListGasCost,
+		"setNone":            ModifyAllowListGasCost,
+		"setManager":         ModifyAllowListGasCost,
+		"readAllowList":      ReadAllowListGasCost,
+		"batchSetAdmin":      ModifyAllowListGasCost,
+		"batchSetEnabled":    ModifyAllowListGasCost,
+		"batchSetNone":       ModifyAllowListGasCost,
+		"getRoleMemberCount": ReadAllowListGasCost,
+		"getRoleMembers":     ReadAllowListGasCost,
+		"propose":            ProposeGasCost,
+		"approve":            ApproveGasCost,
+		"execute":            ExecuteGasCost,
+		"getProposal":        GetProposalGasCost,
+	}
 )
 
-// AllowListConfig specifies the initial set of allow list admins.
+// withAllowListGasCosts returns the default gas costs of the allow list functions together with [gasCosts]
+// for the gas costs of a precompile that is wired to an allow list.
+func withAllowListGasCosts(gasCosts map[string]uint64) map[string]uint64 {
+	merged := make(map[string]uint64, len(allowListGasCosts)+len(gasCosts))
+	for function, cost := range allowListGasCosts {
+		merged[function] = cost
+	}
+	for function, cost := range gasCosts {
+		merged[function] = cost
+	}
+	return merged
+}
+
+// withAllowListABI returns an ABI that contains the functions and events of the IAllowList interface
+// together with those of [contractABI] for the ABI of a precompile that is wired to an allow list.
+func withAllowListABI(contractABI abi.ABI) abi.ABI {
+	merged := abi.ABI{
+		Methods: make(map[string]abi.Method, len(AllowListABI.Methods)+len(contractABI.Methods)),
+		Events:  make(map[string]abi.Event, len(AllowListABI.Events)+len(contractABI.Events)),
+		Errors:  make(map[string]abi.Error),
+	}
+	for _, parsed := range []abi.ABI{AllowListABI, contractABI} {
+		for name, method := range parsed.Methods {
+			merged.Methods[name] = method
+		}
+		for name, event := range parsed.Events {
+			merged.Events[name] = event
+		}
+		for name, abiErr := range parsed.Errors {
+			merged.Errors[name] = abiErr
+		}
+	}
+	return merged
+}
+
+// AllowListConfig specifies the initial set of allow list admins and enabled addresses and the
+// optional multisig that approves proposals to the precompile.
 type AllowListConfig struct {
-	AllowListAdmins []common.Address `json:"adminAddresses"`
+	AllowListAdmins  []common.Address `json:"adminAddresses"`
+	EnabledAddresses []common.Address `json:"enabledAddresses,omitempty"`
+	Multisig         *MultisigConfig  `json:"multisig,omitempty"`
 }
 
 // Configure initializes the address space of [precompileAddr] by initializing the role of each of
-// the addresses in [AllowListAdmins].
+// the addresses in [AllowListAdmins] and [EnabledAddresses] in the order they are specified.
 func (c *AllowListConfig) Configure(state StateDB, precompileAddr common.Address) {
 	for _, adminAddr := range c.AllowListAdmins {
 		setAllowListRole(state, precompileAddr, adminAddr, AllowListAdmin)
 	}
+	for _, enabledAddr := range c.EnabledAddresses {
+		setAllowListRole(state, precompileAddr, enabledAddr, AllowListEnabled)
+	}
+	if c.Multisig != nil {
+		c.Multisig.Configure(state, precompileAddr)
+	}
+}
+
+// Verify returns an error if [EnabledAddresses] contains duplicate addresses, if an address is specified
+// as both an admin and an enabled address or if [Multisig] is invalid.
+// Note: duplicate admins are checked separately by VerifyAdmins, since they were accepted by existing chains.
+func (c *AllowListConfig) Verify() error {
+	admins := make(map[common.Address]struct{}, len(c.AllowListAdmins))
+	for _, admin := range c.AllowListAdmins {
+		admins[admin] = struct{}{}
+	}
+	enabled := make(map[common.Address]struct{}, len(c.EnabledAddresses))
+	for _, enabledAddr := range c.EnabledAddresses {
+		if _, ok := admins[enabledAddr]; ok {
+			return fmt.Errorf("%w: %s is also an admin", ErrDuplicateEnabledAddress, enabledAddr)
+		}
+		if _, ok := enabled[enabledAddr]; ok {
+			return fmt.Errorf("%w: %s", ErrDuplicateEnabledAddress, enabledAddr)
+		}
+		enabled[enabledAddr] = struct{}{}
+	}
+	if c.Multisig != nil {
+		return c.Multisig.Verify()
+	}
+	return nil
+}
+
+// VerifyAdmins returns an error if [AllowListAdmins] contains duplicate addresses.
+func (c *AllowListConfig) VerifyAdmins() error {
+	admins := make(map[common.Address]struct{}, len(c.AllowListAdmins))
+	for _, admin := range c.AllowListAdmins {
+		if _, ok := admins[admin]; ok {
+			return fmt.Errorf("%w: %s", ErrDuplicateAdmin, admin)
+		}
+		admins[admin] = struct{}{}
+	}
+	return nil
 }
 
-// Equal returns true iff [other] has the same admins in the same order in its allow list.
+// Equal returns true iff [other] has the same admins and enabled addresses in the same order in its allow list.
 func (c *AllowListConfig) Equal(other *AllowListConfig) bool {
 	if other == nil {
 		return false
 	}
-	if len(c.AllowListAdmins) != len(other.AllowListAdmins) {
+	if !addressesEqual(c.AllowListAdmins, other.AllowListAdmins) || !addressesEqual(c.EnabledAddresses, other.EnabledAddresses) {
 		return false
 	}
-	for i, admin := range c.AllowListAdmins {
-		if admin != other.AllowListAdmins[i] {
+	return c.Multisig.Equal(other.Multisig)
+}
+
+// addressesEqual returns true iff [a] and [b] contain the same addresses in the same order.
+func addressesEqual(a, b []common.Address) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, addr := range a {
+		if addr != b[i] {
 			return false
 		}
 	}
@@ -63,13 +217,29 @@ func (c *AllowListConfig) Equal(other *AllowListConfig) bool {
 // Valid returns true iff [s] represents a valid role.
 func (s AllowListRole) Valid() bool {
 	switch s {
-	case AllowListNoRole, AllowListEnabled, AllowListAdmin:
+	case AllowListNoRole, AllowListEnabled, AllowListAdmin, AllowListManager:
 		return true
 	default:
 		return false
 	}
 }
 
+// String returns the name of [s] if it is a valid role and its hex encoding otherwise.
+func (s AllowListRole) String() string {
+	switch s {
+	case AllowListNoRole:
+		return "NoRole"
+	case AllowListEnabled:
+		return "Enabled"
+	case AllowListAdmin:
+		return "Admin"
+	case AllowListManager:
+		return "Manager"
+	default:
+		return common.Hash(s).Hex()
+	}
+}
+
 // IsNoRole returns true if [s] indicates no specific role.
 func (s AllowListRole) IsNoRole() bool {
 	switch s {
@@ -90,16 +260,40 @@ func (s AllowListRole) IsAdmin() bool {
 	}
 }
 
+// IsManager returns true if [s] indicates the permission to add and remove enabled addresses.
+func (s AllowListRole) IsManager() bool {
+	switch s {
+	case AllowListManager:
+		return true
+	default:
+		return false
+	}
+}
+
 // IsEnabled returns true if [s] indicates that it has permission to access the resource.
 func (s AllowListRole) IsEnabled() bool {
 	switch s {
-	case AllowListAdmin, AllowListEnabled:
+	case AllowListAdmin, AllowListManager, AllowListEnabled:
 		return true
 	default:
 		return false
 	}
 }
 
+// CanModify returns true if an address with role [s] is allowed to change the role of an
+// address from [from] to [to]. Admins can assign any role, while managers can only add and
+// remove enabled addresses.
+func (s AllowListRole) CanModify(from, to AllowListRole) bool {
+	switch s {
+	case AllowListAdmin:
+		return true
+	case AllowListManager:
+		return (from.IsNoRole() || from == AllowListEnabled) && (to.IsNoRole() || to == AllowListEnabled)
+	default:
+		return false
+	}
+}
+
 // getAllowListStatus returns the allow list role of [address] for the precompile
 // at [precompileAddr]
 func getAllowListStatus(state StateDB, precompileAddr common.Address, address common.Address) AllowListRole {
@@ -108,12 +302,28 @@ func getAllowListStatus(state StateDB, precompileAddr common.Address, address co
 	return AllowListRole(state.GetState(precompileAddr, addressKey))
 }
 
+// GetAllowListStatus returns the allow list role of [address] for the precompile at [precompileAddr],
+// which may be any precompile that is wired to an allow list.
+func GetAllowListStatus(state StateDB, precompileAddr common.Address, address common.Address) AllowListRole {
+	return getAllowListStatus(state, precompileAddr, address)
+}
+
 // setAllowListRole sets the permissions of [address] to [role] for the precompile
-// at [precompileAddr].
+// at [precompileAddr] and moves [address] from the members of its previous role to
+// the members of [role] if the allow list of the precompile is indexed.
 // assumes [role] has already been verified as valid.
 func setAllowListRole(stateDB StateDB, precompileAddr, address common.Address, role AllowListRole) {
 	// Generate the state key for [address]
 	addressKey := address.Hash()
+	previousRole := AllowListRole(stateDB.GetState(precompileAddr, addressKey))
+	if previousRole != role && isAllowListIndexActivated(stateDB, precompileAddr) {
+		if !previousRole.IsNoRole() {
+			removeAllowListRoleMember(stateDB, precompileAddr, previousRole, address)
+		}
+		if !role.IsNoRole() {
+			addAllowListRoleMember(stateDB, precompileAddr, role, address)
+		}
+	}
 	// Assign [role] to the address
 	stateDB.SetState(precompileAddr, addressKey, common.Hash(role))
 }
@@ -132,6 +342,8 @@ func PackModifyAllowList(address common.Address, role AllowListRole) ([]byte, er
 		input = append(input, setEnabledSignature...)
 	case AllowListNoRole:
 		input = append(input, setNoneSignature...)
+	case AllowListManager:
+		input = append(input, setManagerSignature...)
 	default:
 		return nil, fmt.Errorf("cannot pack modify list input with invalid role: %s", role)
 	}
@@ -148,11 +360,44 @@ func PackReadAllowList(address common.Address) []byte {
 	return input
 }
 
-// createAllowListRoleSetter returns an execution function for setting the allow list status of the input address argument to [role].
+// PackBatchModifyAllowList packs [addresses] into the input for setting the role of each of them to [role].
+func PackBatchModifyAllowList(addresses []common.Address, role AllowListRole) ([]byte, error) {
+	switch role {
+	case AllowListAdmin:
+		return AllowListABI.Pack("batchSetAdmin", addresses)
+	case AllowListEnabled:
+		return AllowListABI.Pack("batchSetEnabled", addresses)
+	case AllowListNoRole:
+		return AllowListABI.Pack("batchSetNone", addresses)
+	default:
+		return nil, fmt.Errorf("cannot pack batch modify list input with invalid role: %s", role)
+	}
+}
+
+// PackGetRoleMemberCount packs [role] into the input data to the getRoleMemberCount function
+func PackGetRoleMemberCount(role AllowListRole) ([]byte, error) {
+	return AllowListABI.Pack("getRoleMemberCount", common.Hash(role).Big())
+}
+
+// PackGetRoleMembers packs [role], [offset] and [limit] into the input data to the getRoleMembers function
+func PackGetRoleMembers(role AllowListRole, offset uint64, limit uint64) ([]byte, error) {
+	return AllowListABI.Pack("getRoleMembers", common.Hash(role).Big(), new(big.Int).SetUint64(offset), new(big.Int).SetUint64(limit))
+}
+
+// UnpackGetRoleMembersOutput attempts to unpack [output] as the members returned by getRoleMembers.
+func UnpackGetRoleMembersOutput(output []byte) ([]common.Address, error) {
+	values, err := AllowListABI.Unpack("getRoleMembers", output)
+	if err != nil {
+		return nil, err
+	}
+	return values[0].([]common.Address), nil
+}
+
+// createAllowListRoleSetter returns an execution function [function] for setting the allow list status of the input address argument to [role].
 // This execution function is speciifc to [precompileAddr].
-func createAllowListRoleSetter(precompileAddr common.Address, role AllowListRole) RunStatefulPrecompileFunc {
+func createAllowListRoleSetter(precompileAddr common.Address, function string, role AllowListRole) RunStatefulPrecompileFunc {
 	return func(evm PrecompileAccessibleState, callerAddr, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
-		if remainingGas, err = deductGas(suppliedGas, ModifyAllowListGasCost); err != nil {
+		if remainingGas, err = deductGas(suppliedGas, getGasCost(evm, precompileAddr, function, upgradedGasCost(evm, ModifyAllowListGasCost, LegacyModifyAllowListGasCost))); err != nil {
 			return nil, 0, err
 		}
 
@@ -166,18 +411,76 @@ func createAllowListRoleSetter(precompileAddr common.Address, role AllowListRole
 			return nil, remainingGas, vmerrs.ErrWriteProtection
 		}
 
-		stateDB := evm.GetStateDB()
+		if err := modifyAllowList(evm, precompileAddr, callerAddr, []common.Address{modifyAddress}, role); err != nil {
+			return nil, remainingGas, err
+		}
+		// Return an empty output and the remaining gas
+		return []byte{}, remainingGas, nil
+	}
+}
+
+// createAllowListBatchRoleSetter returns an execution function [function] for setting the allow list status of each of the
+// addresses in the input array argument to [role]. The gas cost is [ModifyAllowListGasCost] per address, unless overridden,
+// in addition to the cost of unpacking the input.
+// This execution function is specific to [precompileAddr].
+func createAllowListBatchRoleSetter(precompileAddr common.Address, function string, role AllowListRole) RunStatefulPrecompileABIFunc {
+	return func(evm PrecompileAccessibleState, callerAddr, addr common.Address, args []interface{}, suppliedGas uint64, readOnly bool) (outputs []interface{}, remainingGas uint64, err error) {
+		modifyAddresses := args[0].([]common.Address)
+		if remainingGas, err = deductGasPerItem(suppliedGas, uint64(len(modifyAddresses)), getGasCost(evm, precompileAddr, function, ModifyAllowListGasCost)); err != nil {
+			return nil, 0, err
+		}
+
+		if readOnly {
+			return nil, remainingGas, vmerrs.ErrWriteProtection
+		}
+
+		if err := modifyAllowList(evm, precompileAddr, callerAddr, modifyAddresses, role); err != nil {
+			return nil, remainingGas, err
+		}
+		return nil, remainingGas, nil
+	}
+}
+
+// modifyAllowList sets the role of each of [modifyAddresses] to [role] in the allow list of [precompileAddr]
+// and emits a RoleSet log for each of them once the PrecompileV2 upgrade is activated, if [callerAddr] is
+// allowed to modify the role of all of them.
+func modifyAllowList(evm PrecompileAccessibleState, precompileAddr, callerAddr common.Address, modifyAddresses []common.Address, role AllowListRole) error {
+	stateDB := evm.GetStateDB()
+
+	if role.IsManager() && !evm.GetChainConfig().IsAllowListManager(evm.GetBlockContext().Timestamp()) {
+		return ErrManagerRoleNotActivated
+	}
 
-		// Verify that the caller is in the allow list and therefore has the right to modify it
-		callerStatus := getAllowListStatus(stateDB, precompileAddr, callerAddr)
-		if !callerStatus.IsAdmin() {
-			return nil, remainingGas, fmt.Errorf("%w: %s", ErrCannotModifyAllowList, callerAddr)
+	// Verify that the caller is in the allow list and therefore has the right to modify it
+	callerStatus := getAllowListStatus(stateDB, precompileAddr, callerAddr)
+	if !callerStatus.IsAdmin() && !callerStatus.IsManager() {
+		return fmt.Errorf("%w: %s", ErrCannotModifyAllowList, callerAddr)
+	}
+	for _, modifyAddress := range modifyAddresses {
+		if modifyStatus := getAllowListStatus(stateDB, precompileAddr, modifyAddress); !callerStatus.CanModify(modifyStatus, role) {
+			return fmt.Errorf("%w: manager %s cannot change the role of %s from %s to %s", ErrCannotModifyAllowList, callerAddr, modifyAddress, modifyStatus, role)
 		}
+	}
 
+	emitLogs := isPrecompileV2(evm)
+	for _, modifyAddress := range modifyAddresses {
 		setAllowListRole(stateDB, precompileAddr, modifyAddress, role)
-		// Return an empty output and the remaining gas
-		return []byte{}, remainingGas, nil
+		if !emitLogs {
+			continue
+		}
+		stateDB.AddLog(
+			precompileAddr,
+			[]common.Hash{
+				RoleSetEventID,
+				common.Hash(role),
+				modifyAddress.Hash(),
+				callerAddr.Hash(),
+			},
+			nil,
+			evm.GetBlockContext().Number().Uint64(),
+		)
 	}
+	return nil
 }
 
 // createReadAllowList returns an execution function that reads the allow list for the given [precompileAddr].
@@ -185,7 +488,7 @@ func createAllowListRoleSetter(precompileAddr common.Address, role AllowListRole
 // designated role of that address
 func createReadAllowList(precompileAddr common.Address) RunStatefulPrecompileFunc {
 	return func(evm PrecompileAccessibleState, callerAddr common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
-		if remainingGas, err = deductGas(suppliedGas, ReadAllowListGasCost); err != nil {
+		if remainingGas, err = deductGas(suppliedGas, getGasCost(evm, precompileAddr, "readAllowList", ReadAllowListGasCost)); err != nil {
 			return nil, 0, err
 		}
 
@@ -200,6 +503,74 @@ func createReadAllowList(precompileAddr common.Address) RunStatefulPrecompileFun
 	}
 }
 
+// createGetRoleMemberCount returns an execution function that returns the number of addresses with the role
+// in the input argument in the allow list of [precompileAddr]. Fails with [ErrAllowListIndexIncomplete] if the
+// index of the allow list misses roles granted before its activation.
+func createGetRoleMemberCount(precompileAddr common.Address) RunStatefulPrecompileABIFunc {
+	return func(evm PrecompileAccessibleState, callerAddr common.Address, addr common.Address, args []interface{}, suppliedGas uint64, readOnly bool) (outputs []interface{}, remainingGas uint64, err error) {
+		if remainingGas, err = deductGas(suppliedGas, getGasCost(evm, precompileAddr, "getRoleMemberCount", ReadAllowListGasCost)); err != nil {
+			return nil, 0, err
+		}
+
+		role, err := unpackEnumerableRole(args[0].(*big.Int))
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		stateDB := evm.GetStateDB()
+		if !isAllowListIndexComplete(stateDB, precompileAddr) {
+			return nil, remainingGas, ErrAllowListIndexIncomplete
+		}
+		count := getAllowListRoleMemberCount(stateDB, precompileAddr, role)
+		return []interface{}{new(big.Int).SetUint64(count)}, remainingGas, nil
+	}
+}
+
+// createGetRoleMembers returns an execution function that pages through the addresses with the role in the
+// input arguments in the allow list of [precompileAddr]. It returns up to limit members starting from offset
+// and charges [ReadAllowListGasCost], unless overridden, per returned member in addition to the read of the member count.
+// Fails with [ErrAllowListIndexIncomplete] if the index of the allow list misses roles granted before its activation.
+func createGetRoleMembers(precompileAddr common.Address) RunStatefulPrecompileABIFunc {
+	return func(evm PrecompileAccessibleState, callerAddr common.Address, addr common.Address, args []interface{}, suppliedGas uint64, readOnly bool) (outputs []interface{}, remainingGas uint64, err error) {
+		gasCost := getGasCost(evm, precompileAddr, "getRoleMembers", ReadAllowListGasCost)
+		if remainingGas, err = deductGas(suppliedGas, gasCost); err != nil {
+			return nil, 0, err
+		}
+
+		role, err := unpackEnumerableRole(args[0].(*big.Int))
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		stateDB := evm.GetStateDB()
+		if !isAllowListIndexComplete(stateDB, precompileAddr) {
+			return nil, remainingGas, ErrAllowListIndexIncomplete
+		}
+		count := getAllowListRoleMemberCount(stateDB, precompileAddr, role)
+		offset, limit := args[1].(*big.Int), args[2].(*big.Int)
+		if !offset.IsUint64() || offset.Uint64() >= count {
+			return []interface{}{[]common.Address{}}, remainingGas, nil
+		}
+		numMembers := count - offset.Uint64()
+		if limit.IsUint64() && limit.Uint64() < numMembers {
+			numMembers = limit.Uint64()
+		}
+		if remainingGas, err = deductGasPerItem(remainingGas, numMembers, gasCost); err != nil {
+			return nil, 0, err
+		}
+
+		members := getAllowListRoleMembers(stateDB, precompileAddr, role, offset.Uint64(), numMembers)
+		return []interface{}{members}, remainingGas, nil
+	}
+}
+
+// unpackEnumerableRole returns the role represented by [role] if its members can be enumerated.
+func unpackEnumerableRole(role *big.Int) (AllowListRole, error) {
+	allowListRole := AllowListRole(common.BigToHash(role))
+	if !allowListRole.Valid() || allowListRole.IsNoRole() {
+		return AllowListNoRole, fmt.Errorf("%w: %s", ErrInvalidAllowListRole, role)
+	}
+	return allowListRole, nil
+}
+
 // createAllowListPrecompile returns a StatefulPrecompiledContract with R/W control of an allow list at [precompileAddr]
 func createAllowListPrecompile(precompileAddr common.Address) StatefulPrecompiledContract {
 	// Construct the contract with no fallback function.
@@ -209,10 +580,21 @@ func createAllowListPrecompile(precompileAddr common.Address) StatefulPrecompile
 }
 
 func createAllowListFunctions(precompileAddr common.Address) []*statefulPrecompileFunction {
-	setAdmin := newStatefulPrecompileFunction(setAdminSignature, createAllowListRoleSetter(precompileAddr, AllowListAdmin))
-	setEnabled := newStatefulPrecompileFunction(setEnabledSignature, createAllowListRoleSetter(precompileAddr, AllowListEnabled))
-	setNone := newStatefulPrecompileFunction(setNoneSignature, createAllowListRoleSetter(precompileAddr, AllowListNoRole))
+	setAdmin := newStatefulPrecompileFunction(setAdminSignature, createAllowListRoleSetter(precompileAddr, "setAdmin", AllowListAdmin))
+	setEnabled := newStatefulPrecompileFunction(setEnabledSignature, createAllowListRoleSetter(precompileAddr, "setEnabled", AllowListEnabled))
+	setNone := newStatefulPrecompileFunction(setNoneSignature, createAllowListRoleSetter(precompileAddr, "setNone", AllowListNoRole))
 	read := newStatefulPrecompileFunction(readAllowListSignature, createReadAllowList(precompileAddr))
-
-	return []*statefulPrecompileFunction{setAdmin, setEnabled, setNone, read}
+	setManager := newStatefulPrecompileFunction(setManagerSignature, createAllowListRoleSetter(precompileAddr, "setManager", AllowListManager))
+	batchSetAdmin := activatedByPrecompileV2(newStatefulPrecompileABIFunction(AllowListABI.Methods["batchSetAdmin"], createAllowListBatchRoleSetter(precompileAddr, "batchSetAdmin", AllowListAdmin)))
+	batchSetEnabled := activatedByPrecompileV2(newStatefulPrecompileABIFunction(AllowListABI.Methods["batchSetEnabled"], createAllowListBatchRoleSetter(precompileAddr, "batchSetEnabled", AllowListEnabled)))
+	batchSetNone := activatedByPrecompileV2(newStatefulPrecompileABIFunction(AllowListABI.Methods["batchSetNone"], createAllowListBatchRoleSetter(precompileAddr, "batchSetNone", AllowListNoRole)))
+	getRoleMemberCount := activatedByPrecompileV2(newStatefulPrecompileABIFunction(AllowListABI.Methods["getRoleMemberCount"], createGetRoleMemberCount(precompileAddr)))
+	getRoleMembers := activatedByPrecompileV2(newStatefulPrecompileABIFunction(AllowListABI.Methods["getRoleMembers"], createGetRoleMembers(precompileAddr)))
+	propose := activatedByPrecompileV2(newStatefulPrecompileABIFunction(AllowListABI.Methods["propose"], createPropose(precompileAddr)))
+	approve := activatedByPrecompileV2(newStatefulPrecompileABIFunction(AllowListABI.Methods["approve"], createApprove(precompileAddr)))
+	execute := activatedByPrecompileV2(newStatefulPrecompileABIFunction(AllowListABI.Methods["execute"], createExecute(precompileAddr)))
+	getProposal := activatedByPrecompileV2(newStatefulPrecompileABIFunction(AllowListABI.Methods["getProposal"], createGetProposal(precompileAddr)))
+
+	return []*statefulPrecompileFunction{setAdmin, setEnabled, setNone, read, setManager, batchSetAdmin, batchSetEnabled, batchSetNone, getRoleMemberCount, getRoleMembers,
+		propose, approve, execute, getProposal}
 }
@@ -0,0 +1,181 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// The members of each role of an allow list are kept in an enumerable index in the storage of the precompile,
+// next to the role of each address, which is stored at the hash of the address.
+// For each role, the index consists of:
+// - the number of members stored at allowListRoleCountKey(role)
+// - the member at position i in [1, count] stored at allowListRoleMemberKey(role, i)
+// - the position of each member stored at allowListRolePositionKey(role, address), where 0 means not a member
+// Members are removed by moving the last member of the role into the position of the removed member,
+// so the order of the members is not preserved.
+// The index is only maintained once it is activated by ActivateAllowListIndex with the PrecompileV2 upgrade,
+// so that the state of the blocks before the upgrade is unchanged. The activation records whether the index
+// is complete, since roles granted before the activation cannot be enumerated from the state.
+var (
+	allowListRoleCountPrefix    = []byte("allowListRoleCount")
+	allowListRoleMemberPrefix   = []byte("allowListRoleMember")
+	allowListRolePositionPrefix = []byte("allowListRolePosition")
+
+	allowListIndexActivatedKey = crypto.Keccak256Hash([]byte("allowListIndexActivated"))
+
+	// Values stored at allowListIndexActivatedKey once the index is activated
+	allowListIndexComplete   = common.BigToHash(common.Big1)
+	allowListIndexIncomplete = common.BigToHash(common.Big2)
+)
+
+// allowListPrecompileConfig is implemented by the configs of precompiles that are wired to an allow list.
+type allowListPrecompileConfig interface {
+	getAllowListConfig() *AllowListConfig
+}
+
+func (c *AllowListConfig) getAllowListConfig() *AllowListConfig { return c }
+
+// isAllowListIndexActivated returns true if the members of the allow list of [precompileAddr] are indexed.
+func isAllowListIndexActivated(state StateDB, precompileAddr common.Address) bool {
+	return state.GetState(precompileAddr, allowListIndexActivatedKey) != (common.Hash{})
+}
+
+// isAllowListIndexComplete returns true if every address with a role in the allow list of [precompileAddr]
+// is indexed.
+func isAllowListIndexComplete(state StateDB, precompileAddr common.Address) bool {
+	return state.GetState(precompileAddr, allowListIndexActivatedKey) == allowListIndexComplete
+}
+
+// ActivateAllowListIndex starts indexing the members of the allow list of [precompileAddr] if it is not indexed yet.
+// The addresses listed by the allow list configs in [configs], which should be the configs of the precompile activated
+// so far, and [precompileAddr] itself are indexed under their current role.
+// The index is complete only if the precompile is enabled with an empty storage in the block from [parentTimestamp]
+// to [blockTimestamp], so that all of its roles were granted by [configs]. Otherwise, roles granted on-chain before the
+// activation cannot be enumerated and the index is marked as incomplete until the storage of the precompile is wiped.
+// Does nothing if none of [configs] wire the precompile to an allow list.
+func ActivateAllowListIndex(state StateDB, precompileAddr common.Address, configs []StatefulPrecompileConfig, parentTimestamp *big.Int, blockTimestamp *big.Int) {
+	if isAllowListIndexActivated(state, precompileAddr) {
+		return
+	}
+	var (
+		hasAllowList bool
+		candidates   = []common.Address{precompileAddr}
+	)
+	for _, config := range configs {
+		allowListConfig, ok := config.(allowListPrecompileConfig)
+		if !ok {
+			continue
+		}
+		hasAllowList = true
+		candidates = append(candidates, allowListConfig.getAllowListConfig().AllowListAdmins...)
+		candidates = append(candidates, allowListConfig.getAllowListConfig().EnabledAddresses...)
+	}
+	if !hasAllowList {
+		return
+	}
+	if isEnabledWithEmptyStorage(configs, parentTimestamp, blockTimestamp) {
+		state.SetState(precompileAddr, allowListIndexActivatedKey, allowListIndexComplete)
+	} else {
+		state.SetState(precompileAddr, allowListIndexActivatedKey, allowListIndexIncomplete)
+	}
+	for _, address := range candidates {
+		role := getAllowListStatus(state, precompileAddr, address)
+		if role.IsNoRole() || state.GetState(precompileAddr, allowListRolePositionKey(role, address)) != (common.Hash{}) {
+			continue
+		}
+		addAllowListRoleMember(state, precompileAddr, role, address)
+	}
+}
+
+// isEnabledWithEmptyStorage returns true if the last config enabling the precompile in [configs] activates in the
+// block from [parentTimestamp] to [blockTimestamp] and starts from an empty storage, either because it wipes the
+// storage or because the precompile was never enabled before or its storage was wiped when it was last disabled.
+func isEnabledWithEmptyStorage(configs []StatefulPrecompileConfig, parentTimestamp *big.Int, blockTimestamp *big.Int) bool {
+	last := -1
+	for i, config := range configs {
+		if !config.IsDisabled() && !config.IsReconfigure() {
+			last = i
+		}
+	}
+	if last < 0 || !utils.IsForkTransition(configs[last].Timestamp(), parentTimestamp, blockTimestamp) {
+		return false
+	}
+	if configs[last].GetStorageMode() == StorageModeWipe || last == 0 {
+		return true
+	}
+	previous := configs[last-1]
+	return previous.IsDisabled() && EffectiveStorageMode(previous) == StorageModeWipe
+}
+
+// allowListRoleCountKey returns the storage key of the number of members of [role].
+func allowListRoleCountKey(role AllowListRole) common.Hash {
+	return crypto.Keccak256Hash(allowListRoleCountPrefix, role[:])
+}
+
+// allowListRoleMemberKey returns the storage key of the member of [role] at [position].
+func allowListRoleMemberKey(role AllowListRole, position uint64) common.Hash {
+	return crypto.Keccak256Hash(allowListRoleMemberPrefix, role[:], common.BigToHash(new(big.Int).SetUint64(position)).Bytes())
+}
+
+// allowListRolePositionKey returns the storage key of the position of [address] in the members of [role].
+func allowListRolePositionKey(role AllowListRole, address common.Address) common.Hash {
+	return crypto.Keccak256Hash(allowListRolePositionPrefix, role[:], address.Bytes())
+}
+
+// getAllowListRoleMemberCount returns the number of addresses with [role] in the allow list of the precompile
+// at [precompileAddr].
+func getAllowListRoleMemberCount(state StateDB, precompileAddr common.Address, role AllowListRole) uint64 {
+	return state.GetState(precompileAddr, allowListRoleCountKey(role)).Big().Uint64()
+}
+
+// getAllowListRoleMembers returns up to [limit] addresses with [role] in the allow list of the precompile at
+// [precompileAddr], starting from the member at [offset].
+func getAllowListRoleMembers(state StateDB, precompileAddr common.Address, role AllowListRole, offset uint64, limit uint64) []common.Address {
+	count := getAllowListRoleMemberCount(state, precompileAddr, role)
+	if offset >= count {
+		return []common.Address{}
+	}
+	if limit > count-offset {
+		limit = count - offset
+	}
+	members := make([]common.Address, 0, limit)
+	for position := offset + 1; position <= offset+limit; position++ {
+		members = append(members, common.BytesToAddress(state.GetState(precompileAddr, allowListRoleMemberKey(role, position)).Bytes()))
+	}
+	return members
+}
+
+// addAllowListRoleMember appends [address] to the members of [role].
+// Assumes [address] is not a member of [role] yet.
+func addAllowListRoleMember(state StateDB, precompileAddr common.Address, role AllowListRole, address common.Address) {
+	position := getAllowListRoleMemberCount(state, precompileAddr, role) + 1
+	positionHash := common.BigToHash(new(big.Int).SetUint64(position))
+	state.SetState(precompileAddr, allowListRoleMemberKey(role, position), address.Hash())
+	state.SetState(precompileAddr, allowListRolePositionKey(role, address), positionHash)
+	state.SetState(precompileAddr, allowListRoleCountKey(role), positionHash)
+}
+
+// removeAllowListRoleMember removes [address] from the members of [role] by moving the last member
+// of [role] into its position. Does nothing if [address] is not a member of [role].
+func removeAllowListRoleMember(state StateDB, precompileAddr common.Address, role AllowListRole, address common.Address) {
+	positionKey := allowListRolePositionKey(role, address)
+	position := state.GetState(precompileAddr, positionKey).Big().Uint64()
+	if position == 0 {
+		return
+	}
+	count := getAllowListRoleMemberCount(state, precompileAddr, role)
+	if position != count {
+		lastMember := state.GetState(precompileAddr, allowListRoleMemberKey(role, count))
+		state.SetState(precompileAddr, allowListRoleMemberKey(role, position), lastMember)
+		state.SetState(precompileAddr, allowListRolePositionKey(role, common.BytesToAddress(lastMember.Bytes())), common.BigToHash(new(big.Int).SetUint64(position)))
+	}
+	state.SetState(precompileAddr, allowListRoleMemberKey(role, count), common.Hash{})
+	state.SetState(precompileAddr, positionKey, common.Hash{})
+	state.SetState(precompileAddr, allowListRoleCountKey(role), common.BigToHash(new(big.Int).SetUint64(count-1)))
+}
@@ -0,0 +1,175 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockStorageStateDB implements the storage of StateDB in memory.
+type mockStorageStateDB struct {
+	StateDB
+	storage map[common.Address]map[common.Hash]common.Hash
+}
+
+func newMockStorageStateDB() *mockStorageStateDB {
+	return &mockStorageStateDB{storage: make(map[common.Address]map[common.Hash]common.Hash)}
+}
+
+func (m *mockStorageStateDB) GetState(addr common.Address, key common.Hash) common.Hash {
+	return m.storage[addr][key]
+}
+
+func (m *mockStorageStateDB) SetState(addr common.Address, key common.Hash, value common.Hash) {
+	if _, ok := m.storage[addr]; !ok {
+		m.storage[addr] = make(map[common.Hash]common.Hash)
+	}
+	if value == (common.Hash{}) {
+		delete(m.storage[addr], key)
+		return
+	}
+	m.storage[addr][key] = value
+}
+
+func TestAllowListRoleIndex(t *testing.T) {
+	var (
+		state          = newMockStorageStateDB()
+		precompileAddr = ContractDeployerAllowListAddress
+		addrA          = common.Address{0xa}
+		addrB          = common.Address{0xb}
+		addrC          = common.Address{0xc}
+	)
+	ActivateAllowListIndex(state, precompileAddr, []StatefulPrecompileConfig{NewContractDeployerAllowListConfig(common.Big0, nil)}, nil, common.Big0)
+	for _, addr := range []common.Address{addrA, addrB, addrC} {
+		setAllowListRole(state, precompileAddr, addr, AllowListEnabled)
+	}
+	// setting the same role again does not duplicate the member
+	setAllowListRole(state, precompileAddr, addrA, AllowListEnabled)
+	assert.Equal(t, uint64(3), getAllowListRoleMemberCount(state, precompileAddr, AllowListEnabled))
+	assert.Equal(t, []common.Address{addrA, addrB, addrC}, getAllowListRoleMembers(state, precompileAddr, AllowListEnabled, 0, 10))
+
+	// removing a member moves the last member into its position
+	setAllowListRole(state, precompileAddr, addrA, AllowListAdmin)
+	assert.Equal(t, []common.Address{addrC, addrB}, getAllowListRoleMembers(state, precompileAddr, AllowListEnabled, 0, 10))
+	assert.Equal(t, []common.Address{addrA}, getAllowListRoleMembers(state, precompileAddr, AllowListAdmin, 0, 10))
+
+	// paging through the members
+	assert.Equal(t, []common.Address{addrC}, getAllowListRoleMembers(state, precompileAddr, AllowListEnabled, 0, 1))
+	assert.Equal(t, []common.Address{addrB}, getAllowListRoleMembers(state, precompileAddr, AllowListEnabled, 1, 10))
+	assert.Equal(t, []common.Address{}, getAllowListRoleMembers(state, precompileAddr, AllowListEnabled, 2, 10))
+
+	// removing the last member of each role clears the index
+	setAllowListRole(state, precompileAddr, addrB, AllowListNoRole)
+	setAllowListRole(state, precompileAddr, addrC, AllowListNoRole)
+	setAllowListRole(state, precompileAddr, addrA, AllowListNoRole)
+	assert.Equal(t, uint64(0), getAllowListRoleMemberCount(state, precompileAddr, AllowListEnabled))
+	assert.Equal(t, uint64(0), getAllowListRoleMemberCount(state, precompileAddr, AllowListAdmin))
+	assert.Equal(t, map[common.Hash]common.Hash{allowListIndexActivatedKey: allowListIndexComplete}, state.storage[precompileAddr])
+}
+
+func TestActivateAllowListIndex(t *testing.T) {
+	var (
+		state          = newMockStorageStateDB()
+		precompileAddr = ContractDeployerAllowListAddress
+		admin          = common.Address{0xa}
+		enabled        = common.Address{0xb}
+		removed        = common.Address{0xc}
+		granted        = common.Address{0xd}
+	)
+	configs := []StatefulPrecompileConfig{
+		&ContractDeployerAllowListConfig{AllowListConfig: AllowListConfig{AllowListAdmins: []common.Address{admin}, EnabledAddresses: []common.Address{enabled, removed}}},
+		&ContractDeployerAllowListConfig{AllowListConfig: AllowListConfig{EnabledAddresses: []common.Address{enabled}}},
+	}
+	for _, config := range configs {
+		config.Configure(nil, state, nil)
+	}
+	setAllowListRole(state, precompileAddr, removed, AllowListNoRole)
+	setAllowListRole(state, precompileAddr, granted, AllowListEnabled)
+	// roles are not indexed before the activation
+	assert.Equal(t, uint64(0), getAllowListRoleMemberCount(state, precompileAddr, AllowListEnabled))
+
+	ActivateAllowListIndex(state, precompileAddr, configs, common.Big1, common.Big2)
+	assert.False(t, isAllowListIndexComplete(state, precompileAddr))
+	assert.Equal(t, []common.Address{admin}, getAllowListRoleMembers(state, precompileAddr, AllowListAdmin, 0, 10))
+	assert.Equal(t, []common.Address{enabled}, getAllowListRoleMembers(state, precompileAddr, AllowListEnabled, 0, 10))
+
+	// roles granted before the activation are indexed once they are set again
+	setAllowListRole(state, precompileAddr, granted, AllowListAdmin)
+	assert.Equal(t, []common.Address{admin, granted}, getAllowListRoleMembers(state, precompileAddr, AllowListAdmin, 0, 10))
+
+	// the activation is only applied once
+	setAllowListRole(state, precompileAddr, enabled, AllowListNoRole)
+	ActivateAllowListIndex(state, precompileAddr, configs, common.Big1, common.Big2)
+	assert.Equal(t, uint64(0), getAllowListRoleMemberCount(state, precompileAddr, AllowListEnabled))
+
+	// the index is not activated for precompiles without an allow list
+	ActivateAllowListIndex(state, WarpMessengerAddress, []StatefulPrecompileConfig{&WarpMessengerConfig{}}, common.Big1, common.Big2)
+	assert.Empty(t, state.storage[WarpMessengerAddress])
+}
+
+func TestAllowListIndexComplete(t *testing.T) {
+	enable := func(timestamp int64, storageMode StorageMode) StatefulPrecompileConfig {
+		config := NewContractDeployerAllowListConfig(big.NewInt(timestamp), nil)
+		config.StorageMode = storageMode
+		return config
+	}
+	disable := func(timestamp int64, storageMode StorageMode) StatefulPrecompileConfig {
+		config := NewDisableContractDeployerAllowListConfig(big.NewInt(timestamp))
+		config.StorageMode = storageMode
+		return config
+	}
+	reconfigure := &ContractDeployerAllowListConfig{UpgradeableConfig: UpgradeableConfig{BlockTimestamp: big.NewInt(3), Reconfiguration: true}}
+
+	for name, test := range map[string]struct {
+		configs         []StatefulPrecompileConfig
+		parentTimestamp *big.Int
+		expectComplete  bool
+	}{
+		"enabled at genesis": {
+			configs:        []StatefulPrecompileConfig{enable(0, "")},
+			expectComplete: true,
+		},
+		"enabled in the block": {
+			configs:         []StatefulPrecompileConfig{enable(10, "")},
+			parentTimestamp: big.NewInt(9),
+			expectComplete:  true,
+		},
+		"enabled before the block": {
+			configs:         []StatefulPrecompileConfig{enable(1, "")},
+			parentTimestamp: big.NewInt(9),
+			expectComplete:  false,
+		},
+		"reconfigured in the block": {
+			configs:         []StatefulPrecompileConfig{enable(1, ""), reconfigure},
+			parentTimestamp: big.NewInt(2),
+			expectComplete:  false,
+		},
+		"re-enabled after a wiping disable": {
+			configs:         []StatefulPrecompileConfig{enable(1, ""), disable(2, ""), enable(10, "")},
+			parentTimestamp: big.NewInt(9),
+			expectComplete:  true,
+		},
+		"re-enabled after a preserving disable": {
+			configs:         []StatefulPrecompileConfig{enable(1, ""), disable(2, StorageModePreserve), enable(10, "")},
+			parentTimestamp: big.NewInt(9),
+			expectComplete:  false,
+		},
+		"re-enabled with wipe after a preserving disable": {
+			configs:         []StatefulPrecompileConfig{enable(1, ""), disable(2, StorageModePreserve), enable(10, StorageModeWipe)},
+			parentTimestamp: big.NewInt(9),
+			expectComplete:  true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			state := newMockStorageStateDB()
+			ActivateAllowListIndex(state, ContractDeployerAllowListAddress, test.configs, test.parentTimestamp, big.NewInt(10))
+			assert.True(t, isAllowListIndexActivated(state, ContractDeployerAllowListAddress))
+			assert.Equal(t, test.expectComplete, isAllowListIndexComplete(state, ContractDeployerAllowListAddress))
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowListConfigVerify(t *testing.T) {
+	tests := map[string]struct {
+		config      AllowListConfig
+		expectedErr error
+	}{
+		"admins and enabled addresses": {
+			config: AllowListConfig{AllowListAdmins: []common.Address{{1}}, EnabledAddresses: []common.Address{{2}, {3}}},
+		},
+		"duplicate enabled address": {
+			config:      AllowListConfig{EnabledAddresses: []common.Address{{2}, {2}}},
+			expectedErr: ErrDuplicateEnabledAddress,
+		},
+		"enabled address is an admin": {
+			config:      AllowListConfig{AllowListAdmins: []common.Address{{1}}, EnabledAddresses: []common.Address{{1}}},
+			expectedErr: ErrDuplicateEnabledAddress,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.config.Verify()
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAllowListConfigVerifyAdmins(t *testing.T) {
+	config := AllowListConfig{AllowListAdmins: []common.Address{{1}, {2}}}
+	assert.NoError(t, config.VerifyAdmins())
+
+	// duplicate admins are only rejected by VerifyAdmins
+	config.AllowListAdmins = append(config.AllowListAdmins, common.Address{1})
+	assert.NoError(t, config.Verify())
+	assert.ErrorIs(t, config.VerifyAdmins(), ErrDuplicateAdmin)
+}
+
+func TestAllowListConfigure(t *testing.T) {
+	var (
+		state          = newMockStorageStateDB()
+		precompileAddr = TxAllowListAddress
+		config         = &AllowListConfig{AllowListAdmins: []common.Address{{1}}, EnabledAddresses: []common.Address{{2}, {3}}}
+	)
+	config.Configure(state, precompileAddr)
+	assert.Equal(t, AllowListAdmin, getAllowListStatus(state, precompileAddr, common.Address{1}))
+	assert.Equal(t, AllowListEnabled, getAllowListStatus(state, precompileAddr, common.Address{2}))
+	assert.Equal(t, AllowListEnabled, getAllowListStatus(state, precompileAddr, common.Address{3}))
+	// the members are indexed once the index is activated
+	assert.Equal(t, uint64(0), getAllowListRoleMemberCount(state, precompileAddr, AllowListEnabled))
+	ActivateAllowListIndex(state, precompileAddr, []StatefulPrecompileConfig{&TxAllowListConfig{AllowListConfig: *config}}, nil, common.Big0)
+	assert.Equal(t, []common.Address{{2}, {3}}, getAllowListRoleMembers(state, precompileAddr, AllowListEnabled, 0, 10))
+
+	assert.True(t, config.Equal(&AllowListConfig{AllowListAdmins: []common.Address{{1}}, EnabledAddresses: []common.Address{{2}, {3}}}))
+	assert.False(t, config.Equal(&AllowListConfig{AllowListAdmins: []common.Address{{1}}, EnabledAddresses: []common.Address{{3}, {2}}}))
+	assert.False(t, config.Equal(&AllowListConfig{AllowListAdmins: []common.Address{{1}}}))
+}
@@ -21,6 +21,8 @@ type RunStatefulPrecompileFunc func(accessibleState PrecompileAccessibleState, c
 type PrecompileAccessibleState interface {
 	GetStateDB() StateDB
 	GetBlockContext() BlockContext
+	GetChainID() *big.Int
+	GetChainConfig() ChainConfig
 }
 
 // BlockContext defines an interface that provides information to a stateful precompile
@@ -37,6 +39,15 @@ type BlockContext interface {
 type ChainConfig interface {
 	// GetFeeConfig returns the original FeeConfig that was set in the genesis.
 	GetFeeConfig() commontype.FeeConfig
+	// AllowedFeeRecipients returns true if fee recipients are allowed in the genesis.
+	AllowedFeeRecipients() bool
+	// IsAllowListManager returns true if the Manager role of allow lists is activated at [blockTimestamp].
+	IsAllowListManager(blockTimestamp *big.Int) bool
+	// IsPrecompileV2 returns true if the PrecompileV2 upgrade of stateful precompiles is activated at [blockTimestamp].
+	IsPrecompileV2(blockTimestamp *big.Int) bool
+	// GetActivePrecompileConfig returns the latest config of the precompile at [address] activated at
+	// [blockTimestamp] or nil if it was never enabled.
+	GetActivePrecompileConfig(address common.Address, blockTimestamp *big.Int) StatefulPrecompileConfig
 }
 
 // StateDB is the interface for accessing EVM state
@@ -57,6 +68,12 @@ type StateDB interface {
 	Exist(common.Address) bool
 
 	Suicide(common.Address) bool
+
+	AddLog(addr common.Address, topics []common.Hash, data []byte, blockNumber uint64)
+
+	// GetPredicateStorageSlots returns the storage keys of the access list tuple for [address]
+	// in the current transaction concatenated into a single byte slice, and whether such a tuple exists.
+	GetPredicateStorageSlots(address common.Address) ([]byte, bool)
 }
 
 // StatefulPrecompiledContract is the interface for executing a precompiled contract
@@ -72,6 +89,10 @@ type statefulPrecompileFunction struct {
 	selector []byte
 	// execute is performed when this function is selected
 	execute RunStatefulPrecompileFunc
+	// activated returns whether the function can be called at the block of [accessibleState], or is nil if the
+	// function can always be called. Before it is activated, the function is treated as if its selector is
+	// invalid, so that functions added by a network upgrade do not change the execution of earlier blocks.
+	activated func(accessibleState PrecompileAccessibleState) bool
 }
 
 // newStatefulPrecompileFunction creates a stateful precompile function with the given arguments
@@ -82,6 +103,12 @@ func newStatefulPrecompileFunction(selector []byte, execute RunStatefulPrecompil
 	}
 }
 
+// activatedByPrecompileV2 restricts [function] to the blocks where the PrecompileV2 upgrade is activated.
+func activatedByPrecompileV2(function *statefulPrecompileFunction) *statefulPrecompileFunction {
+	function.activated = isPrecompileV2
+	return function
+}
+
 // statefulPrecompileWithFunctionSelectors implements StatefulPrecompiledContract by using 4 byte function selectors to pass
 // off responsibilities to internal execution functions.
 // Note: because we only ever read from [functions] there no lock is required to make it thread-safe.
@@ -131,7 +158,7 @@ func (s *statefulPrecompileWithFunctionSelectors) Run(accessibleState Precompile
 	selector := input[:selectorLen]
 	functionInput := input[selectorLen:]
 	function, ok := s.functions[string(selector)]
-	if !ok {
+	if !ok || (function.activated != nil && !function.activated(accessibleState)) {
 		return nil, suppliedGas, fmt.Errorf("invalid function selector %#x", selector)
 	}
 
@@ -15,6 +15,19 @@ var (
 	ContractDeployerAllowListPrecompile StatefulPrecompiledContract = createAllowListPrecompile(ContractDeployerAllowListAddress)
 )
 
+// ContractDeployerAllowListConfigKey is the key used in json config files to specify the ContractDeployerAllowListConfig.
+const ContractDeployerAllowListConfigKey = "contractDeployerAllowListConfig"
+
+func init() {
+	mustRegisterModule(Module{
+		ConfigKey: ContractDeployerAllowListConfigKey,
+		Address:   ContractDeployerAllowListAddress,
+		NewConfig: func() StatefulPrecompileConfig { return &ContractDeployerAllowListConfig{} },
+		GasCosts:  withAllowListGasCosts(nil),
+		ABI:       AllowListABI,
+	})
+}
+
 // ContractDeployerAllowListConfig wraps [AllowListConfig] and uses it to implement the StatefulPrecompileConfig
 // interface while adding in the contract deployer specific precompile address.
 type ContractDeployerAllowListConfig struct {
@@ -52,6 +65,11 @@ func (c *ContractDeployerAllowListConfig) Configure(_ ChainConfig, state StateDB
 	c.AllowListConfig.Configure(state, ContractDeployerAllowListAddress)
 }
 
+// Reconfigure adds the admins and enabled addresses of [c] to the existing allow list.
+func (c *ContractDeployerAllowListConfig) Reconfigure(_ ChainConfig, state StateDB, _ BlockContext) {
+	c.AllowListConfig.Configure(state, ContractDeployerAllowListAddress)
+}
+
 // Contract returns the singleton stateful precompiled contract to be used for the allow list.
 func (c *ContractDeployerAllowListConfig) Contract() StatefulPrecompiledContract {
 	return ContractDeployerAllowListPrecompile
@@ -4,12 +4,17 @@
 package precompile
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 
 	"github.com/ava-labs/subnet-evm/vmerrs"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
 )
 
 const (
@@ -17,6 +22,14 @@ const (
 	mintInputAmountSlot
 
 	mintInputLen = common.HashLength + common.HashLength
+
+	// ContractNativeMinterRawABI is the ABI of the INativeMinter interface excluding the allow list functions
+	ContractNativeMinterRawABI = `[
+	{"type":"event","name":"NativeCoinMinted","anonymous":false,"inputs":[{"indexed":true,"name":"sender","type":"address"},{"indexed":true,"name":"recipient","type":"address"},{"indexed":false,"name":"amount","type":"uint256"}]},
+	{"type":"function","name":"mintNativeCoin","stateMutability":"nonpayable","inputs":[{"name":"addr","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"totalMinted","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"remainingAllowance","stateMutability":"view","inputs":[{"name":"addr","type":"address"}],"outputs":[{"name":"","type":"uint256"}]}
+]`
 )
 
 var (
@@ -24,15 +37,69 @@ var (
 	// Singleton StatefulPrecompiledContract for minting native assets by permissioned callers.
 	ContractNativeMinterPrecompile StatefulPrecompiledContract = createNativeMinterPrecompile(ContractNativeMinterAddress)
 
+	ContractNativeMinterABI = mustParseABI(ContractNativeMinterRawABI)
+
 	mintSignature = CalculateFunctionSelector("mintNativeCoin(address,uint256)") // address, amount
 	ErrCannotMint = errors.New("non-enabled cannot mint")
+
+	ErrMintExceedsMaxSupply = errors.New("mint exceeds max supply")
+	ErrMintExceedsAllowance = errors.New("mint exceeds minter allowance")
+	ErrInvalidMaxSupply     = errors.New("invalid max supply")
+	ErrInvalidMintAllowance = errors.New("invalid minter allowance")
+	ErrInvalidInitialMint   = errors.New("invalid initial mint")
+
+	// Storage keys of the minting limits of the native minter. The allowance of each minter is stored at the hash
+	// of minterAllowancePrefix and its address, next to a flag stored at the hash of minterAllowanceSetPrefix and
+	// its address that marks its minting as limited by the allowance.
+	// The minters with an allowance are listed at the hash of minterAllowanceListPrefix and their index, and the
+	// length of the list is stored at minterAllowanceCountKey, so that their allowances can be cleared when the
+	// native minter is configured again.
+	maxSupplyKey              = crypto.Keccak256Hash([]byte("nativeMinterMaxSupply"))
+	totalMintedKey            = crypto.Keccak256Hash([]byte("nativeMinterTotalMinted"))
+	minterAllowanceCountKey   = crypto.Keccak256Hash([]byte("nativeMinterAllowanceCount"))
+	minterAllowancePrefix     = []byte("nativeMinterAllowance")
+	minterAllowanceSetPrefix  = []byte("nativeMinterAllowanceSet")
+	minterAllowanceListPrefix = []byte("nativeMinterAllowanceList")
+
+	// NativeCoinMintedEventID is the first topic of the log emitted by mintNativeCoin.
+	// The remaining topics are the caller and the recipient of the minted coins and
+	// the data of the log is the minted amount.
+	NativeCoinMintedEventID = crypto.Keccak256Hash([]byte("NativeCoinMinted(address,address,uint256)"))
 )
 
+// ContractNativeMinterConfigKey is the key used in json config files to specify the ContractNativeMinterConfig.
+const ContractNativeMinterConfigKey = "contractNativeMinterConfig"
+
+func init() {
+	mustRegisterModule(Module{
+		ConfigKey: ContractNativeMinterConfigKey,
+		Address:   ContractNativeMinterAddress,
+		NewConfig: func() StatefulPrecompileConfig { return &ContractNativeMinterConfig{} },
+		GasCosts: withAllowListGasCosts(map[string]uint64{
+			"mintNativeCoin":     MintGasCost,
+			"totalMinted":        TotalMintedGasCost,
+			"remainingAllowance": RemainingAllowanceGasCost,
+		}),
+		ABI: withAllowListABI(ContractNativeMinterABI),
+	})
+}
+
 // ContractNativeMinterConfig wraps [AllowListConfig] and uses it to implement the StatefulPrecompileConfig
-// interface while adding in the ContractNativeMinter specific precompile address.
+// interface while adding in the ContractNativeMinter specific precompile address and minting limits.
 type ContractNativeMinterConfig struct {
 	AllowListConfig
 	UpgradeableConfig
+	// MaxSupply is the maximum amount of native coins that can be minted in total through the precompile
+	// since its storage was last wiped. Minting is not capped if MaxSupply is nil.
+	// Note: the minting limits are only enforced once the PrecompileV2 upgrade is activated.
+	MaxSupply *math.HexOrDecimal256 `json:"maxSupply,omitempty"`
+	// MinterAllowances limits the amount of native coins that each of its addresses can mint in total.
+	// Minters without an allowance are only limited by [MaxSupply].
+	MinterAllowances map[common.Address]*math.HexOrDecimal256 `json:"minterAllowances,omitempty"`
+	// InitialMint is the amount of native coins minted to each of its addresses when the precompile is activated.
+	// The initial mint counts towards [MaxSupply] but not towards the allowance of any minter.
+	// InitialMint cannot be specified when reconfiguring the native minter.
+	InitialMint map[common.Address]*math.HexOrDecimal256 `json:"initialMint,omitempty"`
 }
 
 // NewContractNativeMinterConfig returns a config for a network upgrade at [blockTimestamp] that enables
@@ -60,9 +127,116 @@ func (c *ContractNativeMinterConfig) Address() common.Address {
 	return ContractNativeMinterAddress
 }
 
-// Configure configures [state] with the desired admins based on [c].
+// Configure configures [state] with the desired admins and minting limits based on [c] and mints
+// the [InitialMint] of each address in ascending order of the addresses.
+// Note: the total amount minted is only kept if the native minter is enabled again on the storage
+// preserved when it was disabled. Otherwise, the storage is wiped and the total amount minted starts
+// from zero, so [MaxSupply] only applies to the coins minted since the native minter was enabled.
+// Upgrades that specify both [InitialMint] and [MaxSupply] on preserved storage are rejected by the
+// chain config, so the initial mint can only be skipped if the total amount minted would overflow,
+// in which case a warning is logged.
 func (c *ContractNativeMinterConfig) Configure(_ ChainConfig, state StateDB, _ BlockContext) {
+	c.configureLimits(state)
+
+	recipients := make([]common.Address, 0, len(c.InitialMint))
+	for recipient := range c.InitialMint {
+		recipients = append(recipients, recipient)
+	}
+	sort.Slice(recipients, func(i, j int) bool {
+		return bytes.Compare(recipients[i][:], recipients[j][:]) < 0
+	})
+	if len(recipients) == 0 {
+		return
+	}
+	totalMinted := GetNativeMinterTotalMinted(state)
+	for _, recipient := range recipients {
+		totalMinted.Add(totalMinted, (*big.Int)(c.InitialMint[recipient]))
+	}
+	if totalMinted.Cmp(math.MaxBig256) > 0 || (c.MaxSupply != nil && totalMinted.Cmp((*big.Int)(c.MaxSupply)) > 0) {
+		log.Warn("skipping the initial mint of the native minter exceeding its max supply", "timestamp", c.Timestamp(), "totalMinted", totalMinted)
+		return
+	}
+	for _, recipient := range recipients {
+		if !state.Exist(recipient) {
+			state.CreateAccount(recipient)
+		}
+		state.AddBalance(recipient, (*big.Int)(c.InitialMint[recipient]))
+	}
+	state.SetState(ContractNativeMinterAddress, totalMintedKey, common.BigToHash(totalMinted))
+}
+
+// Reconfigure adds the admins and enabled addresses of [c] to the existing allow list and replaces the max supply
+// and the minter allowances with those of [c]. The total amount already minted is kept and nothing is minted.
+func (c *ContractNativeMinterConfig) Reconfigure(_ ChainConfig, state StateDB, _ BlockContext) {
+	c.configureLimits(state)
+}
+
+// configureLimits adds the admins and enabled addresses of [c] to the allow list of the native minter and
+// replaces the max supply and the minter allowances in [state] with those of [c]. The allowances of the
+// minters that are not in [c.MinterAllowances] are cleared.
+func (c *ContractNativeMinterConfig) configureLimits(state StateDB) {
 	c.AllowListConfig.Configure(state, ContractNativeMinterAddress)
+
+	maxSupply := common.Hash{}
+	if c.MaxSupply != nil {
+		maxSupply = common.BigToHash((*big.Int)(c.MaxSupply))
+	}
+	state.SetState(ContractNativeMinterAddress, maxSupplyKey, maxSupply)
+
+	count := state.GetState(ContractNativeMinterAddress, minterAllowanceCountKey).Big().Uint64()
+	for i := uint64(0); i < count; i++ {
+		listKey := minterAllowanceListKey(i)
+		clearMinterAllowance(state, common.BytesToAddress(state.GetState(ContractNativeMinterAddress, listKey).Bytes()))
+		state.SetState(ContractNativeMinterAddress, listKey, common.Hash{})
+	}
+	minters := make([]common.Address, 0, len(c.MinterAllowances))
+	for minter := range c.MinterAllowances {
+		minters = append(minters, minter)
+	}
+	sort.Slice(minters, func(i, j int) bool {
+		return bytes.Compare(minters[i][:], minters[j][:]) < 0
+	})
+	for i, minter := range minters {
+		setMinterAllowance(state, minter, (*big.Int)(c.MinterAllowances[minter]))
+		state.SetState(ContractNativeMinterAddress, minterAllowanceListKey(uint64(i)), minter.Hash())
+	}
+	if count > 0 || len(minters) > 0 {
+		state.SetState(ContractNativeMinterAddress, minterAllowanceCountKey, common.BigToHash(new(big.Int).SetUint64(uint64(len(minters)))))
+	}
+}
+
+// Verify returns an error if [c] has an invalid allow list, a max supply, an allowance or an initial mint
+// out of range, or if the initial mint exceeds the max supply or is specified by a reconfiguration.
+// The range of the max supply and of the initial mint is (0, 2^256) and the range of an allowance is [0, 2^256).
+func (c *ContractNativeMinterConfig) Verify() error {
+	if err := c.AllowListConfig.Verify(); err != nil {
+		return err
+	}
+	if c.MaxSupply != nil && ((*big.Int)(c.MaxSupply).Sign() <= 0 || (*big.Int)(c.MaxSupply).Cmp(math.MaxBig256) > 0) {
+		return fmt.Errorf("%w: %s", ErrInvalidMaxSupply, (*big.Int)(c.MaxSupply))
+	}
+	for minter, allowance := range c.MinterAllowances {
+		if allowance == nil || (*big.Int)(allowance).Sign() < 0 || (*big.Int)(allowance).Cmp(math.MaxBig256) > 0 {
+			return fmt.Errorf("%w: %s for %s", ErrInvalidMintAllowance, (*big.Int)(allowance), minter)
+		}
+	}
+	if c.IsReconfigure() && len(c.InitialMint) > 0 {
+		return fmt.Errorf("%w: cannot be specified when reconfiguring the native minter", ErrInvalidInitialMint)
+	}
+	initialSupply := new(big.Int)
+	for recipient, amount := range c.InitialMint {
+		if amount == nil || (*big.Int)(amount).Sign() <= 0 || (*big.Int)(amount).Cmp(math.MaxBig256) > 0 {
+			return fmt.Errorf("%w: %s for %s", ErrInvalidInitialMint, (*big.Int)(amount), recipient)
+		}
+		initialSupply.Add(initialSupply, (*big.Int)(amount))
+	}
+	if initialSupply.Cmp(math.MaxBig256) > 0 {
+		return fmt.Errorf("%w: total %s exceeds the maximum uint256 value", ErrInvalidInitialMint, initialSupply)
+	}
+	if c.MaxSupply != nil && initialSupply.Cmp((*big.Int)(c.MaxSupply)) > 0 {
+		return fmt.Errorf("%w: total %s exceeds max supply %s", ErrInvalidInitialMint, initialSupply, (*big.Int)(c.MaxSupply))
+	}
+	return nil
 }
 
 // Contract returns the singleton stateful precompiled contract to be used for the native minter.
@@ -77,7 +251,34 @@ func (c *ContractNativeMinterConfig) Equal(s StatefulPrecompileConfig) bool {
 	if !ok {
 		return false
 	}
-	return c.UpgradeableConfig.Equal(&other.UpgradeableConfig) && c.AllowListConfig.Equal(&other.AllowListConfig)
+	if !c.UpgradeableConfig.Equal(&other.UpgradeableConfig) || !c.AllowListConfig.Equal(&other.AllowListConfig) {
+		return false
+	}
+	return hexOrDecimalEqual(c.MaxSupply, other.MaxSupply) &&
+		hexOrDecimalMapEqual(c.MinterAllowances, other.MinterAllowances) &&
+		hexOrDecimalMapEqual(c.InitialMint, other.InitialMint)
+}
+
+// hexOrDecimalMapEqual returns true if [a] and [b] have the same keys mapped to the same numbers.
+func hexOrDecimalMapEqual(a, b map[common.Address]*math.HexOrDecimal256) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for addr, value := range a {
+		otherValue, ok := b[addr]
+		if !ok || !hexOrDecimalEqual(value, otherValue) {
+			return false
+		}
+	}
+	return true
+}
+
+// hexOrDecimalEqual returns true if [a] and [b] are both nil or represent the same number.
+func hexOrDecimalEqual(a, b *math.HexOrDecimal256) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return (*big.Int)(a).Cmp((*big.Int)(b)) == 0
 }
 
 // GetContractNativeMinterStatus returns the role of [address] for the minter list.
@@ -91,6 +292,65 @@ func SetContractNativeMinterStatus(stateDB StateDB, address common.Address, role
 	setAllowListRole(stateDB, ContractNativeMinterAddress, address, role)
 }
 
+// GetNativeMinterTotalMinted returns the total amount of native coins minted through the native minter.
+func GetNativeMinterTotalMinted(stateDB StateDB) *big.Int {
+	return stateDB.GetState(ContractNativeMinterAddress, totalMintedKey).Big()
+}
+
+// GetNativeMinterRemainingAllowance returns the amount of native coins that [address] can still mint
+// within its allowance and the max supply. If no max supply is set, the supply is capped at the maximum uint256 value.
+// The role of [address] is not taken into account.
+func GetNativeMinterRemainingAllowance(stateDB StateDB, address common.Address) *big.Int {
+	// The total minted must fit in a storage slot, so the supply is capped at the maximum uint256 value
+	maxSupply := stateDB.GetState(ContractNativeMinterAddress, maxSupplyKey).Big()
+	if maxSupply.Sign() == 0 {
+		maxSupply = math.MaxBig256
+	}
+	remaining := new(big.Int).Sub(maxSupply, GetNativeMinterTotalMinted(stateDB))
+	if remaining.Sign() < 0 {
+		remaining = new(big.Int)
+	}
+	if allowance, ok := getMinterAllowance(stateDB, address); ok && allowance.Cmp(remaining) < 0 {
+		remaining = allowance
+	}
+	return remaining
+}
+
+// getMinterAllowance returns the remaining allowance of [minter] and true if its minting is limited by an allowance.
+func getMinterAllowance(stateDB StateDB, minter common.Address) (*big.Int, bool) {
+	if stateDB.GetState(ContractNativeMinterAddress, crypto.Keccak256Hash(minterAllowanceSetPrefix, minter.Bytes())) == (common.Hash{}) {
+		return nil, false
+	}
+	return stateDB.GetState(ContractNativeMinterAddress, crypto.Keccak256Hash(minterAllowancePrefix, minter.Bytes())).Big(), true
+}
+
+// setMinterAllowance limits the amount of native coins that [minter] can mint to [allowance].
+func setMinterAllowance(stateDB StateDB, minter common.Address, allowance *big.Int) {
+	stateDB.SetState(ContractNativeMinterAddress, crypto.Keccak256Hash(minterAllowanceSetPrefix, minter.Bytes()), common.BigToHash(common.Big1))
+	stateDB.SetState(ContractNativeMinterAddress, crypto.Keccak256Hash(minterAllowancePrefix, minter.Bytes()), common.BigToHash(allowance))
+}
+
+// clearMinterAllowance removes the allowance of [minter], so that its minting is only limited by the max supply.
+func clearMinterAllowance(stateDB StateDB, minter common.Address) {
+	stateDB.SetState(ContractNativeMinterAddress, crypto.Keccak256Hash(minterAllowanceSetPrefix, minter.Bytes()), common.Hash{})
+	stateDB.SetState(ContractNativeMinterAddress, crypto.Keccak256Hash(minterAllowancePrefix, minter.Bytes()), common.Hash{})
+}
+
+// minterAllowanceListKey returns the storage key of the [index]-th minter with an allowance.
+func minterAllowanceListKey(index uint64) common.Hash {
+	return crypto.Keccak256Hash(minterAllowanceListPrefix, common.BigToHash(new(big.Int).SetUint64(index)).Bytes())
+}
+
+// PackTotalMinted packs the input data to the totalMinted function
+func PackTotalMinted() ([]byte, error) {
+	return ContractNativeMinterABI.Pack("totalMinted")
+}
+
+// PackRemainingAllowance packs [address] into the input data to the remainingAllowance function
+func PackRemainingAllowance(address common.Address) ([]byte, error) {
+	return ContractNativeMinterABI.Pack("remainingAllowance", address)
+}
+
 // PackMintInput packs [address] and [amount] into the appropriate arguments for minting operation.
 // Assumes that [amount] can be represented by 32 bytes.
 func PackMintInput(address common.Address, amount *big.Int) ([]byte, error) {
@@ -118,7 +378,7 @@ func UnpackMintInput(input []byte) (common.Address, *big.Int, error) {
 // mintNativeCoin checks if the caller is permissioned for minting operation.
 // The execution function parses the [input] into native coin amount and receiver address.
 func mintNativeCoin(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
-	if remainingGas, err = deductGas(suppliedGas, MintGasCost); err != nil {
+	if remainingGas, err = deductGas(suppliedGas, getGasCost(accessibleState, ContractNativeMinterAddress, "mintNativeCoin", upgradedGasCost(accessibleState, MintGasCost, LegacyMintGasCost))); err != nil {
 		return nil, 0, err
 	}
 
@@ -137,6 +397,26 @@ func mintNativeCoin(accessibleState PrecompileAccessibleState, caller common.Add
 	if !callerStatus.IsEnabled() {
 		return nil, remainingGas, fmt.Errorf("%w: %s", ErrCannotMint, caller)
 	}
+	if err := verifyMultisigCaller(accessibleState, ContractNativeMinterAddress, caller); err != nil {
+		return nil, remainingGas, err
+	}
+
+	// Verify that minting [amount] stays within the max supply and the allowance of the caller.
+	// The minting limits are not enforced nor accounted for before the PrecompileV2 upgrade.
+	if isPrecompileV2(accessibleState) {
+		remaining := GetNativeMinterRemainingAllowance(stateDB, caller)
+		if amount.Cmp(remaining) > 0 {
+			if allowance, ok := getMinterAllowance(stateDB, caller); ok && amount.Cmp(allowance) > 0 {
+				return nil, remainingGas, fmt.Errorf("%w: %s exceeds remaining allowance %s of %s", ErrMintExceedsAllowance, amount, allowance, caller)
+			}
+			return nil, remainingGas, fmt.Errorf("%w: %s exceeds remaining supply %s", ErrMintExceedsMaxSupply, amount, remaining)
+		}
+		totalMinted := new(big.Int).Add(GetNativeMinterTotalMinted(stateDB), amount)
+		stateDB.SetState(ContractNativeMinterAddress, totalMintedKey, common.BigToHash(totalMinted))
+		if allowance, ok := getMinterAllowance(stateDB, caller); ok {
+			setMinterAllowance(stateDB, caller, allowance.Sub(allowance, amount))
+		}
+	}
 
 	// if there is no address in the state, create one.
 	if !stateDB.Exist(to) {
@@ -144,17 +424,48 @@ func mintNativeCoin(accessibleState PrecompileAccessibleState, caller common.Add
 	}
 
 	stateDB.AddBalance(to, amount)
+	if isPrecompileV2(accessibleState) {
+		stateDB.AddLog(
+			ContractNativeMinterAddress,
+			[]common.Hash{
+				NativeCoinMintedEventID,
+				caller.Hash(),
+				to.Hash(),
+			},
+			common.BigToHash(amount).Bytes(),
+			accessibleState.GetBlockContext().Number().Uint64(),
+		)
+	}
 	// Return an empty output and the remaining gas
 	return []byte{}, remainingGas, nil
 }
 
+// totalMinted returns the total amount of native coins minted through the native minter.
+func totalMinted(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, args []interface{}, suppliedGas uint64, readOnly bool) (outputs []interface{}, remainingGas uint64, err error) {
+	if remainingGas, err = deductGas(suppliedGas, getGasCost(accessibleState, ContractNativeMinterAddress, "totalMinted", TotalMintedGasCost)); err != nil {
+		return nil, 0, err
+	}
+	return []interface{}{GetNativeMinterTotalMinted(accessibleState.GetStateDB())}, remainingGas, nil
+}
+
+// remainingAllowance returns the amount of native coins that the address in the input can still mint.
+func remainingAllowance(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, args []interface{}, suppliedGas uint64, readOnly bool) (outputs []interface{}, remainingGas uint64, err error) {
+	if remainingGas, err = deductGas(suppliedGas, getGasCost(accessibleState, ContractNativeMinterAddress, "remainingAllowance", RemainingAllowanceGasCost)); err != nil {
+		return nil, 0, err
+	}
+	minter := args[0].(common.Address)
+	return []interface{}{GetNativeMinterRemainingAllowance(accessibleState.GetStateDB(), minter)}, remainingGas, nil
+}
+
 // createNativeMinterPrecompile returns a StatefulPrecompiledContract with R/W control of an allow list at [precompileAddr] and a native coin minter.
 func createNativeMinterPrecompile(precompileAddr common.Address) StatefulPrecompiledContract {
 	enabledFuncs := createAllowListFunctions(precompileAddr)
 
 	mintFunc := newStatefulPrecompileFunction(mintSignature, mintNativeCoin)
+	totalMintedFunc := activatedByPrecompileV2(newStatefulPrecompileABIFunction(ContractNativeMinterABI.Methods["totalMinted"], totalMinted))
+	remainingAllowanceFunc := activatedByPrecompileV2(newStatefulPrecompileABIFunction(ContractNativeMinterABI.Methods["remainingAllowance"], remainingAllowance))
 
-	enabledFuncs = append(enabledFuncs, mintFunc)
+	enabledFuncs = append(enabledFuncs, mintFunc, totalMintedFunc, remainingAllowanceFunc)
 	// Construct the contract with no fallback function.
 	contract := newStatefulPrecompileWithFunctionSelectors(nil, enabledFuncs)
 	return contract
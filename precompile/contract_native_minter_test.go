@@ -0,0 +1,171 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContractNativeMinterConfigVerify(t *testing.T) {
+	tests := map[string]struct {
+		config      *ContractNativeMinterConfig
+		expectedErr error
+	}{
+		"no limits": {
+			config: NewContractNativeMinterConfig(big.NewInt(3), nil),
+		},
+		"valid limits": {
+			config: &ContractNativeMinterConfig{
+				MaxSupply:        math.NewHexOrDecimal256(100),
+				MinterAllowances: map[common.Address]*math.HexOrDecimal256{{1}: math.NewHexOrDecimal256(0)},
+			},
+		},
+		"zero max supply": {
+			config:      &ContractNativeMinterConfig{MaxSupply: math.NewHexOrDecimal256(0)},
+			expectedErr: ErrInvalidMaxSupply,
+		},
+		"negative allowance": {
+			config:      &ContractNativeMinterConfig{MinterAllowances: map[common.Address]*math.HexOrDecimal256{{1}: math.NewHexOrDecimal256(-1)}},
+			expectedErr: ErrInvalidMintAllowance,
+		},
+		"initial mint within max supply": {
+			config: &ContractNativeMinterConfig{
+				MaxSupply:   math.NewHexOrDecimal256(100),
+				InitialMint: map[common.Address]*math.HexOrDecimal256{{1}: math.NewHexOrDecimal256(60), {2}: math.NewHexOrDecimal256(40)},
+			},
+		},
+		"initial mint exceeds max supply": {
+			config: &ContractNativeMinterConfig{
+				MaxSupply:   math.NewHexOrDecimal256(100),
+				InitialMint: map[common.Address]*math.HexOrDecimal256{{1}: math.NewHexOrDecimal256(60), {2}: math.NewHexOrDecimal256(41)},
+			},
+			expectedErr: ErrInvalidInitialMint,
+		},
+		"initial mint exceeds max uint256": {
+			config: &ContractNativeMinterConfig{
+				InitialMint: map[common.Address]*math.HexOrDecimal256{{1}: (*math.HexOrDecimal256)(math.MaxBig256), {2}: math.NewHexOrDecimal256(1)},
+			},
+			expectedErr: ErrInvalidInitialMint,
+		},
+		"zero initial mint": {
+			config:      &ContractNativeMinterConfig{InitialMint: map[common.Address]*math.HexOrDecimal256{{1}: math.NewHexOrDecimal256(0)}},
+			expectedErr: ErrInvalidInitialMint,
+		},
+		"initial mint exceeding max uint256": {
+			config:      &ContractNativeMinterConfig{InitialMint: map[common.Address]*math.HexOrDecimal256{{1}: (*math.HexOrDecimal256)(new(big.Int).Lsh(common.Big1, 256))}},
+			expectedErr: ErrInvalidInitialMint,
+		},
+		"max supply exceeding max uint256": {
+			config:      &ContractNativeMinterConfig{MaxSupply: (*math.HexOrDecimal256)(new(big.Int).Lsh(common.Big1, 256))},
+			expectedErr: ErrInvalidMaxSupply,
+		},
+		"allowance exceeding max uint256": {
+			config:      &ContractNativeMinterConfig{MinterAllowances: map[common.Address]*math.HexOrDecimal256{{1}: (*math.HexOrDecimal256)(new(big.Int).Lsh(common.Big1, 256))}},
+			expectedErr: ErrInvalidMintAllowance,
+		},
+		"initial mint on reconfigure": {
+			config: &ContractNativeMinterConfig{
+				UpgradeableConfig: UpgradeableConfig{BlockTimestamp: big.NewInt(1), Reconfiguration: true},
+				InitialMint:       map[common.Address]*math.HexOrDecimal256{{1}: math.NewHexOrDecimal256(1)},
+			},
+			expectedErr: ErrInvalidInitialMint,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.config.Verify()
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestContractNativeMinterConfigEqual(t *testing.T) {
+	config := &ContractNativeMinterConfig{
+		MaxSupply:        math.NewHexOrDecimal256(100),
+		MinterAllowances: map[common.Address]*math.HexOrDecimal256{{1}: math.NewHexOrDecimal256(10)},
+	}
+	assert.True(t, config.Equal(&ContractNativeMinterConfig{
+		MaxSupply:        math.NewHexOrDecimal256(100),
+		MinterAllowances: map[common.Address]*math.HexOrDecimal256{{1}: math.NewHexOrDecimal256(10)},
+	}))
+	assert.False(t, config.Equal(&ContractNativeMinterConfig{
+		MinterAllowances: map[common.Address]*math.HexOrDecimal256{{1}: math.NewHexOrDecimal256(10)},
+	}))
+	assert.False(t, config.Equal(&ContractNativeMinterConfig{
+		MaxSupply:        math.NewHexOrDecimal256(100),
+		MinterAllowances: map[common.Address]*math.HexOrDecimal256{{2}: math.NewHexOrDecimal256(10)},
+	}))
+	assert.False(t, config.Equal(&ContractNativeMinterConfig{
+		MaxSupply:        math.NewHexOrDecimal256(100),
+		MinterAllowances: map[common.Address]*math.HexOrDecimal256{{1}: math.NewHexOrDecimal256(10)},
+		InitialMint:      map[common.Address]*math.HexOrDecimal256{{1}: math.NewHexOrDecimal256(10)},
+	}))
+}
+
+func TestNativeMinterRemainingAllowance(t *testing.T) {
+	var (
+		state   = newMockStorageStateDB()
+		limited = common.Address{1}
+		other   = common.Address{2}
+	)
+	// no limits
+	assert.Equal(t, math.MaxBig256, GetNativeMinterRemainingAllowance(state, limited))
+
+	config := &ContractNativeMinterConfig{
+		MaxSupply:        math.NewHexOrDecimal256(100),
+		MinterAllowances: map[common.Address]*math.HexOrDecimal256{limited: math.NewHexOrDecimal256(30)},
+	}
+	config.Configure(nil, state, nil)
+	assert.Equal(t, big.NewInt(30), GetNativeMinterRemainingAllowance(state, limited))
+	assert.Equal(t, big.NewInt(100), GetNativeMinterRemainingAllowance(state, other))
+
+	// the remaining supply caps the allowance
+	state.SetState(ContractNativeMinterAddress, totalMintedKey, common.BigToHash(big.NewInt(80)))
+	assert.Equal(t, big.NewInt(20), GetNativeMinterRemainingAllowance(state, limited))
+	assert.Equal(t, big.NewInt(20), GetNativeMinterRemainingAllowance(state, other))
+
+	// lowering the max supply below the total minted leaves no supply
+	(&ContractNativeMinterConfig{MaxSupply: math.NewHexOrDecimal256(50)}).Configure(nil, state, nil)
+	assert.Equal(t, big.NewInt(0), GetNativeMinterRemainingAllowance(state, other))
+}
+
+func TestNativeMinterReconfigureLimits(t *testing.T) {
+	var (
+		state   = newMockStorageStateDB()
+		limited = common.Address{1}
+		removed = common.Address{2}
+	)
+	(&ContractNativeMinterConfig{
+		MaxSupply: math.NewHexOrDecimal256(100),
+		MinterAllowances: map[common.Address]*math.HexOrDecimal256{
+			limited: math.NewHexOrDecimal256(10),
+			removed: math.NewHexOrDecimal256(20),
+		},
+	}).Configure(nil, state, nil)
+	assert.Equal(t, big.NewInt(20), GetNativeMinterRemainingAllowance(state, removed))
+
+	// The allowances of the minters missing from the new config are cleared
+	(&ContractNativeMinterConfig{
+		UpgradeableConfig: UpgradeableConfig{Reconfiguration: true},
+		MaxSupply:         math.NewHexOrDecimal256(50),
+		MinterAllowances:  map[common.Address]*math.HexOrDecimal256{limited: math.NewHexOrDecimal256(5)},
+	}).Reconfigure(nil, state, nil)
+	assert.Equal(t, big.NewInt(5), GetNativeMinterRemainingAllowance(state, limited))
+	assert.Equal(t, big.NewInt(50), GetNativeMinterRemainingAllowance(state, removed))
+	_, ok := getMinterAllowance(state, removed)
+	assert.False(t, ok)
+
+	(&ContractNativeMinterConfig{}).Reconfigure(nil, state, nil)
+	assert.Equal(t, math.MaxBig256, GetNativeMinterRemainingAllowance(state, limited))
+	assert.Empty(t, state.storage[ContractNativeMinterAddress])
+}
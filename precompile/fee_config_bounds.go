@@ -0,0 +1,169 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/commontype"
+	"github.com/ava-labs/subnet-evm/utils"
+)
+
+var (
+	ErrInvalidFeeConfigBounds  = errors.New("invalid fee config bounds")
+	ErrFeeConfigOutOfBounds    = errors.New("fee config out of bounds")
+	ErrFeeConfigChangeTooLarge = errors.New("fee config change too large")
+)
+
+// FeeConfigFieldBounds bounds the value of a fee config field and the change of its value in a single
+// fee config change. Each bound is optional.
+type FeeConfigFieldBounds struct {
+	Min      *big.Int `json:"min,omitempty"`
+	Max      *big.Int `json:"max,omitempty"`
+	MaxDelta *big.Int `json:"maxDelta,omitempty"`
+}
+
+// FeeConfigBounds specifies the optional bounds of each field of the fee configs that can be set
+// through the fee config manager.
+type FeeConfigBounds struct {
+	GasLimit                 *FeeConfigFieldBounds `json:"gasLimit,omitempty"`
+	TargetBlockRate          *FeeConfigFieldBounds `json:"targetBlockRate,omitempty"`
+	MinBaseFee               *FeeConfigFieldBounds `json:"minBaseFee,omitempty"`
+	TargetGas                *FeeConfigFieldBounds `json:"targetGas,omitempty"`
+	BaseFeeChangeDenominator *FeeConfigFieldBounds `json:"baseFeeChangeDenominator,omitempty"`
+	MinBlockGasCost          *FeeConfigFieldBounds `json:"minBlockGasCost,omitempty"`
+	MaxBlockGasCost          *FeeConfigFieldBounds `json:"maxBlockGasCost,omitempty"`
+	BlockGasCostStep         *FeeConfigFieldBounds `json:"blockGasCostStep,omitempty"`
+}
+
+// feeConfigField accesses the value of a fee config field and its bounds.
+type feeConfigField struct {
+	name   string
+	value  func(feeConfig commontype.FeeConfig) *big.Int
+	bounds func(b *FeeConfigBounds) *FeeConfigFieldBounds
+}
+
+// feeConfigFields are the bounded fields of the fee config in the same order as the arguments of setFeeConfig.
+var feeConfigFields = []feeConfigField{
+	{
+		name:   "gasLimit",
+		value:  func(f commontype.FeeConfig) *big.Int { return f.GasLimit },
+		bounds: func(b *FeeConfigBounds) *FeeConfigFieldBounds { return b.GasLimit },
+	},
+	{
+		name:   "targetBlockRate",
+		value:  func(f commontype.FeeConfig) *big.Int { return new(big.Int).SetUint64(f.TargetBlockRate) },
+		bounds: func(b *FeeConfigBounds) *FeeConfigFieldBounds { return b.TargetBlockRate },
+	},
+	{
+		name:   "minBaseFee",
+		value:  func(f commontype.FeeConfig) *big.Int { return f.MinBaseFee },
+		bounds: func(b *FeeConfigBounds) *FeeConfigFieldBounds { return b.MinBaseFee },
+	},
+	{
+		name:   "targetGas",
+		value:  func(f commontype.FeeConfig) *big.Int { return f.TargetGas },
+		bounds: func(b *FeeConfigBounds) *FeeConfigFieldBounds { return b.TargetGas },
+	},
+	{
+		name:   "baseFeeChangeDenominator",
+		value:  func(f commontype.FeeConfig) *big.Int { return f.BaseFeeChangeDenominator },
+		bounds: func(b *FeeConfigBounds) *FeeConfigFieldBounds { return b.BaseFeeChangeDenominator },
+	},
+	{
+		name:   "minBlockGasCost",
+		value:  func(f commontype.FeeConfig) *big.Int { return f.MinBlockGasCost },
+		bounds: func(b *FeeConfigBounds) *FeeConfigFieldBounds { return b.MinBlockGasCost },
+	},
+	{
+		name:   "maxBlockGasCost",
+		value:  func(f commontype.FeeConfig) *big.Int { return f.MaxBlockGasCost },
+		bounds: func(b *FeeConfigBounds) *FeeConfigFieldBounds { return b.MaxBlockGasCost },
+	},
+	{
+		name:   "blockGasCostStep",
+		value:  func(f commontype.FeeConfig) *big.Int { return f.BlockGasCostStep },
+		bounds: func(b *FeeConfigBounds) *FeeConfigFieldBounds { return b.BlockGasCostStep },
+	},
+}
+
+// Verify returns an error if any of the bounds of [b] is negative or a minimum is greater than its maximum.
+func (b *FeeConfigBounds) Verify() error {
+	for _, field := range feeConfigFields {
+		bounds := field.bounds(b)
+		if bounds == nil {
+			continue
+		}
+		for _, bound := range []*big.Int{bounds.Min, bounds.Max, bounds.MaxDelta} {
+			if bound != nil && bound.Sign() < 0 {
+				return fmt.Errorf("%w: %s bound %d cannot be negative", ErrInvalidFeeConfigBounds, field.name, bound)
+			}
+		}
+		if bounds.Min != nil && bounds.Max != nil && bounds.Min.Cmp(bounds.Max) > 0 {
+			return fmt.Errorf("%w: %s min %d cannot be greater than max %d", ErrInvalidFeeConfigBounds, field.name, bounds.Min, bounds.Max)
+		}
+	}
+	return nil
+}
+
+// VerifyFeeConfig returns an error if a field of [feeConfig] is outside of its min/max bounds.
+func (b *FeeConfigBounds) VerifyFeeConfig(feeConfig commontype.FeeConfig) error {
+	for _, field := range feeConfigFields {
+		bounds := field.bounds(b)
+		if bounds == nil {
+			continue
+		}
+		value := field.value(feeConfig)
+		if bounds.Min != nil && value.Cmp(bounds.Min) < 0 {
+			return fmt.Errorf("%w: %s = %d is below the minimum %d", ErrFeeConfigOutOfBounds, field.name, value, bounds.Min)
+		}
+		if bounds.Max != nil && value.Cmp(bounds.Max) > 0 {
+			return fmt.Errorf("%w: %s = %d is above the maximum %d", ErrFeeConfigOutOfBounds, field.name, value, bounds.Max)
+		}
+	}
+	return nil
+}
+
+// VerifyChange returns an error if a field of [to] is outside of its min/max bounds or differs from
+// the same field of [from] by more than its max delta.
+func (b *FeeConfigBounds) VerifyChange(from commontype.FeeConfig, to commontype.FeeConfig) error {
+	if err := b.VerifyFeeConfig(to); err != nil {
+		return err
+	}
+	for _, field := range feeConfigFields {
+		bounds := field.bounds(b)
+		if bounds == nil || bounds.MaxDelta == nil {
+			continue
+		}
+		fromValue, toValue := field.value(from), field.value(to)
+		delta := new(big.Int).Sub(toValue, fromValue)
+		if delta.Abs(delta).Cmp(bounds.MaxDelta) > 0 {
+			return fmt.Errorf("%w: %s change from %d to %d exceeds the max delta %d", ErrFeeConfigChangeTooLarge, field.name, fromValue, toValue, bounds.MaxDelta)
+		}
+	}
+	return nil
+}
+
+// Equal returns true if [b] and [other] specify the same bounds.
+func (b *FeeConfigBounds) Equal(other *FeeConfigBounds) bool {
+	if b == nil || other == nil {
+		return b == other
+	}
+	for _, field := range feeConfigFields {
+		if !field.bounds(b).equal(field.bounds(other)) {
+			return false
+		}
+	}
+	return true
+}
+
+// equal returns true if [f] and [other] specify the same bounds.
+func (f *FeeConfigFieldBounds) equal(other *FeeConfigFieldBounds) bool {
+	if f == nil || other == nil {
+		return f == other
+	}
+	return utils.BigNumEqual(f.Min, other.Min) && utils.BigNumEqual(f.Max, other.Max) && utils.BigNumEqual(f.MaxDelta, other.MaxDelta)
+}
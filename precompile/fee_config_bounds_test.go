@@ -0,0 +1,109 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeeConfigBoundsVerify(t *testing.T) {
+	tests := map[string]struct {
+		bounds      FeeConfigBounds
+		expectedErr error
+	}{
+		"no bounds": {},
+		"valid bounds": {
+			bounds: FeeConfigBounds{
+				GasLimit:   &FeeConfigFieldBounds{Min: big.NewInt(1), Max: big.NewInt(1), MaxDelta: big.NewInt(0)},
+				MinBaseFee: &FeeConfigFieldBounds{Max: big.NewInt(100)},
+			},
+		},
+		"min greater than max": {
+			bounds:      FeeConfigBounds{TargetGas: &FeeConfigFieldBounds{Min: big.NewInt(2), Max: big.NewInt(1)}},
+			expectedErr: ErrInvalidFeeConfigBounds,
+		},
+		"negative max delta": {
+			bounds:      FeeConfigBounds{TargetBlockRate: &FeeConfigFieldBounds{MaxDelta: big.NewInt(-1)}},
+			expectedErr: ErrInvalidFeeConfigBounds,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.bounds.Verify()
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestFeeConfigBoundsVerifyChange(t *testing.T) {
+	bounds := &FeeConfigBounds{
+		GasLimit:        &FeeConfigFieldBounds{Min: big.NewInt(1_000_000), Max: big.NewInt(20_000_000), MaxDelta: big.NewInt(2_000_000)},
+		TargetBlockRate: &FeeConfigFieldBounds{MaxDelta: big.NewInt(1)},
+	}
+	from := testPendingFeeConfig
+	tests := map[string]struct {
+		gasLimit    *big.Int
+		blockRate   uint64
+		expectedErr error
+	}{
+		"within bounds": {
+			gasLimit:  big.NewInt(10_000_000),
+			blockRate: 3,
+		},
+		"below min": {
+			gasLimit:    big.NewInt(1),
+			blockRate:   2,
+			expectedErr: ErrFeeConfigOutOfBounds,
+		},
+		"above max": {
+			gasLimit:    big.NewInt(30_000_000),
+			blockRate:   2,
+			expectedErr: ErrFeeConfigOutOfBounds,
+		},
+		"gas limit increase too large": {
+			gasLimit:    big.NewInt(10_000_001),
+			blockRate:   2,
+			expectedErr: ErrFeeConfigChangeTooLarge,
+		},
+		"block rate decrease too large": {
+			gasLimit:    big.NewInt(8_000_000),
+			blockRate:   0,
+			expectedErr: ErrFeeConfigChangeTooLarge,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			to := from
+			to.GasLimit = test.gasLimit
+			to.TargetBlockRate = test.blockRate
+			err := bounds.VerifyChange(from, to)
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestFeeConfigManagerConfigBoundsJSON(t *testing.T) {
+	var config FeeConfigManagerConfig
+	err := json.Unmarshal([]byte(`{"blockTimestamp":0,"feeConfigBounds":{"gasLimit":{"min":1000000,"max":20000000,"maxDelta":2000000}}}`), &config)
+	assert.NoError(t, err)
+	assert.True(t, config.Equal(&FeeConfigManagerConfig{
+		UpgradeableConfig: UpgradeableConfig{BlockTimestamp: big.NewInt(0)},
+		FeeConfigBounds: &FeeConfigBounds{
+			GasLimit: &FeeConfigFieldBounds{Min: big.NewInt(1_000_000), Max: big.NewInt(20_000_000), MaxDelta: big.NewInt(2_000_000)},
+		},
+	}))
+	assert.False(t, config.Equal(&FeeConfigManagerConfig{UpgradeableConfig: UpgradeableConfig{BlockTimestamp: big.NewInt(0)}}))
+}
@@ -4,6 +4,7 @@
 package precompile
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math/big"
@@ -11,6 +12,7 @@ import (
 	"github.com/ava-labs/subnet-evm/commontype"
 	"github.com/ava-labs/subnet-evm/vmerrs"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 const (
@@ -30,28 +32,98 @@ const (
 
 	// [numFeeConfigField] fields in FeeConfig struct
 	feeConfigInputLen = common.HashLength * numFeeConfigField
+	// fee config followed by the activation timestamp
+	pendingFeeConfigLen = feeConfigInputLen + common.HashLength
+
+	// DefaultMinFeeConfigDelay is the minimum delay in seconds between scheduling a fee config
+	// with setFeeConfigAt and its activation, unless configured otherwise.
+	DefaultMinFeeConfigDelay uint64 = 60 * 60 // 1 hour
 )
 
+// FeeConfigManagerRawABI is the ABI of the IFeeManager interface excluding the allow list functions
+const FeeConfigManagerRawABI = `[
+	{"type":"function","name":"setFeeConfig","stateMutability":"nonpayable","inputs":[{"name":"gasLimit","type":"uint256"},{"name":"targetBlockRate","type":"uint256"},{"name":"minBaseFee","type":"uint256"},{"name":"targetGas","type":"uint256"},{"name":"baseFeeChangeDenominator","type":"uint256"},{"name":"minBlockGasCost","type":"uint256"},{"name":"maxBlockGasCost","type":"uint256"},{"name":"blockGasCostStep","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"getFeeConfig","stateMutability":"view","inputs":[],"outputs":[{"name":"gasLimit","type":"uint256"},{"name":"targetBlockRate","type":"uint256"},{"name":"minBaseFee","type":"uint256"},{"name":"targetGas","type":"uint256"},{"name":"baseFeeChangeDenominator","type":"uint256"},{"name":"minBlockGasCost","type":"uint256"},{"name":"maxBlockGasCost","type":"uint256"},{"name":"blockGasCostStep","type":"uint256"}]},
+	{"type":"function","name":"getFeeConfigLastChangedAt","stateMutability":"view","inputs":[],"outputs":[{"name":"blockNumber","type":"uint256"}]},
+	{"type":"function","name":"setFeeConfigAt","stateMutability":"nonpayable","inputs":[{"name":"gasLimit","type":"uint256"},{"name":"targetBlockRate","type":"uint256"},{"name":"minBaseFee","type":"uint256"},{"name":"targetGas","type":"uint256"},{"name":"baseFeeChangeDenominator","type":"uint256"},{"name":"minBlockGasCost","type":"uint256"},{"name":"maxBlockGasCost","type":"uint256"},{"name":"blockGasCostStep","type":"uint256"},{"name":"activationTimestamp","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"getPendingFeeConfig","stateMutability":"view","inputs":[],"outputs":[{"name":"gasLimit","type":"uint256"},{"name":"targetBlockRate","type":"uint256"},{"name":"minBaseFee","type":"uint256"},{"name":"targetGas","type":"uint256"},{"name":"baseFeeChangeDenominator","type":"uint256"},{"name":"minBlockGasCost","type":"uint256"},{"name":"maxBlockGasCost","type":"uint256"},{"name":"blockGasCostStep","type":"uint256"},{"name":"activationTimestamp","type":"uint256"}]},
+	{"type":"function","name":"cancelPendingFeeConfig","stateMutability":"nonpayable","inputs":[],"outputs":[]}
+]`
+
 var (
 	_ StatefulPrecompileConfig = &FeeConfigManagerConfig{}
 
+	FeeConfigManagerABI = mustParseABI(FeeConfigManagerRawABI)
+
 	// Singleton StatefulPrecompiledContract for setting fee configs by permissioned callers.
 	FeeConfigManagerPrecompile StatefulPrecompiledContract = createFeeConfigManagerPrecompile(FeeConfigManagerAddress)
 
 	setFeeConfigSignature              = CalculateFunctionSelector("setFeeConfig(uint256,uint256,uint256,uint256,uint256,uint256,uint256,uint256)")
 	getFeeConfigSignature              = CalculateFunctionSelector("getFeeConfig()")
 	getFeeConfigLastChangedAtSignature = CalculateFunctionSelector("getFeeConfigLastChangedAt()")
-
-	feeConfigLastChangedAtKey = common.Hash{'l', 'c', 'a'}
-
-	ErrCannotChangeFee = errors.New("non-enabled cannot change fee config")
+	setFeeConfigAtSignature            = CalculateFunctionSelector("setFeeConfigAt(uint256,uint256,uint256,uint256,uint256,uint256,uint256,uint256,uint256)")
+	getPendingFeeConfigSignature       = CalculateFunctionSelector("getPendingFeeConfig()")
+	cancelPendingFeeConfigSignature    = CalculateFunctionSelector("cancelPendingFeeConfig()")
+
+	feeConfigLastChangedAtKey           = common.Hash{'l', 'c', 'a'}
+	pendingFeeConfigActivationKey       = common.Hash{'p', 'a', 't'}
+	minFeeConfigDelayKey                = common.Hash{'m', 'f', 'd'}
+	pendingFeeConfigFieldKeyPrefix byte = 'p'
+
+	ErrCannotChangeFee                = errors.New("non-enabled cannot change fee config")
+	ErrFeeConfigActivationTooEarly    = errors.New("fee config activation timestamp is too early")
+	ErrNoPendingFeeConfig             = errors.New("no pending fee config")
+	ErrInvalidFeeConfigActivationTime = errors.New("invalid fee config activation timestamp")
+	ErrFeeConfigTimeLocked            = errors.New("fee config changes are time locked")
+
+	// FeeConfigChangedEventID is the first topic of the log emitted by setFeeConfig.
+	// The remaining topic is the caller and the data of the log is the new fee config packed
+	// in the same order as the arguments of setFeeConfig.
+	FeeConfigChangedEventID = crypto.Keccak256Hash([]byte("FeeConfigChanged(address,uint256,uint256,uint256,uint256,uint256,uint256,uint256,uint256)"))
+
+	// FeeConfigScheduledEventID is the first topic of the log emitted by setFeeConfigAt.
+	// The remaining topic is the caller and the data of the log is the scheduled fee config
+	// followed by its activation timestamp, packed in the same order as the arguments of setFeeConfigAt.
+	FeeConfigScheduledEventID = crypto.Keccak256Hash([]byte("FeeConfigScheduled(address,uint256,uint256,uint256,uint256,uint256,uint256,uint256,uint256,uint256)"))
+
+	// PendingFeeConfigCancelledEventID is the first topic of the log emitted by cancelPendingFeeConfig.
+	// The remaining topic is the caller and the data of the log is the activation timestamp of the
+	// cancelled fee config.
+	PendingFeeConfigCancelledEventID = crypto.Keccak256Hash([]byte("PendingFeeConfigCancelled(address,uint256)"))
 )
 
+// FeeConfigManagerConfigKey is the key used in json config files to specify the FeeConfigManagerConfig.
+const FeeConfigManagerConfigKey = "feeManagerConfig"
+
+func init() {
+	mustRegisterModule(Module{
+		ConfigKey: FeeConfigManagerConfigKey,
+		Address:   FeeConfigManagerAddress,
+		NewConfig: func() StatefulPrecompileConfig { return &FeeConfigManagerConfig{} },
+		GasCosts: withAllowListGasCosts(map[string]uint64{
+			"setFeeConfig":              SetFeeConfigGasCost,
+			"getFeeConfig":              GetFeeConfigGasCost,
+			"getFeeConfigLastChangedAt": GetLastChangedAtGasCost,
+			"setFeeConfigAt":            SetFeeConfigAtGasCost,
+			"getPendingFeeConfig":       GetPendingFeeConfigGasCost,
+			"cancelPendingFeeConfig":    CancelPendingFeeConfigGasCost,
+		}),
+		ABI: withAllowListABI(FeeConfigManagerABI),
+	})
+}
+
 // FeeConfigManagerConfig wraps [AllowListConfig] and uses it to implement the StatefulPrecompileConfig
 // interface while adding in the FeeConfigManager specific precompile address.
 type FeeConfigManagerConfig struct {
 	AllowListConfig // Config for the fee config manager allow list
 	UpgradeableConfig
+	// MinFeeConfigDelay is the minimum delay in seconds between scheduling a fee config with
+	// setFeeConfigAt and its activation. Defaults to [DefaultMinFeeConfigDelay] if zero.
+	// If MinFeeConfigDelay is set, setFeeConfig is rejected so that every fee config change is time locked.
+	MinFeeConfigDelay uint64 `json:"minFeeConfigDelay,omitempty"`
+	// FeeConfigBounds optionally bounds the fee configs that can be set through the fee config manager
+	// and the change of each field in a single fee config change.
+	FeeConfigBounds *FeeConfigBounds `json:"feeConfigBounds,omitempty"`
 }
 
 // NewFeeManagerConfig returns a config for a network upgrade at [blockTimestamp] that enables
@@ -86,15 +158,38 @@ func (c *FeeConfigManagerConfig) Equal(s StatefulPrecompileConfig) bool {
 	if !ok {
 		return false
 	}
-	return c.UpgradeableConfig.Equal(&other.UpgradeableConfig) && c.AllowListConfig.Equal(&other.AllowListConfig)
+	return c.UpgradeableConfig.Equal(&other.UpgradeableConfig) && c.AllowListConfig.Equal(&other.AllowListConfig) &&
+		c.MinFeeConfigDelay == other.MinFeeConfigDelay && c.FeeConfigBounds.Equal(other.FeeConfigBounds)
 }
 
-// Configure configures [state] with the desired admins based on [c].
+// Verify returns an error if [c] has an invalid allow list or invalid fee config bounds.
+func (c *FeeConfigManagerConfig) Verify() error {
+	if err := c.AllowListConfig.Verify(); err != nil {
+		return err
+	}
+	if c.FeeConfigBounds != nil {
+		return c.FeeConfigBounds.Verify()
+	}
+	return nil
+}
+
+// Configure configures [state] with the desired admins and minimum fee config delay based on [c].
 func (c *FeeConfigManagerConfig) Configure(chainConfig ChainConfig, state StateDB, blockContext BlockContext) {
 	// Store the initial fee config into the state when the fee config manager activates.
 	if err := StoreFeeConfig(state, chainConfig.GetFeeConfig(), blockContext); err != nil {
 		panic(fmt.Sprintf("fee config should have been verified in genesis: %s", err))
 	}
+	// Drop any fee config that was pending when the fee config manager was last disabled.
+	clearPendingFeeConfig(state)
+	state.SetState(FeeConfigManagerAddress, minFeeConfigDelayKey, common.BigToHash(new(big.Int).SetUint64(c.MinFeeConfigDelay)))
+	c.AllowListConfig.Configure(state, FeeConfigManagerAddress)
+}
+
+// Reconfigure adds the admins and enabled addresses of [c] to the existing allow list and sets the minimum
+// delay of scheduled fee config changes, while keeping the current and pending fee configs.
+// Note: the fee config bounds of [c] apply to the next fee config change.
+func (c *FeeConfigManagerConfig) Reconfigure(_ ChainConfig, state StateDB, _ BlockContext) {
+	state.SetState(FeeConfigManagerAddress, minFeeConfigDelayKey, common.BigToHash(new(big.Int).SetUint64(c.MinFeeConfigDelay)))
 	c.AllowListConfig.Configure(state, FeeConfigManagerAddress)
 }
 
@@ -124,6 +219,48 @@ func PackGetLastChangedAtInput() []byte {
 	return getFeeConfigLastChangedAtSignature
 }
 
+// PackGetPendingFeeConfigInput packs the getPendingFeeConfig signature
+func PackGetPendingFeeConfigInput() []byte {
+	return getPendingFeeConfigSignature
+}
+
+// PackCancelPendingFeeConfigInput packs the cancelPendingFeeConfig signature
+func PackCancelPendingFeeConfigInput() []byte {
+	return cancelPendingFeeConfigSignature
+}
+
+// PackSetFeeConfigAt packs [feeConfig] and [activationTimestamp] with the selector into the appropriate
+// arguments for scheduling a fee config.
+func PackSetFeeConfigAt(feeConfig commontype.FeeConfig, activationTimestamp *big.Int) ([]byte, error) {
+	// function selector (4 bytes) + input(feeConfig, activationTimestamp)
+	res := make([]byte, len(setFeeConfigAtSignature)+pendingFeeConfigLen)
+	copy(res, setFeeConfigAtSignature)
+	copy(res[len(setFeeConfigAtSignature):], packPendingFeeConfig(feeConfig, activationTimestamp))
+	return res, nil
+}
+
+// packPendingFeeConfig packs [feeConfig] followed by [activationTimestamp] without the selector.
+func packPendingFeeConfig(feeConfig commontype.FeeConfig, activationTimestamp *big.Int) []byte {
+	res := make([]byte, pendingFeeConfigLen)
+	copy(res, packFeeConfigHelper(feeConfig, false))
+	copy(res[feeConfigInputLen:], common.BigToHash(activationTimestamp).Bytes())
+	return res
+}
+
+// UnpackPendingFeeConfig attempts to unpack [input] into a fee config followed by its activation timestamp,
+// which is both the input of setFeeConfigAt and the output of getPendingFeeConfig.
+// Assumes that [input] does not include selector (omits first 4 bytes in PackSetFeeConfigAt)
+func UnpackPendingFeeConfig(input []byte) (commontype.FeeConfig, *big.Int, error) {
+	if len(input) != pendingFeeConfigLen {
+		return commontype.FeeConfig{}, nil, fmt.Errorf("invalid input length for pending fee config: %d", len(input))
+	}
+	feeConfig, err := UnpackFeeConfigInput(input[:feeConfigInputLen])
+	if err != nil {
+		return commontype.FeeConfig{}, nil, err
+	}
+	return feeConfig, new(big.Int).SetBytes(input[feeConfigInputLen:]), nil
+}
+
 // PackFeeConfig packs [feeConfig] without the selector into the appropriate arguments for fee config operations.
 func PackFeeConfig(feeConfig commontype.FeeConfig) ([]byte, error) {
 	//  input(feeConfig)
@@ -159,6 +296,15 @@ func packFeeConfigHelper(feeConfig commontype.FeeConfig, useSelector bool) []byt
 	return res
 }
 
+// UnpackSetFeeConfigInput attempts to unpack [input] of a call to setFeeConfig, including its selector, into
+// the fee config it sets.
+func UnpackSetFeeConfigInput(input []byte) (commontype.FeeConfig, error) {
+	if len(input) < selectorLen || !bytes.Equal(input[:selectorLen], setFeeConfigSignature) {
+		return commontype.FeeConfig{}, errors.New("input is not a call to setFeeConfig")
+	}
+	return UnpackFeeConfigInput(input[selectorLen:])
+}
+
 // UnpackFeeConfigInput attempts to unpack [input] into the arguments to the fee config precompile
 // assumes that [input] does not include selector (omits first 4 bytes in PackSetFeeConfigInput)
 func UnpackFeeConfigInput(input []byte) (commontype.FeeConfig, error) {
@@ -195,9 +341,24 @@ func UnpackFeeConfigInput(input []byte) (commontype.FeeConfig, error) {
 
 // GetStoredFeeConfig returns fee config from contract storage in given state
 func GetStoredFeeConfig(stateDB StateDB) commontype.FeeConfig {
+	return readFeeConfig(stateDB, storedFeeConfigKey)
+}
+
+// storedFeeConfigKey returns the storage key of the fee config field [field].
+func storedFeeConfigKey(field int) common.Hash {
+	return common.Hash{byte(field)}
+}
+
+// pendingFeeConfigKey returns the storage key of the pending fee config field [field].
+func pendingFeeConfigKey(field int) common.Hash {
+	return common.Hash{pendingFeeConfigFieldKeyPrefix, byte(field)}
+}
+
+// readFeeConfig reads the fee config stored at the keys returned by [fieldKey] from [stateDB].
+func readFeeConfig(stateDB StateDB, fieldKey func(field int) common.Hash) commontype.FeeConfig {
 	feeConfig := commontype.FeeConfig{}
 	for i := minFeeConfigFieldKey; i <= numFeeConfigField; i++ {
-		val := stateDB.GetState(FeeConfigManagerAddress, common.Hash{byte(i)})
+		val := stateDB.GetState(FeeConfigManagerAddress, fieldKey(i))
 		switch i {
 		case gasLimitKey:
 			feeConfig.GasLimit = new(big.Int).Set(val.Big())
@@ -227,13 +388,99 @@ func GetFeeConfigLastChangedAt(stateDB StateDB) *big.Int {
 	return val.Big()
 }
 
+// GetPendingFeeConfig returns the fee config scheduled by setFeeConfigAt and its activation timestamp.
+// The activation timestamp is zero if there is no pending fee config.
+func GetPendingFeeConfig(stateDB StateDB) (commontype.FeeConfig, *big.Int) {
+	activationTimestamp := stateDB.GetState(FeeConfigManagerAddress, pendingFeeConfigActivationKey).Big()
+	return readFeeConfig(stateDB, pendingFeeConfigKey), activationTimestamp
+}
+
+// GetMinFeeConfigDelay returns the minimum delay in seconds between scheduling a fee config and its activation.
+func GetMinFeeConfigDelay(stateDB StateDB) uint64 {
+	minDelay := stateDB.GetState(FeeConfigManagerAddress, minFeeConfigDelayKey).Big().Uint64()
+	if minDelay == 0 {
+		return DefaultMinFeeConfigDelay
+	}
+	return minDelay
+}
+
+// IsFeeConfigTimeLocked returns true if a minimum fee config delay is configured in [stateDB], in which case
+// the fee config can only be changed through setFeeConfigAt.
+func IsFeeConfigTimeLocked(stateDB StateDB) bool {
+	return stateDB.GetState(FeeConfigManagerAddress, minFeeConfigDelayKey) != (common.Hash{})
+}
+
+// IsPendingFeeConfigActive returns true if a fee config pending with [activationTimestamp] applies to
+// the blocks built on a parent block with [parentTimestamp].
+// A pending fee config applies to the blocks built on a parent with a timestamp at or after its activation
+// timestamp, which matches how the fee config of a block is resolved from the state of its parent.
+func IsPendingFeeConfigActive(activationTimestamp *big.Int, parentTimestamp *big.Int) bool {
+	return activationTimestamp.Sign() != 0 && parentTimestamp != nil && activationTimestamp.Cmp(parentTimestamp) <= 0
+}
+
+// IsPendingFeeConfigApplied returns true if a fee config pending with [activationTimestamp] is active for the
+// blocks built on a parent block with [parentTimestamp] and the change from [storedFeeConfig] to [pendingFeeConfig]
+// is within the fee config bounds of the fee config manager config of [chainConfig] active at [parentTimestamp].
+// An active pending fee config that is not applied is dropped.
+func IsPendingFeeConfigApplied(chainConfig ChainConfig, storedFeeConfig commontype.FeeConfig, pendingFeeConfig commontype.FeeConfig, activationTimestamp *big.Int, parentTimestamp *big.Int) bool {
+	return IsPendingFeeConfigActive(activationTimestamp, parentTimestamp) &&
+		verifyFeeConfigBounds(chainConfig, parentTimestamp, storedFeeConfig, pendingFeeConfig) == nil
+}
+
+// ApplyPendingFeeConfig stores the pending fee config in [stateDB] as the fee config changed at the block in
+// [blockContext] if it is active for a block built on a parent with [parentTimestamp], and clears it.
+// The pending fee config is dropped instead if it violates the fee config bounds of the fee config manager
+// config of [chainConfig] active at [parentTimestamp], since the bounds may have changed since it was scheduled.
+// Note: this is called before the transactions of the block are processed, so no log is emitted: an applied
+// fee config is observed through the block number returned by getFeeConfigLastChangedAt, and a dropped fee
+// config through the cleared pending fee config.
+func ApplyPendingFeeConfig(chainConfig ChainConfig, stateDB StateDB, parentTimestamp *big.Int, blockContext BlockContext) {
+	pendingFeeConfig, activationTimestamp := GetPendingFeeConfig(stateDB)
+	if !IsPendingFeeConfigActive(activationTimestamp, parentTimestamp) {
+		return
+	}
+	clearPendingFeeConfig(stateDB)
+	if !IsPendingFeeConfigApplied(chainConfig, GetStoredFeeConfig(stateDB), pendingFeeConfig, activationTimestamp, parentTimestamp) {
+		return
+	}
+	if err := StoreFeeConfig(stateDB, pendingFeeConfig, blockContext); err != nil {
+		panic(fmt.Sprintf("pending fee config should have been verified in setFeeConfigAt: %s", err))
+	}
+}
+
 // StoreFeeConfig stores given [feeConfig] and block number in the [blockContext] to the [stateDB].
 // A validation on [feeConfig] is done before storing.
 func StoreFeeConfig(stateDB StateDB, feeConfig commontype.FeeConfig, blockContext BlockContext) error {
 	if err := feeConfig.Verify(); err != nil {
 		return err
 	}
+	writeFeeConfig(stateDB, feeConfig, storedFeeConfigKey)
 
+	blockNumber := blockContext.Number()
+	if blockNumber == nil {
+		return fmt.Errorf("blockNumber cannot be nil")
+	}
+	stateDB.SetState(FeeConfigManagerAddress, feeConfigLastChangedAtKey, common.BigToHash(blockNumber))
+
+	return nil
+}
+
+// storePendingFeeConfig schedules [feeConfig] to be activated at [activationTimestamp], replacing any pending fee config.
+func storePendingFeeConfig(stateDB StateDB, feeConfig commontype.FeeConfig, activationTimestamp *big.Int) {
+	writeFeeConfig(stateDB, feeConfig, pendingFeeConfigKey)
+	stateDB.SetState(FeeConfigManagerAddress, pendingFeeConfigActivationKey, common.BigToHash(activationTimestamp))
+}
+
+// clearPendingFeeConfig removes the pending fee config from [stateDB].
+func clearPendingFeeConfig(stateDB StateDB) {
+	for i := minFeeConfigFieldKey; i <= numFeeConfigField; i++ {
+		stateDB.SetState(FeeConfigManagerAddress, pendingFeeConfigKey(i), common.Hash{})
+	}
+	stateDB.SetState(FeeConfigManagerAddress, pendingFeeConfigActivationKey, common.Hash{})
+}
+
+// writeFeeConfig writes [feeConfig] to the keys returned by [fieldKey] in [stateDB].
+func writeFeeConfig(stateDB StateDB, feeConfig commontype.FeeConfig, fieldKey func(field int) common.Hash) {
 	for i := minFeeConfigFieldKey; i <= numFeeConfigField; i++ {
 		var input common.Hash
 		switch i {
@@ -256,22 +503,35 @@ func StoreFeeConfig(stateDB StateDB, feeConfig commontype.FeeConfig, blockContex
 		default:
 			panic(fmt.Sprintf("unknown fee config key: %d", i))
 		}
-		stateDB.SetState(FeeConfigManagerAddress, common.Hash{byte(i)}, input)
+		stateDB.SetState(FeeConfigManagerAddress, fieldKey(i), input)
 	}
+}
 
-	blockNumber := blockContext.Number()
-	if blockNumber == nil {
-		return fmt.Errorf("blockNumber cannot be nil")
+// verifyFeeConfigChange returns an error if [feeConfig] is invalid or violates the fee config bounds of
+// the fee config manager config active at the block of [accessibleState], in which case the change from
+// the currently stored fee config is bounded as well.
+func verifyFeeConfigChange(accessibleState PrecompileAccessibleState, feeConfig commontype.FeeConfig) error {
+	if err := feeConfig.Verify(); err != nil {
+		return err
 	}
-	stateDB.SetState(FeeConfigManagerAddress, feeConfigLastChangedAtKey, common.BigToHash(blockNumber))
+	return verifyFeeConfigBounds(accessibleState.GetChainConfig(), accessibleState.GetBlockContext().Timestamp(), GetStoredFeeConfig(accessibleState.GetStateDB()), feeConfig)
+}
 
-	return nil
+// verifyFeeConfigBounds returns an error if the change from [storedFeeConfig] to [feeConfig] violates the fee
+// config bounds of the fee config manager config of [chainConfig] active at [timestamp].
+func verifyFeeConfigBounds(chainConfig ChainConfig, timestamp *big.Int, storedFeeConfig commontype.FeeConfig, feeConfig commontype.FeeConfig) error {
+	config, ok := chainConfig.GetActivePrecompileConfig(FeeConfigManagerAddress, timestamp).(*FeeConfigManagerConfig)
+	if !ok || config.FeeConfigBounds == nil {
+		return nil
+	}
+	return config.FeeConfigBounds.VerifyChange(storedFeeConfig, feeConfig)
 }
 
 // setFeeConfig checks if the caller has permissions to set the fee config.
 // The execution function parses [input] into FeeConfig structure and sets contract storage accordingly.
+// Once the PrecompileV2 upgrade is activated, it is rejected if a minimum fee config delay is configured.
 func setFeeConfig(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
-	if remainingGas, err = deductGas(suppliedGas, SetFeeConfigGasCost); err != nil {
+	if remainingGas, err = deductGas(suppliedGas, getGasCost(accessibleState, FeeConfigManagerAddress, "setFeeConfig", upgradedGasCost(accessibleState, SetFeeConfigGasCost, LegacySetFeeConfigGasCost))); err != nil {
 		return nil, 0, err
 	}
 
@@ -290,11 +550,33 @@ func setFeeConfig(accessibleState PrecompileAccessibleState, caller common.Addre
 	if !callerStatus.IsEnabled() {
 		return nil, remainingGas, fmt.Errorf("%w: %s", ErrCannotChangeFee, caller)
 	}
+	if err := verifyMultisigCaller(accessibleState, FeeConfigManagerAddress, caller); err != nil {
+		return nil, remainingGas, err
+	}
+	if isPrecompileV2(accessibleState) && IsFeeConfigTimeLocked(stateDB) {
+		return nil, remainingGas, fmt.Errorf("%w: use setFeeConfigAt", ErrFeeConfigTimeLocked)
+	}
 
-	if err := StoreFeeConfig(stateDB, feeConfig, accessibleState.GetBlockContext()); err != nil {
+	if err := verifyFeeConfigChange(accessibleState, feeConfig); err != nil {
 		return nil, remainingGas, err
 	}
 
+	blockContext := accessibleState.GetBlockContext()
+	if err := StoreFeeConfig(stateDB, feeConfig, blockContext); err != nil {
+		return nil, remainingGas, err
+	}
+	if isPrecompileV2(accessibleState) {
+		stateDB.AddLog(
+			FeeConfigManagerAddress,
+			[]common.Hash{
+				FeeConfigChangedEventID,
+				caller.Hash(),
+			},
+			packFeeConfigHelper(feeConfig, false),
+			blockContext.Number().Uint64(),
+		)
+	}
+
 	// Return an empty output and the remaining gas
 	return []byte{}, remainingGas, nil
 }
@@ -302,7 +584,7 @@ func setFeeConfig(accessibleState PrecompileAccessibleState, caller common.Addre
 // getFeeConfig returns the stored fee config as an output.
 // The execution function reads the contract state for the stored fee config and returns the output.
 func getFeeConfig(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
-	if remainingGas, err = deductGas(suppliedGas, GetFeeConfigGasCost); err != nil {
+	if remainingGas, err = deductGas(suppliedGas, getGasCost(accessibleState, FeeConfigManagerAddress, "getFeeConfig", GetFeeConfigGasCost)); err != nil {
 		return nil, 0, err
 	}
 
@@ -320,7 +602,7 @@ func getFeeConfig(accessibleState PrecompileAccessibleState, caller common.Addre
 // getFeeConfigLastChangedAt returns the block number that fee config was last changed in.
 // The execution function reads the contract state for the stored block number and returns the output.
 func getFeeConfigLastChangedAt(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
-	if remainingGas, err = deductGas(suppliedGas, GetLastChangedAtGasCost); err != nil {
+	if remainingGas, err = deductGas(suppliedGas, getGasCost(accessibleState, FeeConfigManagerAddress, "getFeeConfigLastChangedAt", GetLastChangedAtGasCost)); err != nil {
 		return nil, 0, err
 	}
 
@@ -330,6 +612,116 @@ func getFeeConfigLastChangedAt(accessibleState PrecompileAccessibleState, caller
 	return common.BigToHash(lastChangedAt).Bytes(), remainingGas, err
 }
 
+// setFeeConfigAt checks if the caller has permissions to set the fee config.
+// The execution function parses [input] into a FeeConfig structure and an activation timestamp, and schedules
+// the fee config to be activated at the activation timestamp, replacing any pending fee config.
+// The activation timestamp must be at least the minimum fee config delay after the current block timestamp.
+func setFeeConfigAt(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = deductGas(suppliedGas, getGasCost(accessibleState, FeeConfigManagerAddress, "setFeeConfigAt", SetFeeConfigAtGasCost)); err != nil {
+		return nil, 0, err
+	}
+
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+
+	feeConfig, activationTimestamp, err := UnpackPendingFeeConfig(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	// Verify that the caller is in the allow list and therefore has the right to modify it
+	callerStatus := getAllowListStatus(stateDB, FeeConfigManagerAddress, caller)
+	if !callerStatus.IsEnabled() {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrCannotChangeFee, caller)
+	}
+	if err := verifyMultisigCaller(accessibleState, FeeConfigManagerAddress, caller); err != nil {
+		return nil, remainingGas, err
+	}
+
+	// Note: the change is bounded relative to the fee config stored when it is scheduled.
+	if err := verifyFeeConfigChange(accessibleState, feeConfig); err != nil {
+		return nil, remainingGas, err
+	}
+	if !activationTimestamp.IsUint64() {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrInvalidFeeConfigActivationTime, activationTimestamp)
+	}
+	blockContext := accessibleState.GetBlockContext()
+	earliestActivation := new(big.Int).Add(blockContext.Timestamp(), new(big.Int).SetUint64(GetMinFeeConfigDelay(stateDB)))
+	if activationTimestamp.Cmp(earliestActivation) < 0 {
+		return nil, remainingGas, fmt.Errorf("%w: %s is before %s", ErrFeeConfigActivationTooEarly, activationTimestamp, earliestActivation)
+	}
+
+	storePendingFeeConfig(stateDB, feeConfig, activationTimestamp)
+	stateDB.AddLog(
+		FeeConfigManagerAddress,
+		[]common.Hash{
+			FeeConfigScheduledEventID,
+			caller.Hash(),
+		},
+		packPendingFeeConfig(feeConfig, activationTimestamp),
+		blockContext.Number().Uint64(),
+	)
+
+	// Return an empty output and the remaining gas
+	return []byte{}, remainingGas, nil
+}
+
+// getPendingFeeConfig returns the pending fee config followed by its activation timestamp as an output.
+// The output is all zeros if there is no pending fee config.
+func getPendingFeeConfig(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = deductGas(suppliedGas, getGasCost(accessibleState, FeeConfigManagerAddress, "getPendingFeeConfig", GetPendingFeeConfigGasCost)); err != nil {
+		return nil, 0, err
+	}
+
+	feeConfig, activationTimestamp := GetPendingFeeConfig(accessibleState.GetStateDB())
+
+	// Return the pending fee config as output and the remaining gas
+	return packPendingFeeConfig(feeConfig, activationTimestamp), remainingGas, nil
+}
+
+// cancelPendingFeeConfig checks if the caller has permissions to set the fee config.
+// The execution function removes the pending fee config and returns an error if there is none.
+func cancelPendingFeeConfig(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = deductGas(suppliedGas, getGasCost(accessibleState, FeeConfigManagerAddress, "cancelPendingFeeConfig", CancelPendingFeeConfigGasCost)); err != nil {
+		return nil, 0, err
+	}
+
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	// Verify that the caller is in the allow list and therefore has the right to modify it
+	callerStatus := getAllowListStatus(stateDB, FeeConfigManagerAddress, caller)
+	if !callerStatus.IsEnabled() {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrCannotChangeFee, caller)
+	}
+	if err := verifyMultisigCaller(accessibleState, FeeConfigManagerAddress, caller); err != nil {
+		return nil, remainingGas, err
+	}
+
+	_, activationTimestamp := GetPendingFeeConfig(stateDB)
+	if activationTimestamp.Sign() == 0 {
+		return nil, remainingGas, ErrNoPendingFeeConfig
+	}
+
+	clearPendingFeeConfig(stateDB)
+	stateDB.AddLog(
+		FeeConfigManagerAddress,
+		[]common.Hash{
+			PendingFeeConfigCancelledEventID,
+			caller.Hash(),
+		},
+		common.BigToHash(activationTimestamp).Bytes(),
+		accessibleState.GetBlockContext().Number().Uint64(),
+	)
+
+	// Return an empty output and the remaining gas
+	return []byte{}, remainingGas, nil
+}
+
 // createFeeConfigManagerPrecompile returns a StatefulPrecompiledContract
 // with getters and setters for the chain's fee config. Access to the getters/setters
 // is controlled by an allow list for [precompileAddr].
@@ -339,8 +731,12 @@ func createFeeConfigManagerPrecompile(precompileAddr common.Address) StatefulPre
 	setFeeConfigFunc := newStatefulPrecompileFunction(setFeeConfigSignature, setFeeConfig)
 	getFeeConfigFunc := newStatefulPrecompileFunction(getFeeConfigSignature, getFeeConfig)
 	getFeeConfigLastChangedAtFunc := newStatefulPrecompileFunction(getFeeConfigLastChangedAtSignature, getFeeConfigLastChangedAt)
+	setFeeConfigAtFunc := activatedByPrecompileV2(newStatefulPrecompileFunction(setFeeConfigAtSignature, setFeeConfigAt))
+	getPendingFeeConfigFunc := activatedByPrecompileV2(newStatefulPrecompileFunction(getPendingFeeConfigSignature, getPendingFeeConfig))
+	cancelPendingFeeConfigFunc := activatedByPrecompileV2(newStatefulPrecompileFunction(cancelPendingFeeConfigSignature, cancelPendingFeeConfig))
 
-	feeConfigManagerFunctions = append(feeConfigManagerFunctions, setFeeConfigFunc, getFeeConfigFunc, getFeeConfigLastChangedAtFunc)
+	feeConfigManagerFunctions = append(feeConfigManagerFunctions, setFeeConfigFunc, getFeeConfigFunc, getFeeConfigLastChangedAtFunc,
+		setFeeConfigAtFunc, getPendingFeeConfigFunc, cancelPendingFeeConfigFunc)
 	// Construct the contract with no fallback function.
 	contract := newStatefulPrecompileWithFunctionSelectors(nil, feeConfigManagerFunctions)
 	return contract
@@ -0,0 +1,137 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/commontype"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type testBlockContext struct {
+	number    *big.Int
+	timestamp *big.Int
+}
+
+func (b *testBlockContext) Number() *big.Int    { return b.number }
+func (b *testBlockContext) Timestamp() *big.Int { return b.timestamp }
+
+var testPendingFeeConfig = commontype.FeeConfig{
+	GasLimit:                 big.NewInt(8_000_000),
+	TargetBlockRate:          2,
+	MinBaseFee:               big.NewInt(25_000_000_000),
+	TargetGas:                big.NewInt(15_000_000),
+	BaseFeeChangeDenominator: big.NewInt(36),
+	MinBlockGasCost:          big.NewInt(0),
+	MaxBlockGasCost:          big.NewInt(1_000_000),
+	BlockGasCostStep:         big.NewInt(200_000),
+}
+
+func TestPackUnpackPendingFeeConfig(t *testing.T) {
+	input, err := PackSetFeeConfigAt(testPendingFeeConfig, big.NewInt(1234))
+	assert.NoError(t, err)
+	assert.Equal(t, setFeeConfigAtSignature, input[:selectorLen])
+
+	feeConfig, activationTimestamp, err := UnpackPendingFeeConfig(input[selectorLen:])
+	assert.NoError(t, err)
+	// compare the packed fee configs since unpacked zero values have a different internal representation
+	assert.Equal(t, packFeeConfigHelper(testPendingFeeConfig, false), packFeeConfigHelper(feeConfig, false))
+	assert.Equal(t, uint64(1234), activationTimestamp.Uint64())
+
+	_, _, err = UnpackPendingFeeConfig(input[selectorLen+1:])
+	assert.Error(t, err)
+}
+
+func TestUnpackSetFeeConfigInput(t *testing.T) {
+	input, err := PackSetFeeConfig(testPendingFeeConfig)
+	assert.NoError(t, err)
+
+	feeConfig, err := UnpackSetFeeConfigInput(input)
+	assert.NoError(t, err)
+	assert.Equal(t, packFeeConfigHelper(testPendingFeeConfig, false), packFeeConfigHelper(feeConfig, false))
+
+	input, err = PackSetFeeConfigAt(testPendingFeeConfig, big.NewInt(1234))
+	assert.NoError(t, err)
+	_, err = UnpackSetFeeConfigInput(input)
+	assert.Error(t, err)
+
+	_, err = UnpackSetFeeConfigInput(setFeeConfigSignature[:selectorLen-1])
+	assert.Error(t, err)
+}
+
+// testChainConfig implements the fee config manager config lookup of ChainConfig with [feeManagerConfig]
+// active at every timestamp.
+type testChainConfig struct {
+	ChainConfig
+	feeManagerConfig *FeeConfigManagerConfig
+}
+
+func (c *testChainConfig) GetActivePrecompileConfig(common.Address, *big.Int) StatefulPrecompileConfig {
+	return c.feeManagerConfig
+}
+
+// testLogStateDB records the topics of the logs added to the storage of mockStorageStateDB.
+type testLogStateDB struct {
+	*mockStorageStateDB
+	logs [][]common.Hash
+}
+
+func (s *testLogStateDB) AddLog(_ common.Address, topics []common.Hash, _ []byte, _ uint64) {
+	s.logs = append(s.logs, topics)
+}
+
+func TestApplyPendingFeeConfig(t *testing.T) {
+	state := &testLogStateDB{mockStorageStateDB: newMockStorageStateDB()}
+	chainConfig := &testChainConfig{feeManagerConfig: &FeeConfigManagerConfig{}}
+	storePendingFeeConfig(state, testPendingFeeConfig, big.NewInt(100))
+
+	// not applied before the parent timestamp reaches the activation timestamp
+	ApplyPendingFeeConfig(chainConfig, state, big.NewInt(99), &testBlockContext{number: big.NewInt(5), timestamp: big.NewInt(101)})
+	assert.Equal(t, 0, GetFeeConfigLastChangedAt(state).Sign())
+	_, activationTimestamp := GetPendingFeeConfig(state)
+	assert.Equal(t, big.NewInt(100), activationTimestamp)
+	assert.Empty(t, state.logs)
+
+	ApplyPendingFeeConfig(chainConfig, state, big.NewInt(100), &testBlockContext{number: big.NewInt(6), timestamp: big.NewInt(102)})
+	assert.Equal(t, testPendingFeeConfig, GetStoredFeeConfig(state))
+	assert.Equal(t, big.NewInt(6), GetFeeConfigLastChangedAt(state))
+	_, activationTimestamp = GetPendingFeeConfig(state)
+	assert.Equal(t, 0, activationTimestamp.Sign())
+	assert.False(t, IsPendingFeeConfigActive(activationTimestamp, big.NewInt(200)))
+	// the change is only observed through the state, since no transaction receipt exists for it
+	assert.Empty(t, state.logs)
+}
+
+func TestApplyPendingFeeConfigOutOfBounds(t *testing.T) {
+	state := &testLogStateDB{mockStorageStateDB: newMockStorageStateDB()}
+	assert.NoError(t, StoreFeeConfig(state, testPendingFeeConfig, &testBlockContext{number: big.NewInt(1)}))
+	feeConfig := testPendingFeeConfig
+	feeConfig.GasLimit = big.NewInt(10_000_000)
+	storePendingFeeConfig(state, feeConfig, big.NewInt(100))
+
+	// the bounds were tightened after the fee config was scheduled
+	chainConfig := &testChainConfig{feeManagerConfig: &FeeConfigManagerConfig{
+		FeeConfigBounds: &FeeConfigBounds{GasLimit: &FeeConfigFieldBounds{MaxDelta: big.NewInt(1_000_000)}},
+	}}
+	assert.True(t, IsPendingFeeConfigActive(big.NewInt(100), big.NewInt(100)))
+	assert.False(t, IsPendingFeeConfigApplied(chainConfig, testPendingFeeConfig, feeConfig, big.NewInt(100), big.NewInt(100)))
+
+	ApplyPendingFeeConfig(chainConfig, state, big.NewInt(100), &testBlockContext{number: big.NewInt(6), timestamp: big.NewInt(102)})
+	assert.Equal(t, testPendingFeeConfig, GetStoredFeeConfig(state))
+	assert.Equal(t, big.NewInt(1), GetFeeConfigLastChangedAt(state))
+	_, activationTimestamp := GetPendingFeeConfig(state)
+	assert.Equal(t, 0, activationTimestamp.Sign())
+	assert.Empty(t, state.logs)
+}
+
+func TestGetMinFeeConfigDelay(t *testing.T) {
+	state := newMockStorageStateDB()
+	assert.Equal(t, DefaultMinFeeConfigDelay, GetMinFeeConfigDelay(state))
+
+	state.SetState(FeeConfigManagerAddress, minFeeConfigDelayKey, common.BigToHash(big.NewInt(10)))
+	assert.Equal(t, uint64(10), GetMinFeeConfigDelay(state))
+}
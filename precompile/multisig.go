@@ -0,0 +1,419 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Every allow list precompile can execute privileged actions approved by a threshold of approvers
+// through proposals. A proposal holds the input of a call to the precompile itself, which is executed
+// with the precompile as the caller once it is approved by [MultisigConfig.Threshold] approvers.
+// The precompile is made an admin of its own allow list when a multisig is configured, so that
+// approved proposals pass the allow list checks of the privileged functions.
+// Once a multisig is configured, minting and fee config changes are only accepted from approved
+// proposals, and configuring another multisig replaces the approvers and expires the pending proposals.
+var (
+	// ProposalCreatedEventID is the first topic of the log emitted by propose. The remaining topics are
+	// the proposal ID and the proposer and the data of the log is the ABI encoded action of the proposal.
+	ProposalCreatedEventID = crypto.Keccak256Hash([]byte("ProposalCreated(uint256,address,bytes)"))
+	// ProposalApprovedEventID is the first topic of the log emitted by approve. The remaining topics are
+	// the proposal ID and the approver.
+	ProposalApprovedEventID = crypto.Keccak256Hash([]byte("ProposalApproved(uint256,address)"))
+	// ProposalExecutedEventID is the first topic of the log emitted by execute. The remaining topics are
+	// the proposal ID and the executor.
+	ProposalExecutedEventID = crypto.Keccak256Hash([]byte("ProposalExecuted(uint256,address)"))
+
+	ErrInvalidMultisigConfig     = errors.New("invalid multisig config")
+	ErrNotApprover               = errors.New("non-approver cannot propose, approve or execute")
+	ErrInvalidProposalAction     = errors.New("invalid proposal action")
+	ErrProposalNotFound          = errors.New("proposal not found")
+	ErrProposalAlreadyApproved   = errors.New("proposal already approved by approver")
+	ErrProposalAlreadyExecuted   = errors.New("proposal already executed")
+	ErrProposalThresholdNotMet   = errors.New("proposal does not have enough approvals")
+	ErrProposalPrecompileUnknown = errors.New("no precompile registered at proposal address")
+	ErrProposalExpired           = errors.New("proposal was created for a previous multisig")
+	ErrMultisigApprovalRequired  = errors.New("action requires a proposal approved by the multisig")
+
+	multisigThresholdKey        = crypto.Keccak256Hash([]byte("multisigThreshold"))
+	multisigProposalNonceKey    = crypto.Keccak256Hash([]byte("multisigProposalNonce"))
+	multisigProposalFloorKey    = crypto.Keccak256Hash([]byte("multisigProposalFloor"))
+	multisigApproverCountKey    = crypto.Keccak256Hash([]byte("multisigApproverCount"))
+	multisigApproverPrefix      = []byte("multisigApprover")
+	multisigApproverListPrefix  = []byte("multisigApproverList")
+	multisigProposalPrefix      = []byte("multisigProposal")
+	multisigProposalApproval    = []byte("multisigProposalApproval")
+	multisigProposalActionChunk = []byte("multisigProposalActionChunk")
+
+	// fields of a proposal
+	proposalActionLengthField = []byte("actionLength")
+	proposalApprovalsField    = []byte("approvals")
+	proposalExecutedField     = []byte("executed")
+)
+
+// MultisigConfig specifies the approvers of the proposals to an allow list precompile and the number
+// of approvals required to execute a proposal.
+type MultisigConfig struct {
+	Approvers []common.Address `json:"approvers"`
+	Threshold uint64           `json:"threshold"`
+}
+
+// Configure replaces the approvers and threshold in the state of [precompileAddr] with those of [c] and
+// makes [precompileAddr] an admin of its own allow list to execute approved proposals.
+// The proposals created before are expired, since they were approved by the previous approvers.
+func (c *MultisigConfig) Configure(state StateDB, precompileAddr common.Address) {
+	state.SetState(precompileAddr, multisigThresholdKey, common.BigToHash(new(big.Int).SetUint64(c.Threshold)))
+	count := state.GetState(precompileAddr, multisigApproverCountKey).Big().Uint64()
+	for i := uint64(0); i < count; i++ {
+		listKey := multisigApproverListKey(i)
+		approver := common.BytesToAddress(state.GetState(precompileAddr, listKey).Bytes())
+		state.SetState(precompileAddr, multisigApproverKey(approver), common.Hash{})
+		state.SetState(precompileAddr, listKey, common.Hash{})
+	}
+	for i, approver := range c.Approvers {
+		state.SetState(precompileAddr, multisigApproverKey(approver), common.BigToHash(common.Big1))
+		state.SetState(precompileAddr, multisigApproverListKey(uint64(i)), approver.Hash())
+	}
+	state.SetState(precompileAddr, multisigApproverCountKey, common.BigToHash(new(big.Int).SetUint64(uint64(len(c.Approvers)))))
+	state.SetState(precompileAddr, multisigProposalFloorKey, state.GetState(precompileAddr, multisigProposalNonceKey))
+	setAllowListRole(state, precompileAddr, precompileAddr, AllowListAdmin)
+}
+
+// Verify returns an error if [c] has duplicate approvers or a threshold that is zero or
+// greater than the number of approvers.
+func (c *MultisigConfig) Verify() error {
+	approvers := make(map[common.Address]struct{}, len(c.Approvers))
+	for _, approver := range c.Approvers {
+		if _, ok := approvers[approver]; ok {
+			return fmt.Errorf("%w: duplicate approver %s", ErrInvalidMultisigConfig, approver)
+		}
+		approvers[approver] = struct{}{}
+	}
+	if c.Threshold == 0 || c.Threshold > uint64(len(c.Approvers)) {
+		return fmt.Errorf("%w: threshold %d must be between 1 and the number of approvers %d", ErrInvalidMultisigConfig, c.Threshold, len(c.Approvers))
+	}
+	return nil
+}
+
+// Equal returns true if [c] and [other] specify the same approvers and threshold.
+func (c *MultisigConfig) Equal(other *MultisigConfig) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+	if c.Threshold != other.Threshold || len(c.Approvers) != len(other.Approvers) {
+		return false
+	}
+	for i, approver := range c.Approvers {
+		if approver != other.Approvers[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// multisigApproverKey returns the storage key of the approver flag of [approver].
+func multisigApproverKey(approver common.Address) common.Hash {
+	return crypto.Keccak256Hash(multisigApproverPrefix, approver.Bytes())
+}
+
+// multisigApproverListKey returns the storage key of the [index]-th approver.
+func multisigApproverListKey(index uint64) common.Hash {
+	return crypto.Keccak256Hash(multisigApproverListPrefix, common.BigToHash(new(big.Int).SetUint64(index)).Bytes())
+}
+
+// proposalFieldKey returns the storage key of [field] of the proposal with [proposalID].
+func proposalFieldKey(proposalID *big.Int, field []byte) common.Hash {
+	return crypto.Keccak256Hash(multisigProposalPrefix, common.BigToHash(proposalID).Bytes(), field)
+}
+
+// proposalApprovalKey returns the storage key of the approval of [approver] for the proposal with [proposalID].
+func proposalApprovalKey(proposalID *big.Int, approver common.Address) common.Hash {
+	return crypto.Keccak256Hash(multisigProposalApproval, common.BigToHash(proposalID).Bytes(), approver.Bytes())
+}
+
+// proposalActionChunkKey returns the storage key of the 32 byte chunk at [index] of the action of the
+// proposal with [proposalID].
+func proposalActionChunkKey(proposalID *big.Int, index int) common.Hash {
+	return crypto.Keccak256Hash(multisigProposalActionChunk, common.BigToHash(proposalID).Bytes(), common.BigToHash(big.NewInt(int64(index))).Bytes())
+}
+
+// IsMultisigApprover returns true if [address] is an approver of the proposals to [precompileAddr].
+func IsMultisigApprover(state StateDB, precompileAddr common.Address, address common.Address) bool {
+	return state.GetState(precompileAddr, multisigApproverKey(address)) != (common.Hash{})
+}
+
+// GetMultisigThreshold returns the number of approvals required to execute a proposal to [precompileAddr].
+func GetMultisigThreshold(state StateDB, precompileAddr common.Address) uint64 {
+	return state.GetState(precompileAddr, multisigThresholdKey).Big().Uint64()
+}
+
+// IsMultisigConfigured returns true if a multisig approves the proposals to [precompileAddr].
+func IsMultisigConfigured(state StateDB, precompileAddr common.Address) bool {
+	return GetMultisigThreshold(state, precompileAddr) != 0
+}
+
+// verifyMultisigCaller returns an error if a multisig is configured for [precompileAddr] once the PrecompileV2
+// upgrade is activated and [callerAddr] is not [precompileAddr] executing an approved proposal.
+// The privileged functions of the precompiles call it after checking the allow list role of [callerAddr].
+func verifyMultisigCaller(evm PrecompileAccessibleState, precompileAddr common.Address, callerAddr common.Address) error {
+	if callerAddr == precompileAddr || !isPrecompileV2(evm) || !IsMultisigConfigured(evm.GetStateDB(), precompileAddr) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s cannot call %s directly", ErrMultisigApprovalRequired, callerAddr, precompileAddr)
+}
+
+// Proposal is an action proposed to an allow list precompile.
+type Proposal struct {
+	Action    []byte
+	Approvals uint64
+	Executed  bool
+}
+
+// GetProposal returns the proposal to [precompileAddr] with [proposalID] and false if it does not exist.
+func GetProposal(state StateDB, precompileAddr common.Address, proposalID *big.Int) (Proposal, bool) {
+	if !proposalExists(state, precompileAddr, proposalID) {
+		return Proposal{}, false
+	}
+	return Proposal{
+		Action:    getProposalAction(state, precompileAddr, proposalID),
+		Approvals: state.GetState(precompileAddr, proposalFieldKey(proposalID, proposalApprovalsField)).Big().Uint64(),
+		Executed:  state.GetState(precompileAddr, proposalFieldKey(proposalID, proposalExecutedField)) != (common.Hash{}),
+	}, true
+}
+
+// proposalExists returns true if [proposalID] was assigned to a proposal to [precompileAddr].
+// Proposal IDs are assigned sequentially starting from 1.
+func proposalExists(state StateDB, precompileAddr common.Address, proposalID *big.Int) bool {
+	nonce := state.GetState(precompileAddr, multisigProposalNonceKey).Big()
+	return proposalID.Sign() > 0 && proposalID.Cmp(nonce) <= 0
+}
+
+// getProposalAction returns the action of the proposal with [proposalID], which is stored in 32 byte chunks.
+func getProposalAction(state StateDB, precompileAddr common.Address, proposalID *big.Int) []byte {
+	length := state.GetState(precompileAddr, proposalFieldKey(proposalID, proposalActionLengthField)).Big().Uint64()
+	action := make([]byte, 0, length+common.HashLength)
+	for i := 0; uint64(len(action)) < length; i++ {
+		action = append(action, state.GetState(precompileAddr, proposalActionChunkKey(proposalID, i)).Bytes()...)
+	}
+	return action[:length]
+}
+
+// storeProposalAction stores [action] as the action of the proposal with [proposalID] in 32 byte chunks.
+func storeProposalAction(state StateDB, precompileAddr common.Address, proposalID *big.Int, action []byte) {
+	state.SetState(precompileAddr, proposalFieldKey(proposalID, proposalActionLengthField), common.BigToHash(big.NewInt(int64(len(action)))))
+	for i := 0; i*common.HashLength < len(action); i++ {
+		chunk := common.RightPadBytes(action[i*common.HashLength:], common.HashLength)[:common.HashLength]
+		state.SetState(precompileAddr, proposalActionChunkKey(proposalID, i), common.BytesToHash(chunk))
+	}
+}
+
+// actionWords returns the number of 32 byte words of [action].
+func actionWords(action []byte) uint64 {
+	return (uint64(len(action)) + common.HashLength - 1) / common.HashLength
+}
+
+// PackPropose packs [action] into the input data to the propose function
+func PackPropose(action []byte) ([]byte, error) {
+	return AllowListABI.Pack("propose", action)
+}
+
+// PackApprove packs [proposalID] into the input data to the approve function
+func PackApprove(proposalID *big.Int) ([]byte, error) {
+	return AllowListABI.Pack("approve", proposalID)
+}
+
+// PackExecute packs [proposalID] into the input data to the execute function
+func PackExecute(proposalID *big.Int) ([]byte, error) {
+	return AllowListABI.Pack("execute", proposalID)
+}
+
+// PackGetProposal packs [proposalID] into the input data to the getProposal function
+func PackGetProposal(proposalID *big.Int) ([]byte, error) {
+	return AllowListABI.Pack("getProposal", proposalID)
+}
+
+// createPropose returns an execution function that stores the action in the input as a new proposal to
+// [precompileAddr] approved by the caller, and returns its proposal ID. The caller must be an approver.
+func createPropose(precompileAddr common.Address) RunStatefulPrecompileABIFunc {
+	return func(evm PrecompileAccessibleState, callerAddr common.Address, addr common.Address, args []interface{}, suppliedGas uint64, readOnly bool) (outputs []interface{}, remainingGas uint64, err error) {
+		action := args[0].([]byte)
+		if remainingGas, err = deductGas(suppliedGas, getGasCost(evm, precompileAddr, "propose", ProposeGasCost)); err != nil {
+			return nil, 0, err
+		}
+		if remainingGas, err = deductGas(remainingGas, actionWords(action)*ProposalActionGasCostPerWord); err != nil {
+			return nil, 0, err
+		}
+
+		if readOnly {
+			return nil, remainingGas, vmerrs.ErrWriteProtection
+		}
+
+		if len(action) < selectorLen {
+			return nil, remainingGas, fmt.Errorf("%w: action must start with a function selector", ErrInvalidProposalAction)
+		}
+		stateDB := evm.GetStateDB()
+		if !IsMultisigApprover(stateDB, precompileAddr, callerAddr) {
+			return nil, remainingGas, fmt.Errorf("%w: %s", ErrNotApprover, callerAddr)
+		}
+
+		proposalID := new(big.Int).Add(stateDB.GetState(precompileAddr, multisigProposalNonceKey).Big(), common.Big1)
+		stateDB.SetState(precompileAddr, multisigProposalNonceKey, common.BigToHash(proposalID))
+		storeProposalAction(stateDB, precompileAddr, proposalID, action)
+		stateDB.SetState(precompileAddr, proposalApprovalKey(proposalID, callerAddr), common.BigToHash(common.Big1))
+		stateDB.SetState(precompileAddr, proposalFieldKey(proposalID, proposalApprovalsField), common.BigToHash(common.Big1))
+
+		data, err := AllowListABI.Events["ProposalCreated"].Inputs.NonIndexed().Pack(action)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		stateDB.AddLog(
+			precompileAddr,
+			[]common.Hash{
+				ProposalCreatedEventID,
+				common.BigToHash(proposalID),
+				callerAddr.Hash(),
+			},
+			data,
+			evm.GetBlockContext().Number().Uint64(),
+		)
+		return []interface{}{proposalID}, remainingGas, nil
+	}
+}
+
+// createApprove returns an execution function that records the approval of the caller for the proposal
+// to [precompileAddr] with the proposal ID in the input. The caller must be an approver.
+func createApprove(precompileAddr common.Address) RunStatefulPrecompileABIFunc {
+	return func(evm PrecompileAccessibleState, callerAddr common.Address, addr common.Address, args []interface{}, suppliedGas uint64, readOnly bool) (outputs []interface{}, remainingGas uint64, err error) {
+		if remainingGas, err = deductGas(suppliedGas, getGasCost(evm, precompileAddr, "approve", ApproveGasCost)); err != nil {
+			return nil, 0, err
+		}
+
+		if readOnly {
+			return nil, remainingGas, vmerrs.ErrWriteProtection
+		}
+
+		proposalID := args[0].(*big.Int)
+		stateDB := evm.GetStateDB()
+		if err := verifyPendingProposal(stateDB, precompileAddr, callerAddr, proposalID); err != nil {
+			return nil, remainingGas, err
+		}
+		approvalKey := proposalApprovalKey(proposalID, callerAddr)
+		if stateDB.GetState(precompileAddr, approvalKey) != (common.Hash{}) {
+			return nil, remainingGas, fmt.Errorf("%w: %s approved proposal %s", ErrProposalAlreadyApproved, callerAddr, proposalID)
+		}
+
+		approvalsKey := proposalFieldKey(proposalID, proposalApprovalsField)
+		approvals := new(big.Int).Add(stateDB.GetState(precompileAddr, approvalsKey).Big(), common.Big1)
+		stateDB.SetState(precompileAddr, approvalKey, common.BigToHash(common.Big1))
+		stateDB.SetState(precompileAddr, approvalsKey, common.BigToHash(approvals))
+		stateDB.AddLog(
+			precompileAddr,
+			[]common.Hash{
+				ProposalApprovedEventID,
+				common.BigToHash(proposalID),
+				callerAddr.Hash(),
+			},
+			nil,
+			evm.GetBlockContext().Number().Uint64(),
+		)
+		return nil, remainingGas, nil
+	}
+}
+
+// createExecute returns an execution function that executes the action of the proposal to [precompileAddr]
+// with the proposal ID in the input, once it has been approved by the threshold of approvers. The action is
+// executed as a call from [precompileAddr] to itself with the remaining gas and its output is returned.
+// The caller must be an approver.
+func createExecute(precompileAddr common.Address) RunStatefulPrecompileABIFunc {
+	return func(evm PrecompileAccessibleState, callerAddr common.Address, addr common.Address, args []interface{}, suppliedGas uint64, readOnly bool) (outputs []interface{}, remainingGas uint64, err error) {
+		if remainingGas, err = deductGas(suppliedGas, getGasCost(evm, precompileAddr, "execute", ExecuteGasCost)); err != nil {
+			return nil, 0, err
+		}
+
+		if readOnly {
+			return nil, remainingGas, vmerrs.ErrWriteProtection
+		}
+
+		proposalID := args[0].(*big.Int)
+		stateDB := evm.GetStateDB()
+		if err := verifyPendingProposal(stateDB, precompileAddr, callerAddr, proposalID); err != nil {
+			return nil, remainingGas, err
+		}
+		approvals := stateDB.GetState(precompileAddr, proposalFieldKey(proposalID, proposalApprovalsField)).Big().Uint64()
+		if threshold := GetMultisigThreshold(stateDB, precompileAddr); approvals < threshold {
+			return nil, remainingGas, fmt.Errorf("%w: proposal %s has %d of %d approvals", ErrProposalThresholdNotMet, proposalID, approvals, threshold)
+		}
+		action := getProposalAction(stateDB, precompileAddr, proposalID)
+		if remainingGas, err = deductGas(remainingGas, actionWords(action)*ProposalReadGasCostPerWord); err != nil {
+			return nil, 0, err
+		}
+		module, ok := GetModuleByAddress(precompileAddr)
+		if !ok {
+			return nil, remainingGas, fmt.Errorf("%w: %s", ErrProposalPrecompileUnknown, precompileAddr)
+		}
+
+		// Mark the proposal as executed before executing its action, so that it cannot be executed again.
+		stateDB.SetState(precompileAddr, proposalFieldKey(proposalID, proposalExecutedField), common.BigToHash(common.Big1))
+		ret, remainingGas, err := module.NewConfig().Contract().Run(evm, precompileAddr, precompileAddr, action, remainingGas, readOnly)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		stateDB.AddLog(
+			precompileAddr,
+			[]common.Hash{
+				ProposalExecutedEventID,
+				common.BigToHash(proposalID),
+				callerAddr.Hash(),
+			},
+			nil,
+			evm.GetBlockContext().Number().Uint64(),
+		)
+		return []interface{}{ret}, remainingGas, nil
+	}
+}
+
+// verifyPendingProposal returns an error if [callerAddr] is not an approver of the proposals to [precompileAddr]
+// or the proposal with [proposalID] does not exist, was created before the current multisig was configured or
+// has already been executed.
+func verifyPendingProposal(stateDB StateDB, precompileAddr common.Address, callerAddr common.Address, proposalID *big.Int) error {
+	if !IsMultisigApprover(stateDB, precompileAddr, callerAddr) {
+		return fmt.Errorf("%w: %s", ErrNotApprover, callerAddr)
+	}
+	if !proposalExists(stateDB, precompileAddr, proposalID) {
+		return fmt.Errorf("%w: %s", ErrProposalNotFound, proposalID)
+	}
+	if proposalID.Cmp(stateDB.GetState(precompileAddr, multisigProposalFloorKey).Big()) <= 0 {
+		return fmt.Errorf("%w: %s", ErrProposalExpired, proposalID)
+	}
+	if stateDB.GetState(precompileAddr, proposalFieldKey(proposalID, proposalExecutedField)) != (common.Hash{}) {
+		return fmt.Errorf("%w: %s", ErrProposalAlreadyExecuted, proposalID)
+	}
+	return nil
+}
+
+// createGetProposal returns an execution function that returns the action, number of approvals and execution
+// status of the proposal to [precompileAddr] with the proposal ID in the input.
+func createGetProposal(precompileAddr common.Address) RunStatefulPrecompileABIFunc {
+	return func(evm PrecompileAccessibleState, callerAddr common.Address, addr common.Address, args []interface{}, suppliedGas uint64, readOnly bool) (outputs []interface{}, remainingGas uint64, err error) {
+		if remainingGas, err = deductGas(suppliedGas, getGasCost(evm, precompileAddr, "getProposal", GetProposalGasCost)); err != nil {
+			return nil, 0, err
+		}
+
+		proposalID := args[0].(*big.Int)
+		proposal, ok := GetProposal(evm.GetStateDB(), precompileAddr, proposalID)
+		if !ok {
+			return nil, remainingGas, fmt.Errorf("%w: %s", ErrProposalNotFound, proposalID)
+		}
+		if remainingGas, err = deductGas(remainingGas, actionWords(proposal.Action)*ProposalReadGasCostPerWord); err != nil {
+			return nil, 0, err
+		}
+		return []interface{}{proposal.Action, new(big.Int).SetUint64(proposal.Approvals), proposal.Executed}, remainingGas, nil
+	}
+}
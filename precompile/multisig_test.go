@@ -0,0 +1,88 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultisigConfigVerify(t *testing.T) {
+	tests := map[string]struct {
+		config      MultisigConfig
+		expectedErr error
+	}{
+		"valid config": {
+			config: MultisigConfig{Approvers: []common.Address{{1}, {2}}, Threshold: 2},
+		},
+		"zero threshold": {
+			config:      MultisigConfig{Approvers: []common.Address{{1}}, Threshold: 0},
+			expectedErr: ErrInvalidMultisigConfig,
+		},
+		"threshold greater than approvers": {
+			config:      MultisigConfig{Approvers: []common.Address{{1}}, Threshold: 2},
+			expectedErr: ErrInvalidMultisigConfig,
+		},
+		"duplicate approvers": {
+			config:      MultisigConfig{Approvers: []common.Address{{1}, {1}}, Threshold: 1},
+			expectedErr: ErrInvalidMultisigConfig,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := (&AllowListConfig{Multisig: &test.config}).Verify()
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestMultisigConfigure(t *testing.T) {
+	var (
+		state          = newMockStorageStateDB()
+		precompileAddr = ContractNativeMinterAddress
+		config         = &AllowListConfig{Multisig: &MultisigConfig{Approvers: []common.Address{{1}, {2}}, Threshold: 2}}
+	)
+	config.Configure(state, precompileAddr)
+	assert.True(t, IsMultisigApprover(state, precompileAddr, common.Address{1}))
+	assert.True(t, IsMultisigApprover(state, precompileAddr, common.Address{2}))
+	assert.False(t, IsMultisigApprover(state, precompileAddr, common.Address{3}))
+	assert.Equal(t, uint64(2), GetMultisigThreshold(state, precompileAddr))
+	// the precompile is an admin of its own allow list to execute approved proposals
+	assert.Equal(t, AllowListAdmin, getAllowListStatus(state, precompileAddr, precompileAddr))
+	assert.True(t, IsMultisigConfigured(state, precompileAddr))
+
+	// the approvers are replaced when the multisig is configured again
+	state.SetState(precompileAddr, multisigProposalNonceKey, common.BigToHash(big.NewInt(3)))
+	config = &AllowListConfig{Multisig: &MultisigConfig{Approvers: []common.Address{{2}, {3}}, Threshold: 1}}
+	config.Configure(state, precompileAddr)
+	assert.False(t, IsMultisigApprover(state, precompileAddr, common.Address{1}))
+	assert.True(t, IsMultisigApprover(state, precompileAddr, common.Address{2}))
+	assert.True(t, IsMultisigApprover(state, precompileAddr, common.Address{3}))
+	assert.Equal(t, uint64(1), GetMultisigThreshold(state, precompileAddr))
+	// the proposals created for the previous approvers are expired
+	assert.ErrorIs(t, verifyPendingProposal(state, precompileAddr, common.Address{2}, big.NewInt(3)), ErrProposalExpired)
+}
+
+func TestProposalAction(t *testing.T) {
+	var (
+		state          = newMockStorageStateDB()
+		precompileAddr = ContractNativeMinterAddress
+	)
+	for i, action := range [][]byte{
+		{1, 2, 3, 4},
+		common.LeftPadBytes([]byte{1}, common.HashLength),
+		append(common.LeftPadBytes([]byte{1}, common.HashLength), 2),
+	} {
+		proposalID := big.NewInt(int64(i + 1))
+		storeProposalAction(state, precompileAddr, proposalID, action)
+		assert.Equal(t, action, getProposalAction(state, precompileAddr, proposalID))
+	}
+}
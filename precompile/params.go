@@ -4,8 +4,6 @@
 package precompile
 
 import (
-	"fmt"
-
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -14,14 +12,69 @@ const (
 	writeGasCostPerSlot = 20_000
 	readGasCostPerSlot  = 5_000
 
-	ModifyAllowListGasCost = writeGasCostPerSlot
+	// Gas costs of emitting a log, matching the LOG opcodes of the EVM
+	logGasCost            = 375
+	logTopicGasCost       = 375
+	logDataGasCostPerByte = 8
+
+	// DynamicInputGasCostPerWord is charged for each 32 bytes of the input of the functions with dynamic
+	// arguments before their input is unpacked and repacked, matching the copy cost of the EVM.
+	DynamicInputGasCostPerWord = 3
+
+	RoleSetEventGasCost          = logGasCost + 4*logTopicGasCost                                           // event ID, role, account and sender topics
+	NativeCoinMintedEventGasCost = logGasCost + 3*logTopicGasCost + common.HashLength*logDataGasCostPerByte // event ID, sender and recipient topics + amount
+	FeeConfigChangedEventGasCost = logGasCost + 2*logTopicGasCost + feeConfigInputLen*logDataGasCostPerByte // event ID and sender topics + fee config
+
+	// AllowListIndexGasCost is the upper bound of the cost of moving an address between the member indexes
+	// of its previous and new role, which allocates up to 2 slots and updates or clears up to 6 slots.
+	AllowListIndexGasCost = 2*writeGasCostPerSlot + 6*readGasCostPerSlot
+
+	ModifyAllowListGasCost = writeGasCostPerSlot + AllowListIndexGasCost + RoleSetEventGasCost
 	ReadAllowListGasCost   = readGasCostPerSlot
 
-	MintGasCost = 30_000
+	ProposalCreatedEventGasCost  = logGasCost + 3*logTopicGasCost + 2*common.HashLength*logDataGasCostPerByte // event ID, proposal ID and proposer topics + offset and length of the action
+	ProposalApprovedEventGasCost = logGasCost + 3*logTopicGasCost                                             // event ID, proposal ID and approver topics
+	ProposalExecutedEventGasCost = logGasCost + 3*logTopicGasCost                                             // event ID, proposal ID and executor topics
 
-	SetFeeConfigGasCost     = writeGasCostPerSlot * (numFeeConfigField + 1) // plus one for setting last changed at
+	ProposeGasCost               = 2*readGasCostPerSlot + 4*writeGasCostPerSlot + ProposalCreatedEventGasCost  // read approver and nonce + write nonce, action length, approval and approval count
+	ProposalActionGasCostPerWord = writeGasCostPerSlot + common.HashLength*logDataGasCostPerByte               // store and log each 32 bytes of the action
+	ApproveGasCost               = 5*readGasCostPerSlot + 2*writeGasCostPerSlot + ProposalApprovedEventGasCost // read approver, nonce, executed, approval and approval count + write approval and approval count
+	ExecuteGasCost               = 6*readGasCostPerSlot + writeGasCostPerSlot + ProposalExecutedEventGasCost   // read approver, nonce, executed, approval count, threshold and action length + write executed
+	GetProposalGasCost           = 4 * readGasCostPerSlot                                                      // nonce, action length, approval count and executed
+	ProposalReadGasCostPerWord   = readGasCostPerSlot                                                          // read each 32 bytes of the action
+
+	TotalMintedGasCost        = readGasCostPerSlot
+	RemainingAllowanceGasCost = 4 * readGasCostPerSlot                                                                    // max supply, total minted and allowance of the minter
+	MintGasCost               = 30_000 + RemainingAllowanceGasCost + 2*writeGasCostPerSlot + NativeCoinMintedEventGasCost // check limits + update total minted and allowance
+
+	SetFeeConfigGasCost     = readGasCostPerSlot*numFeeConfigField + writeGasCostPerSlot*(numFeeConfigField+1) + FeeConfigChangedEventGasCost // read current fee config to bound the change + write fee config plus one for setting last changed at
 	GetFeeConfigGasCost     = readGasCostPerSlot * numFeeConfigField
 	GetLastChangedAtGasCost = readGasCostPerSlot
+
+	// Gas costs charged before the PrecompileV2 upgrade, which does not emit logs, index the members
+	// of allow lists, account for minted coins or bound fee config changes.
+	LegacyModifyAllowListGasCost = writeGasCostPerSlot
+	LegacyMintGasCost            = 30_000
+	LegacySetFeeConfigGasCost    = writeGasCostPerSlot * (numFeeConfigField + 1) // plus one for setting last changed at
+
+	FeeConfigScheduledEventGasCost        = logGasCost + 2*logTopicGasCost + pendingFeeConfigLen*logDataGasCostPerByte // event ID and sender topics + fee config and activation timestamp
+	PendingFeeConfigCancelledEventGasCost = logGasCost + 2*logTopicGasCost + common.HashLength*logDataGasCostPerByte   // event ID and sender topics + activation timestamp
+
+	SetFeeConfigAtGasCost         = readGasCostPerSlot*(numFeeConfigField+1) + writeGasCostPerSlot*(numFeeConfigField+1) + FeeConfigScheduledEventGasCost // read current fee config to bound the change and min delay + write pending fee config and activation timestamp
+	GetPendingFeeConfigGasCost    = readGasCostPerSlot * (numFeeConfigField + 1)                                                                          // pending fee config and activation timestamp
+	CancelPendingFeeConfigGasCost = readGasCostPerSlot + writeGasCostPerSlot*(numFeeConfigField+1) + PendingFeeConfigCancelledEventGasCost                // read and clear pending fee config and activation timestamp
+
+	SendWarpMessageGasCost                    = 20_000
+	SendWarpMessageGasCostPerByte             = 8 // matches the cost per byte of log data
+	GetVerifiedWarpMessageBaseGasCost         = 200_000
+	GetVerifiedWarpMessageGasCostPerByte      = 8
+	GetVerifiedWarpMessagePerValidatorGasCost = 500
+
+	AllowFeeRecipientsGasCost      = writeGasCostPerSlot + ReadAllowListGasCost // write reward address + read allow list
+	SetRewardAddressGasCost        = writeGasCostPerSlot + ReadAllowListGasCost
+	DisableRewardsGasCost          = writeGasCostPerSlot + ReadAllowListGasCost
+	AreFeeRecipientsAllowedGasCost = readGasCostPerSlot
+	CurrentRewardAddressGasCost    = readGasCostPerSlot
 )
 
 // Designated addresses of stateful precompiles
@@ -40,13 +93,9 @@ var (
 	ContractNativeMinterAddress      = common.HexToAddress("0x0200000000000000000000000000000000000001")
 	TxAllowListAddress               = common.HexToAddress("0x0200000000000000000000000000000000000002")
 	FeeConfigManagerAddress          = common.HexToAddress("0x0200000000000000000000000000000000000003")
+	WarpMessengerAddress             = common.HexToAddress("0x0200000000000000000000000000000000000004")
+	RewardManagerAddress             = common.HexToAddress("0x0200000000000000000000000000000000000005")
 
-	UsedAddresses = []common.Address{
-		ContractDeployerAllowListAddress,
-		ContractNativeMinterAddress,
-		TxAllowListAddress,
-		FeeConfigManagerAddress,
-	}
 	reservedRanges = []AddressRange{
 		{
 			common.HexToAddress("0x0100000000000000000000000000000000000000"),
@@ -73,12 +122,3 @@ func ReservedAddress(addr common.Address) bool {
 
 	return false
 }
-
-func init() {
-	// Ensure that every address used by a precompile is in a reserved range.
-	for _, addr := range UsedAddresses {
-		if !ReservedAddress(addr) {
-			panic(fmt.Errorf("address %s used for stateful precompile but not specified in any reserved range", addr))
-		}
-	}
-}
@@ -0,0 +1,121 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ava-labs/subnet-evm/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// registeredModules is the list of registered precompile modules sorted by address.
+	registeredModules = make([]Module, 0)
+
+	errInvalidModule = errors.New("invalid precompile module")
+
+	ErrInvalidGasOverride = errors.New("invalid gas override")
+)
+
+// Module describes a stateful precompile that can be enabled as a network upgrade.
+// Each precompile registers its Module in the init function of its package, after which
+// the chain config handles its activation, (un)marshalling and verification.
+// The contract executed at [Address] is returned by the Contract method of the enabled config,
+// since it may depend on the parameters of the config.
+type Module struct {
+	// ConfigKey is the key used in the genesis and upgrade JSON to specify the config of this precompile.
+	ConfigKey string
+	// Address is the address where the stateful precompile is accessible.
+	// It must be in one of the reserved ranges and must not be used by any other module.
+	Address common.Address
+	// NewConfig returns an empty config for this precompile to unmarshal JSON into.
+	NewConfig func() StatefulPrecompileConfig
+	// GasCosts are the default gas costs of the functions of this precompile keyed by function name.
+	// Only the functions listed here can have their gas cost overridden by the configs of this precompile.
+	GasCosts map[string]uint64
+	// ABI is the interface of this precompile, including the functions of its allow list if it has one.
+	// It is not used to execute the precompile, but allows tooling such as tracers to decode calls to it.
+	ABI abi.ABI
+}
+
+// VerifyGasOverrides returns an error if [overrides] specifies a function that is not listed in the
+// gas costs of [m] or overrides the gas cost of a function with zero.
+func (m Module) VerifyGasOverrides(overrides map[string]uint64) error {
+	for function, cost := range overrides {
+		if _, ok := m.GasCosts[function]; !ok {
+			return fmt.Errorf("%w: unknown function %q of %s", ErrInvalidGasOverride, function, m.ConfigKey)
+		}
+		if cost == 0 {
+			return fmt.Errorf("%w: zero gas cost for function %q of %s", ErrInvalidGasOverride, function, m.ConfigKey)
+		}
+	}
+	return nil
+}
+
+// RegisterModule registers [module] so that it can be enabled through the chain config.
+// Returns an error if the config key or address of [module] is already registered or if the
+// address is not in a reserved range.
+// Note: modules are kept sorted by address, which determines the order in which
+// precompiles are configured.
+func RegisterModule(module Module) error {
+	switch {
+	case module.ConfigKey == "":
+		return fmt.Errorf("%w: empty config key", errInvalidModule)
+	case module.NewConfig == nil:
+		return fmt.Errorf("%w: nil config constructor for %s", errInvalidModule, module.ConfigKey)
+	case !ReservedAddress(module.Address):
+		return fmt.Errorf("address %s used for stateful precompile but not specified in any reserved range", module.Address)
+	}
+
+	for _, registered := range registeredModules {
+		if registered.ConfigKey == module.ConfigKey {
+			return fmt.Errorf("name %s already used by a stateful precompile", module.ConfigKey)
+		}
+		if registered.Address == module.Address {
+			return fmt.Errorf("address %s already used by a stateful precompile", module.Address)
+		}
+	}
+
+	registeredModules = append(registeredModules, module)
+	sort.Slice(registeredModules, func(i, j int) bool {
+		return bytes.Compare(registeredModules[i].Address[:], registeredModules[j].Address[:]) < 0
+	})
+	return nil
+}
+
+// GetModule returns the module registered with [configKey] and true if it exists.
+func GetModule(configKey string) (Module, bool) {
+	for _, module := range registeredModules {
+		if module.ConfigKey == configKey {
+			return module, true
+		}
+	}
+	return Module{}, false
+}
+
+// GetModuleByAddress returns the module registered at [address] and true if it exists.
+func GetModuleByAddress(address common.Address) (Module, bool) {
+	for _, module := range registeredModules {
+		if module.Address == address {
+			return module, true
+		}
+	}
+	return Module{}, false
+}
+
+// RegisteredModules returns the registered modules sorted by address.
+func RegisteredModules() []Module {
+	return registeredModules
+}
+
+// mustRegisterModule registers [module] and panics if it is invalid.
+func mustRegisterModule(module Module) {
+	if err := RegisterModule(module); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,190 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisteredModules(t *testing.T) {
+	modules := RegisteredModules()
+	assert.Len(t, modules, 6)
+	// modules are sorted by address to configure them in a deterministic order
+	for i := 1; i < len(modules); i++ {
+		assert.True(t, modules[i-1].Address.Hash().Big().Cmp(modules[i].Address.Hash().Big()) < 0)
+	}
+
+	module, ok := GetModule(TxAllowListConfigKey)
+	assert.True(t, ok)
+	assert.Equal(t, TxAllowListAddress, module.Address)
+	assert.IsType(t, &TxAllowListConfig{}, module.NewConfig())
+
+	module, ok = GetModuleByAddress(FeeConfigManagerAddress)
+	assert.True(t, ok)
+	assert.Equal(t, FeeConfigManagerConfigKey, module.ConfigKey)
+
+	_, ok = GetModule("unknownConfig")
+	assert.False(t, ok)
+}
+
+func TestRegisterModule(t *testing.T) {
+	newConfig := func() StatefulPrecompileConfig { return &TxAllowListConfig{} }
+	for name, test := range map[string]struct {
+		module      Module
+		expectedErr string
+	}{
+		"empty config key": {
+			module:      Module{Address: common.HexToAddress("0x0300000000000000000000000000000000000000"), NewConfig: newConfig},
+			expectedErr: "empty config key",
+		},
+		"nil config constructor": {
+			module:      Module{ConfigKey: "testConfig", Address: common.HexToAddress("0x0300000000000000000000000000000000000000")},
+			expectedErr: "nil config constructor",
+		},
+		"address not reserved": {
+			module:      Module{ConfigKey: "testConfig", Address: common.HexToAddress("0x0400000000000000000000000000000000000000"), NewConfig: newConfig},
+			expectedErr: "not specified in any reserved range",
+		},
+		"duplicate config key": {
+			module:      Module{ConfigKey: TxAllowListConfigKey, Address: common.HexToAddress("0x0300000000000000000000000000000000000000"), NewConfig: newConfig},
+			expectedErr: "already used",
+		},
+		"duplicate address": {
+			module:      Module{ConfigKey: "testConfig", Address: TxAllowListAddress, NewConfig: newConfig},
+			expectedErr: "already used",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := RegisterModule(test.module)
+			assert.ErrorContains(t, err, test.expectedErr)
+		})
+	}
+	assert.Len(t, RegisteredModules(), 6)
+}
+
+func TestVerifyGasOverrides(t *testing.T) {
+	module, ok := GetModule(ContractNativeMinterConfigKey)
+	assert.True(t, ok)
+	for name, test := range map[string]struct {
+		overrides   map[string]uint64
+		expectedErr string
+	}{
+		"no overrides": {},
+		"precompile function": {
+			overrides: map[string]uint64{"mintNativeCoin": 1_000},
+		},
+		"allow list function": {
+			overrides: map[string]uint64{"setEnabled": 1_000, "readAllowList": 10},
+		},
+		"unknown function": {
+			overrides:   map[string]uint64{"setFeeConfig": 1_000},
+			expectedErr: "unknown function",
+		},
+		"zero gas cost": {
+			overrides:   map[string]uint64{"mintNativeCoin": 0},
+			expectedErr: "zero gas cost",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := module.VerifyGasOverrides(test.overrides)
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrInvalidGasOverride)
+			assert.ErrorContains(t, err, test.expectedErr)
+		})
+	}
+
+	// every function of each precompile has a default gas cost
+	for _, module := range RegisteredModules() {
+		assert.NotEmpty(t, module.GasCosts, module.ConfigKey)
+		for function, cost := range module.GasCosts {
+			assert.NotZero(t, cost, "%s.%s", module.ConfigKey, function)
+		}
+	}
+}
+
+func TestUpgradeableConfigEqualGasOverrides(t *testing.T) {
+	config := &UpgradeableConfig{BlockTimestamp: common.Big1, GasOverrides: map[string]uint64{"setAdmin": 1}}
+	assert.True(t, config.Equal(&UpgradeableConfig{BlockTimestamp: common.Big1, GasOverrides: map[string]uint64{"setAdmin": 1}}))
+	assert.False(t, config.Equal(&UpgradeableConfig{BlockTimestamp: common.Big1, GasOverrides: map[string]uint64{"setAdmin": 2}}))
+	assert.False(t, config.Equal(&UpgradeableConfig{BlockTimestamp: common.Big1, GasOverrides: map[string]uint64{"setNone": 1}}))
+	assert.False(t, config.Equal(&UpgradeableConfig{BlockTimestamp: common.Big1}))
+}
+
+func TestEffectiveStorageMode(t *testing.T) {
+	tests := map[string]struct {
+		config   StatefulPrecompileConfig
+		expected StorageMode
+	}{
+		"enable by default": {
+			config:   NewTxAllowListConfig(common.Big1, nil),
+			expected: StorageModePreserve,
+		},
+		"disable by default": {
+			config:   NewDisableTxAllowListConfig(common.Big1),
+			expected: StorageModeWipe,
+		},
+		"enable with wipe": {
+			config:   &TxAllowListConfig{UpgradeableConfig: UpgradeableConfig{BlockTimestamp: common.Big1, StorageMode: StorageModeWipe}},
+			expected: StorageModeWipe,
+		},
+		"disable with preserve": {
+			config:   &TxAllowListConfig{UpgradeableConfig: UpgradeableConfig{BlockTimestamp: common.Big1, Disable: true, StorageMode: StorageModePreserve}},
+			expected: StorageModePreserve,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, EffectiveStorageMode(test.config))
+		})
+	}
+
+	config := &UpgradeableConfig{BlockTimestamp: common.Big1, StorageMode: StorageModeWipe}
+	assert.True(t, config.Equal(&UpgradeableConfig{BlockTimestamp: common.Big1, StorageMode: StorageModeWipe}))
+	assert.False(t, config.Equal(&UpgradeableConfig{BlockTimestamp: common.Big1}))
+	assert.False(t, StorageMode("keep").Valid())
+}
+
+func TestModuleABIs(t *testing.T) {
+	for _, module := range RegisteredModules() {
+		// every function with a gas cost must be part of the ABI of the module
+		for function := range module.GasCosts {
+			_, ok := module.ABI.Methods[function]
+			assert.True(t, ok, "missing function %s in the ABI of %s", function, module.ConfigKey)
+		}
+	}
+
+	// the selectors of the ABIs must match the selectors the precompiles are executed with
+	for selector, method := range map[string]string{
+		string(setFeeConfigSignature):              "setFeeConfig",
+		string(getFeeConfigSignature):              "getFeeConfig",
+		string(getFeeConfigLastChangedAtSignature): "getFeeConfigLastChangedAt",
+		string(setFeeConfigAtSignature):            "setFeeConfigAt",
+		string(getPendingFeeConfigSignature):       "getPendingFeeConfig",
+		string(cancelPendingFeeConfigSignature):    "cancelPendingFeeConfig",
+	} {
+		assert.Equal(t, []byte(selector), FeeConfigManagerABI.Methods[method].ID, method)
+	}
+	for selector, method := range map[string]string{
+		string(allowFeeRecipientsSignature):      "allowFeeRecipients",
+		string(areFeeRecipientsAllowedSignature): "areFeeRecipientsAllowed",
+		string(currentRewardAddressSignature):    "currentRewardAddress",
+		string(disableRewardsSignature):          "disableRewards",
+		string(setRewardAddressSignature):        "setRewardAddress",
+	} {
+		assert.Equal(t, []byte(selector), RewardManagerABI.Methods[method].ID, method)
+	}
+
+	// the allow list functions are merged into the ABI of precompiles wired to an allow list
+	module, ok := GetModuleByAddress(ContractNativeMinterAddress)
+	assert.True(t, ok)
+	assert.Contains(t, module.ABI.Methods, "mintNativeCoin")
+	assert.Contains(t, module.ABI.Methods, "setAdmin")
+	assert.Contains(t, module.ABI.Events, "RoleSet")
+}
@@ -0,0 +1,368 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/constants"
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	setRewardAddressInputLen = common.HashLength
+)
+
+// RewardManagerRawABI is the ABI of the IRewardManager interface excluding the allow list functions
+const RewardManagerRawABI = `[
+	{"type":"function","name":"allowFeeRecipients","stateMutability":"nonpayable","inputs":[],"outputs":[]},
+	{"type":"function","name":"areFeeRecipientsAllowed","stateMutability":"view","inputs":[],"outputs":[{"name":"isAllowed","type":"bool"}]},
+	{"type":"function","name":"currentRewardAddress","stateMutability":"view","inputs":[],"outputs":[{"name":"rewardAddress","type":"address"}]},
+	{"type":"function","name":"disableRewards","stateMutability":"nonpayable","inputs":[],"outputs":[]},
+	{"type":"function","name":"setRewardAddress","stateMutability":"nonpayable","inputs":[{"name":"addr","type":"address"}],"outputs":[]}
+]`
+
+var (
+	_ StatefulPrecompileConfig = &RewardManagerConfig{}
+
+	RewardManagerABI = mustParseABI(RewardManagerRawABI)
+
+	// Singleton StatefulPrecompiledContract for managing the destination of block rewards by permissioned callers.
+	RewardManagerPrecompile StatefulPrecompiledContract = createRewardManagerPrecompile(RewardManagerAddress)
+
+	allowFeeRecipientsSignature      = CalculateFunctionSelector("allowFeeRecipients()")
+	areFeeRecipientsAllowedSignature = CalculateFunctionSelector("areFeeRecipientsAllowed()")
+	currentRewardAddressSignature    = CalculateFunctionSelector("currentRewardAddress()")
+	disableRewardsSignature          = CalculateFunctionSelector("disableRewards()")
+	setRewardAddressSignature        = CalculateFunctionSelector("setRewardAddress(address)")
+
+	// rewardAddressStorageKey stores either the address that receives block rewards or
+	// [allowFeeRecipientsAddressValue] if block producers may choose their own coinbase.
+	rewardAddressStorageKey        = common.Hash{'r', 'a', 's', 'k'}
+	allowFeeRecipientsAddressValue = common.Hash{'a', 'f', 'r', 'a', 'v'}
+
+	ErrCannotAllowFeeRecipients = errors.New("non-enabled cannot call allowFeeRecipients")
+	ErrCannotSetRewardAddress   = errors.New("non-enabled cannot call setRewardAddress")
+	ErrCannotDisableRewards     = errors.New("non-enabled cannot call disableRewards")
+	ErrCannotEnableBothRewards  = errors.New("cannot enable both fee recipients and reward address at the same time")
+	ErrEmptyRewardAddress       = errors.New("reward address cannot be empty")
+)
+
+// RewardManagerConfigKey is the key used in json config files to specify the RewardManagerConfig.
+const RewardManagerConfigKey = "rewardManagerConfig"
+
+func init() {
+	mustRegisterModule(Module{
+		ConfigKey: RewardManagerConfigKey,
+		Address:   RewardManagerAddress,
+		NewConfig: func() StatefulPrecompileConfig { return &RewardManagerConfig{} },
+		GasCosts: withAllowListGasCosts(map[string]uint64{
+			"allowFeeRecipients":      AllowFeeRecipientsGasCost,
+			"disableRewards":          DisableRewardsGasCost,
+			"setRewardAddress":        SetRewardAddressGasCost,
+			"areFeeRecipientsAllowed": AreFeeRecipientsAllowedGasCost,
+			"currentRewardAddress":    CurrentRewardAddressGasCost,
+		}),
+		ABI: withAllowListABI(RewardManagerABI),
+	})
+}
+
+// InitialRewardConfig specifies how block rewards are distributed when the reward manager is activated.
+// If neither field is set, block rewards are burned.
+type InitialRewardConfig struct {
+	AllowFeeRecipients bool           `json:"allowFeeRecipients"`
+	RewardAddress      common.Address `json:"rewardAddress,omitempty"`
+}
+
+// Verify returns an error if [c] both allows fee recipients and specifies a reward address.
+func (c *InitialRewardConfig) Verify() error {
+	if c.AllowFeeRecipients && c.RewardAddress != (common.Address{}) {
+		return ErrCannotEnableBothRewards
+	}
+	return nil
+}
+
+// Equal returns true iff [other] specifies the same reward configuration as [c].
+func (c *InitialRewardConfig) Equal(other *InitialRewardConfig) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+	return c.AllowFeeRecipients == other.AllowFeeRecipients && c.RewardAddress == other.RewardAddress
+}
+
+// Configure stores the reward configuration specified by [c] in [state].
+func (c *InitialRewardConfig) Configure(state StateDB) {
+	switch {
+	case c.AllowFeeRecipients:
+		EnableAllowFeeRecipients(state)
+	case c.RewardAddress == (common.Address{}):
+		DisableFeeRewards(state)
+	default:
+		StoreRewardAddress(state, c.RewardAddress)
+	}
+}
+
+// RewardManagerConfig wraps [AllowListConfig] and uses it to implement the StatefulPrecompileConfig
+// interface while adding in the RewardManager specific precompile address.
+type RewardManagerConfig struct {
+	AllowListConfig // Config for the reward manager allow list
+	UpgradeableConfig
+	InitialRewardConfig *InitialRewardConfig `json:"initialRewardConfig,omitempty"`
+}
+
+// NewRewardManagerConfig returns a config for a network upgrade at [blockTimestamp] that enables
+// RewardManager with the given [admins] as members of the allowlist and [initialRewardConfig]
+// as the initial reward configuration.
+func NewRewardManagerConfig(blockTimestamp *big.Int, admins []common.Address, initialRewardConfig *InitialRewardConfig) *RewardManagerConfig {
+	return &RewardManagerConfig{
+		AllowListConfig:     AllowListConfig{AllowListAdmins: admins},
+		UpgradeableConfig:   UpgradeableConfig{BlockTimestamp: blockTimestamp},
+		InitialRewardConfig: initialRewardConfig,
+	}
+}
+
+// NewDisableRewardManagerConfig returns config for a network upgrade at [blockTimestamp]
+// that disables RewardManager.
+func NewDisableRewardManagerConfig(blockTimestamp *big.Int) *RewardManagerConfig {
+	return &RewardManagerConfig{
+		UpgradeableConfig: UpgradeableConfig{
+			BlockTimestamp: blockTimestamp,
+			Disable:        true,
+		},
+	}
+}
+
+// Address returns the address of the reward manager contract.
+func (c *RewardManagerConfig) Address() common.Address {
+	return RewardManagerAddress
+}
+
+// Equal returns true if [s] is a [*RewardManagerConfig] and it has been configured identical to [c].
+func (c *RewardManagerConfig) Equal(s StatefulPrecompileConfig) bool {
+	// typecast before comparison
+	other, ok := (s).(*RewardManagerConfig)
+	if !ok {
+		return false
+	}
+	return c.UpgradeableConfig.Equal(&other.UpgradeableConfig) &&
+		c.AllowListConfig.Equal(&other.AllowListConfig) &&
+		c.InitialRewardConfig.Equal(other.InitialRewardConfig)
+}
+
+// Verify returns an error if [c] specifies an invalid initial reward configuration.
+func (c *RewardManagerConfig) Verify() error {
+	if err := c.AllowListConfig.Verify(); err != nil {
+		return err
+	}
+	if c.InitialRewardConfig != nil {
+		return c.InitialRewardConfig.Verify()
+	}
+	return nil
+}
+
+// Configure configures [state] with the desired admins and reward configuration based on [c].
+// If [c] does not specify an initial reward configuration, the behavior of the chain config is
+// preserved: fee recipients are allowed if [chainConfig] allows them, otherwise rewards are burned.
+func (c *RewardManagerConfig) Configure(chainConfig ChainConfig, state StateDB, _ BlockContext) {
+	c.AllowListConfig.Configure(state, RewardManagerAddress)
+	switch {
+	case c.InitialRewardConfig != nil:
+		c.InitialRewardConfig.Configure(state)
+	case chainConfig.AllowedFeeRecipients():
+		EnableAllowFeeRecipients(state)
+	default:
+		DisableFeeRewards(state)
+	}
+}
+
+// Reconfigure adds the admins and enabled addresses of [c] to the existing allow list and applies
+// [InitialRewardConfig] if it is specified, otherwise the current reward configuration is kept.
+func (c *RewardManagerConfig) Reconfigure(_ ChainConfig, state StateDB, _ BlockContext) {
+	c.AllowListConfig.Configure(state, RewardManagerAddress)
+	if c.InitialRewardConfig != nil {
+		c.InitialRewardConfig.Configure(state)
+	}
+}
+
+// Contract returns the singleton stateful precompiled contract to be used for the reward manager.
+func (c *RewardManagerConfig) Contract() StatefulPrecompiledContract {
+	return RewardManagerPrecompile
+}
+
+// GetRewardManagerAllowListStatus returns the role of [address] for the reward manager list.
+func GetRewardManagerAllowListStatus(stateDB StateDB, address common.Address) AllowListRole {
+	return getAllowListStatus(stateDB, RewardManagerAddress, address)
+}
+
+// SetRewardManagerAllowListStatus sets the permissions of [address] to [role] for the
+// reward manager list. assumes [role] has already been verified as valid.
+func SetRewardManagerAllowListStatus(stateDB StateDB, address common.Address, role AllowListRole) {
+	setAllowListRole(stateDB, RewardManagerAddress, address, role)
+}
+
+// PackAllowFeeRecipients packs the allowFeeRecipients signature
+func PackAllowFeeRecipients() []byte {
+	return allowFeeRecipientsSignature
+}
+
+// PackAreFeeRecipientsAllowed packs the areFeeRecipientsAllowed signature
+func PackAreFeeRecipientsAllowed() []byte {
+	return areFeeRecipientsAllowedSignature
+}
+
+// PackCurrentRewardAddress packs the currentRewardAddress signature
+func PackCurrentRewardAddress() []byte {
+	return currentRewardAddressSignature
+}
+
+// PackDisableRewards packs the disableRewards signature
+func PackDisableRewards() []byte {
+	return disableRewardsSignature
+}
+
+// PackSetRewardAddress packs [address] with the selector into the input to setRewardAddress.
+func PackSetRewardAddress(address common.Address) ([]byte, error) {
+	// function selector (4 bytes) + input(hash for address)
+	res := make([]byte, selectorLen+setRewardAddressInputLen)
+	packOrderedHashesWithSelector(res, setRewardAddressSignature, []common.Hash{address.Hash()})
+	return res, nil
+}
+
+// UnpackSetRewardAddressInput attempts to unpack [input] into the argument to setRewardAddress.
+// assumes that [input] does not include selector (omits first 4 bytes in PackSetRewardAddress)
+func UnpackSetRewardAddressInput(input []byte) (common.Address, error) {
+	if len(input) != setRewardAddressInputLen {
+		return common.Address{}, fmt.Errorf("invalid input length for setRewardAddress: %d", len(input))
+	}
+	return common.BytesToAddress(input), nil
+}
+
+// GetStoredRewardAddress returns the address that should receive the block rewards according to
+// [stateDB] and whether fee recipients are allowed, in which case the returned address is empty.
+func GetStoredRewardAddress(stateDB StateDB) (common.Address, bool) {
+	val := stateDB.GetState(RewardManagerAddress, rewardAddressStorageKey)
+	if val == allowFeeRecipientsAddressValue {
+		return common.Address{}, true
+	}
+	return common.BytesToAddress(val.Bytes()), false
+}
+
+// StoreRewardAddress stores [rewardAddress] as the address receiving the block rewards.
+func StoreRewardAddress(stateDB StateDB, rewardAddress common.Address) {
+	stateDB.SetState(RewardManagerAddress, rewardAddressStorageKey, rewardAddress.Hash())
+}
+
+// EnableAllowFeeRecipients allows block producers to claim the block rewards by setting
+// the coinbase of their blocks.
+func EnableAllowFeeRecipients(stateDB StateDB) {
+	stateDB.SetState(RewardManagerAddress, rewardAddressStorageKey, allowFeeRecipientsAddressValue)
+}
+
+// DisableFeeRewards burns the block rewards by sending them to the blackhole address.
+func DisableFeeRewards(stateDB StateDB) {
+	StoreRewardAddress(stateDB, constants.BlackholeAddr)
+}
+
+// createRewardSetter returns an execution function [function] that checks if the caller is enabled in the
+// reward manager allow list and then calls [setter] to update the reward configuration.
+// Returns [errNotEnabled] if the caller is not enabled.
+func createRewardSetter(function string, gasCost uint64, errNotEnabled error, setter func(stateDB StateDB, input []byte) error) RunStatefulPrecompileFunc {
+	return func(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		if remainingGas, err = deductGas(suppliedGas, getGasCost(accessibleState, RewardManagerAddress, function, gasCost)); err != nil {
+			return nil, 0, err
+		}
+
+		if readOnly {
+			return nil, remainingGas, vmerrs.ErrWriteProtection
+		}
+
+		stateDB := accessibleState.GetStateDB()
+		// Verify that the caller is in the allow list and therefore has the right to modify it
+		callerStatus := getAllowListStatus(stateDB, RewardManagerAddress, caller)
+		if !callerStatus.IsEnabled() {
+			return nil, remainingGas, fmt.Errorf("%w: %s", errNotEnabled, caller)
+		}
+
+		if err := setter(stateDB, input); err != nil {
+			return nil, remainingGas, err
+		}
+
+		// Return an empty output and the remaining gas
+		return []byte{}, remainingGas, nil
+	}
+}
+
+// allowFeeRecipients allows block producers to claim the block rewards.
+func allowFeeRecipients(stateDB StateDB, _ []byte) error {
+	EnableAllowFeeRecipients(stateDB)
+	return nil
+}
+
+// disableRewards burns the block rewards.
+func disableRewards(stateDB StateDB, _ []byte) error {
+	DisableFeeRewards(stateDB)
+	return nil
+}
+
+// setRewardAddress parses [input] into the address that receives the block rewards.
+func setRewardAddress(stateDB StateDB, input []byte) error {
+	rewardAddress, err := UnpackSetRewardAddressInput(input)
+	if err != nil {
+		return err
+	}
+	if rewardAddress == (common.Address{}) {
+		return ErrEmptyRewardAddress
+	}
+	StoreRewardAddress(stateDB, rewardAddress)
+	return nil
+}
+
+// areFeeRecipientsAllowed returns true if block producers may claim the block rewards.
+func areFeeRecipientsAllowed(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = deductGas(suppliedGas, getGasCost(accessibleState, RewardManagerAddress, "areFeeRecipientsAllowed", AreFeeRecipientsAllowedGasCost)); err != nil {
+		return nil, 0, err
+	}
+
+	_, allowFeeRecipients := GetStoredRewardAddress(accessibleState.GetStateDB())
+	output := common.Hash{}
+	if allowFeeRecipients {
+		output = common.BigToHash(common.Big1)
+	}
+
+	// Return the result as output and the remaining gas
+	return output.Bytes(), remainingGas, nil
+}
+
+// currentRewardAddress returns the address receiving the block rewards.
+// Returns the zero address if block producers may claim the block rewards.
+func currentRewardAddress(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = deductGas(suppliedGas, getGasCost(accessibleState, RewardManagerAddress, "currentRewardAddress", CurrentRewardAddressGasCost)); err != nil {
+		return nil, 0, err
+	}
+
+	rewardAddress, _ := GetStoredRewardAddress(accessibleState.GetStateDB())
+
+	// Return the reward address as output and the remaining gas
+	return rewardAddress.Hash().Bytes(), remainingGas, nil
+}
+
+// createRewardManagerPrecompile returns a StatefulPrecompiledContract
+// with getters and setters for the destination of block rewards. Access to the setters
+// is controlled by an allow list for [precompileAddr].
+func createRewardManagerPrecompile(precompileAddr common.Address) StatefulPrecompiledContract {
+	rewardManagerFunctions := createAllowListFunctions(precompileAddr)
+
+	allowFeeRecipientsFunc := newStatefulPrecompileFunction(allowFeeRecipientsSignature, createRewardSetter("allowFeeRecipients", AllowFeeRecipientsGasCost, ErrCannotAllowFeeRecipients, allowFeeRecipients))
+	disableRewardsFunc := newStatefulPrecompileFunction(disableRewardsSignature, createRewardSetter("disableRewards", DisableRewardsGasCost, ErrCannotDisableRewards, disableRewards))
+	setRewardAddressFunc := newStatefulPrecompileFunction(setRewardAddressSignature, createRewardSetter("setRewardAddress", SetRewardAddressGasCost, ErrCannotSetRewardAddress, setRewardAddress))
+	areFeeRecipientsAllowedFunc := newStatefulPrecompileFunction(areFeeRecipientsAllowedSignature, areFeeRecipientsAllowed)
+	currentRewardAddressFunc := newStatefulPrecompileFunction(currentRewardAddressSignature, currentRewardAddress)
+
+	rewardManagerFunctions = append(rewardManagerFunctions, allowFeeRecipientsFunc, disableRewardsFunc, setRewardAddressFunc, areFeeRecipientsAllowedFunc, currentRewardAddressFunc)
+	// Construct the contract with no fallback function.
+	return newStatefulPrecompileWithFunctionSelectors(nil, rewardManagerFunctions)
+}
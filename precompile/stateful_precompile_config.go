@@ -20,8 +20,21 @@ type StatefulPrecompileConfig interface {
 	Timestamp() *big.Int
 	// IsDisabled returns true if this network upgrade should disable the precompile.
 	IsDisabled() bool
+	// IsReconfigure returns true if this network upgrade should change the config of the enabled precompile
+	// in place by calling Reconfigure instead of Configure.
+	IsReconfigure() bool
+	// GetGasOverrides returns the gas costs, keyed by function name, that replace the default gas costs
+	// of the functions of the precompile while this network upgrade is active.
+	GetGasOverrides() map[string]uint64
+	// GetStorageMode returns the storage mode specified by this network upgrade, which determines whether
+	// the storage of the precompile is wiped or preserved when it is disabled or re-enabled.
+	// An empty storage mode selects the default, see EffectiveStorageMode.
+	GetStorageMode() StorageMode
 	// Equal returns true if the provided argument configures the same precompile with the same parameters.
 	Equal(StatefulPrecompileConfig) bool
+	// Verify returns an error if the parameters of the config are invalid.
+	// It is only called on configs that enable the precompile.
+	Verify() error
 	// Configure is called on the first block where the stateful precompile should be enabled.
 	// This allows the stateful precompile to configure its own state via [StateDB] and [BlockContext] as necessary.
 	// This function must be deterministic since it will impact the EVM state. If a change to the
@@ -32,6 +45,11 @@ type StatefulPrecompileConfig interface {
 	// provides the config the ability to set its initial state and should only modify the state within
 	// its own address space.
 	Configure(ChainConfig, StateDB, BlockContext)
+	// Reconfigure is called on the first block where a network upgrade that reconfigures the enabled
+	// stateful precompile goes into effect. Unlike Configure, it applies the config on top of the existing
+	// state of the precompile and must not reset the state that is modified by calls to the precompile,
+	// unless the config explicitly specifies it. It must be deterministic for the same reasons as Configure.
+	Reconfigure(ChainConfig, StateDB, BlockContext)
 	// Contract returns a thread-safe singleton that can be used as the StatefulPrecompiledContract when
 	// this config is enabled.
 	Contract() StatefulPrecompiledContract
@@ -41,6 +59,11 @@ type StatefulPrecompileConfig interface {
 // state update to enable the StatefulPrecompile.
 // Assumes that [precompileConfig] is non-nil.
 func Configure(chainConfig ChainConfig, blockContext BlockContext, precompileConfig StatefulPrecompileConfig, state StateDB) {
+	// If the storage of a previously disabled precompile should be wiped, replace the account at its
+	// address with a new account. This discards its storage while keeping its balance.
+	if precompileConfig.GetStorageMode() == StorageModeWipe {
+		state.CreateAccount(precompileConfig.Address())
+	}
 	// Set the nonce of the precompile's address (as is done when a contract is created) to ensure
 	// that it is marked as non-empty and will not be cleaned up when the statedb is finalized.
 	state.SetNonce(precompileConfig.Address(), 1)
@@ -50,3 +73,35 @@ func Configure(chainConfig ChainConfig, blockContext BlockContext, precompileCon
 	state.SetCode(precompileConfig.Address(), []byte{0x1})
 	precompileConfig.Configure(chainConfig, state, blockContext)
 }
+
+// Deconfigure applies the state transition of [precompileConfig] disabling its StatefulPrecompile.
+// By default, the account of the precompile is deleted along with its storage. If the storage should be
+// preserved, only the code of the precompile is removed and the nonce keeps the account from being deleted
+// as empty, so that the storage is still in place if the precompile is re-enabled later.
+// Assumes that [precompileConfig] is non-nil and disables the precompile.
+func Deconfigure(precompileConfig StatefulPrecompileConfig, state StateDB) {
+	if EffectiveStorageMode(precompileConfig) == StorageModePreserve {
+		state.SetCode(precompileConfig.Address(), nil)
+		return
+	}
+	state.Suicide(precompileConfig.Address())
+}
+
+// EffectiveStorageMode returns the storage mode applied by [precompileConfig] when it goes into effect.
+// Upgrades that disable the precompile wipe its storage by default, while upgrades that enable or
+// reconfigure the precompile keep any storage already at its address.
+func EffectiveStorageMode(precompileConfig StatefulPrecompileConfig) StorageMode {
+	if mode := precompileConfig.GetStorageMode(); mode != "" {
+		return mode
+	}
+	if precompileConfig.IsDisabled() {
+		return StorageModeWipe
+	}
+	return StorageModePreserve
+}
+
+// Reconfigure calls Reconfigure on [precompileConfig] to apply it to the state of the already enabled StatefulPrecompile.
+// Assumes that [precompileConfig] is non-nil.
+func Reconfigure(chainConfig ChainConfig, blockContext BlockContext, precompileConfig StatefulPrecompileConfig, state StateDB) {
+	precompileConfig.Reconfigure(chainConfig, state, blockContext)
+}
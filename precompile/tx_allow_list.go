@@ -18,6 +18,19 @@ var (
 	ErrSenderAddressNotAllowListed = errors.New("cannot issue transaction from non-allow listed address")
 )
 
+// TxAllowListConfigKey is the key used in json config files to specify the TxAllowListConfig.
+const TxAllowListConfigKey = "txAllowListConfig"
+
+func init() {
+	mustRegisterModule(Module{
+		ConfigKey: TxAllowListConfigKey,
+		Address:   TxAllowListAddress,
+		NewConfig: func() StatefulPrecompileConfig { return &TxAllowListConfig{} },
+		GasCosts:  withAllowListGasCosts(nil),
+		ABI:       AllowListABI,
+	})
+}
+
 // TxAllowListConfig wraps [AllowListConfig] and uses it to implement the StatefulPrecompileConfig
 // interface while adding in the TxAllowList specific precompile address.
 type TxAllowListConfig struct {
@@ -55,6 +68,11 @@ func (c *TxAllowListConfig) Configure(_ ChainConfig, state StateDB, _ BlockConte
 	c.AllowListConfig.Configure(state, TxAllowListAddress)
 }
 
+// Reconfigure adds the admins and enabled addresses of [c] to the existing allow list.
+func (c *TxAllowListConfig) Reconfigure(_ ChainConfig, state StateDB, _ BlockContext) {
+	c.AllowListConfig.Configure(state, TxAllowListAddress)
+}
+
 // Contract returns the singleton stateful precompiled contract to be used for the allow list.
 func (c *TxAllowListConfig) Contract() StatefulPrecompiledContract {
 	return TxAllowListPrecompile
@@ -4,17 +4,52 @@
 package precompile
 
 import (
+	"errors"
 	"math/big"
 
 	"github.com/ava-labs/subnet-evm/utils"
 )
 
+// StorageMode specifies what happens to the storage of a precompile when a network upgrade disables
+// or re-enables it.
+type StorageMode string
+
+const (
+	// StorageModeWipe deletes the storage of the precompile, so that it starts from a clean state.
+	StorageModeWipe StorageMode = "wipe"
+	// StorageModePreserve keeps the storage of the precompile at its address.
+	StorageModePreserve StorageMode = "preserve"
+)
+
+var ErrInvalidStorageMode = errors.New("invalid storage mode")
+
+// Valid returns true if [m] is a known storage mode or is empty, which selects the default
+// storage mode of the upgrade.
+func (m StorageMode) Valid() bool {
+	switch m {
+	case "", StorageModeWipe, StorageModePreserve:
+		return true
+	default:
+		return false
+	}
+}
+
 // UpgradeableConfig contains the timestamp for the upgrade along with
 // a boolean [Disable]. If [Disable] is set, the upgrade deactivates
-// the precompile and resets its storage.
+// the precompile and resets its storage. If [Reconfiguration] is set, the
+// upgrade applies the config to the already enabled precompile on top
+// of its existing storage.
+// [GasOverrides] optionally replaces the default gas cost of functions of the
+// precompile, keyed by function name, while the upgrade is active.
+// [StorageMode] optionally overrides what happens to the storage of the precompile
+// when the upgrade disables or re-enables it. By default, disabling the precompile
+// wipes its storage and enabling it keeps any storage that is still at its address.
 type UpgradeableConfig struct {
-	BlockTimestamp *big.Int `json:"blockTimestamp"`
-	Disable        bool     `json:"disable,omitempty"`
+	BlockTimestamp  *big.Int          `json:"blockTimestamp"`
+	Disable         bool              `json:"disable,omitempty"`
+	Reconfiguration bool              `json:"reconfigure,omitempty"`
+	GasOverrides    map[string]uint64 `json:"gasOverrides,omitempty"`
+	StorageMode     StorageMode       `json:"storageMode,omitempty"`
 }
 
 // Timestamp returns the timestamp this network upgrade goes into effect.
@@ -27,11 +62,41 @@ func (c *UpgradeableConfig) IsDisabled() bool {
 	return c.Disable
 }
 
-// Equal returns true iff [other] has the same blockTimestamp and has the
-// same on value for the Disable flag.
+// IsReconfigure returns true if the network upgrade changes the config of the
+// enabled precompile without disabling it.
+func (c *UpgradeableConfig) IsReconfigure() bool {
+	return c.Reconfiguration
+}
+
+// GetGasOverrides returns the gas costs that replace the default gas costs of the
+// functions of the precompile, keyed by function name.
+func (c *UpgradeableConfig) GetGasOverrides() map[string]uint64 {
+	return c.GasOverrides
+}
+
+// GetStorageMode returns the storage mode specified by the upgrade, which is empty
+// if the upgrade uses the default storage mode.
+func (c *UpgradeableConfig) GetStorageMode() StorageMode {
+	return c.StorageMode
+}
+
+// Equal returns true iff [other] has the same blockTimestamp, has the
+// same on value for the Disable and Reconfiguration flags, has the same
+// storage mode and has the same gas overrides.
 func (c *UpgradeableConfig) Equal(other *UpgradeableConfig) bool {
 	if other == nil {
 		return false
 	}
-	return c.Disable == other.Disable && utils.BigNumEqual(c.BlockTimestamp, other.BlockTimestamp)
+	if c.Disable != other.Disable || c.Reconfiguration != other.Reconfiguration || c.StorageMode != other.StorageMode || !utils.BigNumEqual(c.BlockTimestamp, other.BlockTimestamp) {
+		return false
+	}
+	if len(c.GasOverrides) != len(other.GasOverrides) {
+		return false
+	}
+	for function, cost := range c.GasOverrides {
+		if otherCost, ok := other.GasOverrides[function]; !ok || cost != otherCost {
+			return false
+		}
+	}
+	return true
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/ava-labs/subnet-evm/vmerrs"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
@@ -33,6 +34,43 @@ func deductGas(suppliedGas uint64, requiredGas uint64) (uint64, error) {
 	return suppliedGas - requiredGas, nil
 }
 
+// deductGasPerItem deducts [gasCostPerItem] for each of [numItems] from [suppliedGas].
+// Returns ErrOutOfGas if the total cost overflows, since it could not be supplied.
+func deductGasPerItem(suppliedGas uint64, numItems uint64, gasCostPerItem uint64) (uint64, error) {
+	requiredGas, overflow := math.SafeMul(numItems, gasCostPerItem)
+	if overflow {
+		return 0, vmerrs.ErrOutOfGas
+	}
+	return deductGas(suppliedGas, requiredGas)
+}
+
+// isPrecompileV2 returns true if the PrecompileV2 upgrade is activated at the block of [accessibleState].
+func isPrecompileV2(accessibleState PrecompileAccessibleState) bool {
+	return accessibleState.GetChainConfig().IsPrecompileV2(accessibleState.GetBlockContext().Timestamp())
+}
+
+// upgradedGasCost returns [cost] if the PrecompileV2 upgrade is activated at the block of [accessibleState]
+// and [legacyCost], the cost charged before the upgrade, otherwise.
+func upgradedGasCost(accessibleState PrecompileAccessibleState, cost uint64, legacyCost uint64) uint64 {
+	if isPrecompileV2(accessibleState) {
+		return cost
+	}
+	return legacyCost
+}
+
+// getGasCost returns the gas cost of [function] of the precompile at [precompileAddr], which is the gas
+// override for [function] in the active config of the precompile if it specifies one and [defaultCost] otherwise.
+func getGasCost(accessibleState PrecompileAccessibleState, precompileAddr common.Address, function string, defaultCost uint64) uint64 {
+	config := accessibleState.GetChainConfig().GetActivePrecompileConfig(precompileAddr, accessibleState.GetBlockContext().Timestamp())
+	if config == nil {
+		return defaultCost
+	}
+	if cost, ok := config.GetGasOverrides()[function]; ok {
+		return cost
+	}
+	return defaultCost
+}
+
 // packOrderedHashesWithSelector packs the function selector and ordered list of hashes into [dst]
 // byte slice.
 // assumes that [dst] has sufficient room for [functionSelector] and [hashes].
@@ -4,8 +4,10 @@
 package precompile
 
 import (
+	"math"
 	"testing"
 
+	"github.com/ava-labs/subnet-evm/vmerrs"
 	"gotest.tools/assert"
 )
 
@@ -64,3 +66,16 @@ func TestFunctionSignatureRegex(t *testing.T) {
 		assert.Equal(t, test.pass, functionSignatureRegex.MatchString(test.str), "unexpected result for %q", test.str)
 	}
 }
+
+func TestDeductGasPerItem(t *testing.T) {
+	remainingGas, err := deductGasPerItem(100, 3, 30)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(10), remainingGas)
+
+	_, err = deductGasPerItem(100, 4, 30)
+	assert.Equal(t, vmerrs.ErrOutOfGas, err)
+
+	// A total cost that overflows is never free
+	_, err = deductGasPerItem(math.MaxUint64, 2, math.MaxUint64/2+1)
+	assert.Equal(t, vmerrs.ErrOutOfGas, err)
+}
@@ -0,0 +1,398 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/vmerrs"
+	"github.com/ava-labs/subnet-evm/warp"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// WarpQuorumDenominator is the denominator of the fraction of the source chain's
+	// validator weight that must sign a message for it to be verified.
+	WarpQuorumDenominator uint64 = 100
+	// WarpDefaultQuorumNumerator is used if the config does not specify a quorum numerator.
+	WarpDefaultQuorumNumerator uint64 = 67
+
+	// WarpMessengerRawABI is the ABI of the warp messenger precompile
+	WarpMessengerRawABI = `[
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "internalType": "bytes32", "name": "destinationChainID", "type": "bytes32"},
+			{"indexed": true, "internalType": "address", "name": "destinationAddress", "type": "address"},
+			{"indexed": true, "internalType": "address", "name": "sender", "type": "address"},
+			{"indexed": false, "internalType": "bytes", "name": "message", "type": "bytes"}
+		],
+		"name": "SendWarpMessage",
+		"type": "event"
+	},
+	{
+		"inputs": [
+			{"internalType": "bytes32", "name": "destinationChainID", "type": "bytes32"},
+			{"internalType": "address", "name": "destinationAddress", "type": "address"},
+			{"internalType": "bytes", "name": "payload", "type": "bytes"}
+		],
+		"name": "sendWarpMessage",
+		"outputs": [
+			{"internalType": "bytes32", "name": "messageID", "type": "bytes32"}
+		],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "getVerifiedWarpMessage",
+		"outputs": [
+			{"internalType": "bytes32", "name": "sourceChainID", "type": "bytes32"},
+			{"internalType": "address", "name": "sourceAddress", "type": "address"},
+			{"internalType": "bytes32", "name": "destinationChainID", "type": "bytes32"},
+			{"internalType": "address", "name": "destinationAddress", "type": "address"},
+			{"internalType": "bytes", "name": "payload", "type": "bytes"}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	}
+]`
+)
+
+var (
+	_ StatefulPrecompileConfig = &WarpMessengerConfig{}
+	_ warp.ValidatorState      = &WarpMessengerConfig{}
+
+	WarpMessengerABI = mustParseABI(WarpMessengerRawABI)
+
+	sendWarpMessageSignature        = CalculateFunctionSelector("sendWarpMessage(bytes32,address,bytes)")
+	getVerifiedWarpMessageSignature = CalculateFunctionSelector("getVerifiedWarpMessage()")
+
+	// SendWarpMessageEventID is the first topic of the log emitted by sendWarpMessage.
+	// The remaining topics are the destination chain ID, destination address and sender
+	// and the data of the log is the ABI encoded bytes of the warp.UnsignedMessage.
+	SendWarpMessageEventID = crypto.Keccak256Hash([]byte("SendWarpMessage(bytes32,address,address,bytes)"))
+
+	ErrMissingWarpMessage   = errors.New("transaction does not include a warp message predicate")
+	ErrInvalidWarpMessage   = errors.New("invalid warp message")
+	ErrWrongWarpDestination = errors.New("warp message is not sent to this chain")
+)
+
+// WarpValidatorSet is the ordered set of validators trusted to sign warp messages on behalf of a source chain.
+type WarpValidatorSet []*warp.Validator
+
+// WarpMessengerConfigKey is the key used in json config files to specify the WarpMessengerConfig.
+const WarpMessengerConfigKey = "warpMessengerConfig"
+
+func init() {
+	mustRegisterModule(Module{
+		ConfigKey: WarpMessengerConfigKey,
+		Address:   WarpMessengerAddress,
+		NewConfig: func() StatefulPrecompileConfig { return &WarpMessengerConfig{} },
+		// The gas overrides replace the base gas costs, while the costs per byte and per validator are fixed.
+		GasCosts: map[string]uint64{
+			"sendWarpMessage":        SendWarpMessageGasCost,
+			"getVerifiedWarpMessage": GetVerifiedWarpMessageBaseGasCost,
+		},
+		ABI: WarpMessengerABI,
+	})
+}
+
+// WarpMessengerConfig implements the StatefulPrecompileConfig interface for the warp messenger precompile.
+// Warp messages are verified against the validator sets configured in [SourceChainValidators], keyed by
+// source chain ID.
+type WarpMessengerConfig struct {
+	UpgradeableConfig
+	QuorumNumerator       uint64                           `json:"quorumNumerator,omitempty"`
+	SourceChainValidators map[common.Hash]WarpValidatorSet `json:"sourceChainValidators,omitempty"`
+}
+
+// NewWarpMessengerConfig returns a config for a network upgrade at [blockTimestamp] that enables
+// the warp messenger, verifying messages from the chains in [sourceChainValidators].
+func NewWarpMessengerConfig(blockTimestamp *big.Int, quorumNumerator uint64, sourceChainValidators map[common.Hash]WarpValidatorSet) *WarpMessengerConfig {
+	return &WarpMessengerConfig{
+		UpgradeableConfig:     UpgradeableConfig{BlockTimestamp: blockTimestamp},
+		QuorumNumerator:       quorumNumerator,
+		SourceChainValidators: sourceChainValidators,
+	}
+}
+
+// NewDisableWarpMessengerConfig returns config for a network upgrade at [blockTimestamp]
+// that disables the warp messenger.
+func NewDisableWarpMessengerConfig(blockTimestamp *big.Int) *WarpMessengerConfig {
+	return &WarpMessengerConfig{
+		UpgradeableConfig: UpgradeableConfig{
+			BlockTimestamp: blockTimestamp,
+			Disable:        true,
+		},
+	}
+}
+
+// Address returns the address of the warp messenger contract.
+func (c *WarpMessengerConfig) Address() common.Address {
+	return WarpMessengerAddress
+}
+
+// Configure is a no-op for the warp messenger since it does not hold any state.
+func (c *WarpMessengerConfig) Configure(_ ChainConfig, _ StateDB, _ BlockContext) {}
+
+// Reconfigure is a no-op for the warp messenger since its config is only used by its contract.
+func (c *WarpMessengerConfig) Reconfigure(_ ChainConfig, _ StateDB, _ BlockContext) {}
+
+// Contract returns the stateful precompiled contract that verifies warp messages using the validators in [c].
+func (c *WarpMessengerConfig) Contract() StatefulPrecompiledContract {
+	return createWarpMessengerPrecompile(c, c.quorumNumerator())
+}
+
+// Equal returns true if [s] is a [*WarpMessengerConfig] and it has been configured identical to [c].
+func (c *WarpMessengerConfig) Equal(s StatefulPrecompileConfig) bool {
+	// typecast before comparison
+	other, ok := (s).(*WarpMessengerConfig)
+	if !ok {
+		return false
+	}
+	if !c.UpgradeableConfig.Equal(&other.UpgradeableConfig) || c.quorumNumerator() != other.quorumNumerator() {
+		return false
+	}
+	if len(c.SourceChainValidators) != len(other.SourceChainValidators) {
+		return false
+	}
+	for chainID, validators := range c.SourceChainValidators {
+		otherValidators, ok := other.SourceChainValidators[chainID]
+		if !ok || len(validators) != len(otherValidators) {
+			return false
+		}
+		for i, validator := range validators {
+			otherValidator := otherValidators[i]
+			if validator == nil || otherValidator == nil {
+				if validator != otherValidator {
+					return false
+				}
+				continue
+			}
+			if validator.Weight != otherValidator.Weight || !validator.PublicKey.Equal(otherValidator.PublicKey) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Verify returns an error if [c] specifies an invalid quorum or an invalid validator set.
+func (c *WarpMessengerConfig) Verify() error {
+	if c.QuorumNumerator > WarpQuorumDenominator {
+		return fmt.Errorf("quorum numerator %d cannot exceed quorum denominator %d", c.QuorumNumerator, WarpQuorumDenominator)
+	}
+	for chainID, validators := range c.SourceChainValidators {
+		if len(validators) == 0 {
+			return fmt.Errorf("source chain %s has an empty validator set", chainID)
+		}
+		if _, err := c.GetValidators(chainID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetValidators implements the warp.ValidatorState interface and returns the validators
+// configured for [sourceChainID].
+func (c *WarpMessengerConfig) GetValidators(sourceChainID common.Hash) ([]*warp.Validator, error) {
+	validators, ok := c.SourceChainValidators[sourceChainID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", warp.ErrUnknownSourceChain, sourceChainID)
+	}
+	for i, validator := range validators {
+		if validator == nil || validator.PublicKey == nil {
+			return nil, fmt.Errorf("validator %d of source chain %s is missing a public key", i, sourceChainID)
+		}
+	}
+	return validators, nil
+}
+
+func (c *WarpMessengerConfig) quorumNumerator() uint64 {
+	if c.QuorumNumerator == 0 {
+		return WarpDefaultQuorumNumerator
+	}
+	return c.QuorumNumerator
+}
+
+// PackSendWarpMessage packs the input to sendWarpMessage.
+func PackSendWarpMessage(destinationChainID common.Hash, destinationAddress common.Address, payload []byte) ([]byte, error) {
+	return WarpMessengerABI.Pack("sendWarpMessage", destinationChainID, destinationAddress, payload)
+}
+
+// UnpackSendWarpMessageInput attempts to unpack [input] into the arguments to sendWarpMessage.
+// Assumes that [input] does not include the function selector.
+func UnpackSendWarpMessageInput(input []byte) (common.Hash, common.Address, []byte, error) {
+	args, err := WarpMessengerABI.Methods["sendWarpMessage"].Inputs.Unpack(input)
+	if err != nil {
+		return common.Hash{}, common.Address{}, nil, err
+	}
+	return common.Hash(args[0].([common.HashLength]byte)), args[1].(common.Address), args[2].([]byte), nil
+}
+
+// PackSendWarpMessageEventData packs [unsignedMessage] as the data of the SendWarpMessage log.
+func PackSendWarpMessageEventData(unsignedMessage []byte) ([]byte, error) {
+	return WarpMessengerABI.Events["SendWarpMessage"].Inputs.NonIndexed().Pack(unsignedMessage)
+}
+
+// UnpackSendWarpMessageEventData attempts to unpack the encoded warp.UnsignedMessage from
+// the data of the SendWarpMessage log.
+func UnpackSendWarpMessageEventData(data []byte) ([]byte, error) {
+	values, err := WarpMessengerABI.Events["SendWarpMessage"].Inputs.NonIndexed().Unpack(data)
+	if err != nil {
+		return nil, err
+	}
+	return values[0].([]byte), nil
+}
+
+// PackGetVerifiedWarpMessage packs the input to getVerifiedWarpMessage.
+func PackGetVerifiedWarpMessage() []byte {
+	return getVerifiedWarpMessageSignature
+}
+
+// PackGetVerifiedWarpMessageOutput packs [message] as the output of getVerifiedWarpMessage.
+func PackGetVerifiedWarpMessageOutput(message *warp.UnsignedMessage) ([]byte, error) {
+	return WarpMessengerABI.Methods["getVerifiedWarpMessage"].Outputs.Pack(
+		message.SourceChainID,
+		message.SourceAddress,
+		message.DestinationChainID,
+		message.DestinationAddress,
+		message.Payload,
+	)
+}
+
+// UnpackGetVerifiedWarpMessageOutput attempts to unpack the output of getVerifiedWarpMessage.
+func UnpackGetVerifiedWarpMessageOutput(output []byte) (*warp.UnsignedMessage, error) {
+	values, err := WarpMessengerABI.Unpack("getVerifiedWarpMessage", output)
+	if err != nil {
+		return nil, err
+	}
+	return &warp.UnsignedMessage{
+		SourceChainID:      common.Hash(values[0].([common.HashLength]byte)),
+		SourceAddress:      values[1].(common.Address),
+		DestinationChainID: common.Hash(values[2].([common.HashLength]byte)),
+		DestinationAddress: values[3].(common.Address),
+		Payload:            values[4].([]byte),
+	}, nil
+}
+
+// sendWarpMessage records an unsigned warp message from [caller] in the logs of the
+// current transaction, where it is picked up by the validators of this chain to sign
+// once the block is accepted.
+func sendWarpMessage(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = deductGas(suppliedGas, getGasCost(accessibleState, WarpMessengerAddress, "sendWarpMessage", SendWarpMessageGasCost)); err != nil {
+		return nil, 0, err
+	}
+
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+
+	destinationChainID, destinationAddress, payload, err := UnpackSendWarpMessageInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	if remainingGas, err = deductGas(remainingGas, uint64(len(payload))*SendWarpMessageGasCostPerByte); err != nil {
+		return nil, 0, err
+	}
+
+	message := &warp.UnsignedMessage{
+		SourceChainID:      common.BigToHash(accessibleState.GetChainID()),
+		SourceAddress:      caller,
+		DestinationChainID: destinationChainID,
+		DestinationAddress: destinationAddress,
+		Payload:            payload,
+	}
+	messageBytes, err := message.Bytes()
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	data, err := PackSendWarpMessageEventData(messageBytes)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	accessibleState.GetStateDB().AddLog(
+		WarpMessengerAddress,
+		[]common.Hash{
+			SendWarpMessageEventID,
+			destinationChainID,
+			destinationAddress.Hash(),
+			caller.Hash(),
+		},
+		data,
+		accessibleState.GetBlockContext().Number().Uint64(),
+	)
+
+	// Return the message ID and the remaining gas
+	return crypto.Keccak256(messageBytes), remainingGas, nil
+}
+
+// createGetVerifiedWarpMessage returns the execution function of getVerifiedWarpMessage, which
+// verifies the signed warp message included in the predicate of the transaction against the
+// validator sets in [validatorState] and returns the unsigned message. Only messages sent to
+// this chain are verified.
+func createGetVerifiedWarpMessage(validatorState warp.ValidatorState, quorumNumerator uint64) RunStatefulPrecompileFunc {
+	return func(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		if remainingGas, err = deductGas(suppliedGas, getGasCost(accessibleState, WarpMessengerAddress, "getVerifiedWarpMessage", GetVerifiedWarpMessageBaseGasCost)); err != nil {
+			return nil, 0, err
+		}
+
+		predicate, ok := accessibleState.GetStateDB().GetPredicateStorageSlots(WarpMessengerAddress)
+		if !ok {
+			return nil, remainingGas, ErrMissingWarpMessage
+		}
+		if remainingGas, err = deductGas(remainingGas, uint64(len(predicate))*GetVerifiedWarpMessageGasCostPerByte); err != nil {
+			return nil, 0, err
+		}
+
+		signedMessageBytes, err := warp.UnpackPredicate(predicate)
+		if err != nil {
+			return nil, remainingGas, fmt.Errorf("%w: %s", ErrInvalidWarpMessage, err)
+		}
+		signedMessage, err := warp.ParseSignedMessage(signedMessageBytes)
+		if err != nil {
+			return nil, remainingGas, fmt.Errorf("%w: %s", ErrInvalidWarpMessage, err)
+		}
+		unsignedMessage, err := warp.ParseUnsignedMessage(signedMessage.UnsignedMessage)
+		if err != nil {
+			return nil, remainingGas, fmt.Errorf("%w: %s", ErrInvalidWarpMessage, err)
+		}
+		if chainID := common.BigToHash(accessibleState.GetChainID()); unsignedMessage.DestinationChainID != chainID {
+			return nil, remainingGas, fmt.Errorf("%w: destination chain %s, expected %s", ErrWrongWarpDestination, unsignedMessage.DestinationChainID, chainID)
+		}
+		validators, err := validatorState.GetValidators(unsignedMessage.SourceChainID)
+		if err != nil {
+			return nil, remainingGas, fmt.Errorf("%w: %s", ErrInvalidWarpMessage, err)
+		}
+		if remainingGas, err = deductGas(remainingGas, uint64(len(validators))*GetVerifiedWarpMessagePerValidatorGasCost); err != nil {
+			return nil, 0, err
+		}
+		if err := signedMessage.Verify(validators, quorumNumerator, WarpQuorumDenominator); err != nil {
+			return nil, remainingGas, fmt.Errorf("%w: %s", ErrInvalidWarpMessage, err)
+		}
+
+		output, err := PackGetVerifiedWarpMessageOutput(unsignedMessage)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+
+		// Return the verified message and the remaining gas
+		return output, remainingGas, nil
+	}
+}
+
+// createWarpMessengerPrecompile returns a StatefulPrecompiledContract that sends warp messages
+// and verifies warp messages signed by the validators in [validatorState].
+func createWarpMessengerPrecompile(validatorState warp.ValidatorState, quorumNumerator uint64) StatefulPrecompiledContract {
+	sendWarpMessageFunc := newStatefulPrecompileFunction(sendWarpMessageSignature, sendWarpMessage)
+	getVerifiedWarpMessageFunc := newStatefulPrecompileFunction(getVerifiedWarpMessageSignature, createGetVerifiedWarpMessage(validatorState, quorumNumerator))
+
+	// Construct the contract with no fallback function.
+	return newStatefulPrecompileWithFunctionSelectors(nil, []*statefulPrecompileFunction{sendWarpMessageFunc, getVerifiedWarpMessageFunc})
+}
@@ -0,0 +1,344 @@
+// (c) 2021-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statesyncclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/version"
+
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/ethdb"
+	"github.com/ava-labs/subnet-evm/ethdb/memorydb"
+	"github.com/ava-labs/subnet-evm/peer"
+	"github.com/ava-labs/subnet-evm/plugin/evm/message"
+	"github.com/ava-labs/subnet-evm/statesync/client/stats"
+	"github.com/ava-labs/subnet-evm/trie"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	// StateSyncVersion is the minimum peer version sent the state sync request types.
+	// Peers only report the version of avalanchego, so this matches peers running a
+	// subnet-evm release that does not serve state sync yet: these peers drop the
+	// requests, which then fail when they time out and are retried with another peer.
+	StateSyncVersion = &version.Application{
+		Major: 1,
+		Minor: 7,
+		Patch: 16,
+	}
+
+	errEmptyResponse          = errors.New("empty response")
+	errTooManyBlocks          = errors.New("response contains more blocks than requested")
+	errHashMismatch           = errors.New("hash does not match expected value")
+	errInvalidRangeProof      = errors.New("failed to verify range proof")
+	errTooManyLeaves          = errors.New("response contains more than requested leaves")
+	errUnmarshalResponse      = errors.New("failed to unmarshal response")
+	errExceededRetryLimit     = errors.New("exceeded request retry limit")
+	errKeyValueLengthMismatch = errors.New("keys and values have different lengths")
+)
+
+var _ Client = &client{}
+
+// Client synchronously fetches data from the network to fulfill state sync requests.
+// Repeatedly requests failed requests until the context to the request is expired.
+type Client interface {
+	// GetLeafs synchronously sends the given request, returning a parsed LeafsResponse or error
+	// Note: this verifies the response including the range proofs.
+	GetLeafs(ctx context.Context, request message.LeafsRequest) (message.LeafsResponse, error)
+
+	// GetBlocks synchronously retrieves blocks starting with specified common.Hash and height up to specified parents
+	// specified range from height to height-parents is inclusive
+	GetBlocks(ctx context.Context, blockHash common.Hash, height uint64, parents uint16) ([]*types.Block, error)
+
+	// GetCode synchronously retrieves code associated with the given hash
+	GetCode(ctx context.Context, hash common.Hash) ([]byte, error)
+}
+
+// parseResponseFn parses given response bytes in context of specified request
+// Validates response in context of the request
+// Ensures the returned interface matches the expected response type of the request
+// Returns the number of elements in the response (specific to the response type, used in metrics)
+type parseResponseFn func(codec codec.Manager, request message.Request, response []byte) (interface{}, int, error)
+
+type client struct {
+	networkClient peer.Client
+	codec         codec.Manager
+	maxAttempts   uint8
+	maxRetryDelay time.Duration
+	stats         stats.ClientSyncerStats
+}
+
+// NewClient returns a state sync Client that sends requests through [networkClient]
+// and retries each request up to [maxAttempts] times, waiting at most [maxRetryDelay]
+// between attempts.
+func NewClient(networkClient peer.Client, codec codec.Manager, maxAttempts uint8, maxRetryDelay time.Duration, stats stats.ClientSyncerStats) Client {
+	return &client{
+		networkClient: networkClient,
+		codec:         codec,
+		maxAttempts:   maxAttempts,
+		maxRetryDelay: maxRetryDelay,
+		stats:         stats,
+	}
+}
+
+// GetLeafs synchronously retrieves leafs as per given [message.LeafsRequest]
+// Retries when:
+// - response bytes could not be unmarshalled to [message.LeafsResponse]
+// - response keys do not correspond to the requested range.
+// - response does not contain a valid merkle proof.
+// Returns error if retries have been exceeded or [ctx] is done
+func (c *client) GetLeafs(ctx context.Context, req message.LeafsRequest) (message.LeafsResponse, error) {
+	data, err := c.get(ctx, req, parseLeafsResponse)
+	if err != nil {
+		return message.LeafsResponse{}, err
+	}
+
+	return data.(message.LeafsResponse), nil
+}
+
+// parseLeafsResponse validates given object as message.LeafsResponse
+// assumes reqIntf is of type message.LeafsRequest
+// returns a non-nil error if the request should be retried
+// returns error when:
+// - response bytes could not be marshalled into message.LeafsResponse
+// - number of response keys is not equal to the response values
+// - response has more keys than requested
+// - the response keys and values fail to verify against the range proof
+func parseLeafsResponse(codec codec.Manager, reqIntf message.Request, data []byte) (interface{}, int, error) {
+	var leafsResponse message.LeafsResponse
+	if _, err := codec.Unmarshal(data, &leafsResponse); err != nil {
+		return nil, 0, err
+	}
+
+	leafsRequest := reqIntf.(message.LeafsRequest)
+
+	// Ensure the response does not contain more than the maximum requested number of leaves.
+	if len(leafsResponse.Keys) > int(leafsRequest.Limit) || len(leafsResponse.Vals) > int(leafsRequest.Limit) {
+		return nil, 0, fmt.Errorf("%w: (%d) > %d)", errTooManyLeaves, len(leafsResponse.Keys), leafsRequest.Limit)
+	}
+
+	// An empty response (no more keys) requires a merkle proof
+	if len(leafsResponse.Keys) == 0 && len(leafsResponse.ProofKeys) == 0 {
+		return nil, 0, fmt.Errorf("empty key response must include merkle proof")
+	}
+
+	if len(leafsResponse.Keys) != len(leafsResponse.Vals) {
+		return nil, 0, fmt.Errorf("%w: (%d) != (%d)", errKeyValueLengthMismatch, len(leafsResponse.Keys), len(leafsResponse.Vals))
+	}
+	if len(leafsResponse.ProofKeys) != len(leafsResponse.ProofVals) {
+		return nil, 0, fmt.Errorf("%w: proof keys (%d) != proof values (%d)", errKeyValueLengthMismatch, len(leafsResponse.ProofKeys), len(leafsResponse.ProofVals))
+	}
+
+	var proof ethdb.KeyValueReader
+	// Populate proof when ProofKeys are present in the response. Its ok to pass it as nil to the trie.VerifyRangeProof
+	// function as it will assert that all the leaves belonging to the specified root are present.
+	if len(leafsResponse.ProofKeys) > 0 {
+		proofDB := memorydb.New()
+		defer proofDB.Close()
+		for i, proofKey := range leafsResponse.ProofKeys {
+			if err := proofDB.Put(proofKey, leafsResponse.ProofVals[i]); err != nil {
+				return nil, 0, err
+			}
+		}
+		proof = proofDB
+	}
+
+	var (
+		firstKey = leafsRequest.Start
+		lastKey  = leafsRequest.End
+	)
+	// Last key is the last returned key in response
+	if len(leafsResponse.Keys) > 0 {
+		lastKey = leafsResponse.Keys[len(leafsResponse.Keys)-1]
+
+		if firstKey == nil {
+			firstKey = bytes.Repeat([]byte{0x00}, len(lastKey))
+		}
+	}
+
+	// VerifyRangeProof verifies that the key-value pairs included in [leafResponse] are all of the keys within the range from start
+	// to the last key returned.
+	// Also ensures the keys are in monotonically increasing order
+	more, err := trie.VerifyRangeProof(leafsRequest.Root, firstKey, lastKey, leafsResponse.Keys, leafsResponse.Vals, proof)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s due to %w", errInvalidRangeProof, err)
+	}
+
+	// Set the [More] flag to indicate if there are more leaves to the right of the last key in the response
+	// that needs to be fetched.
+	leafsResponse.More = more
+
+	return leafsResponse, len(leafsResponse.Keys), nil
+}
+
+// GetBlocks synchronously retrieves up to [parents] blocks starting at [hash] and [height].
+// Retries when:
+// - response bytes could not be unmarshalled
+// - blocks in the response do not form a hash chain starting at [hash]
+// Returns error if retries have been exceeded or [ctx] is done
+func (c *client) GetBlocks(ctx context.Context, hash common.Hash, height uint64, parents uint16) ([]*types.Block, error) {
+	req := message.BlockRequest{
+		Hash:    hash,
+		Height:  height,
+		Parents: parents,
+	}
+
+	data, err := c.get(ctx, req, parseBlocks)
+	if err != nil {
+		return nil, fmt.Errorf("could not get blocks (%s) due to %w", hash, err)
+	}
+
+	return data.(types.Blocks), nil
+}
+
+// parseBlocks validates given object as message.BlockResponse
+// assumes req is of type message.BlockRequest
+// returns types.Blocks as interface{}
+// returns a non-nil error if the request should be retried
+func parseBlocks(codec codec.Manager, req message.Request, data []byte) (interface{}, int, error) {
+	var response message.BlockResponse
+	if _, err := codec.Unmarshal(data, &response); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", errUnmarshalResponse, err)
+	}
+	if len(response.Blocks) == 0 {
+		return nil, 0, errEmptyResponse
+	}
+	blockRequest := req.(message.BlockRequest)
+	numParentsRequested := blockRequest.Parents
+	if len(response.Blocks) > int(numParentsRequested) {
+		return nil, 0, errTooManyBlocks
+	}
+
+	hash := blockRequest.Hash
+
+	// attempt to decode blocks
+	blocks := make(types.Blocks, len(response.Blocks))
+	for i, blkBytes := range response.Blocks {
+		block := new(types.Block)
+		if err := rlp.DecodeBytes(blkBytes, block); err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", errUnmarshalResponse, err)
+		}
+
+		if block.Hash() != hash {
+			return nil, 0, fmt.Errorf("%w for block: (got %v) (expected %v)", errHashMismatch, block.Hash(), hash)
+		}
+
+		blocks[i] = block
+		hash = block.ParentHash()
+	}
+
+	// return decoded blocks
+	return blocks, len(blocks), nil
+}
+
+// GetCode synchronously retrieves the contract code with [hash].
+// Retries when the response could not be unmarshalled or does not hash to [hash].
+// Returns error if retries have been exceeded or [ctx] is done
+func (c *client) GetCode(ctx context.Context, hash common.Hash) ([]byte, error) {
+	req := message.NewCodeRequest(hash)
+
+	data, err := c.get(ctx, req, parseCode)
+	if err != nil {
+		return nil, fmt.Errorf("could not get code (%s): %w", hash, err)
+	}
+
+	return data.([]byte), nil
+}
+
+// parseCode validates given object as a code object
+// assumes req is of type message.CodeRequest
+// returns a non-nil error if the request should be retried
+func parseCode(codec codec.Manager, req message.Request, data []byte) (interface{}, int, error) {
+	var response message.CodeResponse
+	if _, err := codec.Unmarshal(data, &response); err != nil {
+		return nil, 0, err
+	}
+
+	codeRequest := req.(message.CodeRequest)
+	if len(response.Data) == 0 {
+		return nil, 0, errEmptyResponse
+	}
+	if hash := crypto.Keccak256Hash(response.Data); hash != codeRequest.Hash {
+		return nil, 0, fmt.Errorf("%w for code: (got %v) (requested %v)", errHashMismatch, hash, codeRequest.Hash)
+	}
+
+	return response.Data, len(response.Data), nil
+}
+
+// get submits given request and blockingly returns with either a parsed response object or an error
+// if [c.maxAttempts] is exceeded or [ctx] is done.
+// Each attempt is sent to a peer that matches [StateSyncVersion] and invalid responses are
+// counted as failed attempts. An attempt is abandoned as soon as [ctx] is done.
+func (c *client) get(ctx context.Context, request message.Request, parseFn parseResponseFn) (interface{}, error) {
+	metric, err := c.stats.GetMetric(request)
+	if err != nil {
+		return nil, err
+	}
+	requestBytes, err := message.RequestToBytes(c.codec, request)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for attempt := uint8(0); attempt < c.maxAttempts; attempt++ {
+		// If this is a retry attempt, wait before sending the next request.
+		if attempt > 0 {
+			timer := time.NewTimer(c.retryDelay(attempt))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		metric.IncRequested()
+		start := time.Now()
+		response, nodeID, err := c.networkClient.RequestAnyWithContext(ctx, StateSyncVersion, requestBytes)
+		if err != nil {
+			metric.IncFailed()
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			lastErr = err
+			log.Debug("request failed, retrying", "request", request, "attempt", attempt, "err", err)
+			continue
+		}
+		metric.UpdateRequestLatency(time.Since(start))
+
+		responseIntf, numElements, err := parseFn(c.codec, request, response)
+		if err != nil {
+			metric.IncInvalidResponse()
+			c.networkClient.TrackInvalidResponse(nodeID)
+			lastErr = err
+			log.Debug("could not validate response, retrying", "nodeID", nodeID, "request", request, "attempt", attempt, "err", err)
+			continue
+		}
+
+		metric.IncSucceeded()
+		metric.UpdateReceived(int64(numElements))
+		return responseIntf, nil
+	}
+
+	return nil, fmt.Errorf("%w (%d) for %s: %v", errExceededRetryLimit, c.maxAttempts, request, lastErr)
+}
+
+// retryDelay returns the time to wait before sending retry [attempt], doubling the delay
+// after every failed attempt up to [c.maxRetryDelay].
+func (c *client) retryDelay(attempt uint8) time.Duration {
+	delay := 10 * time.Millisecond << attempt
+	if delay <= 0 || delay > c.maxRetryDelay {
+		return c.maxRetryDelay
+	}
+	return delay
+}
@@ -0,0 +1,192 @@
+// (c) 2021-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statesyncclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/version"
+	"github.com/ava-labs/subnet-evm/consensus/dummy"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/ethdb/memorydb"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/peer"
+	"github.com/ava-labs/subnet-evm/plugin/evm/message"
+	"github.com/ava-labs/subnet-evm/statesync/client/stats"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ peer.Client = &mockNetwork{}
+
+// mockNetwork responds to the i-th request with [responses][i] or [errs][i].
+type mockNetwork struct {
+	responses        [][]byte
+	errs             []error
+	onRequest        func()
+	numCalls         int
+	invalidResponses int
+}
+
+func (m *mockNetwork) RequestAny(minVersion *version.Application, request []byte) ([]byte, ids.NodeID, error) {
+	return m.RequestAnyWithContext(context.Background(), minVersion, request)
+}
+
+func (m *mockNetwork) RequestAnyWithContext(ctx context.Context, _ *version.Application, _ []byte) ([]byte, ids.NodeID, error) {
+	if m.onRequest != nil {
+		m.onRequest()
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, ids.EmptyNodeID, err
+	}
+	if m.numCalls >= len(m.responses) {
+		return nil, ids.EmptyNodeID, errors.New("no more responses")
+	}
+	response, err := m.responses[m.numCalls], m.errs[m.numCalls]
+	m.numCalls++
+	return response, ids.GenerateTestNodeID(), err
+}
+
+func (m *mockNetwork) Request(ids.NodeID, []byte) ([]byte, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockNetwork) RequestWithContext(context.Context, ids.NodeID, []byte) ([]byte, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockNetwork) Gossip([]byte) error { return nil }
+
+func (m *mockNetwork) TrackInvalidResponse(ids.NodeID) { m.invalidResponses++ }
+
+// mockResponse queues [response] and [err] as the response to the next request.
+func (m *mockNetwork) mockResponse(response []byte, err error) {
+	m.responses = append(m.responses, response)
+	m.errs = append(m.errs, err)
+}
+
+func buildTestBlocks(t *testing.T, numBlocks int) []*types.Block {
+	gspec := &core.Genesis{
+		Config: params.TestChainConfig,
+	}
+	memdb := memorydb.New()
+	genesis := gspec.MustCommit(memdb)
+	blocks, _, err := core.GenerateChain(params.TestChainConfig, genesis, dummy.NewETHFaker(), memdb, numBlocks, 0, func(i int, b *core.BlockGen) {})
+	if err != nil {
+		t.Fatal("unexpected error when generating test blockchain", err)
+	}
+	return blocks
+}
+
+// blockResponse returns the marshalled response with [blocks] in descending order of height.
+func blockResponse(t *testing.T, codec codec.Manager, blocks []*types.Block) []byte {
+	var response message.BlockResponse
+	for i := len(blocks) - 1; i >= 0; i-- {
+		blockBytes, err := rlp.EncodeToBytes(blocks[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		response.Blocks = append(response.Blocks, blockBytes)
+	}
+	responseBytes, err := codec.Marshal(message.Version, response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return responseBytes
+}
+
+func TestGetBlocksRetries(t *testing.T) {
+	codec, err := message.BuildCodec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks := buildTestBlocks(t, 16)
+	last := blocks[len(blocks)-1]
+
+	network := &mockNetwork{}
+	network.mockResponse(nil, errors.New("request failed"))
+	network.mockResponse([]byte("invalid response"), nil)
+	// blocks that do not start at the requested hash
+	network.mockResponse(blockResponse(t, codec, blocks[:8]), nil)
+	network.mockResponse(blockResponse(t, codec, blocks[8:]), nil)
+
+	client := NewClient(network, codec, 4, time.Millisecond, stats.NewNoOpStats())
+	response, err := client.GetBlocks(context.Background(), last.Hash(), last.NumberU64(), 8)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, network.numCalls)
+	assert.Equal(t, 2, network.invalidResponses)
+	if assert.Len(t, response, 8) {
+		for i, block := range response {
+			assert.Equal(t, blocks[len(blocks)-1-i].Hash(), block.Hash())
+		}
+	}
+}
+
+func TestGetExceedsRetryLimit(t *testing.T) {
+	codec, err := message.BuildCodec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks := buildTestBlocks(t, 4)
+	last := blocks[len(blocks)-1]
+
+	network := &mockNetwork{}
+	for i := 0; i < 3; i++ {
+		network.mockResponse(nil, errors.New("request failed"))
+	}
+	network.mockResponse(blockResponse(t, codec, blocks), nil)
+
+	client := NewClient(network, codec, 3, time.Millisecond, stats.NewNoOpStats())
+	_, err = client.GetBlocks(context.Background(), last.Hash(), last.NumberU64(), 4)
+	assert.ErrorIs(t, err, errExceededRetryLimit)
+	assert.Equal(t, 3, network.numCalls)
+}
+
+func TestGetCancelledDuringRequest(t *testing.T) {
+	codec, err := message.BuildCodec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks := buildTestBlocks(t, 4)
+	last := blocks[len(blocks)-1]
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	network := &mockNetwork{onRequest: cancel}
+	network.mockResponse(blockResponse(t, codec, blocks), nil)
+
+	// The context is passed to the network, which abandons the request once it is cancelled.
+	client := NewClient(network, codec, 2, time.Millisecond, stats.NewNoOpStats())
+	_, err = client.GetBlocks(ctx, last.Hash(), last.NumberU64(), 4)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, network.numCalls)
+}
+
+func TestGetCancelledDuringRetryDelay(t *testing.T) {
+	codec, err := message.BuildCodec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks := buildTestBlocks(t, 4)
+	last := blocks[len(blocks)-1]
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	network := &mockNetwork{}
+	network.mockResponse(nil, errors.New("request failed"))
+	network.mockResponse(blockResponse(t, codec, blocks), nil)
+
+	// The context is cancelled while the client waits to retry the failed request, which is then not retried.
+	time.AfterFunc(10*time.Millisecond, cancel)
+	client := NewClient(network, codec, 2, time.Minute, stats.NewNoOpStats())
+	_, err = client.GetBlocks(ctx, last.Hash(), last.NumberU64(), 4)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, network.numCalls)
+}
@@ -0,0 +1,182 @@
+// (c) 2021-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package stats
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/subnet-evm/metrics"
+	"github.com/ava-labs/subnet-evm/plugin/evm/message"
+)
+
+var (
+	_ ClientSyncerStats = &clientSyncerStats{}
+	_ ClientSyncerStats = &noopStats{}
+)
+
+// MessageMetric reports prometheus metrics for a single request type sent by the state sync client
+type MessageMetric interface {
+	IncRequested()
+	IncSucceeded()
+	IncFailed()
+	IncInvalidResponse()
+	UpdateReceived(int64)
+	UpdateRequestLatency(time.Duration)
+}
+
+type messageMetric struct {
+	requested       metrics.Counter // Number of times a request has been sent
+	succeeded       metrics.Counter // Number of times a request has succeeded
+	failed          metrics.Counter // Number of times a request failed (does not include invalid responses)
+	invalidResponse metrics.Counter // Number of times a request failed due to an invalid response
+	received        metrics.Counter // Number of items that have been received
+
+	requestLatency metrics.Timer // Latency for this request
+}
+
+func NewMessageMetric(name string) MessageMetric {
+	return &messageMetric{
+		requested:       metrics.GetOrRegisterCounter(fmt.Sprintf("%s_requested", name), nil),
+		succeeded:       metrics.GetOrRegisterCounter(fmt.Sprintf("%s_succeeded", name), nil),
+		failed:          metrics.GetOrRegisterCounter(fmt.Sprintf("%s_failed", name), nil),
+		invalidResponse: metrics.GetOrRegisterCounter(fmt.Sprintf("%s_invalid_response", name), nil),
+		received:        metrics.GetOrRegisterCounter(fmt.Sprintf("%s_received", name), nil),
+		requestLatency:  metrics.GetOrRegisterTimer(fmt.Sprintf("%s_request_latency", name), nil),
+	}
+}
+
+func (m *messageMetric) IncRequested() {
+	m.requested.Inc(1)
+}
+
+func (m *messageMetric) IncSucceeded() {
+	m.succeeded.Inc(1)
+}
+
+func (m *messageMetric) IncFailed() {
+	m.failed.Inc(1)
+}
+
+func (m *messageMetric) IncInvalidResponse() {
+	m.invalidResponse.Inc(1)
+}
+
+func (m *messageMetric) UpdateReceived(size int64) {
+	m.received.Inc(size)
+}
+
+func (m *messageMetric) UpdateRequestLatency(duration time.Duration) {
+	m.requestLatency.Update(duration)
+}
+
+// ClientSyncerStats reports prometheus metrics for the state sync client and
+// the progress of an ongoing sync
+type ClientSyncerStats interface {
+	// GetMetric returns the MessageMetric for the type of [msg]
+	GetMetric(msg message.Request) (MessageMetric, error)
+
+	// Sync progress stats
+	IncTriesSynced()
+	IncTriesSkipped()
+	UpdateLeafsSynced(numLeafs int64)
+	IncCodeSynced()
+	IncCodeSkipped()
+	IncBlocksSynced()
+}
+
+type clientSyncerStats struct {
+	leafMetric, codeMetric, blockMetric MessageMetric
+
+	triesSynced  metrics.Counter
+	triesSkipped metrics.Counter
+	leafsSynced  metrics.Counter
+	codeSynced   metrics.Counter
+	codeSkipped  metrics.Counter
+	blocksSynced metrics.Counter
+}
+
+// NewClientSyncerStats returns stats for the state sync client
+func NewClientSyncerStats() ClientSyncerStats {
+	return &clientSyncerStats{
+		leafMetric:  NewMessageMetric("sync_leafs"),
+		codeMetric:  NewMessageMetric("sync_code"),
+		blockMetric: NewMessageMetric("sync_blocks"),
+
+		triesSynced:  metrics.GetOrRegisterCounter("sync_tries_synced", nil),
+		triesSkipped: metrics.GetOrRegisterCounter("sync_tries_skipped", nil),
+		leafsSynced:  metrics.GetOrRegisterCounter("sync_leafs_synced", nil),
+		codeSynced:   metrics.GetOrRegisterCounter("sync_code_synced", nil),
+		codeSkipped:  metrics.GetOrRegisterCounter("sync_code_skipped", nil),
+		blocksSynced: metrics.GetOrRegisterCounter("sync_blocks_synced", nil),
+	}
+}
+
+// GetMetric returns the appropriate message metric for the given request
+func (c *clientSyncerStats) GetMetric(msgIntf message.Request) (MessageMetric, error) {
+	switch msgIntf.(type) {
+	case message.BlockRequest:
+		return c.blockMetric, nil
+	case message.CodeRequest:
+		return c.codeMetric, nil
+	case message.LeafsRequest:
+		return c.leafMetric, nil
+	default:
+		return nil, fmt.Errorf("attempted to get metric for invalid request with type %T", msgIntf)
+	}
+}
+
+func (c *clientSyncerStats) IncTriesSynced() {
+	c.triesSynced.Inc(1)
+}
+
+func (c *clientSyncerStats) IncTriesSkipped() {
+	c.triesSkipped.Inc(1)
+}
+
+func (c *clientSyncerStats) UpdateLeafsSynced(numLeafs int64) {
+	c.leafsSynced.Inc(numLeafs)
+}
+
+func (c *clientSyncerStats) IncCodeSynced() {
+	c.codeSynced.Inc(1)
+}
+
+func (c *clientSyncerStats) IncCodeSkipped() {
+	c.codeSkipped.Inc(1)
+}
+
+func (c *clientSyncerStats) IncBlocksSynced() {
+	c.blocksSynced.Inc(1)
+}
+
+// no-op implementation of ClientSyncerStats
+type noopStats struct {
+	noop noopMsgMetric
+}
+
+type noopMsgMetric struct{}
+
+func (noopMsgMetric) IncRequested()                      {}
+func (noopMsgMetric) IncSucceeded()                      {}
+func (noopMsgMetric) IncFailed()                         {}
+func (noopMsgMetric) IncInvalidResponse()                {}
+func (noopMsgMetric) UpdateReceived(int64)               {}
+func (noopMsgMetric) UpdateRequestLatency(time.Duration) {}
+
+func NewNoOpStats() ClientSyncerStats {
+	return &noopStats{}
+}
+
+func (n noopStats) GetMetric(_ message.Request) (MessageMetric, error) {
+	return n.noop, nil
+}
+
+// all operations are no-ops
+func (n noopStats) IncTriesSynced()         {}
+func (n noopStats) IncTriesSkipped()        {}
+func (n noopStats) UpdateLeafsSynced(int64) {}
+func (n noopStats) IncCodeSynced()          {}
+func (n noopStats) IncCodeSkipped()         {}
+func (n noopStats) IncBlocksSynced()        {}
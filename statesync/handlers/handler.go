@@ -8,6 +8,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/subnet-evm/plugin/evm/message"
+	"github.com/ethereum/go-ethereum/log"
 )
 
 var _ message.RequestHandler = &syncHandler{}
@@ -41,3 +42,9 @@ func (s *syncHandler) HandleBlockRequest(ctx context.Context, nodeID ids.NodeID,
 func (s *syncHandler) HandleCodeRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, codeRequest message.CodeRequest) ([]byte, error) {
 	return s.codeRequestHandler.OnCodeRequest(ctx, nodeID, requestID, codeRequest)
 }
+
+// HandleTxsPullRequest drops the request, since the mempool is not served by the state sync handlers.
+func (s *syncHandler) HandleTxsPullRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, txsPullRequest message.TxsPullRequest) ([]byte, error) {
+	log.Debug("dropping unexpected TxsPullRequest", "peerID", nodeID, "requestID", requestID)
+	return nil, nil
+}
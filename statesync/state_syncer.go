@@ -0,0 +1,293 @@
+// (c) 2021-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statesync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/subnet-evm/core/rawdb"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/ethdb"
+	"github.com/ava-labs/subnet-evm/plugin/evm/message"
+	statesyncclient "github.com/ava-labs/subnet-evm/statesync/client"
+	"github.com/ava-labs/subnet-evm/statesync/client/stats"
+	"github.com/ava-labs/subnet-evm/trie"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// defaultLeafsRequestLimit is the maximum number of leaves requested in a single message.LeafsRequest.
+	// Note: the server caps the number of leaves it returns at a lower value.
+	defaultLeafsRequestLimit = 1024
+	// defaultNumWorkers is the number of storage tries and code hashes fetched concurrently
+	defaultNumWorkers = 8
+)
+
+var (
+	emptyCodeHash = crypto.Keccak256Hash(nil)
+
+	// startKey and endKey are the left and right edges of every trie synced, since
+	// all keys in the secure tries are the 32 byte hashes of the original keys.
+	// The full length start key is sent so the range proof of the first page is
+	// generated for the same key the client verifies it against.
+	startKey = make([]byte, common.HashLength)
+	endKey   = bytes.Repeat([]byte{0xff}, common.HashLength)
+)
+
+// StateSyncerConfig holds the parameters to sync the EVM state trie at [Root].
+type StateSyncerConfig struct {
+	Root       common.Hash
+	Client     statesyncclient.Client
+	DB         ethdb.Database
+	Stats      stats.ClientSyncerStats
+	NumWorkers int // Number of storage tries and code hashes fetched concurrently (defaults to [defaultNumWorkers])
+}
+
+// stateSyncer syncs the account trie at [root] along with every storage trie
+// and contract code referenced by its accounts.
+//
+// Each trie is rebuilt through a StackTrie, which writes nodes to the database
+// bottom up so the root node of a trie is only written once all of its children
+// have been persisted. This is used to resume an interrupted sync: any trie
+// whose root node is already present in the database is skipped, as is any code
+// that is already present.
+// Note: a StackTrie cannot be restored from the nodes it already flushed, so an
+// interrupted trie is fetched again from its first leaf. In particular, resuming
+// restarts the account trie from the start and only skips the storage tries and
+// code completed before the interruption.
+type stateSyncer struct {
+	root       common.Hash
+	client     statesyncclient.Client
+	db         ethdb.Database
+	stats      stats.ClientSyncerStats
+	numWorkers int
+}
+
+// NewStateSyncer returns a syncer for the state trie specified in [config].
+func NewStateSyncer(config *StateSyncerConfig) (*stateSyncer, error) {
+	if config.Client == nil {
+		return nil, fmt.Errorf("state syncer requires a non-nil client")
+	}
+	numWorkers := config.NumWorkers
+	if numWorkers <= 0 {
+		numWorkers = defaultNumWorkers
+	}
+	syncerStats := config.Stats
+	if syncerStats == nil {
+		syncerStats = stats.NewNoOpStats()
+	}
+	return &stateSyncer{
+		root:       config.Root,
+		client:     config.Client,
+		db:         config.DB,
+		stats:      syncerStats,
+		numWorkers: numWorkers,
+	}, nil
+}
+
+// Sync blocks until the state trie, all of its storage tries and all of the
+// contract code it references have been written to the database, or until an
+// error occurs or [ctx] is cancelled.
+func (s *stateSyncer) Sync(ctx context.Context) error {
+	log.Info("starting state sync", "root", s.root)
+	return s.syncTrie(ctx, s.root, s.onAccountLeafs)
+}
+
+// onAccountLeafs parses the accounts in a single page of account trie leaves and
+// syncs the storage tries and code they reference before the next page is requested.
+func (s *stateSyncer) onAccountLeafs(ctx context.Context, keys [][]byte, vals [][]byte) error {
+	var (
+		storageRoots = make([]common.Hash, 0)
+		codeHashes   = make([]common.Hash, 0)
+		seen         = make(map[common.Hash]struct{})
+	)
+	for i, val := range vals {
+		var acc types.StateAccount
+		if err := rlp.DecodeBytes(val, &acc); err != nil {
+			return fmt.Errorf("could not decode account at key %s: %w", common.BytesToHash(keys[i]), err)
+		}
+		if acc.Root != types.EmptyRootHash && acc.Root != (common.Hash{}) {
+			if _, ok := seen[acc.Root]; !ok {
+				seen[acc.Root] = struct{}{}
+				storageRoots = append(storageRoots, acc.Root)
+			}
+		}
+		if codeHash := common.BytesToHash(acc.CodeHash); codeHash != emptyCodeHash && codeHash != (common.Hash{}) {
+			if _, ok := seen[codeHash]; !ok {
+				seen[codeHash] = struct{}{}
+				codeHashes = append(codeHashes, codeHash)
+			}
+		}
+	}
+
+	if err := s.runTasks(ctx, len(storageRoots), func(ctx context.Context, i int) error {
+		return s.syncTrie(ctx, storageRoots[i], nil)
+	}); err != nil {
+		return err
+	}
+	return s.syncCode(ctx, codeHashes)
+}
+
+// syncCode fetches every code hash in [codeHashes] that is not already present in
+// the database using [s.numWorkers] concurrent requests, then writes the fetched code
+// to the database in a single batch.
+func (s *stateSyncer) syncCode(ctx context.Context, codeHashes []common.Hash) error {
+	missing := make([]common.Hash, 0, len(codeHashes))
+	for _, codeHash := range codeHashes {
+		if rawdb.HasCodeWithPrefix(s.db, codeHash) {
+			s.stats.IncCodeSkipped()
+			continue
+		}
+		missing = append(missing, codeHash)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	var (
+		codeLock sync.Mutex
+		codes    = make(map[common.Hash][]byte, len(missing))
+	)
+	if err := s.runTasks(ctx, len(missing), func(ctx context.Context, i int) error {
+		code, err := s.client.GetCode(ctx, missing[i])
+		if err != nil {
+			return err
+		}
+		codeLock.Lock()
+		codes[missing[i]] = code
+		codeLock.Unlock()
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	batch := s.db.NewBatch()
+	for codeHash, code := range codes {
+		rawdb.WriteCode(batch, codeHash, code)
+		s.stats.IncCodeSynced()
+	}
+	return batch.Write()
+}
+
+// runTasks calls [task] with every index in [0, numTasks) using at most [s.numWorkers]
+// goroutines. Returns the first error encountered, after which remaining tasks are
+// not started.
+func (s *stateSyncer) runTasks(ctx context.Context, numTasks int, task func(ctx context.Context, i int) error) error {
+	eg, egCtx := errgroup.WithContext(ctx)
+	tasks := make(chan int)
+	for w := 0; w < s.numWorkers && w < numTasks; w++ {
+		eg.Go(func() error {
+			for i := range tasks {
+				if err := task(egCtx, i); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	eg.Go(func() error {
+		defer close(tasks)
+		for i := 0; i < numTasks; i++ {
+			select {
+			case tasks <- i:
+			case <-egCtx.Done():
+				return egCtx.Err()
+			}
+		}
+		return nil
+	})
+	return eg.Wait()
+}
+
+// syncTrie fetches all of the leaves of the trie at [root] from the network and
+// rebuilds the trie in [s.db]. If [onLeafs] is non-nil, it is called with every
+// page of leaves after the page has been added to the trie.
+// Returns nil immediately if the root node of the trie is already present.
+func (s *stateSyncer) syncTrie(ctx context.Context, root common.Hash, onLeafs func(ctx context.Context, keys [][]byte, vals [][]byte) error) error {
+	if rawdb.HasTrieNode(s.db, root) {
+		s.stats.IncTriesSkipped()
+		return nil
+	}
+
+	batch := s.db.NewBatch()
+	stackTrie := trie.NewStackTrie(batch)
+
+	start := startKey
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		leafsResponse, err := s.client.GetLeafs(ctx, message.LeafsRequest{
+			Root:  root,
+			Start: start,
+			End:   endKey,
+			Limit: defaultLeafsRequestLimit,
+		})
+		if err != nil {
+			return fmt.Errorf("could not get leafs for trie %s: %w", root, err)
+		}
+
+		for i, key := range leafsResponse.Keys {
+			if err := stackTrie.TryUpdate(key, leafsResponse.Vals[i]); err != nil {
+				return err
+			}
+		}
+		s.stats.UpdateLeafsSynced(int64(len(leafsResponse.Keys)))
+
+		if onLeafs != nil && len(leafsResponse.Keys) > 0 {
+			if err := onLeafs(ctx, leafsResponse.Keys, leafsResponse.Vals); err != nil {
+				return err
+			}
+		}
+
+		// Flush the trie nodes written so far to keep memory usage bounded.
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+
+		if !leafsResponse.More || len(leafsResponse.Keys) == 0 {
+			break
+		}
+		if start = incrementKey(leafsResponse.Keys[len(leafsResponse.Keys)-1]); start == nil {
+			break
+		}
+	}
+
+	computedRoot, err := stackTrie.Commit()
+	if err != nil {
+		return err
+	}
+	if computedRoot != root {
+		return fmt.Errorf("synced trie root mismatch: (expected %s) (computed %s)", root, computedRoot)
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	s.stats.IncTriesSynced()
+	log.Debug("synced trie", "root", root)
+	return nil
+}
+
+// incrementKey returns the key immediately following [key] in lexicographical order
+// of keys with the same length. Returns nil if [key] is the maximal key.
+func incrementKey(key []byte) []byte {
+	next := common.CopyBytes(key)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			return next
+		}
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+// (c) 2021-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statesync
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/version"
+	"github.com/ava-labs/subnet-evm/core/rawdb"
+	"github.com/ava-labs/subnet-evm/core/state"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/ethdb"
+	"github.com/ava-labs/subnet-evm/peer"
+	"github.com/ava-labs/subnet-evm/plugin/evm/message"
+	statesyncclient "github.com/ava-labs/subnet-evm/statesync/client"
+	"github.com/ava-labs/subnet-evm/statesync/client/stats"
+	"github.com/ava-labs/subnet-evm/statesync/handlers"
+	handlerstats "github.com/ava-labs/subnet-evm/statesync/handlers/stats"
+	"github.com/ava-labs/subnet-evm/trie"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ peer.Client = &testNetworkClient{}
+
+// testNetworkClient serves every request with [handler] in process.
+type testNetworkClient struct {
+	codec   codec.Manager
+	handler message.RequestHandler
+}
+
+func (c *testNetworkClient) RequestAny(minVersion *version.Application, requestBytes []byte) ([]byte, ids.NodeID, error) {
+	return c.RequestAnyWithContext(context.Background(), minVersion, requestBytes)
+}
+
+func (c *testNetworkClient) RequestAnyWithContext(ctx context.Context, _ *version.Application, requestBytes []byte) ([]byte, ids.NodeID, error) {
+	nodeID := ids.GenerateTestNodeID()
+	response, err := c.RequestWithContext(ctx, nodeID, requestBytes)
+	return response, nodeID, err
+}
+
+func (c *testNetworkClient) Request(nodeID ids.NodeID, requestBytes []byte) ([]byte, error) {
+	return c.RequestWithContext(context.Background(), nodeID, requestBytes)
+}
+
+func (c *testNetworkClient) RequestWithContext(ctx context.Context, nodeID ids.NodeID, requestBytes []byte) ([]byte, error) {
+	var request message.Request
+	if _, err := c.codec.Unmarshal(requestBytes, &request); err != nil {
+		return nil, err
+	}
+	return request.Handle(ctx, nodeID, 0, c.handler)
+}
+
+func (c *testNetworkClient) Gossip([]byte) error { return nil }
+
+func (c *testNetworkClient) TrackInvalidResponse(ids.NodeID) {}
+
+// fillState creates [numAccounts] accounts in [db], every other one with
+// contract code and storage, and returns the committed state root.
+func fillState(t *testing.T, db ethdb.Database, numAccounts int) common.Hash {
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(db), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < numAccounts; i++ {
+		addr := common.BigToAddress(big.NewInt(int64(i + 1)))
+		statedb.SetBalance(addr, big.NewInt(r.Int63()))
+		statedb.SetNonce(addr, uint64(i))
+		if i%2 == 0 {
+			continue
+		}
+		code := make([]byte, 32+r.Intn(64))
+		r.Read(code)
+		statedb.SetCode(addr, code)
+		for j := 0; j < 1+r.Intn(2000); j++ {
+			statedb.SetState(addr, common.BigToHash(big.NewInt(int64(j))), common.BigToHash(big.NewInt(r.Int63())))
+		}
+	}
+
+	root, err := statedb.Commit(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := statedb.Database().TrieDB().Commit(root, false, nil); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func newTestSyncClient(t *testing.T, serverDB ethdb.Database) statesyncclient.Client {
+	codec, err := message.BuildCodec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	handlerStats := handlerstats.NewNoopHandlerStats()
+	trieDB := trie.NewDatabase(serverDB)
+	networkClient := &testNetworkClient{
+		codec: codec,
+		handler: handlers.NewSyncHandler(
+			handlers.NewLeafsRequestHandler(trieDB, handlerStats, codec),
+			nil,
+			handlers.NewCodeRequestHandler(serverDB, handlerStats, codec),
+		),
+	}
+	return statesyncclient.NewClient(networkClient, codec, 1, time.Millisecond, stats.NewNoOpStats())
+}
+
+// assertStateEqual asserts that the accounts created by [fillState] have the same
+// balance, nonce, code and storage root in [clientDB] as in [serverDB].
+func assertStateEqual(t *testing.T, root common.Hash, numAccounts int, serverDB, clientDB ethdb.Database) {
+	serverState, err := state.New(root, state.NewDatabase(serverDB), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientState, err := state.New(root, state.NewDatabase(clientDB), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < numAccounts; i++ {
+		addr := common.BigToAddress(big.NewInt(int64(i + 1)))
+		assert.Equal(t, serverState.GetBalance(addr), clientState.GetBalance(addr))
+		assert.Equal(t, serverState.GetNonce(addr), clientState.GetNonce(addr))
+		assert.Equal(t, serverState.GetCode(addr), clientState.GetCode(addr))
+		assert.Equal(t, serverState.GetState(addr, common.Hash{}), clientState.GetState(addr, common.Hash{}))
+		if serverTrie := serverState.StorageTrie(addr); serverTrie != nil {
+			clientTrie := clientState.StorageTrie(addr)
+			if assert.NotNil(t, clientTrie) {
+				assert.Equal(t, serverTrie.Hash(), clientTrie.Hash())
+			}
+		}
+	}
+}
+
+// failingClient fails every request
+type failingClient struct{}
+
+func (failingClient) GetLeafs(context.Context, message.LeafsRequest) (message.LeafsResponse, error) {
+	return message.LeafsResponse{}, errors.New("unexpected leafs request")
+}
+
+func (failingClient) GetBlocks(context.Context, common.Hash, uint64, uint16) ([]*types.Block, error) {
+	return nil, errors.New("unexpected blocks request")
+}
+
+func (failingClient) GetCode(context.Context, common.Hash) ([]byte, error) {
+	return nil, errors.New("unexpected code request")
+}
+
+func TestStateSyncer(t *testing.T) {
+	serverDB := rawdb.NewMemoryDatabase()
+	numAccounts := 2500
+	root := fillState(t, serverDB, numAccounts)
+
+	clientDB := rawdb.NewMemoryDatabase()
+	syncer, err := NewStateSyncer(&StateSyncerConfig{
+		Root:   root,
+		Client: newTestSyncClient(t, serverDB),
+		DB:     clientDB,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, syncer.Sync(context.Background()))
+	assertStateEqual(t, root, numAccounts, serverDB, clientDB)
+
+	// Syncing the same root again must not send any requests since
+	// the root of the trie is already present.
+	syncer, err = NewStateSyncer(&StateSyncerConfig{
+		Root:   root,
+		Client: failingClient{},
+		DB:     clientDB,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, syncer.Sync(context.Background()))
+}
+
+func TestStateSyncerCancelled(t *testing.T) {
+	serverDB := rawdb.NewMemoryDatabase()
+	root := fillState(t, serverDB, 100)
+
+	syncer, err := NewStateSyncer(&StateSyncerConfig{
+		Root:   root,
+		Client: newTestSyncClient(t, serverDB),
+		DB:     rawdb.NewMemoryDatabase(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, syncer.Sync(ctx), context.Canceled)
+}
+
+func TestIncrementKey(t *testing.T) {
+	assert.Equal(t, []byte{0x00, 0x01}, incrementKey([]byte{0x00, 0x00}))
+	assert.Equal(t, []byte{0x01, 0x00}, incrementKey([]byte{0x00, 0xff}))
+	assert.Nil(t, incrementKey([]byte{0xff, 0xff}))
+}
@@ -0,0 +1,330 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto/bls12381"
+	"golang.org/x/crypto/hkdf"
+)
+
+// BLS signatures over BLS12-381 with public keys in G1 and signatures in G2.
+// Messages are hashed to G2 with the hash_to_curve encoding of the
+// BLS12381G2_XMD:SHA-256_SSWU_RO_ suite of RFC 9380. The bls12381 package only
+// provides the map_to_curve step of the suite (MapToCurve, which also clears the
+// cofactor), so the hash_to_field step is implemented here and both are checked
+// against the test vectors of the RFC.
+//
+// Note: aggregate signatures are only secure against rogue key attacks if the
+// aggregated public keys are known to be held by their owners, which is the
+// case for the validator sets configured in the warp precompile.
+const (
+	SecretKeyLen = 32
+	PublicKeyLen = 96  // uncompressed G1 point
+	SignatureLen = 192 // uncompressed G2 point
+
+	fieldElementLen = 48
+	// hashToFieldLen is the number of bytes expanded from the message for each
+	// coordinate of the two Fp2 elements mapped to the curve.
+	hashToFieldLen = 64
+	// keyGenLen is the number of bytes derived by KeyGen before reducing them
+	// modulo the order of the group.
+	keyGenLen = 48
+	// minKeyGenSeedLen is the minimum length of the key material given to KeyGen
+	minKeyGenSeedLen = 32
+)
+
+var (
+	errInvalidSecretKey = errors.New("invalid secret key")
+	errInvalidPublicKey = errors.New("invalid public key")
+	errInvalidSignature = errors.New("invalid signature")
+	errNoPublicKeys     = errors.New("no public keys to aggregate")
+	errNoSignatures     = errors.New("no signatures to aggregate")
+	errShortSeed        = errors.New("seed is too short")
+
+	// hashToCurveDST is the domain separation tag used when hashing messages to G2
+	hashToCurveDST = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_")
+
+	// keyGenSalt is the initial salt of KeyGen
+	keyGenSalt = []byte("BLS-SIG-KEYGEN-SALT-")
+
+	// fieldModulus is the modulus of the base field of BLS12-381
+	fieldModulus, _ = new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
+)
+
+var _ Signer = &SecretKey{}
+
+// Signer signs messages with a BLS secret key
+type Signer interface {
+	// PublicKey returns the public key verifying the signatures of the signer
+	PublicKey() *PublicKey
+	// Sign returns the signature of [msg]
+	Sign(msg []byte) *Signature
+}
+
+// SecretKey is a BLS secret key
+type SecretKey struct {
+	scalar *big.Int
+}
+
+// PublicKey is a BLS public key
+type PublicKey struct {
+	point *bls12381.PointG1
+}
+
+// Signature is a BLS signature or an aggregate of BLS signatures
+type Signature struct {
+	point *bls12381.PointG2
+}
+
+// NewSecretKey generates a new random secret key
+func NewSecretKey() (*SecretKey, error) {
+	order := bls12381.NewG1().Q()
+	for {
+		scalar, err := rand.Int(rand.Reader, order)
+		if err != nil {
+			return nil, err
+		}
+		if scalar.Sign() != 0 {
+			return &SecretKey{scalar: scalar}, nil
+		}
+	}
+}
+
+// SecretKeyFromSeed deterministically derives a secret key from [seed] with the
+// KeyGen procedure of the BLS signature specification. [seed] must hold at least
+// 32 bytes of secret key material.
+func SecretKeyFromSeed(seed []byte) (*SecretKey, error) {
+	if len(seed) < minKeyGenSeedLen {
+		return nil, fmt.Errorf("%w: expected at least %d bytes but got %d", errShortSeed, minKeyGenSeedLen, len(seed))
+	}
+	var (
+		order = bls12381.NewG1().Q()
+		ikm   = append(append([]byte{}, seed...), 0)
+		info  = []byte{0, keyGenLen}
+		salt  = keyGenSalt
+	)
+	for {
+		saltHash := sha256.Sum256(salt)
+		salt = saltHash[:]
+		okm := make([]byte, keyGenLen)
+		if _, err := io.ReadFull(hkdf.New(sha256.New, ikm, salt, info), okm); err != nil {
+			return nil, err
+		}
+		scalar := new(big.Int).SetBytes(okm)
+		scalar.Mod(scalar, order)
+		if scalar.Sign() != 0 {
+			return &SecretKey{scalar: scalar}, nil
+		}
+	}
+}
+
+// SecretKeyFromBytes parses a secret key serialized with [SecretKey.Bytes]
+func SecretKeyFromBytes(b []byte) (*SecretKey, error) {
+	if len(b) != SecretKeyLen {
+		return nil, fmt.Errorf("%w: expected %d bytes but got %d", errInvalidSecretKey, SecretKeyLen, len(b))
+	}
+	scalar := new(big.Int).SetBytes(b)
+	if scalar.Sign() == 0 || scalar.Cmp(bls12381.NewG1().Q()) >= 0 {
+		return nil, fmt.Errorf("%w: scalar out of range", errInvalidSecretKey)
+	}
+	return &SecretKey{scalar: scalar}, nil
+}
+
+// Bytes returns the big endian encoding of [sk]
+func (sk *SecretKey) Bytes() []byte {
+	b := make([]byte, SecretKeyLen)
+	return sk.scalar.FillBytes(b)
+}
+
+// PublicKey returns the public key corresponding to [sk]
+func (sk *SecretKey) PublicKey() *PublicKey {
+	g1 := bls12381.NewG1()
+	return &PublicKey{point: g1.MulScalar(g1.New(), g1.One(), sk.scalar)}
+}
+
+// Sign returns the signature of [msg] by [sk]
+func (sk *SecretKey) Sign(msg []byte) *Signature {
+	g2 := bls12381.NewG2()
+	return &Signature{point: g2.MulScalar(g2.New(), hashToG2(msg), sk.scalar)}
+}
+
+// PublicKeyFromBytes parses an uncompressed public key and verifies that
+// it is a non-zero point of the prime order subgroup.
+func PublicKeyFromBytes(b []byte) (*PublicKey, error) {
+	if len(b) != PublicKeyLen {
+		return nil, fmt.Errorf("%w: expected %d bytes but got %d", errInvalidPublicKey, PublicKeyLen, len(b))
+	}
+	g1 := bls12381.NewG1()
+	point, err := g1.FromBytes(b)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidPublicKey, err)
+	}
+	if g1.IsZero(point) || !g1.InCorrectSubgroup(point) {
+		return nil, fmt.Errorf("%w: point not in subgroup", errInvalidPublicKey)
+	}
+	return &PublicKey{point: point}, nil
+}
+
+// Bytes returns the uncompressed encoding of [pk]
+func (pk *PublicKey) Bytes() []byte {
+	// Note: ToBytes converts the point to affine form in place, so encode a copy
+	// to allow [pk] to be used concurrently.
+	return bls12381.NewG1().ToBytes(new(bls12381.PointG1).Set(pk.point))
+}
+
+// Equal returns true if [pk] and [other] are the same public key
+func (pk *PublicKey) Equal(other *PublicKey) bool {
+	if pk == nil || other == nil {
+		return pk == other
+	}
+	return bls12381.NewG1().Equal(pk.point, other.point)
+}
+
+// MarshalJSON encodes [pk] as a hex string
+func (pk *PublicKey) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hexutil.Bytes(pk.Bytes()))
+}
+
+// UnmarshalJSON decodes and validates a hex encoded public key
+func (pk *PublicKey) UnmarshalJSON(data []byte) error {
+	var b hexutil.Bytes
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	parsed, err := PublicKeyFromBytes(b)
+	if err != nil {
+		return err
+	}
+	*pk = *parsed
+	return nil
+}
+
+// SignatureFromBytes parses an uncompressed signature and verifies that
+// it is a point of the prime order subgroup.
+func SignatureFromBytes(b []byte) (*Signature, error) {
+	if len(b) != SignatureLen {
+		return nil, fmt.Errorf("%w: expected %d bytes but got %d", errInvalidSignature, SignatureLen, len(b))
+	}
+	g2 := bls12381.NewG2()
+	point, err := g2.FromBytes(b)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidSignature, err)
+	}
+	if !g2.InCorrectSubgroup(point) {
+		return nil, fmt.Errorf("%w: point not in subgroup", errInvalidSignature)
+	}
+	return &Signature{point: point}, nil
+}
+
+// Bytes returns the uncompressed encoding of [sig]
+func (sig *Signature) Bytes() []byte {
+	return bls12381.NewG2().ToBytes(new(bls12381.PointG2).Set(sig.point))
+}
+
+// Verify returns true if [sig] is a valid signature of [msg] by [pk]
+func Verify(pk *PublicKey, sig *Signature, msg []byte) bool {
+	g1 := bls12381.NewG1()
+	// e(pk, H(msg)) == e(g1, sig)
+	// Note: the pairing engine modifies the points it is given in place,
+	// so copies of [pk] and [sig] are used.
+	engine := bls12381.NewPairingEngine()
+	engine.AddPair(new(bls12381.PointG1).Set(pk.point), hashToG2(msg))
+	engine.AddPairInv(g1.One(), new(bls12381.PointG2).Set(sig.point))
+	return engine.Check()
+}
+
+// AggregatePublicKeys returns the sum of [pks]
+func AggregatePublicKeys(pks []*PublicKey) (*PublicKey, error) {
+	if len(pks) == 0 {
+		return nil, errNoPublicKeys
+	}
+	g1 := bls12381.NewG1()
+	agg := g1.Zero()
+	for _, pk := range pks {
+		g1.Add(agg, agg, pk.point)
+	}
+	return &PublicKey{point: agg}, nil
+}
+
+// AggregateSignatures returns the sum of [sigs]
+func AggregateSignatures(sigs []*Signature) (*Signature, error) {
+	if len(sigs) == 0 {
+		return nil, errNoSignatures
+	}
+	g2 := bls12381.NewG2()
+	agg := g2.Zero()
+	for _, sig := range sigs {
+		g2.Add(agg, agg, sig.point)
+	}
+	return &Signature{point: agg}, nil
+}
+
+// hashToG2 hashes [msg] to a point in G2 with hash_to_curve: [msg] is hashed to
+// two Fp2 elements with hash_to_field, each of them is mapped to the curve by
+// the SSWU map of the bls12381 package and the points are added.
+// Note: MapToCurve clears the cofactor of each point, which is equivalent to
+// clearing the cofactor of their sum.
+func hashToG2(msg []byte) *bls12381.PointG2 {
+	g2 := bls12381.NewG2()
+	uniformBytes := expandMessageXMD(msg, hashToCurveDST, 4*hashToFieldLen)
+
+	result := g2.Zero()
+	for i := 0; i < 2; i++ {
+		// MapToCurve expects the Fp2 element encoded as c1 || c0
+		fp2Bytes := make([]byte, 2*fieldElementLen)
+		for j := 0; j < 2; j++ {
+			offset := (2*i + j) * hashToFieldLen
+			e := new(big.Int).SetBytes(uniformBytes[offset : offset+hashToFieldLen])
+			e.Mod(e, fieldModulus)
+			e.FillBytes(fp2Bytes[(1-j)*fieldElementLen : (2-j)*fieldElementLen])
+		}
+		point, err := g2.MapToCurve(fp2Bytes)
+		if err != nil {
+			// Cannot happen since the field elements are reduced modulo p
+			panic(err)
+		}
+		g2.Add(result, result, point)
+	}
+	return g2.Affine(result)
+}
+
+// expandMessageXMD implements expand_message_xmd with SHA-256 as specified in
+// the hash to curve specification.
+func expandMessageXMD(msg []byte, dst []byte, lenInBytes int) []byte {
+	const hashLen = sha256.Size
+	ell := (lenInBytes + hashLen - 1) / hashLen
+
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+
+	h := sha256.New()
+	h.Write(make([]byte, sha256.BlockSize))
+	h.Write(msg)
+	h.Write([]byte{byte(lenInBytes >> 8), byte(lenInBytes), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	out := make([]byte, 0, ell*hashLen)
+	bi := make([]byte, hashLen)
+	for i := 1; i <= ell; i++ {
+		h.Reset()
+		for j := range bi {
+			bi[j] ^= b0[j]
+		}
+		h.Write(bi)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(nil)
+		out = append(out, bi...)
+	}
+	return out[:lenInBytes]
+}
@@ -0,0 +1,117 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto/bls12381"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandMessageXMD(t *testing.T) {
+	// Test vectors from RFC 9380, Section K.1
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	for msg, expected := range map[string]string{
+		"":    "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235",
+		"abc": "d8ccab23b5985ccea865c6c97b6e5b8350e794e603b4b97902f53a8a0d605615",
+	} {
+		assert.Equal(t, expected, hex.EncodeToString(expandMessageXMD([]byte(msg), dst, 32)), "msg: %q", msg)
+	}
+}
+
+func TestHashToG2(t *testing.T) {
+	// Test vector from RFC 9380, Section J.10.1
+	dst := hashToCurveDST
+	hashToCurveDST = []byte("QUUX-V01-CS02-with-BLS12381G2_XMD:SHA-256_SSWU_RO_")
+	defer func() { hashToCurveDST = dst }()
+
+	expected := "05cb8437535e20ecffaef7752baddf98034139c38452458baeefab379ba13dff5bf5dd71b72418717047f5b0f37da03d" + // x.c1
+		"0141ebfbdca40eb85b87142e130ab689c673cf60f1a3e98d69335266f30d9b8d4ac44c1038e9dcdd5393faf5c41fb78a" + // x.c0
+		"12424ac32561493f3fe3c260708a12b7c620e7be00099a974e259ddc7d1f6395c3c811cdd19f1e8dbf3e9ecfdcbab8d6" + // y.c1
+		"0503921d7f6a12805e72940b963c0cf3471c7b2a524950ca195d11062ee75ec076daf2d4bc358c4b190c0c98064fdd92" // y.c0
+	assert.Equal(t, expected, hex.EncodeToString(bls12381.NewG2().ToBytes(hashToG2(nil))))
+
+	expected = "139cddbccdc5e91b9623efd38c49f81a6f83f175e80b06fc374de9eb4b41dfe4ca3a230ed250fbe3a2acf73a41177fd8" + // x.c1
+		"02c2d18e033b960562aae3cab37a27ce00d80ccd5ba4b7fe0e7a210245129dbec7780ccc7954725f4168aff2787776e6" // x.c0
+	assert.Equal(t, expected, hex.EncodeToString(bls12381.NewG2().ToBytes(hashToG2([]byte("abc")))[:2*fieldElementLen]))
+}
+
+func TestSecretKeyFromSeed(t *testing.T) {
+	seed := make([]byte, minKeyGenSeedLen)
+	sk, err := SecretKeyFromSeed(seed)
+	assert.NoError(t, err)
+	otherSk, err := SecretKeyFromSeed(seed)
+	assert.NoError(t, err)
+	assert.Equal(t, sk.Bytes(), otherSk.Bytes())
+
+	seed[0] = 1
+	otherSk, err = SecretKeyFromSeed(seed)
+	assert.NoError(t, err)
+	assert.NotEqual(t, sk.Bytes(), otherSk.Bytes())
+
+	_, err = SecretKeyFromSeed(seed[1:])
+	assert.ErrorIs(t, err, errShortSeed)
+}
+
+func TestSignVerify(t *testing.T) {
+	msg := []byte("message")
+	sk, err := NewSecretKey()
+	assert.NoError(t, err)
+	otherSk, err := NewSecretKey()
+	assert.NoError(t, err)
+
+	sig := sk.Sign(msg)
+	assert.True(t, Verify(sk.PublicKey(), sig, msg))
+	assert.False(t, Verify(sk.PublicKey(), sig, []byte("other message")))
+	assert.False(t, Verify(otherSk.PublicKey(), sig, msg))
+
+	// Aggregate signatures verify against the aggregate of the signers' public keys
+	aggSig, err := AggregateSignatures([]*Signature{sig, otherSk.Sign(msg)})
+	assert.NoError(t, err)
+	aggPk, err := AggregatePublicKeys([]*PublicKey{sk.PublicKey(), otherSk.PublicKey()})
+	assert.NoError(t, err)
+	assert.True(t, Verify(aggPk, aggSig, msg))
+	assert.False(t, Verify(sk.PublicKey(), aggSig, msg))
+
+	_, err = AggregateSignatures(nil)
+	assert.ErrorIs(t, err, errNoSignatures)
+	_, err = AggregatePublicKeys(nil)
+	assert.ErrorIs(t, err, errNoPublicKeys)
+}
+
+func TestSerialization(t *testing.T) {
+	sk, err := NewSecretKey()
+	assert.NoError(t, err)
+	parsedSk, err := SecretKeyFromBytes(sk.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, sk.Bytes(), parsedSk.Bytes())
+	_, err = SecretKeyFromBytes(make([]byte, SecretKeyLen))
+	assert.ErrorIs(t, err, errInvalidSecretKey)
+
+	pk := sk.PublicKey()
+	parsedPk, err := PublicKeyFromBytes(pk.Bytes())
+	assert.NoError(t, err)
+	assert.True(t, pk.Equal(parsedPk))
+	_, err = PublicKeyFromBytes(make([]byte, PublicKeyLen))
+	assert.ErrorIs(t, err, errInvalidPublicKey)
+	_, err = PublicKeyFromBytes(pk.Bytes()[1:])
+	assert.ErrorIs(t, err, errInvalidPublicKey)
+
+	pkJSON, err := json.Marshal(pk)
+	assert.NoError(t, err)
+	jsonPk := new(PublicKey)
+	assert.NoError(t, json.Unmarshal(pkJSON, jsonPk))
+	assert.True(t, pk.Equal(jsonPk))
+
+	msg := []byte("message")
+	sig := sk.Sign(msg)
+	parsedSig, err := SignatureFromBytes(sig.Bytes())
+	assert.NoError(t, err)
+	assert.True(t, Verify(parsedPk, parsedSig, msg))
+	_, err = SignatureFromBytes(sig.Bytes()[1:])
+	assert.ErrorIs(t, err, errInvalidSignature)
+}
@@ -0,0 +1,142 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	errUnknownSigner      = errors.New("signer bit set references an unknown validator")
+	errNoSigners          = errors.New("no signers")
+	errInsufficientWeight = errors.New("insufficient signed weight")
+	errWeightOverflow     = errors.New("validator weight overflow")
+	errInvalidQuorum      = errors.New("invalid quorum")
+	errSignatureMismatch  = errors.New("aggregate signature does not match signers")
+)
+
+// UnsignedMessage is a message sent from [SourceAddress] on the chain identified by
+// [SourceChainID] to [DestinationAddress] on the chain identified by [DestinationChainID].
+type UnsignedMessage struct {
+	SourceChainID      common.Hash
+	SourceAddress      common.Address
+	DestinationChainID common.Hash
+	DestinationAddress common.Address
+	Payload            []byte
+}
+
+// ParseUnsignedMessage parses an UnsignedMessage encoded with [UnsignedMessage.Bytes]
+func ParseUnsignedMessage(b []byte) (*UnsignedMessage, error) {
+	msg := new(UnsignedMessage)
+	if err := rlp.DecodeBytes(b, msg); err != nil {
+		return nil, fmt.Errorf("failed to parse unsigned warp message: %w", err)
+	}
+	return msg, nil
+}
+
+// Bytes returns the canonical encoding of [m], which is the payload signed by validators
+func (m *UnsignedMessage) Bytes() ([]byte, error) {
+	return rlp.EncodeToBytes(m)
+}
+
+// ID returns the hash of the encoding of [m]
+func (m *UnsignedMessage) ID() (common.Hash, error) {
+	b, err := m.Bytes()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(b), nil
+}
+
+// SignedMessage is an encoded UnsignedMessage along with the aggregate signature
+// of a subset of the validators of the source chain.
+// Bit i of [Signers] (interpreted as a big endian integer) is set if the validator at
+// index i of the source chain's validator set contributed to [Signature].
+type SignedMessage struct {
+	UnsignedMessage []byte
+	Signers         []byte
+	Signature       []byte
+}
+
+// ParseSignedMessage parses a SignedMessage encoded with [SignedMessage.Bytes]
+func ParseSignedMessage(b []byte) (*SignedMessage, error) {
+	msg := new(SignedMessage)
+	if err := rlp.DecodeBytes(b, msg); err != nil {
+		return nil, fmt.Errorf("failed to parse signed warp message: %w", err)
+	}
+	return msg, nil
+}
+
+// Bytes returns the encoding of [m]
+func (m *SignedMessage) Bytes() ([]byte, error) {
+	return rlp.EncodeToBytes(m)
+}
+
+// NewSigners returns the signer bit set with the bits at [indices] set
+func NewSigners(indices ...int) []byte {
+	bits := new(big.Int)
+	for _, index := range indices {
+		bits.SetBit(bits, index, 1)
+	}
+	return bits.Bytes()
+}
+
+// Verify verifies that [m] is signed by validators in [validators] holding at least
+// [quorumNumerator]/[quorumDenominator] of the total weight of [validators].
+func (m *SignedMessage) Verify(validators []*Validator, quorumNumerator, quorumDenominator uint64) error {
+	if quorumDenominator == 0 || quorumNumerator == 0 || quorumNumerator > quorumDenominator {
+		return fmt.Errorf("%w: %d/%d", errInvalidQuorum, quorumNumerator, quorumDenominator)
+	}
+
+	signers := new(big.Int).SetBytes(m.Signers)
+	if signers.BitLen() > len(validators) {
+		return fmt.Errorf("%w: bit %d set with %d validators", errUnknownSigner, signers.BitLen()-1, len(validators))
+	}
+
+	var (
+		totalWeight  uint64
+		signedWeight uint64
+		publicKeys   = make([]*PublicKey, 0, len(validators))
+	)
+	for i, validator := range validators {
+		if totalWeight+validator.Weight < totalWeight {
+			return errWeightOverflow
+		}
+		totalWeight += validator.Weight
+		if signers.Bit(i) == 0 {
+			continue
+		}
+		signedWeight += validator.Weight
+		publicKeys = append(publicKeys, validator.PublicKey)
+	}
+	if len(publicKeys) == 0 {
+		return errNoSigners
+	}
+
+	// Check signedWeight / totalWeight >= quorumNumerator / quorumDenominator
+	scaledSignedWeight := new(big.Int).Mul(new(big.Int).SetUint64(signedWeight), new(big.Int).SetUint64(quorumDenominator))
+	scaledTotalWeight := new(big.Int).Mul(new(big.Int).SetUint64(totalWeight), new(big.Int).SetUint64(quorumNumerator))
+	if scaledSignedWeight.Cmp(scaledTotalWeight) < 0 {
+		return fmt.Errorf("%w: signed weight %d of total weight %d (quorum %d/%d)", errInsufficientWeight, signedWeight, totalWeight, quorumNumerator, quorumDenominator)
+	}
+
+	signature, err := SignatureFromBytes(m.Signature)
+	if err != nil {
+		return err
+	}
+	aggregatePublicKey, err := AggregatePublicKeys(publicKeys)
+	if err != nil {
+		return err
+	}
+	if !Verify(aggregatePublicKey, signature, m.UnsignedMessage) {
+		return errSignatureMismatch
+	}
+	return nil
+}
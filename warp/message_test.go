@@ -0,0 +1,189 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// testValidatorState is a ValidatorState with a fixed set of validators for a single source chain
+type testValidatorState struct {
+	sourceChainID common.Hash
+	secretKeys    []*SecretKey
+	validators    []*Validator
+}
+
+func newTestValidatorState(t *testing.T, sourceChainID common.Hash, weights ...uint64) *testValidatorState {
+	vs := &testValidatorState{sourceChainID: sourceChainID}
+	for _, weight := range weights {
+		sk, err := NewSecretKey()
+		assert.NoError(t, err)
+		vs.secretKeys = append(vs.secretKeys, sk)
+		vs.validators = append(vs.validators, &Validator{PublicKey: sk.PublicKey(), Weight: weight})
+	}
+	return vs
+}
+
+func (vs *testValidatorState) GetValidators(sourceChainID common.Hash) ([]*Validator, error) {
+	if sourceChainID != vs.sourceChainID {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSourceChain, sourceChainID)
+	}
+	return vs.validators, nil
+}
+
+// sign returns [unsignedMessage] signed by the validators at [indices]
+func (vs *testValidatorState) sign(t *testing.T, unsignedMessage *UnsignedMessage, indices ...int) *SignedMessage {
+	unsignedMessageBytes, err := unsignedMessage.Bytes()
+	assert.NoError(t, err)
+	signatures := make([]*Signature, 0, len(indices))
+	for _, index := range indices {
+		signatures = append(signatures, vs.secretKeys[index].Sign(unsignedMessageBytes))
+	}
+	signature, err := AggregateSignatures(signatures)
+	assert.NoError(t, err)
+	return &SignedMessage{
+		UnsignedMessage: unsignedMessageBytes,
+		Signers:         NewSigners(indices...),
+		Signature:       signature.Bytes(),
+	}
+}
+
+func TestMessageSerialization(t *testing.T) {
+	unsignedMessage := &UnsignedMessage{
+		SourceChainID:      common.HexToHash("0x01"),
+		SourceAddress:      common.HexToAddress("0x02"),
+		DestinationChainID: common.HexToHash("0x03"),
+		DestinationAddress: common.HexToAddress("0x04"),
+		Payload:            []byte("payload"),
+	}
+	unsignedMessageBytes, err := unsignedMessage.Bytes()
+	assert.NoError(t, err)
+	parsedUnsignedMessage, err := ParseUnsignedMessage(unsignedMessageBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, unsignedMessage, parsedUnsignedMessage)
+
+	vs := newTestValidatorState(t, unsignedMessage.SourceChainID, 1)
+	signedMessage := vs.sign(t, unsignedMessage, 0)
+	signedMessageBytes, err := signedMessage.Bytes()
+	assert.NoError(t, err)
+	parsedSignedMessage, err := ParseSignedMessage(signedMessageBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, signedMessage, parsedSignedMessage)
+
+	_, err = ParseSignedMessage(unsignedMessageBytes)
+	assert.Error(t, err)
+}
+
+func TestSignedMessageVerify(t *testing.T) {
+	sourceChainID := common.HexToHash("0x01")
+	unsignedMessage := &UnsignedMessage{
+		SourceChainID:      sourceChainID,
+		SourceAddress:      common.HexToAddress("0x02"),
+		DestinationChainID: common.HexToHash("0x03"),
+		DestinationAddress: common.HexToAddress("0x04"),
+		Payload:            []byte("payload"),
+	}
+	vs := newTestValidatorState(t, sourceChainID, 10, 20, 30, 40)
+
+	type test struct {
+		signedMessage     func() *SignedMessage
+		quorumNumerator   uint64
+		quorumDenominator uint64
+		expectedErr       error
+	}
+	for name, test := range map[string]test{
+		"all validators": {
+			signedMessage:     func() *SignedMessage { return vs.sign(t, unsignedMessage, 0, 1, 2, 3) },
+			quorumNumerator:   67,
+			quorumDenominator: 100,
+		},
+		"exact quorum": {
+			signedMessage:     func() *SignedMessage { return vs.sign(t, unsignedMessage, 0, 1, 2) },
+			quorumNumerator:   60,
+			quorumDenominator: 100,
+		},
+		"insufficient weight": {
+			signedMessage:     func() *SignedMessage { return vs.sign(t, unsignedMessage, 0, 1, 2) },
+			quorumNumerator:   61,
+			quorumDenominator: 100,
+			expectedErr:       errInsufficientWeight,
+		},
+		"no signers": {
+			signedMessage: func() *SignedMessage {
+				signedMessage := vs.sign(t, unsignedMessage, 0)
+				signedMessage.Signers = nil
+				return signedMessage
+			},
+			quorumNumerator:   1,
+			quorumDenominator: 100,
+			expectedErr:       errNoSigners,
+		},
+		"unknown signer": {
+			signedMessage: func() *SignedMessage {
+				signedMessage := vs.sign(t, unsignedMessage, 0, 1, 2, 3)
+				signedMessage.Signers = NewSigners(0, 1, 2, 3, 4)
+				return signedMessage
+			},
+			quorumNumerator:   67,
+			quorumDenominator: 100,
+			expectedErr:       errUnknownSigner,
+		},
+		"mismatched signers": {
+			signedMessage: func() *SignedMessage {
+				signedMessage := vs.sign(t, unsignedMessage, 0, 1, 2)
+				signedMessage.Signers = NewSigners(1, 2, 3)
+				return signedMessage
+			},
+			quorumNumerator:   67,
+			quorumDenominator: 100,
+			expectedErr:       errSignatureMismatch,
+		},
+		"modified message": {
+			signedMessage: func() *SignedMessage {
+				modifiedMessage := *unsignedMessage
+				modifiedMessage.Payload = []byte("modified payload")
+				signedMessage := vs.sign(t, unsignedMessage, 0, 1, 2, 3)
+				signedMessage.UnsignedMessage, _ = modifiedMessage.Bytes()
+				return signedMessage
+			},
+			quorumNumerator:   67,
+			quorumDenominator: 100,
+			expectedErr:       errSignatureMismatch,
+		},
+		"invalid signature": {
+			signedMessage: func() *SignedMessage {
+				signedMessage := vs.sign(t, unsignedMessage, 0, 1, 2, 3)
+				signedMessage.Signature = signedMessage.Signature[1:]
+				return signedMessage
+			},
+			quorumNumerator:   67,
+			quorumDenominator: 100,
+			expectedErr:       errInvalidSignature,
+		},
+		"invalid quorum": {
+			signedMessage:     func() *SignedMessage { return vs.sign(t, unsignedMessage, 0, 1, 2, 3) },
+			quorumNumerator:   101,
+			quorumDenominator: 100,
+			expectedErr:       errInvalidQuorum,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			validators, err := vs.GetValidators(sourceChainID)
+			assert.NoError(t, err)
+			err = test.signedMessage().Verify(validators, test.quorumNumerator, test.quorumDenominator)
+			if test.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.expectedErr)
+			}
+		})
+	}
+
+	_, err := vs.GetValidators(common.HexToHash("0x02"))
+	assert.ErrorIs(t, err, ErrUnknownSourceChain)
+}
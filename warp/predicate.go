@@ -0,0 +1,49 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// predicateDelimiter terminates the packed predicate so that trailing zero bytes
+// of the original payload can be distinguished from padding.
+const predicateDelimiter = byte(0xff)
+
+var errInvalidPredicatePadding = errors.New("invalid predicate padding")
+
+// PackPredicate packs [b] into the storage keys of an access list tuple.
+// [b] is followed by a delimiter byte and right padded with zeroes to a
+// multiple of 32 bytes.
+func PackPredicate(b []byte) []common.Hash {
+	packed := make([]byte, len(b)+1)
+	copy(packed, b)
+	packed[len(b)] = predicateDelimiter
+
+	keys := make([]common.Hash, (len(packed)+common.HashLength-1)/common.HashLength)
+	for i := range keys {
+		copy(keys[i][:], packed[i*common.HashLength:])
+	}
+	return keys
+}
+
+// UnpackPredicate returns the bytes packed into the concatenated storage keys
+// [packed] by PackPredicate.
+func UnpackPredicate(packed []byte) ([]byte, error) {
+	if len(packed)%common.HashLength != 0 {
+		return nil, errInvalidPredicatePadding
+	}
+	end := len(packed) - 1
+	for end >= 0 && packed[end] == 0 {
+		end--
+	}
+	// The padding must be shorter than a single storage key and must
+	// be preceded by the delimiter.
+	if end < 0 || len(packed)-end > common.HashLength || packed[end] != predicateDelimiter {
+		return nil, errInvalidPredicatePadding
+	}
+	return packed[:end], nil
+}
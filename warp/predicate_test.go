@@ -0,0 +1,44 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackUnpackPredicate(t *testing.T) {
+	for _, length := range []int{0, 1, 31, 32, 33, 64, 100} {
+		b := bytes.Repeat([]byte{0}, length)
+		if length > 0 {
+			b[0] = 1
+		}
+		keys := PackPredicate(b)
+		assert.Len(t, keys, length/common.HashLength+1)
+
+		packed := make([]byte, 0, len(keys)*common.HashLength)
+		for _, key := range keys {
+			packed = append(packed, key.Bytes()...)
+		}
+		unpacked, err := UnpackPredicate(packed)
+		assert.NoError(t, err)
+		assert.Equal(t, b, unpacked)
+	}
+}
+
+func TestUnpackInvalidPredicate(t *testing.T) {
+	for name, packed := range map[string][]byte{
+		"empty":             {},
+		"not padded":        {1, 2, 3, predicateDelimiter},
+		"missing delimiter": make([]byte, common.HashLength),
+		"excess padding":    append(append([]byte{predicateDelimiter}, make([]byte, common.HashLength-1)...), make([]byte, common.HashLength)...),
+		"non-zero padding":  append([]byte{predicateDelimiter}, bytes.Repeat([]byte{1}, common.HashLength-1)...),
+	} {
+		_, err := UnpackPredicate(packed)
+		assert.ErrorIs(t, err, errInvalidPredicatePadding, name)
+	}
+}
@@ -0,0 +1,28 @@
+// (c) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrUnknownSourceChain is returned by a ValidatorState that does not know the
+// validator set of the requested chain.
+var ErrUnknownSourceChain = errors.New("unknown source chain")
+
+// Validator is a member of the validator set of a chain that can sign warp messages
+type Validator struct {
+	PublicKey *PublicKey `json:"publicKey"`
+	Weight    uint64     `json:"weight"`
+}
+
+// ValidatorState provides the validator sets used to verify warp messages
+type ValidatorState interface {
+	// GetValidators returns the ordered validator set of [sourceChainID].
+	// The order of the validators determines the meaning of the signer bit set
+	// of a SignedMessage.
+	GetValidators(sourceChainID common.Hash) ([]*Validator, error)
+}